@@ -2,13 +2,18 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"pet-clinical-history/internal/adapters/auth/jwt"
+	"pet-clinical-history/internal/adapters/auth/odin"
+	"pet-clinical-history/internal/ports/auth"
 	"pet-clinical-history/internal/router"
 
 	_ "pet-clinical-history/docs" // importa docs generados por swag
@@ -45,13 +50,26 @@ func main() {
 		addr = ":" + v
 	}
 
-	// MVP: sin verifier para modo dev.
-	// Más adelante aquí podrás wirear Odin-IAM (AuthVerifier real).
-	r := router.NewRouter(router.Options{AuthVerifier: nil})
+	verifier, err := authVerifierFromEnv()
+	if err != nil {
+		log.Fatalf("auth config: %v", err)
+	}
+	shutdownTimeout, err := shutdownTimeoutFromEnv()
+	if err != nil {
+		log.Fatalf("shutdown config: %v", err)
+	}
+	inviteScopes, err := router.DefaultInviteScopesFromEnv()
+	if err != nil {
+		log.Fatalf("grants config: %v", err)
+	}
+	r := router.NewRouter(router.Options{AuthVerifier: verifier, DefaultInviteScopes: inviteScopes})
+
+	// Contador de requests en curso, para loguear el drenaje en el shutdown.
+	active := &inFlight{}
 
 	srv := &http.Server{
 		Addr:         addr,
-		Handler:      r,
+		Handler:      active.Wrap(r),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
@@ -80,13 +98,74 @@ func main() {
 		// si errCh cierra sin error, caemos a shutdown igual
 	}
 
-	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// Graceful shutdown: deja de aceptar conexiones y espera a los requests en curso
+	log.Printf("draining %d in-flight requests (grace period %s)", active.Count(), shutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("shutdown error: %v", err)
+		log.Printf("shutdown error: %v (%d requests still in flight)", err, active.Count())
 	} else {
 		log.Printf("server stopped")
 	}
 }
+
+// authVerifierFromEnv elige el verificador según AUTH_MODE:
+// - dev (default): sin verifier, el middleware acepta X-Debug-User-ID.
+// - jwt: validación local; JWT_SECRET (HS256) y/o JWT_PUBLIC_KEY_FILE (PEM, RS256);
+// JWT_INTEGRATION_CLAIM / JWT_ADMIN_CLAIM cambian los claims de integración y de admin.
+// - odin: round-trip a Odin-IAM con ODIN_BASE_URL / ODIN_API_KEY, con cache de verificaciones
+// (ODIN_VERIFY_CACHE_TTL como duración Go, p.ej. "60s"; default odin.DefaultCacheTTL). El
+// contrato de verificación se ajusta con ODIN_VERIFY_PATH y ODIN_USER_ID_FIELD /
+// ODIN_EMAIL_FIELD / ODIN_TENANT_FIELD / ODIN_INTEGRATION_FIELD / ODIN_ADMIN_FIELD (vacíos =>
+// defaults de odin.Config).
+func authVerifierFromEnv() (auth.AuthVerifier, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("AUTH_MODE"))); mode {
+	case "", "dev":
+		return nil, nil
+	case "jwt":
+		cfg := jwt.Config{
+			Secret:           []byte(os.Getenv("JWT_SECRET")),
+			IntegrationClaim: os.Getenv("JWT_INTEGRATION_CLAIM"),
+			AdminClaim:       os.Getenv("JWT_ADMIN_CLAIM"),
+		}
+		if path := os.Getenv("JWT_PUBLIC_KEY_FILE"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if cfg.PublicKey, err = jwt.ParseRSAPublicKeyPEM(data); err != nil {
+				return nil, err
+			}
+		}
+		if len(cfg.Secret) == 0 && cfg.PublicKey == nil {
+			return nil, fmt.Errorf("AUTH_MODE=jwt requires JWT_SECRET or JWT_PUBLIC_KEY_FILE")
+		}
+		return jwt.NewVerifier(cfg), nil
+	case "odin":
+		client := odin.NewClient(odin.Config{
+			BaseURL:          os.Getenv("ODIN_BASE_URL"),
+			APIKey:           os.Getenv("ODIN_API_KEY"),
+			VerifyPath:       os.Getenv("ODIN_VERIFY_PATH"),
+			UserIDField:      os.Getenv("ODIN_USER_ID_FIELD"),
+			EmailField:       os.Getenv("ODIN_EMAIL_FIELD"),
+			TenantField:      os.Getenv("ODIN_TENANT_FIELD"),
+			IntegrationField: os.Getenv("ODIN_INTEGRATION_FIELD"),
+			AdminField:       os.Getenv("ODIN_ADMIN_FIELD"),
+		})
+		if !client.IsConfigured() {
+			return nil, fmt.Errorf("AUTH_MODE=odin requires ODIN_BASE_URL and ODIN_API_KEY")
+		}
+		var ttl time.Duration
+		if v := os.Getenv("ODIN_VERIFY_CACHE_TTL"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ODIN_VERIFY_CACHE_TTL: %w", err)
+			}
+			ttl = d
+		}
+		return odin.NewCachingVerifier(odin.NewVerifier(client), ttl, 0), nil
+	default:
+		return nil, fmt.Errorf("unknown AUTH_MODE %q (dev|jwt|odin)", mode)
+	}
+}
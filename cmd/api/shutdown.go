@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// defaultShutdownTimeout es el plazo para drenar requests en curso al recibir SIGTERM/SIGINT.
+const defaultShutdownTimeout = 10 * time.Second
+
+// shutdownTimeoutFromEnv lee SHUTDOWN_TIMEOUT (duración Go, p.ej. "30s"); vacío => default.
+func shutdownTimeoutFromEnv() (time.Duration, error) {
+	v := os.Getenv("SHUTDOWN_TIMEOUT")
+	if v == "" {
+		return defaultShutdownTimeout, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid SHUTDOWN_TIMEOUT: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid SHUTDOWN_TIMEOUT: must be positive, got %s", d)
+	}
+	return d, nil
+}
+
+// inFlight cuenta los requests en curso, para informar cuántos se drenan en el shutdown.
+type inFlight struct {
+	n atomic.Int64
+}
+
+// Wrap envuelve el handler: el contador sube al entrar y baja al terminar (también si hay panic).
+func (c *inFlight) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.n.Add(1)
+		defer c.n.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Count devuelve la cantidad de requests en curso.
+func (c *inFlight) Count() int64 {
+	return c.n.Load()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownTimeoutFromEnv(t *testing.T) {
+	cases := []struct {
+		env     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", defaultShutdownTimeout, false},
+		{"30s", 30 * time.Second, false},
+		{"1m30s", 90 * time.Second, false},
+		{"30", 0, true},
+		{"0s", 0, true},
+		{"-5s", 0, true},
+	}
+	for _, c := range cases {
+		t.Setenv("SHUTDOWN_TIMEOUT", c.env)
+		got, err := shutdownTimeoutFromEnv()
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Fatalf("SHUTDOWN_TIMEOUT=%q: expected %s (err=%v), got %s (err=%v)", c.env, c.want, c.wantErr, got, err)
+		}
+	}
+}
+
+func TestInFlight_CountsActiveRequests(t *testing.T) {
+	active := &inFlight{}
+	release := make(chan struct{})
+	var started sync.WaitGroup
+
+	h := active.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started.Done()
+		<-release
+		if r.URL.Path == "/panic" {
+			panic("boom")
+		}
+	}))
+	serve := func(path string) {
+		defer func() { _ = recover() }()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	var done sync.WaitGroup
+	for _, path := range []string{"/pets", "/pets", "/panic"} {
+		started.Add(1)
+		done.Add(1)
+		go func(p string) {
+			defer done.Done()
+			serve(p)
+		}(path)
+	}
+	started.Wait()
+	if n := active.Count(); n != 3 {
+		t.Fatalf("expected 3 in-flight requests, got %d", n)
+	}
+
+	close(release)
+	done.Wait()
+	if n := active.Count(); n != 0 {
+		t.Fatalf("expected counter back to 0 (including the panicking request), got %d", n)
+	}
+}
@@ -24,9 +24,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/grants/{grantID}/accept": {
+        "/grants/scopes/validate": {
             "post": {
-                "description": "Acepta una invitación pendiente para que el usuario autenticado se convierta en delegado de una mascota. Solo el grantee puede aceptar su invitación. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "description": "Valida un set de scopes propuesto con las mismas reglas que la invitación, sin crear nada. Devuelve los scopes normalizados (sin duplicados) y los no reconocidos, para que la UI pueda marcarlos antes de invitar. Cualquier usuario autenticado puede usarlo. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "consumes": [
                     "application/json"
                 ],
@@ -36,7 +36,65 @@ const docTemplate = `{
                 "tags": [
                     "accessgrants"
                 ],
-                "summary": "Aceptar una invitación de grant",
+                "summary": "Validar un set de scopes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Scopes a validar",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accessgrants.validateScopesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accessgrants.validateScopesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid json",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/grants/{grantID}": {
+            "patch": {
+                "description": "Reemplaza los scopes de un grant existente (invitado o activo). Solo el owner de la mascota puede hacerlo. Los scopes se validan estrictamente; si el grant es una sub-delegación no puede exceder los scopes del delegador, y los grants sub-delegados a partir de él se recortan. Es la forma explícita de cambiar scopes (re-invitar sigue actualizándolos, pero no se recomienda). Los scopes deben estar habilitados en el plan del owner (capability ` + "`" + `pet:grants:\u003cscope\u003e` + "`" + `). Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accessgrants"
+                ],
+                "summary": "Modificar los scopes de un grant",
                 "parameters": [
                     {
                         "type": "string",
@@ -52,10 +110,19 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID del grant a aceptar",
+                        "description": "ID del grant",
                         "name": "grantID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Scopes nuevos (reemplazan a los actuales)",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accessgrants.updateGrantScopesRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -66,47 +133,59 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid input",
+                        "description": "invalid json / invalid input (scope no soportado o vacío)",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "401": {
                         "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "402": {
+                        "description": "error.code: capability_missing (el plan del owner no permite otorgar alguno de los scopes; el mensaje los lista)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "403": {
-                        "description": "forbidden",
+                        "description": "forbidden / scopes exceed delegator's grant",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "404": {
                         "description": "not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "409": {
-                        "description": "bad state para aceptar (ej: ya aceptado/revocado)",
+                        "description": "invalid state (grant revocado o rechazado)",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "503": {
+                        "description": "capabilities unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
         },
-        "/grants/{grantID}/revoke": {
+        "/grants/{grantID}/accept": {
             "post": {
-                "description": "Revoca un grant existente. Puede ser ejecutado por el owner o, según la lógica de negocio, por el delegado cuando corresponda. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "description": "Acepta una invitación pendiente para que el usuario autenticado se convierta en delegado de una mascota. Solo el grantee puede aceptar su invitación, y solo antes de su ` + "`" + `invite_expires_at` + "`" + ` (después → 409; el owner debe re-invitar). Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "consumes": [
                     "application/json"
                 ],
@@ -116,7 +195,7 @@ const docTemplate = `{
                 "tags": [
                     "accessgrants"
                 ],
-                "summary": "Revocar un grant",
+                "summary": "Aceptar una invitación de grant",
                 "parameters": [
                     {
                         "type": "string",
@@ -132,7 +211,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID del grant a revocar",
+                        "description": "ID del grant a aceptar",
                         "name": "grantID",
                         "in": "path",
                         "required": true
@@ -148,39 +227,45 @@ const docTemplate = `{
                     "400": {
                         "description": "invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "401": {
                         "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "403": {
                         "description": "forbidden",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "404": {
                         "description": "not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "bad state para aceptar (ej: revocado, rechazado o invitación vencida)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
         },
-        "/me/grants": {
-            "get": {
-                "description": "Lista los grants donde el usuario autenticado es el delegado (grantee). Opcionalmente filtra por estado mediante ` + "`" + `status=invited,active` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+        "/grants/{grantID}/decline": {
+            "post": {
+                "description": "Rechaza una invitación pendiente; el grant queda en ` + "`" + `declined` + "`" + ` y no otorga acceso. Solo el grantee puede rechazar su invitación. Es idempotente si ya estaba rechazada. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "consumes": [
                     "application/json"
                 ],
@@ -190,7 +275,7 @@ const docTemplate = `{
                 "tags": [
                     "accessgrants"
                 ],
-                "summary": "Listar mis grants como delegado",
+                "summary": "Rechazar una invitación de grant",
                 "parameters": [
                     {
                         "type": "string",
@@ -206,46 +291,68 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Lista CSV de estados permitidos (ej: invited,active)",
-                        "name": "status",
-                        "in": "query"
+                        "description": "ID del grant a rechazar",
+                        "name": "grantID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/accessgrants.grantResponse"
-                            }
+                            "$ref": "#/definitions/accessgrants.grantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "401": {
                         "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "bad state para rechazar (ej: ya aceptado/revocado)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
         },
-        "/me/pets": {
-            "get": {
-                "description": "Lista las mascotas compartidas con el usuario autenticado mediante grants activos que incluyan el scope ` + "`" + `pet:read` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+        "/grants/{grantID}/leave": {
+            "post": {
+                "description": "El delegado renuncia a su propio grant (invitado o activo): queda ` + "`" + `revoked` + "`" + ` y pierde el acceso de inmediato; sus sub-delegaciones se revocan en cascada. Solo el grantee puede hacerlo (el owner usa ` + "`" + `/revoke` + "`" + `). Es idempotente si ya estaba revocado. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pets"
+                    "accessgrants"
                 ],
-                "summary": "Listar mascotas compartidas conmigo",
+                "summary": "Abandonar un grant (delegado)",
                 "parameters": [
                     {
                         "type": "string",
@@ -258,31 +365,74 @@ const docTemplate = `{
                         "description": "Bearer token en producción",
                         "name": "Authorization",
                         "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del grant a abandonar",
+                        "name": "grantID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/pets.sharedPetResponse"
-                            }
+                            "$ref": "#/definitions/accessgrants.grantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "bad state (ej: invitación rechazada)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
         },
-        "/pets": {
-            "get": {
-                "description": "Lista todas las mascotas cuyo propietario es el usuario autenticado. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod). Solo el owner ve este listado; los delegados no listan aquí.",
+        "/grants/{grantID}/revoke": {
+            "post": {
+                "description": "Revoca un grant existente y, en cascada, todos los grants sub-delegados a partir de él. Puede ejecutarlo el owner de la mascota o el delegado que otorgó el grant (sub-delegación). Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "pets"
+                    "accessgrants"
                 ],
-                "summary": "Listar mis mascotas",
+                "summary": "Revocar un grant",
                 "parameters": [
                     {
                         "type": "string",
@@ -295,28 +445,58 @@ const docTemplate = `{
                         "description": "Bearer token en producción",
                         "name": "Authorization",
                         "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del grant a revocar",
+                        "name": "grantID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/pets.petResponse"
-                            }
+                            "$ref": "#/definitions/accessgrants.grantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "401": {
                         "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Crea una mascota. Species: ` + "`" + `dog` + "`" + `, ` + "`" + `cat` + "`" + `. Breeds Perro: ` + "`" + `labrador` + "`" + `, ` + "`" + `golden_retriever` + "`" + `, ` + "`" + `poodle` + "`" + `, etc. Breeds Gato: ` + "`" + `persian` + "`" + `, ` + "`" + `common` + "`" + `, etc.",
+            }
+        },
+        "/me/grants": {
+            "get": {
+                "description": "Lista los grants donde el usuario autenticado es el delegado (grantee). Opcionalmente filtra por estado mediante ` + "`" + `status=invited,active` + "`" + `. Una invitación cuyo plazo para aceptar (` + "`" + `invite_expires_at` + "`" + `) ya pasó figura como ` + "`" + `expired` + "`" + `. Paginado por cursor (orden updated_at desc, id desc): si hay más grants la respuesta trae ` + "`" + `has_more: true` + "`" + ` e incluye ` + "`" + `next_cursor` + "`" + `, que se pasa como ` + "`" + `cursor` + "`" + ` para pedir la página siguiente (con el mismo ` + "`" + `status` + "`" + `). Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "consumes": [
                     "application/json"
                 ],
@@ -324,9 +504,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "pets"
+                    "accessgrants"
                 ],
-                "summary": "Crear una mascota",
+                "summary": "Listar mis grants como delegado",
                 "parameters": [
                     {
                         "type": "string",
@@ -341,41 +521,62 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Datos de la mascota; birth_date opcional (YYYY-MM-DD)",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/pets.createPetRequest"
-                        }
+                        "type": "string",
+                        "description": "Lista CSV de estados permitidos: invited, active, revoked, declined, expired (ej: invited,active)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de grants por página (default 50, máx 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco de la página siguiente (next_cursor de la respuesta anterior)",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/pets.petResponse"
+                            "$ref": "#/definitions/accessgrants.grantListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "cursor inválido",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "401": {
                         "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
         },
-        "/pets/{petID}": {
+        "/me/pets": {
             "get": {
-                "description": "Obtiene el perfil de una mascota. El dueño siempre tiene acceso (bypass). Un delegado necesita un grant activo con scope ` + "`" + `pet:read` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "description": "Lista las mascotas compartidas con el usuario autenticado mediante grants activos que incluyan el scope ` + "`" + `pet:read` + "`" + `. Paginado por cursor sobre los grants (orden updated_at desc, id desc): si hay más la respuesta trae ` + "`" + `has_more: true` + "`" + ` e incluye ` + "`" + `next_cursor` + "`" + `, que se pasa como ` + "`" + `cursor` + "`" + ` para pedir la página siguiente. Una página puede traer menos de ` + "`" + `limit` + "`" + ` mascotas (grants sin ` + "`" + `pet:read` + "`" + ` o de mascotas borradas no se muestran). Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "pets"
                 ],
-                "summary": "Obtener perfil de mascota",
+                "summary": "Listar mascotas compartidas conmigo",
                 "parameters": [
                     {
                         "type": "string",
@@ -389,47 +590,57 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header"
                     },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de grants por página (default 50, máx 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "ID de la mascota",
-                        "name": "petID",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor opaco de la página siguiente (next_cursor de la respuesta anterior)",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/pets.petResponse"
+                            "$ref": "#/definitions/pets.sharedPetListResponse"
                         }
                     },
-                    "403": {
-                        "description": "forbidden",
+                    "400": {
+                        "description": "cursor inválido",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
-                    "404": {
-                        "description": "pet not found",
+                    "401": {
+                        "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Actualiza parcialmente el perfil de una mascota. El dueño siempre tiene acceso (bypass). Un delegado necesita un grant activo con scope ` + "`" + `pet:edit_profile` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod). Campo ` + "`" + `birth_date` + "`" + ` se maneja con semántica PATCH especial: si no se envía, no cambia; si se envía como ` + "`" + `null` + "`" + `, se limpia; si se envía como string ` + "`" + `YYYY-MM-DD` + "`" + `, se actualiza.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/me/pets/all": {
+            "get": {
+                "description": "Une en una sola lista las mascotas propias (como ` + "`" + `GET /pets` + "`" + `) y las compartidas conmigo (como ` + "`" + `GET /me/pets` + "`" + `: grants activos con ` + "`" + `pet:read` + "`" + `). Cada entrada indica ` + "`" + `relationship` + "`" + ` (` + "`" + `owner` + "`" + ` o ` + "`" + `delegate` + "`" + `); las de delegado traen además el grant y sus scopes. Primero las propias, luego las compartidas. Si una mascota apareciera por ambos caminos, se informa una sola vez como ` + "`" + `owner` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "pets"
                 ],
-                "summary": "Actualizar perfil de mascota",
+                "summary": "Listar mis mascotas (propias y compartidas)",
                 "parameters": [
                     {
                         "type": "string",
@@ -442,53 +653,104 @@ const docTemplate = `{
                         "description": "Bearer token en producción",
                         "name": "Authorization",
                         "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/pets.myPetResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/reminders": {
+            "get": {
+                "description": "Devuelve los próximos vencimientos (p.ej. próxima desparasitación, antipulgas o refuerzo de vacuna) de todas las mascotas del usuario autenticado, dentro de la ventana ` + "`" + `within` + "`" + `, ordenados por fecha ascendente. Solo considera mascotas propias y eventos activos. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Recordatorios de todas mis mascotas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
                     },
                     {
                         "type": "string",
-                        "description": "ID de la mascota",
-                        "name": "petID",
-                        "in": "path",
-                        "required": true
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
                     },
                     {
-                        "description": "Campos a actualizar",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/pets.updatePetRequest"
-                        }
+                        "type": "string",
+                        "description": "Ventana hacia adelante: días (` + "`" + `30d` + "`" + `) o duración Go (` + "`" + `72h` + "`" + `). Por defecto 30d, máximo 365d",
+                        "name": "within",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/pets.petResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/events.reminderResponse"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "forbidden",
+                    "400": {
+                        "description": "within inválido",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
         },
-        "/pets/{petID}/events": {
+        "/pets": {
             "get": {
-                "description": "Lista los eventos clínicos de una mascota. El dueño siempre puede verlos. Un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod). Permite filtrar por tipos, rango de fechas y texto.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lista todas las mascotas cuyo propietario es el usuario autenticado. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod). Solo el owner ve este listado; los delegados no listan aquí. Con ` + "`" + `include=last_activity` + "`" + ` agrega ` + "`" + `last_event_at` + "`" + ` (occurred_at del evento activo más reciente); ` + "`" + `sort` + "`" + ` ordena por ` + "`" + `created_at` + "`" + ` (default), ` + "`" + `name` + "`" + ` o ` + "`" + `updated_at` + "`" + `, con ` + "`" + `order=asc|desc` + "`" + ` (default asc); ` + "`" + `sort=last_activity` + "`" + ` ordena por esa fecha, más reciente primero y mascotas sin eventos al final (no admite ` + "`" + `order` + "`" + `). ` + "`" + `limit` + "`" + ` (1..200) y ` + "`" + `offset` + "`" + ` paginan el resultado ya ordenado. ` + "`" + `species` + "`" + ` filtra por especie exacta y ` + "`" + `q` + "`" + ` busca en el nombre (substring, sin distinguir mayúsculas); sin parámetros devuelve todas las vigentes. Las archivadas por el owner (` + "`" + `status=archived` + "`" + `) solo aparecen con ` + "`" + `include_archived=true` + "`" + `.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "pets"
                 ],
-                "summary": "Listar eventos de una mascota",
+                "summary": "Listar mis mascotas",
                 "parameters": [
                     {
                         "type": "string",
@@ -503,41 +765,69 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
+                        "enum": [
+                            "last_activity"
+                        ],
                         "type": "string",
-                        "description": "ID de la mascota",
-                        "name": "petID",
-                        "in": "path",
-                        "required": true
+                        "description": "CSV de campos calculados a incluir",
+                        "name": "include",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Máximo de eventos a devolver (1-200). Por defecto 50",
-                        "name": "limit",
+                        "enum": [
+                            "created_at",
+                            "name",
+                            "updated_at",
+                            "last_activity"
+                        ],
+                        "type": "string",
+                        "description": "Orden del listado (default: created_at)",
+                        "name": "sort",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
                         "type": "string",
-                        "description": "Lista CSV de tipos de evento a incluir (ej: MEDICAL_VISIT,BATH)",
-                        "name": "types",
+                        "description": "Dirección del orden (default: asc)",
+                        "name": "order",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Fecha/hora mínima occurred_at (RFC3339)",
-                        "name": "from",
+                        "type": "integer",
+                        "description": "Máximo de mascotas a devolver (1..200; default: todas)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de mascotas a saltear",
+                        "name": "offset",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "dog",
+                            "cat"
+                        ],
                         "type": "string",
-                        "description": "Fecha/hora máxima occurred_at (RFC3339)",
-                        "name": "to",
+                        "description": "Filtrar por especie",
+                        "name": "species",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Texto de búsqueda libre en título/notas",
+                        "description": "Texto a buscar en el nombre",
                         "name": "q",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir las mascotas archivadas (default: false)",
+                        "name": "include_archived",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -546,44 +836,32 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/events.eventResponse"
+                                "$ref": "#/definitions/pets.petResponse"
                             }
                         }
                     },
                     "400": {
-                        "description": "Parámetros de filtro inválidos",
+                        "description": "include / include_archived / sort / order / limit / offset inválido",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "401": {
                         "description": "unauthorized",
                         "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "403": {
-                        "description": "forbidden",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "pet not found",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Crea un nuevo evento clínico para la mascota indicada. El dueño siempre puede crear eventos. Un delegado necesita un grant activo con scope ` + "`" + `events:create` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "description": "Crea una mascota. Species: ` + "`" + `dog` + "`" + `, ` + "`" + `cat` + "`" + `. Breeds Perro: ` + "`" + `labrador` + "`" + `, ` + "`" + `golden_retriever` + "`" + `, ` + "`" + `poodle` + "`" + `, etc. Breeds Gato: ` + "`" + `persian` + "`" + `, ` + "`" + `common` + "`" + `, etc. Si el plan del usuario tiene la cuota ` + "`" + `pets:max` + "`" + `, no se puede superar (cuentan las mascotas vigentes del owner). Con ` + "`" + `Idempotency-Key` + "`" + `, un reintento del mismo usuario devuelve la mascota original (mismo 201) sin crear otra.",
                 "consumes": [
                     "application/json"
                 ],
@@ -591,9 +869,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "pets"
                 ],
-                "summary": "Crear evento de mascota",
+                "summary": "Crear una mascota",
                 "parameters": [
                     {
                         "type": "string",
@@ -609,18 +887,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID de la mascota",
-                        "name": "petID",
-                        "in": "path",
-                        "required": true
+                        "description": "Opcional (máx. 255): un reintento con la misma key devuelve la mascota ya creada (201) en lugar de duplicarla",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     },
                     {
-                        "description": "Datos del evento; occurred_at en formato RFC3339",
+                        "description": "Datos de la mascota; birth_date opcional (YYYY-MM-DD)",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/events.createEventRequest"
+                            "$ref": "#/definitions/pets.createPetRequest"
                         }
                     }
                 ],
@@ -628,49 +905,52 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/events.eventResponse"
+                            "$ref": "#/definitions/pets.petResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid json / occurred_at inválido / reglas de negocio",
+                        "description": "invalid json / birth_date / datos inválidos",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "401": {
                         "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
-                    "403": {
-                        "description": "forbidden",
+                    "402": {
+                        "description": "error.code: quota_exceeded (el plan no admite más mascotas)",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
-                    "404": {
-                        "description": "pet not found",
+                    "409": {
+                        "description": "error.code: microchip_taken (el microchip ya es de otra mascota)",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "503": {
+                        "description": "capabilities unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
         },
-        "/pets/{petID}/events/{eventID}/void": {
-            "post": {
-                "description": "Anula un evento existente de la mascota. El dueño siempre puede anular. Un delegado necesita un grant activo con scope ` + "`" + `events:void` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
-                "consumes": [
-                    "application/json"
-                ],
+        "/pets/lookup": {
+            "get": {
+                "description": "Devuelve la mascota con ese microchip si el usuario es su dueño o tiene un grant activo con scope ` + "`" + `pet:read` + "`" + `. En cualquier otro caso (no existe, sin acceso) responde 404, para no revelar qué microchips están registrados. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "pets"
                 ],
-                "summary": "Anular (void) un evento",
+                "summary": "Buscar mascota por microchip",
                 "parameters": [
                     {
                         "type": "string",
@@ -686,16 +966,9 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID de la mascota",
-                        "name": "petID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID del evento",
-                        "name": "eventID",
-                        "in": "path",
+                        "description": "Microchip a buscar (10-15 caracteres alfanuméricos)",
+                        "name": "microchip",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -703,49 +976,46 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/events.eventResponse"
+                            "$ref": "#/definitions/pets.petResponse"
                         }
                     },
-                    "401": {
-                        "description": "unauthorized",
+                    "400": {
+                        "description": "microchip inválido",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
-                    "403": {
-                        "description": "forbidden",
+                    "401": {
+                        "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "404": {
-                        "description": "event not found",
+                        "description": "pet not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
         },
-        "/pets/{petID}/grants": {
+        "/pets/microchip-available": {
             "get": {
-                "description": "Lista todos los grants asociados a una mascota. Solo el owner de la mascota puede verlos. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Indica si un microchip ya está registrado en alguna mascota, para evitar conflictos antes de crearla. Solo devuelve un booleano; nunca expone la mascota ni su owner. Cualquier usuario autenticado puede usarlo. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "accessgrants"
+                    "pets"
                 ],
-                "summary": "Listar grants por mascota",
+                "summary": "Verificar disponibilidad de un microchip",
                 "parameters": [
                     {
                         "type": "string",
@@ -761,9 +1031,9 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID de la mascota",
-                        "name": "petID",
-                        "in": "path",
+                        "description": "Microchip a verificar (10-15 caracteres alfanuméricos)",
+                        "name": "microchip",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -771,50 +1041,40 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/accessgrants.grantResponse"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "unauthorized",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/pets.microchipAvailableResponse"
                         }
                     },
-                    "403": {
-                        "description": "forbidden",
+                    "400": {
+                        "description": "microchip inválido",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
-                    "404": {
-                        "description": "pet not found",
+                    "401": {
+                        "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Crea una invitación (grant) para que otro usuario acceda a la mascota. Solo el owner de la mascota puede invitar. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/pets/{petID}": {
+            "get": {
+                "description": "Obtiene el perfil de una mascota. El dueño siempre tiene acceso (bypass). Un delegado necesita un grant activo con scope ` + "`" + `pet:read` + "`" + `. La respuesta trae un ` + "`" + `ETag` + "`" + ` débil; si el cliente lo reenvía en ` + "`" + `If-None-Match` + "`" + ` y la mascota no cambió responde 304 sin body. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "accessgrants"
+                    "pets"
                 ],
-                "summary": "Invitar delegado a una mascota",
+                "summary": "Obtener perfil de mascota",
                 "parameters": [
                     {
                         "type": "string",
@@ -830,310 +1090,3398 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "ID de la mascota compartida",
+                        "description": "ETag de una respuesta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
                         "name": "petID",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.petResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "ETag débil de la respuesta"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "not modified"
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Borra la mascota. Antes revoca todos sus grants (ningún delegado conserva acceso) y anula —sin borrar— todos sus eventos activos, para que el historial no quede huérfano. Solo el owner puede hacerlo. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pets"
+                ],
+                "summary": "Borrar una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
                     },
                     {
-                        "description": "Datos de la invitación (usuario delegado y scopes otorgados)",
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.deletePetResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden (no es owner)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Actualiza parcialmente el perfil de una mascota. El dueño siempre tiene acceso (bypass). Un delegado necesita un grant activo con scope ` + "`" + `pet:edit_profile` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod). Campo ` + "`" + `birth_date` + "`" + ` se maneja con semántica PATCH especial: si no se envía, no cambia; si se envía como ` + "`" + `null` + "`" + `, se limpia; si se envía como string ` + "`" + `YYYY-MM-DD` + "`" + `, se actualiza.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pets"
+                ],
+                "summary": "Actualizar perfil de mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a actualizar",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/accessgrants.inviteGrantRequest"
+                            "$ref": "#/definitions/pets.updatePetRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/accessgrants.grantResponse"
+                            "$ref": "#/definitions/pets.petResponse"
                         }
                     },
-                    "400": {
-                        "description": "invalid json / invalid input / grantee_user_id requerido",
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "error.code: microchip_taken (el microchip ya es de otra mascota)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/access": {
+            "get": {
+                "description": "Devuelve, para el usuario autenticado, si es dueño de la mascota, el estado de su grant (` + "`" + `active` + "`" + `, ` + "`" + `invited` + "`" + ` o ` + "`" + `none` + "`" + `) y sus scopes efectivos, en una sola llamada (pantalla de detalle). Sin acceso no responde 403 sino ` + "`" + `is_owner: false, grant_status: none` + "`" + `, para que la UI muestre \"pedir acceso\"; una invitación vencida cuenta como ` + "`" + `none` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pets"
+                ],
+                "summary": "Acceso del usuario a una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.petAccessResponse"
                         }
                     },
                     "401": {
                         "description": "unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/access-list": {
+            "get": {
+                "description": "Vista consolidada para el owner de todos los delegados de la mascota: grants activos, invitaciones pendientes y revocados (al final, rechazados y vencidos), en ese orden y el más reciente primero dentro de cada estado. A diferencia de ` + "`" + `GET /pets/{petID}/grants` + "`" + `, devuelve un resumen por grant (delegado, estado, scopes, alta y último uso). Solo el owner. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accessgrants"
+                ],
+                "summary": "Quién puede ver esta mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/accessgrants.accessListEntry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "403": {
                         "description": "forbidden",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "404": {
                         "description": "pet not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/httpjson.ErrorBody"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "accessgrants.Scope": {
-            "type": "string",
-            "enum": [
-                "pet:read",
-                "pet:edit_profile",
-                "events:read",
-                "events:create",
-                "events:void",
-                "attachments:add"
-            ],
-            "x-enum-varnames": [
-                "ScopePetRead",
-                "ScopePetEditProfile",
-                "ScopeEventsRead",
-                "ScopeEventsCreate",
-                "ScopeEventsVoid",
-                "ScopeAttachmentsAdd"
-            ]
         },
-        "accessgrants.Status": {
-            "type": "string",
-            "enum": [
+        "/pets/{petID}/access-log": {
+            "get": {
+                "description": "Devuelve el registro de lecturas (perfil y listado de eventos) hechas por delegados sobre la mascota, más recientes primero. Las lecturas del propio owner no se registran. Solo el owner puede consultarlo. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accesslog"
+                ],
+                "summary": "Listar lecturas de delegados sobre una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de entradas a devolver (1-200). Por defecto 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/accesslog.accessLogEntryResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/archive": {
+            "post": {
+                "description": "Marca la mascota como ` + "`" + `archived` + "`" + ` (p.ej. falleció o se dio en adopción): deja de aparecer en ` + "`" + `GET /pets` + "`" + ` (salvo ` + "`" + `include_archived=true` + "`" + `) pero conserva eventos y grants y sigue accesible por ID. Idempotente. Solo el owner puede hacerlo; una mascota fusionada en otra responde 409. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pets"
+                ],
+                "summary": "Archivar una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.petResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden (no es owner)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "pet archived (fusionada)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events": {
+            "get": {
+                "description": "Lista los eventos clínicos de una mascota. El dueño siempre puede verlos. Un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + ` y no ve los eventos con visibilidad ` + "`" + `private` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod). Permite filtrar por tipos, rango de fechas, texto, estado (por defecto solo ` + "`" + `active` + "`" + `) y actor. Paginado por cursor (orden occurred_at desc, id desc): si hay más eventos la respuesta trae ` + "`" + `has_more: true` + "`" + ` e incluye ` + "`" + `next_cursor` + "`" + `, que se pasa como ` + "`" + `cursor` + "`" + ` para pedir la página siguiente. ` + "`" + `count` + "`" + ` es la cantidad de eventos de la página y ` + "`" + `limit` + "`" + ` el límite efectivo. ` + "`" + `q` + "`" + ` busca por substring; con ` + "`" + `rank=true` + "`" + ` se ordena por relevancia (mejor coincidencia primero), sin paginar, y cada evento trae un ` + "`" + `snippet` + "`" + ` con los términos resaltados entre ` + "`" + `**` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Listar eventos de una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de eventos a devolver (1-200). Por defecto 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lista CSV de tipos de evento a incluir (ej: MEDICAL_VISIT,BATH)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha/hora mínima occurred_at (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha/hora máxima occurred_at (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Texto de búsqueda libre en título/notas",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Si es true ordena por relevancia respecto de q y agrega snippet (requiere q; no admite cursor ni order)",
+                        "name": "rank",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "voided",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Estado de los eventos (default: active; all incluye los anulados)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Si es true incluye los eventos anulados (equivale a status=all; status manda si viene)",
+                        "name": "include_voided",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Solo eventos registrados por este usuario",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Orden por occurred_at (default: desc, más reciente primero)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco de la página siguiente (next_cursor de la respuesta anterior, con el mismo order)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.eventListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros de filtro inválidos / cursor inválido / rank sin q o con cursor/order",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Crea un nuevo evento clínico para la mascota indicada. El dueño siempre puede crear eventos. Un delegado necesita un grant activo con scope ` + "`" + `events:create` + "`" + `. Si el token es de integración, el evento se registra como ` + "`" + `EXTERNAL_SYSTEM` + "`" + ` y se guardan ` + "`" + `origin_clinic_id` + "`" + ` / ` + "`" + `origin_system` + "`" + `. Un token de integración o de admin puede enviar ` + "`" + `recorded_at` + "`" + ` (no futuro) para preservar la fecha de carga original al importar; para usuarios normales esos campos se ignoran. Con ` + "`" + `Idempotency-Key` + "`" + `, un reintento del mismo usuario en la misma mascota devuelve el evento original (mismo 201) sin crear otro ni consumir cuota. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Crear evento de mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, simula un token de integración del sistema indicado",
+                        "name": "X-Debug-Integration-System",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opcional (máx. 255): un reintento con la misma key devuelve el evento ya creado (201) en lugar de duplicarlo",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Datos del evento; occurred_at en formato RFC3339. ` + "`" + `preventive` + "`" + ` solo aplica a DEWORMING / FLEA_TREATMENT; ` + "`" + `measurement` + "`" + ` (unit kg|lb) es obligatorio y exclusivo de WEIGHT_RECORDED; ` + "`" + `medication` + "`" + ` (name y start_date obligatorios) solo aplica a MEDICATION_PRESCRIBED; ` + "`" + `vaccine` + "`" + ` (name, lot, next_due) solo aplica a VACCINE",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/events.createEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/events.eventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid json / occurred_at inválido / campo obligatorio faltante (p.ej. VACCINE requires field \\\"title\\\")",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "402": {
+                        "description": "error.code: quota_exceeded (la mascota alcanzó el máximo de eventos)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events/batch": {
+            "post": {
+                "description": "Crea hasta 100 eventos de una mascota en un solo request (p.ej. el historial de una clínica). Cada item tiene el mismo formato que ` + "`" + `POST /pets/{petID}/events` + "`" + `. Con ` + "`" + `mode=atomic` + "`" + ` (default) es todo o nada: si algún item es inválido no se crea ninguno y la respuesta (400) indica los errores por ` + "`" + `index` + "`" + `; con postgres se inserta en una transacción. Con ` + "`" + `mode=best_effort` + "`" + ` se crean los válidos y se informan los que fallaron (200). El dueño siempre puede importar; un delegado necesita un grant activo con scope ` + "`" + `events:create` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Importar eventos en lote",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "atomic",
+                            "best_effort"
+                        ],
+                        "type": "string",
+                        "description": "Qué hacer si falla algún item (default: atomic)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Eventos a crear (1-100)",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/events.batchCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "best_effort: resultado por item",
+                        "schema": {
+                            "$ref": "#/definitions/events.batchCreateResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "atomic: todos creados",
+                        "schema": {
+                            "$ref": "#/definitions/events.batchCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "atomic: lote rechazado (errores por index); o invalid json / mode / cantidad de eventos (texto)",
+                        "schema": {
+                            "$ref": "#/definitions/events.batchCreateResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "402": {
+                        "description": "error.code: quota_exceeded (el lote no entra en el máximo de eventos)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events/export": {
+            "get": {
+                "description": "Descarga el timeline de la mascota como CSV (columnas id, type, occurred_at, title, notes, actor, status), con los mismos filtros y permisos que el listado: el dueño siempre puede exportar; un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + ` y no recibe los eventos ` + "`" + `private` + "`" + `. Se exportan todos los eventos que cumplen el filtro (` + "`" + `limit` + "`" + `, ` + "`" + `cursor` + "`" + ` y ` + "`" + `rank` + "`" + ` no aplican). La respuesta se envía en streaming (chunked, sin Content-Length). ` + "`" + `actor` + "`" + ` es el email de quien registró el evento, o su ID si no se conoce. Las celdas que empiezan con ` + "`" + `=` + "`" + `, ` + "`" + `+` + "`" + `, ` + "`" + `-` + "`" + ` o ` + "`" + `@` + "`" + ` se prefijan con ` + "`" + `'` + "`" + ` para que una planilla no las evalúe como fórmulas. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Exportar los eventos de una mascota (CSV)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Formato del export (default: csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lista CSV de tipos de evento a incluir (ej: MEDICAL_VISIT,BATH)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha/hora mínima occurred_at (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha/hora máxima occurred_at (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Texto de búsqueda libre en título/notas",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "voided",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "Estado de los eventos (default: active; all incluye los anulados)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Solo eventos registrados por este usuario",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Orden por occurred_at (default: desc, más reciente primero)",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV con header",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "format no soportado / parámetros de filtro inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events/summary": {
+            "get": {
+                "description": "Devuelve la cantidad de eventos activos por tipo, el total y la fecha (` + "`" + `occurred_at` + "`" + `) del más reciente, sin descargar el timeline. Los eventos anulados no cuentan. El dueño siempre puede verlo. Un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + ` y no cuenta los eventos con visibilidad ` + "`" + `private` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Resumen de eventos de una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.eventsSummaryResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events/used-types": {
+            "get": {
+                "description": "Devuelve solo los tipos de evento que la mascota tiene registrados, con la cantidad de cada uno, ordenados por tipo. Pensado para armar filtros del timeline. Los eventos anulados no cuentan. El dueño siempre puede verlos. Un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + ` y no cuenta los eventos con visibilidad ` + "`" + `private` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Tipos de evento usados por una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/events.usedTypeResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events/{eventID}": {
+            "get": {
+                "description": "Devuelve un evento de la mascota con todos sus detalles. El dueño siempre puede verlo. Un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + ` y no puede ver eventos con visibilidad ` + "`" + `private` + "`" + ` (responde 404). Si el evento pertenece a otra mascota responde 404. La respuesta trae un ` + "`" + `ETag` + "`" + ` débil; si el cliente lo reenvía en ` + "`" + `If-None-Match` + "`" + ` y el evento no cambió (p.ej. no se anuló) responde 304 sin body. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Obtener un evento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de una respuesta anterior",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del evento",
+                        "name": "eventID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.eventResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "ETag débil de la respuesta"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "not modified"
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found / event not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events/{eventID}/attachments": {
+            "get": {
+                "description": "Devuelve los adjuntos (metadata y URL) de un evento de la mascota, en orden de creación. El dueño siempre puede verlos; un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + ` y no ve los de eventos privados. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Listar adjuntos de un evento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del evento",
+                        "name": "eventID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/events.attachmentResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | grant_expired | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found / event not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registra un adjunto (referencia a un archivo ya subido a storage externo; solo metadata y URL) en un evento activo de la mascota y agrega un evento ` + "`" + `ATTACHMENT_ADDED` + "`" + ` a su historia. El dueño siempre puede adjuntar; un delegado necesita un grant activo con scope ` + "`" + `attachments:add` + "`" + `. Además, el plan del dueño de la mascota debe incluir la capability ` + "`" + `pet:attachments:add` + "`" + ` (plans-features); sin resolver configurado se permite (modo dev). Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Adjuntar archivo a un evento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del evento",
+                        "name": "eventID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Datos del adjunto",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/events.addAttachmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/events.attachmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid json / file_name o url inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "402": {
+                        "description": "error.code: capability_missing (el plan no incluye pet:attachments:add)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | grant_expired | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found / event not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "event is voided",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "503": {
+                        "description": "capabilities unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events/{eventID}/restore": {
+            "post": {
+                "description": "Deshace un void: el evento vuelve a ` + "`" + `active` + "`" + `. El dueño siempre puede restaurar. Un delegado necesita un grant activo con scope ` + "`" + `events:void` + "`" + `. Si el evento ya estaba activo responde 409. Cuenta para la cuota de eventos activos (402 si se alcanzó). Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Restaurar un evento anulado",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del evento",
+                        "name": "eventID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.eventResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "402": {
+                        "description": "quota_exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "event not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "event not voided",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/events/{eventID}/void": {
+            "post": {
+                "description": "Anula un evento existente de la mascota. El dueño siempre puede anular. Un delegado necesita un grant activo con scope ` + "`" + `events:void` + "`" + `. Si el evento ya estaba anulado responde 409, salvo con ` + "`" + `idempotent=true` + "`" + ` (conserva quién y por qué se anuló la primera vez). Queda registrado quién lo anuló (` + "`" + `voided_by` + "`" + ` y, si el token lo trae, ` + "`" + `voided_by_email` + "`" + `), cuándo (` + "`" + `voided_at` + "`" + `) y el ` + "`" + `reason` + "`" + ` opcional (máx. 500 caracteres) como ` + "`" + `void_reason` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Anular (void) un evento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del evento",
+                        "name": "eventID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Si es true, anular un evento ya anulado responde 200 en vez de 409",
+                        "name": "idempotent",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Motivo opcional del void",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/events.voidEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.eventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid json / reason demasiado largo",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "event not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "event already voided",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/export.json": {
+            "get": {
+                "description": "Descarga en un único documento el perfil, todos los eventos (incluidos los anulados) y el historial de grants de la mascota, para portabilidad de datos. El dueño siempre puede exportar. Un delegado necesita un grant activo con scope ` + "`" + `pet:export` + "`" + `; en ese caso no se incluyen el historial de grants ni los eventos privados. La respuesta se envía en streaming (chunked, sin Content-Length). Si la mascota supera el tope de eventos del export, se responde 413 salvo que el cliente confirme con ` + "`" + `confirm_full=true` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Exportar el historial completo de una mascota (JSON)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Confirma el export completo aunque supere el tope de eventos",
+                        "name": "confirm_full",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.petExportBundle"
+                        }
+                    },
+                    "400": {
+                        "description": "confirm_full inválido",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "413": {
+                        "description": "error.code: export_too_large (reintentar con confirm_full=true)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/grants": {
+            "get": {
+                "description": "Lista todos los grants asociados a una mascota. Solo el owner de la mascota puede verlos. Cada grant trae ` + "`" + `last_used_at` + "`" + ` (último uso del delegado; ausente si nunca lo usó). Con ` + "`" + `stale_days=N` + "`" + ` devuelve solo los grants activos sin uso en los últimos N días (o nunca usados), para limpiar delegados que no usan su acceso. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accessgrants"
+                ],
+                "summary": "Listar grants por mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Solo grants activos sin uso en los últimos N días (N \u003e 0)",
+                        "name": "stale_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/accessgrants.grantResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "stale_days inválido",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Crea una invitación (grant) para que otro usuario acceda a la mascota. El owner siempre puede invitar. Un delegado con grant activo y scope ` + "`" + `grants:delegate` + "`" + ` puede sub-delegar, solo con un subconjunto de sus propios scopes; el owner puede revocar todo el árbol. Re-invitar a un grantee con grant vigente actualiza sus scopes, pero para cambiarlos se recomienda ` + "`" + `PATCH /grants/{grantID}` + "`" + `. El delegado se indica por ` + "`" + `grantee_user_id` + "`" + ` o por ` + "`" + `grantee_email` + "`" + ` (se resuelve contra el IAM). Cada scope otorgado debe estar habilitado en el plan del owner (capability ` + "`" + `pet:grants:\u003cscope\u003e` + "`" + `); sin resolver configurado no se valida (modo dev). Una mascota admite hasta 20 grants vigentes (invitados o activos; configurable): pasado el máximo una invitación nueva responde 409, pero re-invitar a un grantee existente sigue funcionando. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accessgrants"
+                ],
+                "summary": "Invitar delegado a una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota compartida",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Datos de la invitación (usuario delegado y scopes otorgados)",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accessgrants.inviteGrantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/accessgrants.grantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid json / invalid input / grantee_user_id o grantee_email requerido",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "402": {
+                        "description": "error.code: capability_missing (el plan del owner no permite otorgar alguno de los scopes; el mensaje los lista)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden / scopes exceed delegator's grant",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found / grantee not found (email sin usuario)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "too many grants for pet (la mascota ya tiene el máximo de grants vigentes)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "501": {
+                        "description": "grantee_email not supported (sin resolver configurado)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "503": {
+                        "description": "capabilities unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/grants/revoke-all": {
+            "post": {
+                "description": "Revoca de una vez todos los grants vigentes (invited / active) de la mascota, incluidas las sub-delegaciones. Solo el owner. Es idempotente: una segunda llamada devuelve ` + "`" + `grants_revoked: 0` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accessgrants"
+                ],
+                "summary": "Revocar todos los grants de una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accessgrants.revokeAllGrantsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/medications/active": {
+            "get": {
+                "description": "Devuelve las medicaciones de eventos ` + "`" + `MEDICATION_PRESCRIBED` + "`" + ` activos cuya ` + "`" + `end_date` + "`" + ` es nula o futura, ordenadas por ` + "`" + `start_date` + "`" + ` desc (vista de \"medicación actual\" sin recorrer el timeline). El dueño siempre puede verlas. Un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + ` y no ve las de eventos con visibilidad ` + "`" + `private` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Listar medicaciones vigentes de una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/events.activeMedicationResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/merge": {
+            "post": {
+                "description": "Fusiona ` + "`" + `source_pet_id` + "`" + ` en la mascota del path: mueve todos sus eventos y sus grants vigentes (activos e invitaciones pendientes), archiva el origen (deja de aparecer en listados) y registra un evento ` + "`" + `PROFILE_UPDATED` + "`" + ` en el destino. Si un delegado tenía grant en ambas queda uno solo (el activo antes que la invitación; a igual estado, el del destino) y el otro se revoca con sus sub-delegaciones (` + "`" + `grants_revoked` + "`" + `). Solo el owner de ambas mascotas puede hacerlo. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pets"
+                ],
+                "summary": "Fusionar una mascota duplicada",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota destino",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Mascota duplicada a fusionar",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pets.mergePetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.mergePetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid json / source_pet_id requerido / merge consigo misma",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden (no es owner de ambas)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "pet archived",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/reminders": {
+            "get": {
+                "description": "Devuelve los vencimientos pendientes de la mascota (refuerzos de vacunas y tratamientos preventivos con ` + "`" + `next_due` + "`" + `) dentro de la ventana ` + "`" + `within` + "`" + `, ordenados por fecha ascendente. Solo considera eventos activos. El dueño siempre puede verlos. Un delegado necesita un grant activo con scope ` + "`" + `events:read` + "`" + ` y no ve los de eventos con visibilidad ` + "`" + `private` + "`" + `. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Recordatorios de una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ventana hacia adelante: días (` + "`" + `30d` + "`" + `) o duración Go (` + "`" + `72h` + "`" + `). Por defecto 30d, máximo 365d",
+                        "name": "within",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/events.reminderResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "within inválido",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden; error.reason: no_grant | grant_not_active | missing_scope:\u003cscope\u003e",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/transfer": {
+            "post": {
+                "description": "Entrega la mascota de forma permanente a ` + "`" + `new_owner_user_id` + "`" + `: cambia el owner, revoca los grants donde el nuevo owner era delegado (y sus sub-delegaciones), reasigna los grants vigentes restantes y registra un evento ` + "`" + `PROFILE_UPDATED` + "`" + `. El owner anterior pierde el acceso. Solo el owner actual puede hacerlo. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pets"
+                ],
+                "summary": "Transferir una mascota a otro usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nuevo owner",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pets.transferPetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.transferPetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid json / new_owner_user_id requerido / igual al owner actual",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden (no es owner)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "pet archived",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/pets/{petID}/unarchive": {
+            "post": {
+                "description": "Vuelve la mascota a ` + "`" + `active` + "`" + `: reaparece en ` + "`" + `GET /pets` + "`" + `. Idempotente. Solo el owner puede hacerlo; una mascota fusionada en otra responde 409. Autenticación: ` + "`" + `X-Debug-User-ID` + "`" + ` (dev) o ` + "`" + `Authorization: Bearer \u003ctoken\u003e` + "`" + ` (prod).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pets"
+                ],
+                "summary": "Desarchivar una mascota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Solo en modo dev, ID de usuario para depuración",
+                        "name": "X-Debug-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token en producción",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la mascota",
+                        "name": "petID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.petResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden (no es owner)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "404": {
+                        "description": "pet not found",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "409": {
+                        "description": "pet archived (fusionada)",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/httpjson.ErrorBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/vocab/sex": {
+            "get": {
+                "description": "Devuelve los valores de ` + "`" + `sex` + "`" + ` que acepta la API con su etiqueta en el idioma pedido por ` + "`" + `Accept-Language` + "`" + ` (es, en; default es). No requiere usuario.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocab"
+                ],
+                "summary": "Vocabulario de sexos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idioma de las etiquetas (p.ej. en-US,es;q=0.8)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.vocabResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vocab/species": {
+            "get": {
+                "description": "Devuelve las especies que acepta la API (` + "`" + `species` + "`" + ` al crear/editar una mascota) con su etiqueta en el idioma pedido por ` + "`" + `Accept-Language` + "`" + ` (es, en; default es). No requiere usuario.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vocab"
+                ],
+                "summary": "Vocabulario de especies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idioma de las etiquetas (p.ej. en-US,es;q=0.8)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pets.vocabResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "accessgrants.Scope": {
+            "type": "string",
+            "enum": [
+                "pet:read",
+                "pet:edit_profile",
+                "events:read",
+                "events:create",
+                "events:void",
+                "attachments:add",
+                "pet:export",
+                "grants:delegate"
+            ],
+            "x-enum-varnames": [
+                "ScopePetRead",
+                "ScopePetEditProfile",
+                "ScopeEventsRead",
+                "ScopeEventsCreate",
+                "ScopeEventsVoid",
+                "ScopeAttachmentsAdd",
+                "ScopePetExport",
+                "ScopeGrantsDelegate"
+            ]
+        },
+        "accessgrants.Status": {
+            "type": "string",
+            "enum": [
                 "invited",
                 "active",
-                "revoked"
+                "revoked",
+                "declined",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "StatusInvited",
+                "StatusActive",
+                "StatusRevoked",
+                "StatusDeclined",
+                "StatusExpired"
+            ]
+        },
+        "accessgrants.accessListEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "grant_id": {
+                    "type": "string"
+                },
+                "grantee_user_id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "description": "nil =\u003e nunca usado",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/accessgrants.Status"
+                }
+            }
+        },
+        "accessgrants.grantListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "grants en esta página",
+                    "type": "integer"
+                },
+                "has_more": {
+                    "description": "true =\u003e hay next_cursor",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.grantResponse"
+                    }
+                },
+                "limit": {
+                    "description": "límite efectivo aplicado",
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "accessgrants.grantResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "delegated_by_user_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "grantee_user_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invite_expires_at": {
+                    "description": "Plazo para aceptar la invitación (omitido si no tiene).",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "last_used_at": {
+                    "description": "nil =\u003e nunca usado",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "owner_user_id": {
+                    "type": "string"
+                },
+                "parent_grant_id": {
+                    "type": "string"
+                },
+                "pet_id": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/accessgrants.Status"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "accessgrants.inviteGrantRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "RFC3339 opcional; debe ser futuro",
+                    "type": "string"
+                },
+                "grantee_email": {
+                    "description": "se resuelve a user_id; si vienen ambos gana grantee_user_id",
+                    "type": "string"
+                },
+                "grantee_user_id": {
+                    "description": "uno de grantee_user_id / grantee_email",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "opcional: motivo para el delegado",
+                    "type": "string",
+                    "maxLength": 280
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                }
+            }
+        },
+        "accessgrants.revokeAllGrantsResponse": {
+            "type": "object",
+            "properties": {
+                "grants_revoked": {
+                    "type": "integer"
+                },
+                "pet_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "accessgrants.updateGrantScopesRequest": {
+            "type": "object",
+            "properties": {
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                }
+            }
+        },
+        "accessgrants.validateScopesRequest": {
+            "type": "object",
+            "properties": {
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                }
+            }
+        },
+        "accessgrants.validateScopesResponse": {
+            "type": "object",
+            "properties": {
+                "invalid": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                },
+                "normalized": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "accesslog.Resource": {
+            "type": "string",
+            "enum": [
+                "pet_profile",
+                "events_list",
+                "event_detail",
+                "events_export"
+            ],
+            "x-enum-varnames": [
+                "ResourcePetProfile",
+                "ResourceEventsList",
+                "ResourceEventDetail",
+                "ResourceEventsExport"
+            ]
+        },
+        "accesslog.accessLogEntryResponse": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "grantee_user_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pet_id": {
+                    "type": "string"
+                },
+                "resource": {
+                    "enum": [
+                        "pet_profile",
+                        "events_list",
+                        "event_detail",
+                        "events_export"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/accesslog.Resource"
+                        }
+                    ]
+                }
+            }
+        },
+        "details.MeasurementKind": {
+            "type": "string",
+            "enum": [
+                "weight"
+            ],
+            "x-enum-varnames": [
+                "MeasurementKindWeight"
+            ]
+        },
+        "details.PreventiveKind": {
+            "type": "string",
+            "enum": [
+                "deworming",
+                "flea_treatment"
+            ],
+            "x-enum-varnames": [
+                "PreventiveKindDeworming",
+                "PreventiveKindFleaTreatment"
+            ]
+        },
+        "events.ActorType": {
+            "type": "string",
+            "enum": [
+                "OWNER_USER",
+                "DELEGATE_USER",
+                "EXTERNAL_SYSTEM"
+            ],
+            "x-enum-varnames": [
+                "ActorTypeOwnerUser",
+                "ActorTypeDelegateUser",
+                "ActorTypeExternalSystem"
+            ]
+        },
+        "events.BatchMode": {
+            "type": "string",
+            "enum": [
+                "atomic",
+                "best_effort"
+            ],
+            "x-enum-varnames": [
+                "BatchModeAtomic",
+                "BatchModeBestEffort"
+            ]
+        },
+        "events.EventStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "voided"
             ],
             "x-enum-varnames": [
-                "StatusInvited",
-                "StatusActive",
-                "StatusRevoked"
+                "EventStatusActive",
+                "EventStatusVoided"
+            ]
+        },
+        "events.EventType": {
+            "type": "string",
+            "enum": [
+                "PROFILE_UPDATED",
+                "WEIGHT_RECORDED",
+                "NOTE",
+                "MEDICAL_VISIT",
+                "MEDICATION_PRESCRIBED",
+                "VACCINE",
+                "DEWORMING",
+                "FLEA_TREATMENT",
+                "BATH",
+                "ATTACHMENT_ADDED"
+            ],
+            "x-enum-varnames": [
+                "EventTypeProfileUpdated",
+                "EventTypeWeightRecorded",
+                "EventTypeNote",
+                "EventTypeMedicalVisit",
+                "EventTypeMedicationPresc",
+                "EventTypeVaccine",
+                "EventTypeDeworming",
+                "EventTypeFleaTreatment",
+                "EventTypeBath",
+                "EventTypeAttachmentAdded"
+            ]
+        },
+        "events.Source": {
+            "type": "string",
+            "enum": [
+                "manual",
+                "smartpet",
+                "integration"
+            ],
+            "x-enum-varnames": [
+                "SourceManual",
+                "SourceSmartPet",
+                "SourceIntegration"
+            ]
+        },
+        "events.Visibility": {
+            "type": "string",
+            "enum": [
+                "private",
+                "shared_with_delegates"
+            ],
+            "x-enum-varnames": [
+                "VisibilityPrivate",
+                "VisibilityShared"
             ]
         },
-        "accessgrants.grantResponse": {
+        "events.activeMedicationResponse": {
+            "type": "object",
+            "properties": {
+                "dosage": {
+                    "type": "string"
+                },
+                "dose_unit": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "events.addAttachmentRequest": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "url": {
+                    "description": "http(s)",
+                    "type": "string"
+                }
+            }
+        },
+        "events.attachmentResponse": {
+            "type": "object",
+            "properties": {
+                "added_by": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pet_id": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.batchCreateRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.createEventRequest"
+                    }
+                }
+            }
+        },
+        "events.batchCreateResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "$ref": "#/definitions/events.BatchMode"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.batchItemResponse"
+                    }
+                }
+            }
+        },
+        "events.batchItemResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/events.eventResponse"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "skipped: válido pero no creado (lote atomic rechazado)",
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "failed",
+                        "skipped"
+                    ]
+                }
+            }
+        },
+        "events.createEventRequest": {
+            "type": "object",
+            "properties": {
+                "measurement": {
+                    "description": "Solo para WEIGHT_RECORDED (obligatorio en ese tipo)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/events.measurementRequest"
+                        }
+                    ]
+                },
+                "medication": {
+                    "description": "Solo para MEDICATION_PRESCRIBED (opcional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/events.medicationRequest"
+                        }
+                    ]
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "origin_clinic_id": {
+                    "description": "Solo para integraciones (token de integración); se ignoran para usuarios.",
+                    "type": "string"
+                },
+                "origin_system": {
+                    "description": "default: sistema del token",
+                    "type": "string"
+                },
+                "preventive": {
+                    "description": "Solo para DEWORMING / FLEA_TREATMENT (opcional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/events.preventiveRequest"
+                        }
+                    ]
+                },
+                "recorded_at": {
+                    "description": "RFC3339, importaciones (integración / admin): fecha de carga original (no futura)",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "opcional",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/events.Source"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "NOTE",
+                        "MEDICAL_VISIT",
+                        "VACCINE",
+                        "DEWORMING",
+                        "BATH",
+                        "PROFILE_UPDATED",
+                        "WEIGHT_RECORDED",
+                        "MEDICATION_PRESCRIBED",
+                        "FLEA_TREATMENT",
+                        "ATTACHMENT_ADDED"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/events.EventType"
+                        }
+                    ]
+                },
+                "vaccine": {
+                    "description": "Solo para VACCINE (opcional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/events.vaccineRequest"
+                        }
+                    ]
+                },
+                "visibility": {
+                    "description": "opcional",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/events.Visibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "events.eventListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "eventos en esta página",
+                    "type": "integer"
+                },
+                "has_more": {
+                    "description": "true =\u003e hay next_cursor",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.eventResponse"
+                    }
+                },
+                "limit": {
+                    "description": "límite efectivo aplicado",
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.eventResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "actor_email": {
                     "type": "string"
                 },
-                "grantee_user_id": {
+                "actor_id": {
                     "type": "string"
                 },
+                "actor_type": {
+                    "$ref": "#/definitions/events.ActorType"
+                },
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.attachmentResponse"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
-                "owner_user_id": {
+                "measurement": {
+                    "$ref": "#/definitions/events.measurementResponse"
+                },
+                "medication": {
+                    "$ref": "#/definitions/events.medicationResponse"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "origin_clinic_id": {
+                    "type": "string"
+                },
+                "origin_system": {
                     "type": "string"
                 },
                 "pet_id": {
                     "type": "string"
                 },
-                "revoked_at": {
+                "preventive": {
+                    "$ref": "#/definitions/events.preventiveResponse"
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "snippet": {
+                    "description": "Solo con rank=true: fragmento de title + notes con los términos entre ** (estilo markdown).",
                     "type": "string"
                 },
-                "scopes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/accessgrants.Scope"
-                    }
+                "source": {
+                    "$ref": "#/definitions/events.Source"
                 },
                 "status": {
-                    "$ref": "#/definitions/accessgrants.Status"
+                    "$ref": "#/definitions/events.EventStatus"
                 },
-                "updated_at": {
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/events.EventType"
+                },
+                "vaccine": {
+                    "$ref": "#/definitions/events.vaccineResponse"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/events.Visibility"
+                },
+                "void_reason": {
+                    "type": "string"
+                },
+                "voided_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "voided_by": {
+                    "description": "Solo presentes si el evento está anulado.",
+                    "type": "string"
+                },
+                "voided_by_email": {
                     "type": "string"
                 }
             }
         },
-        "accessgrants.inviteGrantRequest": {
+        "events.eventsSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "by_type": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "last_occurred_at": {
+                    "description": "null si no hay eventos",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "events.grantExport": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "grantee_user_id": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "scopes": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/accessgrants.Scope"
                     }
+                },
+                "status": {
+                    "$ref": "#/definitions/accessgrants.Status"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
                 }
             }
         },
-        "events.ActorType": {
-            "type": "string",
-            "enum": [
-                "OWNER_USER",
-                "DELEGATE_USER",
-                "EXTERNAL_SYSTEM"
-            ],
-            "x-enum-varnames": [
-                "ActorTypeOwnerUser",
-                "ActorTypeDelegateUser",
-                "ActorTypeExternalSystem"
-            ]
+        "events.measurementRequest": {
+            "type": "object",
+            "properties": {
+                "unit": {
+                    "type": "string",
+                    "enum": [
+                        "kg",
+                        "lb"
+                    ]
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
         },
-        "events.EventStatus": {
-            "type": "string",
-            "enum": [
-                "active",
-                "voided"
-            ],
-            "x-enum-varnames": [
-                "EventStatusActive",
-                "EventStatusVoided"
-            ]
+        "events.measurementResponse": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "$ref": "#/definitions/details.MeasurementKind"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
         },
-        "events.EventType": {
-            "type": "string",
-            "enum": [
-                "PROFILE_UPDATED",
-                "WEIGHT_RECORDED",
-                "NOTE",
-                "MEDICAL_VISIT",
-                "MEDICATION_PRESCRIBED",
-                "VACCINE",
-                "DEWORMING",
-                "FLEA_TREATMENT",
-                "BATH",
-                "ATTACHMENT_ADDED"
-            ],
-            "x-enum-varnames": [
-                "EventTypeProfileUpdated",
-                "EventTypeWeightRecorded",
-                "EventTypeNote",
-                "EventTypeMedicalVisit",
-                "EventTypeMedicationPresc",
-                "EventTypeVaccine",
-                "EventTypeDeworming",
-                "EventTypeFleaTreatment",
-                "EventTypeBath",
-                "EventTypeAttachmentAdded"
-            ]
+        "events.medicationRequest": {
+            "type": "object",
+            "properties": {
+                "dosage": {
+                    "description": "\"2\"",
+                    "type": "string"
+                },
+                "dose_unit": {
+                    "description": "\"ml\", \"mg\", etc.",
+                    "type": "string"
+                },
+                "end_date": {
+                    "description": "YYYY-MM-DD o RFC3339, opcional (sin fin =\u003e tratamiento continuo)",
+                    "type": "string"
+                },
+                "frequency": {
+                    "description": "\"cada 12h\"",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "description": "YYYY-MM-DD o RFC3339",
+                    "type": "string"
+                }
+            }
         },
-        "events.Source": {
-            "type": "string",
-            "enum": [
-                "manual",
-                "smartpet",
-                "integration"
-            ],
-            "x-enum-varnames": [
-                "SourceManual",
-                "SourceSmartPet",
-                "SourceIntegration"
-            ]
+        "events.medicationResponse": {
+            "type": "object",
+            "properties": {
+                "dosage": {
+                    "type": "string"
+                },
+                "dose_unit": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
         },
-        "events.Visibility": {
-            "type": "string",
-            "enum": [
-                "private",
-                "shared_with_delegates"
-            ],
-            "x-enum-varnames": [
-                "VisibilityPrivate",
-                "VisibilityShared"
-            ]
+        "events.petExport": {
+            "type": "object",
+            "properties": {
+                "birth_date": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "breed": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "microchip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "owner_user_id": {
+                    "type": "string"
+                },
+                "sex": {
+                    "$ref": "#/definitions/pets.Sex"
+                },
+                "species": {
+                    "$ref": "#/definitions/pets.Species"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "events.petExportBundle": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.eventResponse"
+                    }
+                },
+                "exported_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "grants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.grantExport"
+                    }
+                },
+                "pet": {
+                    "$ref": "#/definitions/events.petExport"
+                }
+            }
+        },
+        "events.preventiveRequest": {
+            "type": "object",
+            "properties": {
+                "dose": {
+                    "type": "string"
+                },
+                "next_due": {
+                    "description": "YYYY-MM-DD o RFC3339, opcional",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "product": {
+                    "type": "string"
+                }
+            }
         },
-        "events.createEventRequest": {
+        "events.preventiveResponse": {
             "type": "object",
             "properties": {
-                "notes": {
+                "dose": {
                     "type": "string"
                 },
-                "occurred_at": {
-                    "description": "RFC3339",
-                    "type": "string"
+                "kind": {
+                    "$ref": "#/definitions/details.PreventiveKind"
                 },
-                "source": {
-                    "description": "opcional",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/events.Source"
-                        }
-                    ]
+                "next_due": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "title": {
+                "notes": {
                     "type": "string"
                 },
-                "type": {
-                    "enum": [
-                        "NOTE",
-                        "MEDICAL_VISIT",
-                        "VACCINE",
-                        "DEWORMING",
-                        "BATH",
-                        "PROFILE_UPDATED",
-                        "WEIGHT_RECORDED",
-                        "MEDICATION_PRESCRIBED",
-                        "FLEA_TREATMENT",
-                        "ATTACHMENT_ADDED"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/events.EventType"
-                        }
-                    ]
-                },
-                "visibility": {
-                    "description": "opcional",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/events.Visibility"
-                        }
-                    ]
+                "product": {
+                    "type": "string"
                 }
             }
         },
-        "events.eventResponse": {
+        "events.reminderResponse": {
             "type": "object",
             "properties": {
-                "actor_id": {
+                "due_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "event_id": {
                     "type": "string"
                 },
-                "actor_type": {
-                    "$ref": "#/definitions/events.ActorType"
+                "event_type": {
+                    "$ref": "#/definitions/events.EventType"
                 },
-                "id": {
+                "kind": {
                     "type": "string"
                 },
-                "notes": {
+                "label": {
                     "type": "string"
                 },
-                "occurred_at": {
+                "pet_id": {
                     "type": "string"
                 },
-                "pet_id": {
+                "pet_name": {
                     "type": "string"
+                }
+            }
+        },
+        "events.usedTypeResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
                 },
-                "recorded_at": {
+                "type": {
+                    "$ref": "#/definitions/events.EventType"
+                }
+            }
+        },
+        "events.vaccineRequest": {
+            "type": "object",
+            "properties": {
+                "lot": {
                     "type": "string"
                 },
-                "source": {
-                    "$ref": "#/definitions/events.Source"
+                "name": {
+                    "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/events.EventStatus"
+                "next_due": {
+                    "description": "YYYY-MM-DD o RFC3339, opcional (próximo refuerzo)",
+                    "type": "string"
+                }
+            }
+        },
+        "events.vaccineResponse": {
+            "type": "object",
+            "properties": {
+                "lot": {
+                    "type": "string"
                 },
-                "title": {
+                "name": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/events.EventType"
+                "next_due": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "events.voidEventRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpjson.ErrorBody": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/httpjson.ErrorDetail"
+                }
+            }
+        },
+        "httpjson.ErrorDetail": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
                 },
-                "visibility": {
-                    "$ref": "#/definitions/events.Visibility"
+                "message": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
                 }
             }
         },
+        "pets.PetStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "archived"
+            ],
+            "x-enum-varnames": [
+                "PetStatusActive",
+                "PetStatusArchived"
+            ]
+        },
+        "pets.Relationship": {
+            "type": "string",
+            "enum": [
+                "owner",
+                "delegate"
+            ],
+            "x-enum-varnames": [
+                "RelationshipOwner",
+                "RelationshipDelegate"
+            ]
+        },
         "pets.Sex": {
             "type": "string",
             "enum": [
@@ -1169,6 +4517,10 @@ const docTemplate = `{
                     "description": "Ej para dog: labrador, poodle. Ej para cat: persian, common.",
                     "type": "string"
                 },
+                "microchip": {
+                    "description": "opcional, 10-15 caracteres alfanuméricos",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1201,6 +4553,20 @@ const docTemplate = `{
                 }
             }
         },
+        "pets.deletePetResponse": {
+            "type": "object",
+            "properties": {
+                "events_voided": {
+                    "type": "integer"
+                },
+                "grants_revoked": {
+                    "type": "integer"
+                },
+                "pet_id": {
+                    "type": "string"
+                }
+            }
+        },
         "pets.grantMini": {
             "type": "object",
             "properties": {
@@ -1212,21 +4578,136 @@ const docTemplate = `{
                 }
             }
         },
+        "pets.mergePetRequest": {
+            "type": "object",
+            "properties": {
+                "source_pet_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "pets.mergePetResponse": {
+            "type": "object",
+            "properties": {
+                "events_moved": {
+                    "type": "integer"
+                },
+                "grants_moved": {
+                    "type": "integer"
+                },
+                "grants_revoked": {
+                    "description": "duplicados del mismo delegado (y sus sub-delegaciones)",
+                    "type": "integer"
+                },
+                "pet": {
+                    "$ref": "#/definitions/pets.petResponse"
+                },
+                "source_pet_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "pets.microchipAvailableResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "pets.myPetResponse": {
+            "type": "object",
+            "properties": {
+                "grant": {
+                    "$ref": "#/definitions/pets.grantMini"
+                },
+                "pet": {
+                    "$ref": "#/definitions/pets.petResponse"
+                },
+                "relationship": {
+                    "enum": [
+                        "owner",
+                        "delegate"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pets.Relationship"
+                        }
+                    ]
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                }
+            }
+        },
+        "pets.petAccessResponse": {
+            "type": "object",
+            "properties": {
+                "grant_id": {
+                    "description": "GrantID del grant activo o de la invitación pendiente (p.ej. para aceptarla).",
+                    "type": "string"
+                },
+                "grant_status": {
+                    "description": "active | invited | none (el owner siempre es none: no necesita grant)",
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "invited",
+                        "none"
+                    ]
+                },
+                "is_owner": {
+                    "type": "boolean"
+                },
+                "scopes": {
+                    "description": "Scopes efectivos: todos para el owner, los del grant activo para un delegado, vacío si no.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accessgrants.Scope"
+                    }
+                }
+            }
+        },
         "pets.petResponse": {
             "type": "object",
             "properties": {
-                "birth_date": {
+                "age_human": {
+                    "description": "p.ej. \"2y 3m\"",
                     "type": "string"
                 },
+                "age_months": {
+                    "description": "Edad calculada desde birth_date con el reloj del servidor (omitida sin birth_date).",
+                    "type": "integer"
+                },
+                "archived_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "birth_date": {
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "breed": {
                     "type": "string"
                 },
                 "created_at": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "date-time"
                 },
                 "id": {
                     "type": "string"
                 },
+                "last_event_at": {
+                    "description": "Solo con ?include=last_activity (omitido si la mascota no tiene eventos).",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "microchip": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1242,7 +4723,45 @@ const docTemplate = `{
                 "species": {
                     "$ref": "#/definitions/pets.Species"
                 },
+                "status": {
+                    "enum": [
+                        "active",
+                        "archived"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pets.PetStatus"
+                        }
+                    ]
+                },
                 "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "pets.sharedPetListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "mascotas en esta página",
+                    "type": "integer"
+                },
+                "has_more": {
+                    "description": "true =\u003e hay next_cursor",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pets.sharedPetResponse"
+                    }
+                },
+                "limit": {
+                    "description": "límite efectivo aplicado (sobre grants)",
+                    "type": "integer"
+                },
+                "next_cursor": {
                     "type": "string"
                 }
             }
@@ -1264,12 +4783,38 @@ const docTemplate = `{
                 }
             }
         },
+        "pets.transferPetRequest": {
+            "type": "object",
+            "properties": {
+                "new_owner_user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "pets.transferPetResponse": {
+            "type": "object",
+            "properties": {
+                "grants_revoked": {
+                    "type": "integer"
+                },
+                "pet": {
+                    "$ref": "#/definitions/pets.petResponse"
+                },
+                "previous_owner_user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "pets.updatePetRequest": {
             "type": "object",
             "properties": {
                 "breed": {
                     "type": "string"
                 },
+                "microchip": {
+                    "description": "\"\" limpia el valor",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1300,6 +4845,31 @@ const docTemplate = `{
                     ]
                 }
             }
+        },
+        "pets.vocabItem": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "pets.vocabResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pets.vocabItem"
+                    }
+                },
+                "language": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
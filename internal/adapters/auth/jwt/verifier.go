@@ -0,0 +1,206 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+
+	"pet-clinical-history/internal/ports/auth"
+)
+
+var (
+	ErrNotConfigured    = errors.New("jwt verifier not configured")
+	ErrTokenMalformed   = errors.New("jwt malformed")
+	ErrUnsupportedAlg   = errors.New("jwt alg not supported")
+	ErrSignatureInvalid = errors.New("jwt signature invalid")
+	ErrTokenExpired     = errors.New("jwt expired")
+	ErrTokenNotYetValid = errors.New("jwt not yet valid")
+	ErrMissingSubject   = errors.New("jwt missing sub")
+	ErrInvalidPublicKey = errors.New("invalid rsa public key")
+)
+
+const (
+	// DefaultIntegrationClaim es el claim con el sistema externo de un token de integración.
+	DefaultIntegrationClaim = "integration_system"
+	// DefaultAdminClaim es el claim (bool) que marca a un operador del servicio.
+	DefaultAdminClaim = "admin"
+)
+
+// Config del verificador local. Se acepta solo el alg cuya clave está configurada
+// (HS256 con Secret, RS256 con PublicKey), para evitar confusión de algoritmos.
+type Config struct {
+	// Secret compartido para HS256.
+	Secret []byte
+
+	// PublicKey para RS256 (ver ParseRSAPublicKeyPEM).
+	PublicKey *rsa.PublicKey
+
+	// Leeway tolera desfase de reloj al validar exp/nbf.
+	Leeway time.Duration
+
+	// IntegrationClaim es el claim (string) que marca un token de integración con el nombre del
+	// sistema externo. Vacío => DefaultIntegrationClaim; si no viene, es un usuario normal.
+	IntegrationClaim string
+
+	// AdminClaim es el claim (bool, true) que marca a un operador del servicio.
+	// Vacío => DefaultAdminClaim.
+	AdminClaim string
+}
+
+// Verifier implementa auth.AuthVerifier validando el token offline (sin llamar a Odin).
+type Verifier struct {
+	cfg Config
+	now func() time.Time
+}
+
+func NewVerifier(cfg Config) *Verifier {
+	return &Verifier{cfg: cfg, now: time.Now}
+}
+
+type header struct {
+	Alg string `json:"alg"`
+}
+
+type payload struct {
+	Sub    string   `json:"sub"`
+	Email  string   `json:"email"`
+	Tenant string   `json:"tenant"`
+	Exp    *float64 `json:"exp"`
+	Nbf    *float64 `json:"nbf"`
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (auth.Claims, error) {
+	if v == nil || (len(v.cfg.Secret) == 0 && v.cfg.PublicKey == nil) {
+		return auth.Claims{}, ErrNotConfigured
+	}
+
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return auth.Claims{}, ErrTokenMalformed
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return auth.Claims{}, ErrTokenMalformed
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return auth.Claims{}, ErrTokenMalformed
+	}
+	if err := v.verifySignature(h.Alg, parts[0]+"."+parts[1], sig); err != nil {
+		return auth.Claims{}, err
+	}
+
+	// Recién con la firma validada se confía en el payload.
+	var p payload
+	if err := decodeSegment(parts[1], &p); err != nil {
+		return auth.Claims{}, ErrTokenMalformed
+	}
+	var raw map[string]any
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return auth.Claims{}, ErrTokenMalformed
+	}
+
+	now := v.now()
+	if p.Exp != nil && !now.Before(unixTime(*p.Exp).Add(v.cfg.Leeway)) {
+		return auth.Claims{}, ErrTokenExpired
+	}
+	if p.Nbf != nil && now.Add(v.cfg.Leeway).Before(unixTime(*p.Nbf)) {
+		return auth.Claims{}, ErrTokenNotYetValid
+	}
+
+	sub := strings.TrimSpace(p.Sub)
+	if sub == "" {
+		return auth.Claims{}, ErrMissingSubject
+	}
+
+	integration, _ := raw[orDefault(v.cfg.IntegrationClaim, DefaultIntegrationClaim)].(string)
+	admin, _ := raw[orDefault(v.cfg.AdminClaim, DefaultAdminClaim)].(bool)
+
+	return auth.Claims{
+		UserID:            sub,
+		Email:             strings.TrimSpace(p.Email),
+		TenantID:          strings.TrimSpace(p.Tenant),
+		IntegrationSystem: strings.TrimSpace(integration),
+		Admin:             admin,
+	}, nil
+}
+
+func orDefault(v, def string) string {
+	if v = strings.TrimSpace(v); v != "" {
+		return v
+	}
+	return def
+}
+
+func (v *Verifier) verifySignature(alg, signingInput string, sig []byte) error {
+	switch alg {
+	case "HS256":
+		if len(v.cfg.Secret) == 0 {
+			return ErrUnsupportedAlg
+		}
+		mac := hmac.New(sha256.New, v.cfg.Secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return ErrSignatureInvalid
+		}
+		return nil
+	case "RS256":
+		if v.cfg.PublicKey == nil {
+			return ErrUnsupportedAlg
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(v.cfg.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			return ErrSignatureInvalid
+		}
+		return nil
+	default:
+		// Incluye "none".
+		return ErrUnsupportedAlg
+	}
+}
+
+// ParseRSAPublicKeyPEM acepta una clave pública RSA en PEM (PKIX "PUBLIC KEY" o PKCS#1 "RSA PUBLIC KEY").
+func ParseRSAPublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, ErrInvalidPublicKey
+		}
+		return key, nil
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	key, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrInvalidPublicKey
+	}
+	return key, nil
+}
+
+func decodeSegment(seg string, out any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+func unixTime(sec float64) time.Time {
+	return time.Unix(0, int64(sec*float64(time.Second)))
+}
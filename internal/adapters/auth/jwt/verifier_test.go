@@ -0,0 +1,196 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("test-secret")
+
+func sign(t *testing.T, alg string, claims map[string]any, key any) string {
+	t.Helper()
+
+	enc := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	input := enc(map[string]string{"alg": alg, "typ": "JWT"}) + "." + enc(claims)
+
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(input))
+		var err error
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("rsa sign: %v", err)
+		}
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifier_HS256_ValidToken(t *testing.T) {
+	v := NewVerifier(Config{Secret: secret})
+	tok := sign(t, "HS256", map[string]any{
+		"sub":    "user-1",
+		"email":  "ana@example.com",
+		"tenant": "t-1",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}, secret)
+
+	c, err := v.Verify(context.Background(), tok)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if c.UserID != "user-1" || c.Email != "ana@example.com" || c.TenantID != "t-1" {
+		t.Fatalf("unexpected claims: %+v", c)
+	}
+}
+
+func TestVerifier_IntegrationClaim(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+
+	// Default: integration_system
+	v := NewVerifier(Config{Secret: secret})
+	c, err := v.Verify(context.Background(), sign(t, "HS256", map[string]any{"sub": "clinic-svc-1", "integration_system": "vetsoft", "exp": exp}, secret))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if c.IntegrationSystem != "vetsoft" || !c.IsIntegration() {
+		t.Fatalf("expected integration vetsoft, got %+v", c)
+	}
+
+	// Usuario normal: sin el claim
+	c, err = v.Verify(context.Background(), sign(t, "HS256", map[string]any{"sub": "user-1", "exp": exp}, secret))
+	if err != nil || c.IsIntegration() {
+		t.Fatalf("expected normal user, got %+v err=%v", c, err)
+	}
+
+	// Claim configurable; el default deja de contar
+	v = NewVerifier(Config{Secret: secret, IntegrationClaim: "client_system"})
+	c, err = v.Verify(context.Background(), sign(t, "HS256", map[string]any{"sub": "clinic-svc-1", "client_system": "clinicapp", "integration_system": "vetsoft", "exp": exp}, secret))
+	if err != nil || c.IntegrationSystem != "clinicapp" {
+		t.Fatalf("expected integration clinicapp, got %+v err=%v", c, err)
+	}
+}
+
+func TestVerifier_AdminClaim(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	v := NewVerifier(Config{Secret: secret})
+
+	c, err := v.Verify(context.Background(), sign(t, "HS256", map[string]any{"sub": "ops-1", "admin": true, "exp": exp}, secret))
+	if err != nil || !c.Admin || !c.CanBackfill() {
+		t.Fatalf("expected admin, got %+v err=%v", c, err)
+	}
+	// Solo cuenta un bool true
+	for _, val := range []any{false, "true", 1} {
+		c, err := v.Verify(context.Background(), sign(t, "HS256", map[string]any{"sub": "user-1", "admin": val, "exp": exp}, secret))
+		if err != nil || c.Admin {
+			t.Fatalf("admin=%v: expected non-admin, got %+v err=%v", val, c, err)
+		}
+	}
+
+	v = NewVerifier(Config{Secret: secret, AdminClaim: "is_staff"})
+	c, err = v.Verify(context.Background(), sign(t, "HS256", map[string]any{"sub": "ops-1", "is_staff": true, "exp": exp}, secret))
+	if err != nil || !c.Admin {
+		t.Fatalf("expected admin with custom claim, got %+v err=%v", c, err)
+	}
+}
+
+func TestVerifier_RS256_ValidToken(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	pub, err := ParseRSAPublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("parse public key: %v", err)
+	}
+
+	v := NewVerifier(Config{PublicKey: pub})
+	tok := sign(t, "RS256", map[string]any{"sub": "user-2", "exp": time.Now().Add(time.Hour).Unix()}, priv)
+
+	c, err := v.Verify(context.Background(), tok)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if c.UserID != "user-2" {
+		t.Fatalf("unexpected claims: %+v", c)
+	}
+
+	// Un token HS256 no se acepta si solo hay clave RSA configurada.
+	if _, err := v.Verify(context.Background(), sign(t, "HS256", map[string]any{"sub": "x"}, secret)); !errors.Is(err, ErrUnsupportedAlg) {
+		t.Fatalf("expected ErrUnsupportedAlg, got %v", err)
+	}
+}
+
+func TestVerifier_RejectsExpired(t *testing.T) {
+	v := NewVerifier(Config{Secret: secret})
+	tok := sign(t, "HS256", map[string]any{"sub": "user-1", "exp": time.Now().Add(-time.Minute).Unix()}, secret)
+
+	if _, err := v.Verify(context.Background(), tok); !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired, got %v", err)
+	}
+
+	// Dentro del leeway sigue siendo válido.
+	v = NewVerifier(Config{Secret: secret, Leeway: 2 * time.Minute})
+	if _, err := v.Verify(context.Background(), tok); err != nil {
+		t.Fatalf("expected token within leeway to verify, got %v", err)
+	}
+}
+
+func TestVerifier_RejectsTampered(t *testing.T) {
+	v := NewVerifier(Config{Secret: secret})
+	tok := sign(t, "HS256", map[string]any{"sub": "user-1"}, secret)
+
+	// Se cambia el payload manteniendo la firma original.
+	parts := strings.Split(tok, ".")
+	forged, _ := json.Marshal(map[string]any{"sub": "admin"})
+	parts[1] = base64.RawURLEncoding.EncodeToString(forged)
+
+	if _, err := v.Verify(context.Background(), strings.Join(parts, ".")); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected ErrSignatureInvalid, got %v", err)
+	}
+
+	// Firma con otro secreto.
+	other := sign(t, "HS256", map[string]any{"sub": "user-1"}, []byte("other"))
+	if _, err := v.Verify(context.Background(), other); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected ErrSignatureInvalid for wrong secret, got %v", err)
+	}
+}
+
+func TestVerifier_RejectsMissingSubAndNone(t *testing.T) {
+	v := NewVerifier(Config{Secret: secret})
+
+	if _, err := v.Verify(context.Background(), sign(t, "HS256", map[string]any{"email": "a@b.c"}, secret)); !errors.Is(err, ErrMissingSubject) {
+		t.Fatalf("expected ErrMissingSubject, got %v", err)
+	}
+
+	none := sign(t, "none", map[string]any{"sub": "user-1"}, nil)
+	if _, err := v.Verify(context.Background(), none); !errors.Is(err, ErrUnsupportedAlg) {
+		t.Fatalf("expected ErrUnsupportedAlg for alg=none, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package odin
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+
+	"pet-clinical-history/internal/ports/auth"
+)
+
+const (
+	// DefaultCacheTTL es cuánto se reutilizan los claims de un token verificado.
+	DefaultCacheTTL = 60 * time.Second
+	// DefaultCacheMaxEntries acota la memoria ante una ráfaga de tokens distintos.
+	DefaultCacheMaxEntries = 10000
+	// negativeCacheTTL es el TTL (más corto) de los rechazos; nunca supera el TTL positivo.
+	negativeCacheTTL = 5 * time.Second
+)
+
+// CachingVerifier envuelve un auth.AuthVerifier con un LRU en memoria indexado por el hash
+// del token (el token en claro no se guarda). Cachea claims válidos por ttl y rechazos por un
+// TTL más corto. Los errores transitorios (upstream, contexto) no se cachean.
+type CachingVerifier struct {
+	inner       auth.AuthVerifier
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxEntries  int
+	now         func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // front = usado más recientemente
+	entries map[[sha256.Size]byte]*list.Element
+}
+
+type cacheEntry struct {
+	key       [sha256.Size]byte
+	claims    auth.Claims
+	err       error
+	expiresAt time.Time
+}
+
+// NewCachingVerifier crea el wrapper. ttl <= 0 => DefaultCacheTTL; maxEntries <= 0 => DefaultCacheMaxEntries.
+func NewCachingVerifier(inner auth.AuthVerifier, ttl time.Duration, maxEntries int) *CachingVerifier {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	negative := negativeCacheTTL
+	if negative > ttl {
+		negative = ttl
+	}
+	return &CachingVerifier{
+		inner:       inner,
+		ttl:         ttl,
+		negativeTTL: negative,
+		maxEntries:  maxEntries,
+		now:         time.Now,
+		order:       list.New(),
+		entries:     map[[sha256.Size]byte]*list.Element{},
+	}
+}
+
+func (c *CachingVerifier) Verify(ctx context.Context, token string) (auth.Claims, error) {
+	key := sha256.Sum256([]byte(token))
+
+	if claims, err, ok := c.get(key); ok {
+		return claims, err
+	}
+
+	// MVP: dos requests concurrentes con el mismo token nuevo pueden verificar ambos upstream.
+	claims, err := c.inner.Verify(ctx, token)
+	switch {
+	case err == nil:
+		c.put(key, claims, nil, c.ttl)
+	case cacheableError(err):
+		c.put(key, auth.Claims{}, err, c.negativeTTL)
+	}
+	return claims, err
+}
+
+func (c *CachingVerifier) get(key [sha256.Size]byte) (auth.Claims, error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return auth.Claims{}, nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !c.now().Before(e.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return auth.Claims{}, nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.claims, e.err, true
+}
+
+func (c *CachingVerifier) put(key [sha256.Size]byte, claims auth.Claims, err error, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, claims: claims, err: err, expiresAt: c.now().Add(ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cacheableError: solo rechazos del token; un upstream caído o un contexto cancelado no
+// dicen nada sobre el token y no deben "pegarse" a requests siguientes.
+func cacheableError(err error) bool {
+	return !errors.Is(err, ErrOdinUpstream) &&
+		!errors.Is(err, ErrOdinNotConfigured) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,105 @@
+package odin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/ports/auth"
+)
+
+type countingVerifier struct {
+	calls int
+	err   error
+}
+
+func (v *countingVerifier) Verify(_ context.Context, token string) (auth.Claims, error) {
+	v.calls++
+	if v.err != nil {
+		return auth.Claims{}, v.err
+	}
+	return auth.Claims{UserID: "user-" + token}, nil
+}
+
+func TestCachingVerifier_ReusesClaimsWithinTTL(t *testing.T) {
+	inner := &countingVerifier{}
+	c := NewCachingVerifier(inner, time.Minute, 10)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		claims, err := c.Verify(context.Background(), "tok")
+		if err != nil || claims.UserID != "user-tok" {
+			t.Fatalf("unexpected verify result: %+v %v", claims, err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected 1 inner call within TTL, got %d", inner.calls)
+	}
+
+	// Vencido el TTL se vuelve a verificar.
+	now = now.Add(time.Minute)
+	if _, err := c.Verify(context.Background(), "tok"); err != nil {
+		t.Fatalf("verify after ttl: %v", err)
+	}
+	if inner.calls != 2 {
+		t.Fatalf("expected inner call after TTL, got %d", inner.calls)
+	}
+}
+
+func TestCachingVerifier_NegativeAndTransientErrors(t *testing.T) {
+	inner := &countingVerifier{err: ErrOdinUnauthorized}
+	c := NewCachingVerifier(inner, time.Minute, 10)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	// Rechazo: cacheado por el TTL corto.
+	for i := 0; i < 2; i++ {
+		if _, err := c.Verify(context.Background(), "bad"); !errors.Is(err, ErrOdinUnauthorized) {
+			t.Fatalf("expected ErrOdinUnauthorized, got %v", err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected negative result cached, got %d calls", inner.calls)
+	}
+	now = now.Add(negativeCacheTTL)
+	_, _ = c.Verify(context.Background(), "bad")
+	if inner.calls != 2 {
+		t.Fatalf("expected negative entry to expire, got %d calls", inner.calls)
+	}
+
+	// Upstream caído: no se cachea.
+	inner.err = fmt.Errorf("odin verify failed: %w", ErrOdinUpstream)
+	inner.calls = 0
+	_, _ = c.Verify(context.Background(), "other")
+	_, _ = c.Verify(context.Background(), "other")
+	if inner.calls != 2 {
+		t.Fatalf("expected upstream errors not cached, got %d calls", inner.calls)
+	}
+}
+
+func TestCachingVerifier_BoundedLRU(t *testing.T) {
+	inner := &countingVerifier{}
+	c := NewCachingVerifier(inner, time.Minute, 2)
+
+	_, _ = c.Verify(context.Background(), "a")
+	_, _ = c.Verify(context.Background(), "b")
+	_, _ = c.Verify(context.Background(), "a") // a pasa a ser el más reciente
+	_, _ = c.Verify(context.Background(), "c") // desaloja b
+
+	if len(c.entries) != 2 || c.order.Len() != 2 {
+		t.Fatalf("expected cache bounded to 2 entries, got %d", len(c.entries))
+	}
+
+	inner.calls = 0
+	_, _ = c.Verify(context.Background(), "a")
+	if inner.calls != 0 {
+		t.Fatalf("expected a still cached")
+	}
+	_, _ = c.Verify(context.Background(), "b")
+	if inner.calls != 1 {
+		t.Fatalf("expected b evicted and re-verified, got %d calls", inner.calls)
+	}
+}
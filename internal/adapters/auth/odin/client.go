@@ -1,15 +1,16 @@
 package odin
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"pet-clinical-history/internal/platform/apiclient"
 	"pet-clinical-history/internal/ports/auth"
 )
 
@@ -19,6 +20,18 @@ var (
 	ErrOdinUpstream      = errors.New("odin upstream error")
 )
 
+// Defaults del contrato de verificación (placeholder hasta que exista el contrato real de Odin).
+const (
+	DefaultVerifyPath  = "/v1/tokens/verify"
+	DefaultUserIDField = "user_id"
+	DefaultEmailField  = "email"
+	DefaultTenantField = "tenant_id"
+	// DefaultIntegrationField es el claim con el sistema externo de un token de integración.
+	DefaultIntegrationField = "integration_system"
+	// DefaultAdminField es el claim (bool) que marca a un operador del servicio.
+	DefaultAdminField = "admin"
+)
+
 // Config del cliente Odin.
 // BaseURL y APIKey normalmente vendrán de env vars en el servicio que lo instancie.
 type Config struct {
@@ -31,42 +44,72 @@ type Config struct {
 
 	// Timeout HTTP (si http.Client es nil, se usa este).
 	Timeout time.Duration
+
+	// Opcional: path del endpoint de verificación. Vacío => DefaultVerifyPath.
+	VerifyPath string
+
+	// Opcional: claves de la respuesta de verificación de las que salen los claims; admiten
+	// anidamiento con puntos (p.ej. "data.user.id"). Vacío => DefaultUserIDField,
+	// DefaultEmailField, DefaultTenantField, DefaultIntegrationField, DefaultAdminField. Si la
+	// clave de integración no viene en la respuesta, el token es de un usuario normal; la de
+	// admin cuenta solo si es true.
+	UserIDField      string
+	EmailField       string
+	TenantField      string
+	IntegrationField string
+	AdminField       string
+
+	// Opcional: transport HTTP (p.ej. uno fake en tests). nil => http.DefaultTransport.
+	Transport http.RoundTripper
 }
 
 type Client struct {
-	baseURL      string
-	apiKey       string
-	apiKeyHeader string
-	httpClient   *http.Client
+	api *apiclient.Client
+
+	verifyPath       string
+	userIDField      string
+	emailField       string
+	tenantField      string
+	integrationField string
+	adminField       string
 }
 
 func NewClient(cfg Config) *Client {
-	h := strings.TrimSpace(cfg.APIKeyHeader)
-	if h == "" {
-		h = "X-Api-Key"
-	}
-	timeout := cfg.Timeout
-	if timeout <= 0 {
-		timeout = 5 * time.Second
+	return &Client{
+		api: apiclient.New(apiclient.Config{
+			BaseURL:      cfg.BaseURL,
+			APIKey:       cfg.APIKey,
+			APIKeyHeader: cfg.APIKeyHeader,
+			Timeout:      cfg.Timeout,
+			Transport:    cfg.Transport,
+		}, apiclient.Errors{
+			Unauthorized: ErrOdinUnauthorized,
+			Upstream:     ErrOdinUpstream,
+			NotFound:     auth.ErrUserNotFound,
+		}),
+		verifyPath:       orDefault(cfg.VerifyPath, DefaultVerifyPath),
+		userIDField:      orDefault(cfg.UserIDField, DefaultUserIDField),
+		emailField:       orDefault(cfg.EmailField, DefaultEmailField),
+		tenantField:      orDefault(cfg.TenantField, DefaultTenantField),
+		integrationField: orDefault(cfg.IntegrationField, DefaultIntegrationField),
+		adminField:       orDefault(cfg.AdminField, DefaultAdminField),
 	}
+}
 
-	return &Client{
-		baseURL:      strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/"),
-		apiKey:       strings.TrimSpace(cfg.APIKey),
-		apiKeyHeader: h,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
+func orDefault(v, def string) string {
+	if v = strings.TrimSpace(v); v != "" {
+		return v
 	}
+	return def
 }
 
 func (c *Client) IsConfigured() bool {
-	return c != nil && c.baseURL != "" && c.apiKey != ""
+	return c != nil && c.api.IsConfigured()
 }
 
 // VerifyToken llama a Odin para verificar un token y traer claims.
-// ⚠️ Endpoint/payload: es un placeholder estable para el esqueleto.
-// Cuando Odin esté listo, reemplazar verifyPath + request/response según contrato real.
+// ⚠️ Endpoint/payload: los defaults son un placeholder estable para el esqueleto; el path y
+// los nombres de los campos de la respuesta se ajustan al contrato real con Config.
 func (c *Client) VerifyToken(ctx context.Context, token string) (auth.Claims, error) {
 	if !c.IsConfigured() {
 		return auth.Claims{}, ErrOdinNotConfigured
@@ -76,58 +119,98 @@ func (c *Client) VerifyToken(ctx context.Context, token string) (auth.Claims, er
 		return auth.Claims{}, ErrOdinUnauthorized
 	}
 
-	// TODO(odin): ajustar path cuando exista contrato real.
-	const verifyPath = "/v1/tokens/verify"
-
 	reqBody := map[string]string{
 		"token": token,
 	}
-	b, _ := json.Marshal(reqBody)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+verifyPath, bytes.NewReader(b))
-	if err != nil {
-		return auth.Claims{}, fmt.Errorf("%w: %v", ErrOdinUpstream, err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set(c.apiKeyHeader, c.apiKey)
+	// Respuesta genérica: los claims se extraen con las claves configuradas.
+	var out map[string]any
 
 	// Algunos IAM esperan el token en Authorization, aunque también vaya en body.
-	req.Header.Set("Authorization", "Bearer "+token)
+	headers := map[string]string{
+		"Authorization": "Bearer " + token,
+	}
+	if err := c.api.DoJSON(ctx, http.MethodPost, c.verifyPath, headers, reqBody, &out); err != nil {
+		return auth.Claims{}, err
+	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return auth.Claims{}, fmt.Errorf("%w: %v", ErrOdinUpstream, err)
+	userID := lookupString(out, c.userIDField)
+	if userID == "" {
+		return auth.Claims{}, fmt.Errorf("odin response missing %s", c.userIDField)
 	}
-	defer resp.Body.Close()
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		// ok
-	case http.StatusUnauthorized, http.StatusForbidden:
-		return auth.Claims{}, ErrOdinUnauthorized
-	default:
-		return auth.Claims{}, fmt.Errorf("%w: status=%d", ErrOdinUpstream, resp.StatusCode)
+	return auth.Claims{
+		UserID:            userID,
+		Email:             lookupString(out, c.emailField),
+		TenantID:          lookupString(out, c.tenantField),
+		IntegrationSystem: lookupString(out, c.integrationField),
+		Admin:             lookupBool(out, c.adminField),
+	}, nil
+}
+
+// lookup busca key en m; con "." baja por objetos anidados.
+func lookup(m map[string]any, key string) (any, bool) {
+	var v any = m
+	for _, part := range strings.Split(key, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[part]; !ok {
+			return nil, false
+		}
 	}
+	return v, true
+}
 
-	// TODO(odin): ajustar fields reales. Esto es un formato típico.
-	var out struct {
-		UserID   string `json:"user_id"`
-		Email    string `json:"email"`
-		TenantID string `json:"tenant_id"`
+// lookupBool devuelve key (ver lookup) si es un bool; otro tipo o ausente => false.
+func lookupBool(m map[string]any, key string) bool {
+	v, _ := lookup(m, key)
+	b, _ := v.(bool)
+	return b
+}
+
+// lookupString busca key en m (ver lookup) y la devuelve como string: los números se
+// formatean (algunos IAM usan IDs numéricos); otro tipo o ausente => "".
+func lookupString(m map[string]any, key string) string {
+	v, ok := lookup(m, key)
+	if !ok {
+		return ""
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return auth.Claims{}, fmt.Errorf("%w: invalid json: %v", ErrOdinUpstream, err)
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
 	}
+	return ""
+}
 
-	out.UserID = strings.TrimSpace(out.UserID)
-	if out.UserID == "" {
-		return auth.Claims{}, errors.New("odin response missing user_id")
+// ResolveByEmail busca en Odin el user_id asociado a un email. Implementa auth.GranteeResolver.
+// ⚠️ Endpoint placeholder, igual que VerifyToken. Un 404 (o user_id vacío) => auth.ErrUserNotFound.
+func (c *Client) ResolveByEmail(ctx context.Context, email string) (string, error) {
+	if !c.IsConfigured() {
+		return "", ErrOdinNotConfigured
+	}
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", auth.ErrUserNotFound
 	}
 
-	return auth.Claims{
-		UserID:   out.UserID,
-		Email:    strings.TrimSpace(out.Email),
-		TenantID: strings.TrimSpace(out.TenantID),
-	}, nil
+	// TODO(odin): ajustar path cuando exista contrato real.
+	path := "/v1/users/lookup?email=" + url.QueryEscape(email)
+
+	var out struct {
+		UserID string `json:"user_id"`
+	}
+	if err := c.api.DoJSON(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return "", err
+	}
+
+	userID := strings.TrimSpace(out.UserID)
+	if userID == "" {
+		return "", auth.ErrUserNotFound
+	}
+	return userID, nil
 }
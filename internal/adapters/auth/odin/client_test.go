@@ -0,0 +1,201 @@
+package odin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pet-clinical-history/internal/ports/auth"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+func TestClient_VerifyToken_SendsAPIKey(t *testing.T) {
+	var gotKey, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-Api-Key")
+		gotAuth = r.Header.Get("Authorization")
+		if gotKey != "odin-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"user_id":"u-1","email":"a@b.c"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(Config{BaseURL: srv.URL, APIKey: "odin-key"})
+	claims, err := c.VerifyToken(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotKey != "odin-key" || gotAuth != "Bearer tok" {
+		t.Fatalf("expected api key and bearer headers, got key=%q auth=%q", gotKey, gotAuth)
+	}
+	if claims.UserID != "u-1" {
+		t.Fatalf("expected user u-1, got %q", claims.UserID)
+	}
+
+	bad := NewClient(Config{BaseURL: srv.URL, APIKey: "wrong"})
+	if _, err := bad.VerifyToken(context.Background(), "tok"); !errors.Is(err, ErrOdinUnauthorized) {
+		t.Fatalf("expected ErrOdinUnauthorized, got %v", err)
+	}
+}
+
+func TestClient_VerifyToken_IntegrationField(t *testing.T) {
+	body := `{"user_id":"u-1"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	// Default: integration_system
+	c := NewClient(Config{BaseURL: srv.URL, APIKey: "odin-key"})
+	if claims, err := c.VerifyToken(context.Background(), "tok"); err != nil || claims.IsIntegration() {
+		t.Fatalf("expected normal user without the field, got %+v err=%v", claims, err)
+	}
+	body = `{"user_id":"clinic-svc-1","integration_system":"vetsoft"}`
+	claims, err := c.VerifyToken(context.Background(), "tok")
+	if err != nil || claims.IntegrationSystem != "vetsoft" {
+		t.Fatalf("expected integration vetsoft, got %+v err=%v", claims, err)
+	}
+
+	// Clave configurable (con anidamiento)
+	body = `{"user_id":"clinic-svc-1","client":{"system":"clinicapp"}}`
+	c = NewClient(Config{BaseURL: srv.URL, APIKey: "odin-key", IntegrationField: "client.system"})
+	claims, err = c.VerifyToken(context.Background(), "tok")
+	if err != nil || claims.IntegrationSystem != "clinicapp" {
+		t.Fatalf("expected integration clinicapp, got %+v err=%v", claims, err)
+	}
+}
+
+func TestClient_VerifyToken_AdminField(t *testing.T) {
+	body := `{"user_id":"ops-1","admin":true}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	c := NewClient(Config{BaseURL: srv.URL, APIKey: "odin-key"})
+	if claims, err := c.VerifyToken(context.Background(), "tok"); err != nil || !claims.Admin {
+		t.Fatalf("expected admin, got %+v err=%v", claims, err)
+	}
+	body = `{"user_id":"u-1","admin":"yes"}`
+	if claims, err := c.VerifyToken(context.Background(), "tok"); err != nil || claims.Admin {
+		t.Fatalf("expected non-admin for non-bool field, got %+v err=%v", claims, err)
+	}
+
+	body = `{"user_id":"ops-1","roles":{"admin":true}}`
+	c = NewClient(Config{BaseURL: srv.URL, APIKey: "odin-key", AdminField: "roles.admin"})
+	if claims, err := c.VerifyToken(context.Background(), "tok"); err != nil || !claims.Admin {
+		t.Fatalf("expected admin with nested field, got %+v err=%v", claims, err)
+	}
+}
+
+func TestClient_ResolveByEmail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "odin-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("email") != "vet@example.com" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"user_id":"vet-1"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(Config{BaseURL: srv.URL, APIKey: "odin-key"})
+
+	id, err := c.ResolveByEmail(context.Background(), "vet@example.com")
+	if err != nil || id != "vet-1" {
+		t.Fatalf("expected vet-1, got %q err=%v", id, err)
+	}
+	if _, err := c.ResolveByEmail(context.Background(), "nobody@example.com"); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestClient_VerifyToken_ForwardsRequestID(t *testing.T) {
+	var gotReqID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReqID = r.Header.Get("X-Request-ID")
+		_, _ = w.Write([]byte(`{"user_id":"u-1"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(Config{BaseURL: srv.URL, APIKey: "odin-key"})
+	ctx := context.WithValue(context.Background(), chimw.RequestIDKey, "req-odin-1")
+	if _, err := c.VerifyToken(ctx, "tok"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotReqID != "req-odin-1" {
+		t.Fatalf("expected forwarded request id, got %q", gotReqID)
+	}
+}
+
+// fakeTransport responde siempre body (200) y registra el path pedido.
+type fakeTransport struct {
+	body    string
+	gotPath string
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.gotPath = req.URL.Path
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestClient_VerifyToken_CustomPathAndFieldMapping(t *testing.T) {
+	tr := &fakeTransport{body: `{"sub":"u-9","data":{"mail":"vet@example.com","org":{"id":42}}}`}
+	c := NewClient(Config{
+		BaseURL:     "http://odin.test",
+		APIKey:      "odin-key",
+		VerifyPath:  "/v2/introspect",
+		UserIDField: "sub",
+		EmailField:  "data.mail",
+		TenantField: "data.org.id",
+		Transport:   tr,
+	})
+
+	claims, err := c.VerifyToken(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tr.gotPath != "/v2/introspect" {
+		t.Fatalf("expected custom verify path, got %q", tr.gotPath)
+	}
+	if claims.UserID != "u-9" || claims.Email != "vet@example.com" || claims.TenantID != "42" {
+		t.Fatalf("expected mapped claims, got %+v", claims)
+	}
+
+	// Sin el campo de user id configurado => error (no claims vacíos)
+	tr.body = `{"user_id":"u-1"}`
+	if _, err := c.VerifyToken(context.Background(), "tok"); err == nil || !strings.Contains(err.Error(), "sub") {
+		t.Fatalf("expected missing sub error, got %v", err)
+	}
+}
+
+func TestClient_VerifyToken_DefaultContract(t *testing.T) {
+	tr := &fakeTransport{body: `{"user_id":"u-1","email":"a@b.c","tenant_id":"t-1"}`}
+	c := NewClient(Config{BaseURL: "http://odin.test", APIKey: "odin-key", Transport: tr})
+
+	claims, err := c.VerifyToken(context.Background(), "tok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tr.gotPath != DefaultVerifyPath {
+		t.Fatalf("expected default verify path, got %q", tr.gotPath)
+	}
+	if claims.UserID != "u-1" || claims.Email != "a@b.c" || claims.TenantID != "t-1" {
+		t.Fatalf("expected default field mapping, got %+v", claims)
+	}
+}
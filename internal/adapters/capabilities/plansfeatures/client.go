@@ -2,12 +2,13 @@ package plansfeatures
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
+
+	"pet-clinical-history/internal/platform/apiclient"
 )
 
 var (
@@ -25,34 +26,25 @@ type Config struct {
 }
 
 type Client struct {
-	baseURL      string
-	apiKey       string
-	apiKeyHeader string
-	httpClient   *http.Client
+	api *apiclient.Client
 }
 
 func NewClient(cfg Config) *Client {
-	h := strings.TrimSpace(cfg.APIKeyHeader)
-	if h == "" {
-		h = "X-Api-Key"
-	}
-	timeout := cfg.Timeout
-	if timeout <= 0 {
-		timeout = 5 * time.Second
-	}
-
 	return &Client{
-		baseURL:      strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/"),
-		apiKey:       strings.TrimSpace(cfg.APIKey),
-		apiKeyHeader: h,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
+		api: apiclient.New(apiclient.Config{
+			BaseURL:      cfg.BaseURL,
+			APIKey:       cfg.APIKey,
+			APIKeyHeader: cfg.APIKeyHeader,
+			Timeout:      cfg.Timeout,
+		}, apiclient.Errors{
+			Unauthorized: ErrPlansUnauthorized,
+			Upstream:     ErrPlansUpstream,
+		}),
 	}
 }
 
 func (c *Client) IsConfigured() bool {
-	return c != nil && c.baseURL != "" && c.apiKey != ""
+	return c != nil && c.api.IsConfigured()
 }
 
 // CapabilitiesResponse es deliberadamente simple.
@@ -60,6 +52,10 @@ func (c *Client) IsConfigured() bool {
 type CapabilitiesResponse struct {
 	// Ejemplo: {"pet:attachments:add": true, "events:void": false}
 	Capabilities map[string]bool `json:"capabilities"`
+
+	// Quotas son los límites numéricos del plan. Ejemplo: {"pets:max": 3}.
+	// Una cuota ausente significa sin tope.
+	Quotas map[string]int `json:"quotas,omitempty"`
 }
 
 // GetCapabilities trae capabilities para un usuario.
@@ -75,32 +71,11 @@ func (c *Client) GetCapabilities(ctx context.Context, userID string) (Capabiliti
 
 	// TODO(plans-features): ajustar path según contrato real.
 	// Una opción típica: GET /v1/capabilities?user_id=...
-	url := fmt.Sprintf("%s/v1/capabilities?user_id=%s", c.baseURL, userID)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return CapabilitiesResponse{}, fmt.Errorf("%w: %v", ErrPlansUpstream, err)
-	}
-	req.Header.Set(c.apiKeyHeader, c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return CapabilitiesResponse{}, fmt.Errorf("%w: %v", ErrPlansUpstream, err)
-	}
-	defer resp.Body.Close()
-
-	switch resp.StatusCode {
-	case http.StatusOK:
-		// ok
-	case http.StatusUnauthorized, http.StatusForbidden:
-		return CapabilitiesResponse{}, ErrPlansUnauthorized
-	default:
-		return CapabilitiesResponse{}, fmt.Errorf("%w: status=%d", ErrPlansUpstream, resp.StatusCode)
-	}
+	path := fmt.Sprintf("/v1/capabilities?user_id=%s", userID)
 
 	var out CapabilitiesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return CapabilitiesResponse{}, fmt.Errorf("%w: invalid json: %v", ErrPlansUpstream, err)
+	if err := c.api.DoJSON(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return CapabilitiesResponse{}, err
 	}
 	if out.Capabilities == nil {
 		out.Capabilities = map[string]bool{}
@@ -0,0 +1,60 @@
+package plansfeatures
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+func TestClient_GetCapabilities_SendsAPIKey(t *testing.T) {
+	var gotKey, gotUser string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-Plans-Key")
+		gotUser = r.URL.Query().Get("user_id")
+		if gotKey != "plans-key" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"capabilities":{"events:void":true}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(Config{BaseURL: srv.URL, APIKey: "plans-key", APIKeyHeader: "X-Plans-Key"})
+	resp, err := c.GetCapabilities(context.Background(), "u-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotKey != "plans-key" || gotUser != "u-1" {
+		t.Fatalf("expected api key and user_id, got key=%q user=%q", gotKey, gotUser)
+	}
+	if !resp.Capabilities["events:void"] {
+		t.Fatalf("expected events:void capability, got %v", resp.Capabilities)
+	}
+
+	bad := NewClient(Config{BaseURL: srv.URL, APIKey: "wrong", APIKeyHeader: "X-Plans-Key"})
+	if _, err := bad.GetCapabilities(context.Background(), "u-1"); !errors.Is(err, ErrPlansUnauthorized) {
+		t.Fatalf("expected ErrPlansUnauthorized, got %v", err)
+	}
+}
+
+func TestClient_GetCapabilities_ForwardsRequestID(t *testing.T) {
+	var gotReqID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReqID = r.Header.Get("X-Request-ID")
+		_, _ = w.Write([]byte(`{"capabilities":{}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(Config{BaseURL: srv.URL, APIKey: "plans-key"})
+	ctx := context.WithValue(context.Background(), chimw.RequestIDKey, "req-plans-1")
+	if _, err := c.GetCapabilities(ctx, "u-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotReqID != "req-plans-1" {
+		t.Fatalf("expected forwarded request id, got %q", gotReqID)
+	}
+}
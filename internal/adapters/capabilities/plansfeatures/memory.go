@@ -0,0 +1,81 @@
+package plansfeatures
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// MemorySource es una fuente de capabilities en memoria, sembrada desde un mapa.
+// Sirve para tests y dev local con escenarios por usuario/feature sin levantar plans-features.
+type MemorySource struct {
+	mu     sync.RWMutex
+	caps   map[string]map[string]bool
+	quotas map[string]map[string]int
+}
+
+// NewMemorySource crea la fuente copiando seed (userID -> capability -> habilitada).
+func NewMemorySource(seed map[string]map[string]bool) *MemorySource {
+	m := &MemorySource{caps: map[string]map[string]bool{}, quotas: map[string]map[string]int{}}
+	for userID, caps := range seed {
+		for capability, enabled := range caps {
+			m.Set(userID, capability, enabled)
+		}
+	}
+	return m
+}
+
+// Set habilita/deshabilita una capability para un usuario.
+func (m *MemorySource) Set(userID, capability string, enabled bool) {
+	userID = strings.TrimSpace(userID)
+	capability = strings.TrimSpace(capability)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.caps[userID] == nil {
+		m.caps[userID] = map[string]bool{}
+	}
+	m.caps[userID][capability] = enabled
+}
+
+// SetQuota fija una cuota numérica (p.ej. pets:max) para un usuario.
+func (m *MemorySource) SetQuota(userID, quota string, limit int) {
+	userID = strings.TrimSpace(userID)
+	quota = strings.TrimSpace(quota)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.quotas[userID] == nil {
+		m.quotas[userID] = map[string]int{}
+	}
+	m.quotas[userID][quota] = limit
+}
+
+func (m *MemorySource) IsConfigured() bool {
+	return m != nil
+}
+
+// GetCapabilities devuelve una copia de las capabilities y cuotas del usuario.
+// Un usuario no sembrado no tiene capabilities (mapa vacío, sin error).
+func (m *MemorySource) GetCapabilities(_ context.Context, userID string) (CapabilitiesResponse, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return CapabilitiesResponse{}, errors.New("userID required")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make(map[string]bool, len(m.caps[userID]))
+	for capability, enabled := range m.caps[userID] {
+		out[capability] = enabled
+	}
+	quotas := make(map[string]int, len(m.quotas[userID]))
+	for quota, limit := range m.quotas[userID] {
+		quotas[quota] = limit
+	}
+	return CapabilitiesResponse{Capabilities: out, Quotas: quotas}, nil
+}
@@ -0,0 +1,103 @@
+package plansfeatures
+
+import (
+	"context"
+	"testing"
+
+	"pet-clinical-history/internal/ports/capabilities"
+)
+
+func TestResolver_MemorySource_SubsetOfCapabilities(t *testing.T) {
+	t.Setenv("ALLOW_ALL_CAPABILITIES", "")
+
+	src := NewMemorySource(map[string]map[string]bool{
+		"user-1": {
+			"pet:attachments:add": true,
+			"events:void":         false,
+		},
+	})
+	// Sin cache: los cambios en la fuente deben verse de inmediato.
+	r := NewResolver(src, WithCacheTTL(-1))
+	ctx := context.Background()
+
+	cases := []struct {
+		userID     string
+		capability string
+		want       bool
+	}{
+		{"user-1", "pet:attachments:add", true},
+		{"user-1", "events:void", false},
+		{"user-1", "pets:export", false},
+		{"user-2", "pet:attachments:add", false},
+	}
+	for _, tc := range cases {
+		got, err := r.Has(ctx, tc.userID, tc.capability)
+		if err != nil {
+			t.Fatalf("Has(%s, %s): unexpected error: %v", tc.userID, tc.capability, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Has(%s, %s) = %v, want %v", tc.userID, tc.capability, got, tc.want)
+		}
+	}
+
+	caps, err := r.Resolve(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Resolve: unexpected error: %v", err)
+	}
+	if len(caps) != 2 || !caps["pet:attachments:add"] || caps["events:void"] {
+		t.Fatalf("unexpected resolved capabilities: %v", caps)
+	}
+
+	// Cambios en la fuente se reflejan sin recrear el resolver
+	src.Set("user-2", "events:void", true)
+	if ok, _ := r.Has(ctx, "user-2", "events:void"); !ok {
+		t.Fatalf("expected user-2 to have events:void after Set")
+	}
+}
+
+func TestResolver_MemorySource_Quota(t *testing.T) {
+	t.Setenv("ALLOW_ALL_CAPABILITIES", "")
+
+	src := NewMemorySource(nil)
+	src.SetQuota("user-1", "pets:max", 3)
+	r := NewResolver(src)
+	ctx := context.Background()
+
+	limit, limited, err := r.Quota(ctx, capabilities.CapabilityCheck{UserID: "user-1", FeatureKey: "pets:max"})
+	if err != nil || !limited || limit != 3 {
+		t.Fatalf("expected pets:max=3, got %d limited=%v err=%v", limit, limited, err)
+	}
+
+	// Sin cuota en el plan => sin tope
+	if _, limited, err := r.Quota(ctx, capabilities.CapabilityCheck{UserID: "user-2", FeatureKey: "pets:max"}); err != nil || limited {
+		t.Fatalf("expected unlimited for user without quota, got limited=%v err=%v", limited, err)
+	}
+
+	t.Setenv("ALLOW_ALL_CAPABILITIES", "true")
+	if _, limited, _ := NewResolver(src).Quota(ctx, capabilities.CapabilityCheck{UserID: "user-1", FeatureKey: "pets:max"}); limited {
+		t.Fatalf("expected allow-all resolver to be unlimited")
+	}
+}
+
+func TestResolver_HasFeature_MapsCheckToHas(t *testing.T) {
+	t.Setenv("ALLOW_ALL_CAPABILITIES", "")
+
+	src := NewMemorySource(map[string]map[string]bool{
+		"user-1": {"pet:attachments:add": true},
+	})
+	// Se usa a través del port, como lo hacen los handlers.
+	var port capabilities.CapabilitiesResolver = NewResolver(src)
+	ctx := context.Background()
+
+	check := capabilities.CapabilityCheck{ProjectKey: "pet-clinical-history", TenantID: "t-1", UserID: "user-1", FeatureKey: "pet:attachments:add"}
+	if ok, err := port.HasFeature(ctx, check); err != nil || !ok {
+		t.Fatalf("expected user-1 to have the feature, got %v err=%v", ok, err)
+	}
+	check.UserID = "user-2"
+	if ok, err := port.HasFeature(ctx, check); err != nil || ok {
+		t.Fatalf("expected user-2 without the feature, got %v err=%v", ok, err)
+	}
+	if _, err := port.HasFeature(ctx, capabilities.CapabilityCheck{UserID: "user-1"}); err == nil {
+		t.Fatalf("expected error for empty FeatureKey")
+	}
+}
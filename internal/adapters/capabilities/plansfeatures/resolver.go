@@ -5,23 +5,117 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"sync"
+	"time"
+
+	"pet-clinical-history/internal/ports/capabilities"
+)
+
+const (
+	// DefaultCacheTTL es cuánto se reutiliza la respuesta de plans-features de un usuario.
+	DefaultCacheTTL = 30 * time.Second
+	// maxCacheEntries acota la memoria del cache ante muchos usuarios distintos.
+	maxCacheEntries = 10000
 )
 
-// Resolver es el componente que el motor usaría para decidir capabilities.
-// Aún no se integra a handlers; queda como esqueleto para el dev que conecte plans-features.
+// Source es lo que el Resolver necesita para obtener capabilities de un usuario.
+// Lo implementan Client (HTTP contra plans-features) y MemorySource (tests / dev local).
+type Source interface {
+	IsConfigured() bool
+	GetCapabilities(ctx context.Context, userID string) (CapabilitiesResponse, error)
+}
+
+// Resolver decide capabilities consultando plans-features (o un Source en memoria).
+// Implementa capabilities.CapabilitiesResolver; se inyecta vía router.Options.Capabilities.
 type Resolver struct {
-	client   *Client
+	client   Source
 	allowAll bool
+
+	// Cache por usuario de la respuesta completa (capabilities + cuotas); ttl <= 0 => sin cache.
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cachedCapabilities
+}
+
+type cachedCapabilities struct {
+	resp      CapabilitiesResponse
+	expiresAt time.Time
 }
 
-// NewResolver crea un resolver.
+// ResolverOption configura el Resolver.
+type ResolverOption func(*Resolver)
+
+// WithCacheTTL cambia el TTL del cache por usuario (0 => DefaultCacheTTL; < 0 => sin cache).
+func WithCacheTTL(ttl time.Duration) ResolverOption {
+	return func(r *Resolver) {
+		if ttl != 0 {
+			r.ttl = ttl
+		}
+	}
+}
+
+// Los handlers dependen de los ports, no de Resolver.
+var (
+	_ capabilities.CapabilitiesResolver = (*Resolver)(nil)
+	_ capabilities.QuotaResolver        = (*Resolver)(nil)
+)
+
+// NewResolver crea un resolver que cachea la respuesta de cada usuario por DefaultCacheTTL.
 // Si ALLOW_ALL_CAPABILITIES=true (env), todo devuelve true (modo dev / fallback).
-func NewResolver(client *Client) *Resolver {
+func NewResolver(client Source, opts ...ResolverOption) *Resolver {
 	allowAll := strings.EqualFold(strings.TrimSpace(os.Getenv("ALLOW_ALL_CAPABILITIES")), "true")
-	return &Resolver{
+	r := &Resolver{
 		client:   client,
 		allowAll: allowAll,
+		ttl:      DefaultCacheTTL,
+		now:      time.Now,
+		entries:  map[string]cachedCapabilities{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// fetch devuelve las capabilities de userID, desde el cache si siguen vigentes.
+// Los errores no se cachean. MVP: dos requests concurrentes del mismo usuario pueden ir ambos upstream.
+func (r *Resolver) fetch(ctx context.Context, userID string) (CapabilitiesResponse, error) {
+	if r == nil || r.client == nil || !r.client.IsConfigured() {
+		// Esqueleto: preferimos fallar explícito en vez de “permitir” sin control.
+		return CapabilitiesResponse{}, ErrPlansNotConfigured
+	}
+	if r.ttl <= 0 {
+		return r.client.GetCapabilities(ctx, userID)
+	}
+
+	now := r.now()
+	r.mu.Lock()
+	e, ok := r.entries[userID]
+	r.mu.Unlock()
+	if ok && now.Before(e.expiresAt) {
+		return e.resp, nil
+	}
+
+	resp, err := r.client.GetCapabilities(ctx, userID)
+	if err != nil {
+		return CapabilitiesResponse{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) >= maxCacheEntries {
+		for id, old := range r.entries {
+			if !now.Before(old.expiresAt) {
+				delete(r.entries, id)
+			}
+		}
+		if len(r.entries) >= maxCacheEntries {
+			r.entries = map[string]cachedCapabilities{}
+		}
 	}
+	r.entries[userID] = cachedCapabilities{resp: resp, expiresAt: now.Add(r.ttl)}
+	return resp, nil
 }
 
 // Has responde si userID tiene una capability.
@@ -36,12 +130,7 @@ func (r *Resolver) Has(ctx context.Context, userID string, capability string) (b
 		return true, nil
 	}
 
-	if r == nil || r.client == nil || !r.client.IsConfigured() {
-		// Esqueleto: preferimos fallar explícito en vez de “permitir” sin control.
-		return false, ErrPlansNotConfigured
-	}
-
-	resp, err := r.client.GetCapabilities(ctx, userID)
+	resp, err := r.fetch(ctx, userID)
 	if err != nil {
 		return false, err
 	}
@@ -49,15 +138,66 @@ func (r *Resolver) Has(ctx context.Context, userID string, capability string) (b
 	return resp.Capabilities[capability], nil
 }
 
+// HasAll responde varias capabilities de userID con una sola consulta a plans-features
+// (para handlers con varios gates). Si allowAll está activo, todas son true sin llamar a upstream.
+func (r *Resolver) HasAll(ctx context.Context, userID string, capabilities []string) (map[string]bool, error) {
+	keys := make([]string, 0, len(capabilities))
+	for _, c := range capabilities {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			return nil, errors.New("capability required")
+		}
+		keys = append(keys, c)
+	}
+
+	out := make(map[string]bool, len(keys))
+	if r.allowAll {
+		for _, c := range keys {
+			out[c] = true
+		}
+		return out, nil
+	}
+
+	resp, err := r.fetch(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range keys {
+		out[c] = resp.Capabilities[c]
+	}
+	return out, nil
+}
+
+// HasFeature implementa capabilities.CapabilitiesResolver sobre Has (capability = FeatureKey).
+// El plan se evalúa por in.UserID; ProjectKey y TenantID no cambian la consulta a plans-features.
+func (r *Resolver) HasFeature(ctx context.Context, in capabilities.CapabilityCheck) (bool, error) {
+	return r.Has(ctx, in.UserID, in.FeatureKey)
+}
+
+// Quota implementa capabilities.QuotaResolver: el límite del plan para in.FeatureKey.
+// Sin esa cuota en el plan (o con allowAll) no hay tope.
+func (r *Resolver) Quota(ctx context.Context, in capabilities.CapabilityCheck) (int, bool, error) {
+	key := strings.TrimSpace(in.FeatureKey)
+	if key == "" {
+		return 0, false, errors.New("quota key required")
+	}
+	if r.allowAll {
+		return 0, false, nil
+	}
+	resp, err := r.fetch(ctx, in.UserID)
+	if err != nil {
+		return 0, false, err
+	}
+	limit, ok := resp.Quotas[key]
+	return limit, ok, nil
+}
+
 // Resolve devuelve el mapa completo de capabilities para userID.
 func (r *Resolver) Resolve(ctx context.Context, userID string) (map[string]bool, error) {
 	if r.allowAll {
 		return map[string]bool{"*": true}, nil
 	}
-	if r == nil || r.client == nil || !r.client.IsConfigured() {
-		return nil, ErrPlansNotConfigured
-	}
-	resp, err := r.client.GetCapabilities(ctx, userID)
+	resp, err := r.fetch(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,83 @@
+package plansfeatures
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSource cuenta las consultas que llegan "upstream".
+type countingSource struct {
+	*MemorySource
+	calls atomic.Int32
+}
+
+func (c *countingSource) GetCapabilities(ctx context.Context, userID string) (CapabilitiesResponse, error) {
+	c.calls.Add(1)
+	return c.MemorySource.GetCapabilities(ctx, userID)
+}
+
+func TestResolver_HasAll_CachesPerUserWithinTTL(t *testing.T) {
+	t.Setenv("ALLOW_ALL_CAPABILITIES", "")
+
+	src := &countingSource{MemorySource: NewMemorySource(map[string]map[string]bool{
+		"user-1": {"events:void": true, "pets:export": false},
+	})}
+	r := NewResolver(src, WithCacheTTL(time.Minute))
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	got, err := r.HasAll(ctx, "user-1", []string{"events:void", "pets:export", "pet:attachments:add"})
+	if err != nil {
+		t.Fatalf("HasAll: unexpected error: %v", err)
+	}
+	if !got["events:void"] || got["pets:export"] || got["pet:attachments:add"] || len(got) != 3 {
+		t.Fatalf("unexpected HasAll result: %v", got)
+	}
+	if ok, _ := r.Has(ctx, "user-1", "events:void"); !ok {
+		t.Fatalf("expected events:void from cache")
+	}
+	if _, err := r.Resolve(ctx, "user-1"); err != nil {
+		t.Fatalf("Resolve: unexpected error: %v", err)
+	}
+	if n := src.calls.Load(); n != 1 {
+		t.Fatalf("expected 1 upstream call within TTL, got %d", n)
+	}
+
+	// Otro usuario tiene su propia entrada
+	if ok, _ := r.Has(ctx, "user-2", "events:void"); ok {
+		t.Fatalf("expected user-2 without events:void")
+	}
+	if n := src.calls.Load(); n != 2 {
+		t.Fatalf("expected 2 upstream calls after a new user, got %d", n)
+	}
+
+	// Vencido el TTL se vuelve a consultar y se ven los cambios
+	src.Set("user-1", "pets:export", true)
+	now = now.Add(time.Minute)
+	if ok, _ := r.Has(ctx, "user-1", "pets:export"); !ok {
+		t.Fatalf("expected refreshed capabilities after TTL")
+	}
+	if n := src.calls.Load(); n != 3 {
+		t.Fatalf("expected refetch after TTL, got %d calls", n)
+	}
+
+	if _, err := r.HasAll(ctx, "user-1", []string{"events:void", " "}); err == nil {
+		t.Fatalf("expected error for empty capability")
+	}
+}
+
+func TestResolver_HasAll_AllowAllSkipsUpstream(t *testing.T) {
+	t.Setenv("ALLOW_ALL_CAPABILITIES", "true")
+
+	src := &countingSource{MemorySource: NewMemorySource(nil)}
+	got, err := NewResolver(src).HasAll(context.Background(), "user-1", []string{"events:void", "pets:export"})
+	if err != nil || !got["events:void"] || !got["pets:export"] {
+		t.Fatalf("expected all true with allow-all, got %v err=%v", got, err)
+	}
+	if n := src.calls.Load(); n != 0 {
+		t.Fatalf("expected no upstream calls with allow-all, got %d", n)
+	}
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"pet-clinical-history/internal/platform/httpclient"
+	"pet-clinical-history/internal/ports/notifications"
+)
+
+const (
+	// SignatureHeader lleva "sha256=<hex>": HMAC-SHA256 del body con el secreto compartido.
+	SignatureHeader = "X-Webhook-Signature"
+	// EventHeader repite el tipo de evento para poder rutear sin parsear el body.
+	EventHeader = "X-Webhook-Event"
+)
+
+var ErrWebhookNotConfigured = errors.New("webhook notifier not configured")
+
+type Config struct {
+	URL    string
+	Secret string
+
+	Timeout time.Duration
+}
+
+// Notifier implementa notifications.GrantNotifier con un POST JSON firmado a Config.URL.
+type Notifier struct {
+	url    string
+	secret []byte
+	http   *httpclient.Client
+}
+
+func NewNotifier(cfg Config) *Notifier {
+	return &Notifier{
+		url:    strings.TrimSpace(cfg.URL),
+		secret: []byte(cfg.Secret),
+		http:   httpclient.New(cfg.Timeout),
+	}
+}
+
+func (n *Notifier) IsConfigured() bool {
+	return n != nil && n.url != ""
+}
+
+// grantEventPayload es el body que recibe el integrador.
+type grantEventPayload struct {
+	Type          string    `json:"type"`
+	GrantID       string    `json:"grant_id"`
+	PetID         string    `json:"pet_id"`
+	TenantID      string    `json:"tenant_id,omitempty"`
+	OwnerUserID   string    `json:"owner_user_id"`
+	GranteeUserID string    `json:"grantee_user_id"`
+	Scopes        []string  `json:"scopes"`
+	ActorUserID   string    `json:"actor_user_id"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+func (n *Notifier) Notify(ctx context.Context, event notifications.GrantEvent) error {
+	if !n.IsConfigured() {
+		return ErrWebhookNotConfigured
+	}
+
+	body, err := json.Marshal(grantEventPayload{
+		Type:          string(event.Type),
+		GrantID:       event.GrantID,
+		PetID:         event.PetID,
+		TenantID:      event.TenantID,
+		OwnerUserID:   event.OwnerUserID,
+		GranteeUserID: event.GranteeUserID,
+		Scopes:        event.Scopes,
+		ActorUserID:   event.ActorUserID,
+		OccurredAt:    event.OccurredAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: marshal payload: %w", err)
+	}
+
+	headers := map[string]string{EventHeader: string(event.Type)}
+	if len(n.secret) > 0 {
+		headers[SignatureHeader] = Sign(n.secret, body)
+	}
+	// RawMessage: se envían exactamente los bytes firmados.
+	return n.http.DoJSON(ctx, http.MethodPost, n.url, headers, json.RawMessage(body), nil)
+}
+
+// Sign devuelve el valor de SignatureHeader para body (el receptor lo recalcula para verificar).
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
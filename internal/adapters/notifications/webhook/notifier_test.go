@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/ports/notifications"
+)
+
+func TestNotifier_PostsSignedPayload(t *testing.T) {
+	var gotBody []byte
+	var gotSig, gotEvent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSig = r.Header.Get(SignatureHeader)
+		gotEvent = r.Header.Get(EventHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	n := NewNotifier(Config{URL: srv.URL, Secret: "s3cret"})
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := n.Notify(context.Background(), notifications.GrantEvent{
+		Type:          notifications.GrantAccepted,
+		GrantID:       "g-1",
+		PetID:         "p-1",
+		OwnerUserID:   "owner-1",
+		GranteeUserID: "vet-1",
+		Scopes:        []string{"pet:read"},
+		ActorUserID:   "vet-1",
+		OccurredAt:    at,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotEvent != "grant.accepted" {
+		t.Fatalf("expected event header grant.accepted, got %q", gotEvent)
+	}
+	if want := Sign([]byte("s3cret"), gotBody); gotSig != want {
+		t.Fatalf("signature mismatch: got %q want %q", gotSig, want)
+	}
+
+	var payload struct {
+		Type        string    `json:"type"`
+		GrantID     string    `json:"grant_id"`
+		ActorUserID string    `json:"actor_user_id"`
+		Scopes      []string  `json:"scopes"`
+		OccurredAt  time.Time `json:"occurred_at"`
+	}
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("decode payload: %v body=%s", err, string(gotBody))
+	}
+	if payload.Type != "grant.accepted" || payload.GrantID != "g-1" || payload.ActorUserID != "vet-1" ||
+		len(payload.Scopes) != 1 || !payload.OccurredAt.Equal(at) {
+		t.Fatalf("unexpected payload: %s", string(gotBody))
+	}
+}
+
+func TestNotifier_ErrorsAndNotConfigured(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if err := NewNotifier(Config{URL: srv.URL}).Notify(context.Background(), notifications.GrantEvent{Type: notifications.GrantRevoked}); err == nil {
+		t.Fatalf("expected error on 500")
+	}
+	if err := NewNotifier(Config{}).Notify(context.Background(), notifications.GrantEvent{}); !errors.Is(err, ErrWebhookNotConfigured) {
+		t.Fatalf("expected ErrWebhookNotConfigured, got %v", err)
+	}
+}
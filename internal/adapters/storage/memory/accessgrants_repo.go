@@ -3,7 +3,10 @@ package memory
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"pet-clinical-history/internal/domain/accessgrants"
 )
@@ -40,31 +43,47 @@ func (r *grantRepo) Update(ctx context.Context, g accessgrants.Grant) error {
 	if g.ID == "" {
 		return errors.New("grant id required")
 	}
-	if _, exists := r.byID[g.ID]; !exists {
+	prev, exists := r.byID[g.ID]
+	if !exists {
 		return ErrNotFound
 	}
+	// LastUsedAt lo maneja MarkUsed (igual que en postgres)
+	g.LastUsedAt = prev.LastUsedAt
 	r.byID[g.ID] = g
 	return nil
 }
 
-func (r *grantRepo) GetByID(ctx context.Context, id string) (accessgrants.Grant, error) {
+func (r *grantRepo) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, exists := r.byID[id]
+	if !exists {
+		return ErrNotFound
+	}
+	g.LastUsedAt = &at
+	r.byID[id] = g
+	return nil
+}
+
+func (r *grantRepo) GetByID(ctx context.Context, tenantID, id string) (accessgrants.Grant, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	g, ok := r.byID[id]
-	if !ok {
+	if !ok || g.TenantID != tenantID {
 		return accessgrants.Grant{}, ErrNotFound
 	}
 	return g, nil
 }
 
-func (r *grantRepo) ListByPet(ctx context.Context, petID string) ([]accessgrants.Grant, error) {
+func (r *grantRepo) ListByPet(ctx context.Context, tenantID, petID string) ([]accessgrants.Grant, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	out := make([]accessgrants.Grant, 0)
 	for _, g := range r.byID {
-		if g.PetID == petID {
+		if g.PetID == petID && g.TenantID == tenantID {
 			out = append(out, g)
 		}
 	}
@@ -73,7 +92,7 @@ func (r *grantRepo) ListByPet(ctx context.Context, petID string) ([]accessgrants
 
 // Defensivo: si por data sucia existieran múltiples grants activos,
 // devolvemos el más reciente por UpdatedAt (y en empate, por CreatedAt).
-func (r *grantRepo) GetActiveGrant(ctx context.Context, petID, granteeUserID string) (accessgrants.Grant, error) {
+func (r *grantRepo) GetActiveGrant(ctx context.Context, tenantID, petID, granteeUserID string) (accessgrants.Grant, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -81,7 +100,7 @@ func (r *grantRepo) GetActiveGrant(ctx context.Context, petID, granteeUserID str
 	has := false
 
 	for _, g := range r.byID {
-		if g.PetID != petID {
+		if g.PetID != petID || g.TenantID != tenantID {
 			continue
 		}
 		if g.GranteeUserID != granteeUserID {
@@ -115,13 +134,45 @@ func (r *grantRepo) GetActiveGrant(ctx context.Context, petID, granteeUserID str
 	return winner, nil
 }
 
-func (r *grantRepo) ListByGrantee(ctx context.Context, granteeUserID string) ([]accessgrants.Grant, error) {
+func (r *grantRepo) ListByGrantee(ctx context.Context, tenantID, granteeUserID string, filter accessgrants.GranteeFilter) ([]accessgrants.Grant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]accessgrants.Grant, 0)
+	for _, g := range r.byID {
+		if g.GranteeUserID != granteeUserID || g.TenantID != tenantID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, g.Status) {
+			continue
+		}
+		if filter.Cursor != nil && !filter.Cursor.After(g) {
+			continue
+		}
+		out = append(out, g)
+	}
+
+	// Mismo orden que Postgres (updated_at desc, id desc): el map no tiene orden y el
+	// keyset necesita uno total para no repetir ni saltear grants entre páginas.
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].UpdatedAt.Equal(out[j].UpdatedAt) {
+			return out[i].UpdatedAt.After(out[j].UpdatedAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+func (r *grantRepo) ListInvitesExpiredBefore(ctx context.Context, before time.Time) ([]accessgrants.Grant, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	out := make([]accessgrants.Grant, 0)
 	for _, g := range r.byID {
-		if g.GranteeUserID == granteeUserID {
+		if g.InviteLapsedAt(before) {
 			out = append(out, g)
 		}
 	}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"pet-clinical-history/internal/domain/accesslog"
+)
+
+type accessLogRepo struct {
+	mu    sync.RWMutex
+	items []accesslog.Entry
+}
+
+func NewAccessLogRepo() accesslog.Repository {
+	return &accessLogRepo{}
+}
+
+func (r *accessLogRepo) Create(ctx context.Context, e accesslog.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e.ID == "" {
+		return errors.New("access log entry id required")
+	}
+	r.items = append(r.items, e)
+	return nil
+}
+
+func (r *accessLogRepo) ListByPet(ctx context.Context, petID string, limit int) ([]accesslog.Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]accesslog.Entry, 0)
+	for _, e := range r.items {
+		if e.PetID == petID {
+			out = append(out, e)
+		}
+	}
+
+	// Más reciente primero
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].At.After(out[j].At)
+	})
+
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
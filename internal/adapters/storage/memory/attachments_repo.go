@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type attachmentsRepo struct {
+	mu      sync.RWMutex
+	byEvent map[string][]details.Attachment // en orden de inserción (= created_at)
+}
+
+func NewAttachmentsRepo() events.AttachmentRepository {
+	return &attachmentsRepo{
+		byEvent: make(map[string][]details.Attachment),
+	}
+}
+
+func (r *attachmentsRepo) Create(ctx context.Context, a details.Attachment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if a.EventID == "" {
+		return errors.New("attachment event id required")
+	}
+	r.byEvent[a.EventID] = append(r.byEvent[a.EventID], a)
+	return nil
+}
+
+func (r *attachmentsRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string][]details.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string][]details.Attachment, len(eventIDs))
+	for _, id := range eventIDs {
+		if items, ok := r.byEvent[id]; ok {
+			out[id] = append([]details.Attachment(nil), items...)
+		}
+	}
+	return out, nil
+}
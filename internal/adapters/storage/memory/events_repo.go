@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"pet-clinical-history/internal/domain/events"
 )
@@ -57,8 +58,45 @@ func (r *eventRepo) ListByPet(ctx context.Context, petID string, filter events.L
 		limit = 50
 	}
 
+	out := r.filterByPet(petID, filter)
+	if len(out) > limit {
+		out = out[:limit]
+	}
+
+	return out, nil
+}
+
+func (r *eventRepo) StreamByPet(ctx context.Context, petID string, filter events.ListFilter, fn func(events.PetEvent) error) error {
+	// Snapshot bajo lock: fn puede ser lento (escribe al cliente) y no debe bloquear escrituras.
+	r.mu.RLock()
+	items := r.filterByPet(petID, filter)
+	r.mu.RUnlock()
+
+	if filter.Limit > 0 && len(items) > filter.Limit {
+		items = items[:filter.Limit]
+	}
+
+	for _, e := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterByPet aplica los filtros de ListFilter y ordena. Requiere r.mu tomado.
+func (r *eventRepo) filterByPet(petID string, filter events.ListFilter) []events.PetEvent {
 	out := make([]events.PetEvent, 0)
 
+	rank := filter.Rank && strings.TrimSpace(filter.Query) != ""
+	var terms []string
+	if rank {
+		terms = searchTerms(filter.Query)
+	}
+
 	for _, e := range r.byID {
 		if e.PetID != petID {
 			continue
@@ -90,8 +128,36 @@ func (r *eventRepo) ListByPet(ctx context.Context, petID string, filter events.L
 			}
 		}
 
-		// Query filter
-		if q := strings.TrimSpace(filter.Query); q != "" {
+		// Visibilidad: los delegados no ven eventos privados
+		if filter.ExcludePrivate && e.Visibility == events.VisibilityPrivate {
+			continue
+		}
+
+		// Estado / actor
+		if filter.Status != nil {
+			if e.Status != *filter.Status {
+				continue
+			}
+		} else if !filter.IncludeVoided && e.Status != events.EventStatusActive {
+			continue
+		}
+		if filter.ActorID != "" && e.Actor.ID != filter.ActorID {
+			continue
+		}
+
+		// Cursor (keyset)
+		if filter.Cursor != nil && !filter.Cursor.After(e, filter.Order) {
+			continue
+		}
+
+		// Query filter (con Rank: todos los términos, como plainto_tsquery en postgres)
+		if rank {
+			m, ok := matchTerms(e.Title+" "+e.Notes, terms)
+			if !ok {
+				continue
+			}
+			e.Match = &m
+		} else if q := strings.TrimSpace(filter.Query); q != "" {
 			hay := strings.ToLower(e.Title + " " + e.Notes)
 			if !strings.Contains(hay, strings.ToLower(q)) {
 				continue
@@ -101,19 +167,119 @@ func (r *eventRepo) ListByPet(ctx context.Context, petID string, filter events.L
 		out = append(out, e)
 	}
 
-	// Orden por occurred_at desc (más reciente primero)
+	// Orden por occurred_at (desc por defecto: más reciente primero), id para desempatar (igual que postgres)
+	asc := filter.Order == events.OrderAsc
 	sort.Slice(out, func(i, j int) bool {
-		return out[i].OccurredAt.After(out[j].OccurredAt)
+		if rank && out[i].Match.Rank != out[j].Match.Rank {
+			return out[i].Match.Rank > out[j].Match.Rank
+		}
+		if !out[i].OccurredAt.Equal(out[j].OccurredAt) {
+			return out[i].OccurredAt.After(out[j].OccurredAt) != asc
+		}
+		return (out[i].ID > out[j].ID) != asc
 	})
 
-	if len(out) > limit {
-		out = out[:limit]
+	return out
+}
+
+func (r *eventRepo) CountTypesByPet(ctx context.Context, petID string, filter events.ListFilter) ([]events.TypeCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := map[events.EventType]int{}
+	for _, e := range r.byID {
+		if e.PetID != petID {
+			continue
+		}
+		if !filter.IncludeVoided && e.Status != events.EventStatusActive {
+			continue
+		}
+		// Visibilidad: los delegados no ven eventos privados
+		if filter.ExcludePrivate && e.Visibility == events.VisibilityPrivate {
+			continue
+		}
+		counts[e.Type]++
 	}
 
+	out := make([]events.TypeCount, 0, len(counts))
+	for t, n := range counts {
+		out = append(out, events.TypeCount{Type: t, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Type < out[j].Type
+	})
 	return out, nil
 }
 
-func (r *eventRepo) Void(ctx context.Context, id string) error {
+func (r *eventRepo) SummarizeByPet(ctx context.Context, petID string, excludePrivate bool) ([]events.TypeSummary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byType := map[events.EventType]*events.TypeSummary{}
+	for _, e := range r.byID {
+		if e.PetID != petID || e.Status != events.EventStatusActive {
+			continue
+		}
+		// Visibilidad: los delegados no ven eventos privados
+		if excludePrivate && e.Visibility == events.VisibilityPrivate {
+			continue
+		}
+		ts, ok := byType[e.Type]
+		if !ok {
+			ts = &events.TypeSummary{Type: e.Type}
+			byType[e.Type] = ts
+		}
+		ts.Count++
+		if e.OccurredAt.After(ts.LastOccurredAt) {
+			ts.LastOccurredAt = e.OccurredAt
+		}
+	}
+
+	out := make([]events.TypeSummary, 0, len(byType))
+	for _, ts := range byType {
+		out = append(out, *ts)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Type < out[j].Type
+	})
+	return out, nil
+}
+
+func (r *eventRepo) CountActiveByPet(ctx context.Context, petID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := 0
+	for _, e := range r.byID {
+		if e.PetID == petID && e.Status == events.EventStatusActive {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *eventRepo) LastOccurredByPets(ctx context.Context, petIDs []string) (map[string]time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]struct{}, len(petIDs))
+	for _, id := range petIDs {
+		wanted[id] = struct{}{}
+	}
+
+	out := map[string]time.Time{}
+	for _, e := range r.byID {
+		if _, ok := wanted[e.PetID]; !ok || e.Status != events.EventStatusActive {
+			continue
+		}
+		if last, ok := out[e.PetID]; !ok || e.OccurredAt.After(last) {
+			out[e.PetID] = e.OccurredAt
+		}
+	}
+	return out, nil
+}
+
+func (r *eventRepo) Void(ctx context.Context, id string, audit events.VoidAudit) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -121,7 +287,111 @@ func (r *eventRepo) Void(ctx context.Context, id string) error {
 	if !ok {
 		return ErrNotFound
 	}
+	// Un reintento sobre un evento ya anulado conserva la auditoría original.
+	if e.Status != events.EventStatusVoided {
+		setVoided(&e, audit)
+	}
+	r.byID[id] = e
+	return nil
+}
+
+// setVoided anula e y registra la auditoría del void.
+func setVoided(e *events.PetEvent, audit events.VoidAudit) {
+	at := audit.At
 	e.Status = events.EventStatusVoided
+	e.VoidedBy = audit.By
+	e.VoidedByEmail = audit.ByEmail
+	e.VoidedAt = &at
+	e.VoidReason = audit.Reason
+}
+
+func (r *eventRepo) VoidIfActive(ctx context.Context, id string, audit events.VoidAudit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.byID[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if e.Status != events.EventStatusActive {
+		return events.ErrAlreadyVoided
+	}
+	setVoided(&e, audit)
 	r.byID[id] = e
 	return nil
 }
+
+func (r *eventRepo) Restore(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.byID[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if e.Status != events.EventStatusVoided {
+		return events.ErrNotVoided
+	}
+	e.Status = events.EventStatusActive
+	e.VoidedBy, e.VoidedByEmail, e.VoidedAt, e.VoidReason = "", "", nil, ""
+	r.byID[id] = e
+	return nil
+}
+
+// snippetRadius es cuántas palabras de contexto rodean al primer término en el snippet.
+const snippetRadius = 8
+
+// searchTerms separa q en términos en minúscula (letras y dígitos), sin repetir.
+func searchTerms(q string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range strings.FieldsFunc(strings.ToLower(q), isNotWordRune) {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// matchTerms es el fallback de ts_rank / ts_headline: el texto matchea si contiene todas las
+// palabras de terms; Rank es la cantidad de apariciones (frecuencia de términos) y el snippet
+// marca las palabras encontradas alrededor de la primera.
+func matchTerms(text string, terms []string) (events.SearchMatch, bool) {
+	if len(terms) == 0 {
+		return events.SearchMatch{}, false
+	}
+	words := strings.Fields(text)
+	hits := map[string]int{}
+	first := -1
+	marked := make([]string, len(words))
+	for i, w := range words {
+		marked[i] = w
+		for _, tok := range strings.FieldsFunc(strings.ToLower(w), isNotWordRune) {
+			for _, t := range terms {
+				if tok == t {
+					hits[t]++
+					marked[i] = events.SnippetStart + w + events.SnippetStop
+					if first < 0 {
+						first = i
+					}
+				}
+			}
+		}
+	}
+
+	total := 0
+	for _, t := range terms {
+		if hits[t] == 0 {
+			return events.SearchMatch{}, false
+		}
+		total += hits[t]
+	}
+
+	from, to := max(first-snippetRadius, 0), min(first+snippetRadius+1, len(words))
+	return events.SearchMatch{Rank: float64(total), Snippet: strings.Join(marked[from:to], " ")}, true
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
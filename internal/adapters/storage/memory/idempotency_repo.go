@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"pet-clinical-history/internal/domain/idempotency"
+)
+
+type idempotencyKey struct {
+	scope, userID, key string
+}
+
+type idempotencyRepo struct {
+	mu    sync.RWMutex
+	byKey map[idempotencyKey]idempotency.Record
+}
+
+func NewIdempotencyRepo() idempotency.Repository {
+	return &idempotencyRepo{
+		byKey: make(map[idempotencyKey]idempotency.Record),
+	}
+}
+
+func (r *idempotencyRepo) Get(ctx context.Context, scope, userID, key string) (idempotency.Record, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rec, ok := r.byKey[idempotencyKey{scope, userID, key}]
+	return rec, ok, nil
+}
+
+func (r *idempotencyRepo) Put(ctx context.Context, rec idempotency.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rec.Key == "" || rec.ResourceID == "" {
+		return errors.New("idempotency key and resource id required")
+	}
+	r.byKey[idempotencyKey{rec.Scope, rec.UserID, rec.Key}] = rec
+	return nil
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type measurementsRepo struct {
+	mu      sync.RWMutex
+	byEvent map[string]details.Measurement
+}
+
+func NewMeasurementsRepo() events.MeasurementRepository {
+	return &measurementsRepo{
+		byEvent: make(map[string]details.Measurement),
+	}
+}
+
+func (r *measurementsRepo) Create(ctx context.Context, d details.Measurement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if d.EventID == "" {
+		return errors.New("measurement event id required")
+	}
+	if _, exists := r.byEvent[d.EventID]; exists {
+		return errors.New("measurement already exists")
+	}
+	r.byEvent[d.EventID] = d
+	return nil
+}
+
+func (r *measurementsRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Measurement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]details.Measurement, len(eventIDs))
+	for _, id := range eventIDs {
+		if d, ok := r.byEvent[id]; ok {
+			out[id] = d
+		}
+	}
+	return out, nil
+}
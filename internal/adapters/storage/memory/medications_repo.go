@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type medicationsRepo struct {
+	mu      sync.RWMutex
+	byEvent map[string]details.Medication
+
+	// events resuelve pet/status/visibilidad del evento (equivalente al JOIN de Postgres).
+	events events.Repository
+}
+
+func NewMedicationsRepo(eventsRepo events.Repository) events.MedicationRepository {
+	return &medicationsRepo{
+		byEvent: make(map[string]details.Medication),
+		events:  eventsRepo,
+	}
+}
+
+func (r *medicationsRepo) Create(ctx context.Context, d details.Medication) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if d.EventID == "" {
+		return errors.New("medication event id required")
+	}
+	if _, exists := r.byEvent[d.EventID]; exists {
+		return errors.New("medication already exists")
+	}
+	r.byEvent[d.EventID] = d
+	return nil
+}
+
+func (r *medicationsRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Medication, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]details.Medication, len(eventIDs))
+	for _, id := range eventIDs {
+		if d, ok := r.byEvent[id]; ok {
+			out[id] = d
+		}
+	}
+	return out, nil
+}
+
+func (r *medicationsRepo) ListActive(ctx context.Context, petID string, at time.Time, excludePrivate bool) ([]details.Medication, error) {
+	// Snapshot para no consultar el repo de eventos con el lock tomado.
+	r.mu.RLock()
+	candidates := make([]details.Medication, 0)
+	for _, d := range r.byEvent {
+		if d.EndDate != nil && !d.EndDate.After(at) {
+			continue
+		}
+		candidates = append(candidates, d)
+	}
+	r.mu.RUnlock()
+
+	out := make([]details.Medication, 0, len(candidates))
+	for _, d := range candidates {
+		e, err := r.events.GetByID(ctx, d.EventID)
+		if err != nil {
+			continue
+		}
+		if e.PetID != petID || e.Status != events.EventStatusActive {
+			continue
+		}
+		if excludePrivate && e.Visibility == events.VisibilityPrivate {
+			continue
+		}
+		out = append(out, d)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].StartDate.After(out[j].StartDate)
+	})
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/domain/pets"
+)
+
+type mergeStore struct {
+	pets        *petRepo
+	events      *eventRepo
+	grants      *grantRepo
+	attachments *attachmentsRepo
+}
+
+// NewMergeStore arma el MergeStore sobre los repos in-memory (deben ser los de este paquete).
+// Toma los locks de los cuatro repos para que el merge sea atómico respecto de otras operaciones.
+func NewMergeStore(petsRepo pets.Repository, eventsRepo events.Repository, grantsRepo accessgrants.Repository, attachmentRepo events.AttachmentRepository) pets.MergeStore {
+	p, _ := petsRepo.(*petRepo)
+	e, _ := eventsRepo.(*eventRepo)
+	g, _ := grantsRepo.(*grantRepo)
+	a, _ := attachmentRepo.(*attachmentsRepo)
+	return &mergeStore{pets: p, events: e, grants: g, attachments: a}
+}
+
+func (m *mergeStore) MergePets(ctx context.Context, sourceID, targetID string, at time.Time) (pets.MergeResult, error) {
+	if m.pets == nil || m.events == nil || m.grants == nil || m.attachments == nil {
+		return pets.MergeResult{}, errors.New("merge store requires memory repos")
+	}
+
+	m.pets.mu.Lock()
+	defer m.pets.mu.Unlock()
+	m.events.mu.Lock()
+	defer m.events.mu.Unlock()
+	m.grants.mu.Lock()
+	defer m.grants.mu.Unlock()
+	m.attachments.mu.Lock()
+	defer m.attachments.mu.Unlock()
+
+	source, ok := m.pets.byID[sourceID]
+	if !ok || source.ArchivedAt != nil {
+		return pets.MergeResult{}, ErrNotFound
+	}
+	if _, ok := m.pets.byID[targetID]; !ok {
+		return pets.MergeResult{}, ErrNotFound
+	}
+
+	var res pets.MergeResult
+	for id, e := range m.events.byID {
+		if e.PetID != sourceID {
+			continue
+		}
+		e.PetID = targetID
+		m.events.byID[id] = e
+		res.EventsMoved++
+	}
+	for eventID, items := range m.attachments.byEvent {
+		for i := range items {
+			if items[i].PetID == sourceID {
+				items[i].PetID = targetID
+			}
+		}
+		m.attachments.byEvent[eventID] = items
+	}
+	var sourceGrants, targetGrants []accessgrants.Grant
+	for _, g := range m.grants.byID {
+		switch g.PetID {
+		case sourceID:
+			sourceGrants = append(sourceGrants, g)
+		case targetID:
+			targetGrants = append(targetGrants, g)
+		}
+	}
+	plan := accessgrants.PlanMerge(sourceGrants, targetGrants)
+	for _, id := range plan.Move {
+		g := m.grants.byID[id]
+		g.PetID = targetID
+		g.UpdatedAt = at
+		m.grants.byID[id] = g
+		res.GrantsMoved++
+	}
+	for _, id := range plan.Revoke {
+		g := m.grants.byID[id]
+		g.Status = accessgrants.StatusRevoked
+		g.UpdatedAt = at
+		g.RevokedAt = &at
+		m.grants.byID[id] = g
+		res.GrantsRevoked++
+	}
+
+	source.ArchivedAt = &at
+	source.UpdatedAt = at
+	m.pets.byID[sourceID] = source
+
+	return res, nil
+}
@@ -35,6 +35,9 @@ func (r *petRepo) Create(ctx context.Context, p pets.Pet) error {
 	if _, exists := r.byID[p.ID]; exists {
 		return errors.New("pet already exists")
 	}
+	if r.microchipTaken(p) {
+		return pets.ErrMicrochipTaken
+	}
 	r.byID[p.ID] = p
 	return nil
 }
@@ -49,36 +52,135 @@ func (r *petRepo) Update(ctx context.Context, p pets.Pet) error {
 	if _, exists := r.byID[p.ID]; !exists {
 		return ErrNotFound
 	}
+	if r.microchipTaken(p) {
+		return pets.ErrMicrochipTaken
+	}
 	r.byID[p.ID] = p
 	return nil
 }
 
-func (r *petRepo) GetByID(ctx context.Context, id string) (pets.Pet, error) {
+func (r *petRepo) GetByID(ctx context.Context, tenantID, id string) (pets.Pet, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	p, ok := r.byID[id]
-	if !ok {
+	if !ok || p.TenantID != tenantID {
 		return pets.Pet{}, ErrNotFound
 	}
 	return p, nil
 }
 
-func (r *petRepo) ListByOwner(ctx context.Context, ownerUserID string) ([]pets.Pet, error) {
+func (r *petRepo) ListByOwner(ctx context.Context, tenantID, ownerUserID string, filter pets.ListFilter) ([]pets.Pet, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	// Mismo criterio que el ILIKE de postgres.
+	query := strings.ToLower(filter.Query)
+
 	out := make([]pets.Pet, 0)
 	for _, p := range r.byID {
-		if p.OwnerUserID == ownerUserID {
-			out = append(out, p)
+		if p.OwnerUserID != ownerUserID || p.TenantID != tenantID || p.ArchivedAt != nil {
+			continue
+		}
+		if !filter.IncludeArchived && p.Status == pets.PetStatusArchived {
+			continue
+		}
+		if filter.Species != "" && p.Species != filter.Species {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(p.Name), query) {
+			continue
 		}
+		out = append(out, p)
 	}
 
-	// Orden estable por created_at asc (solo para consistencia en dev)
+	// Mismo orden que postgres: campo pedido (default created_at) y id como desempate.
 	sort.Slice(out, func(i, j int) bool {
-		return out[i].CreatedAt.Before(out[j].CreatedAt)
+		a, b := out[i], out[j]
+		if filter.Desc {
+			a, b = b, a
+		}
+		switch filter.Sort {
+		case pets.SortName:
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		case pets.SortUpdatedAt:
+			if !a.UpdatedAt.Equal(b.UpdatedAt) {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			}
+		default:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		}
+		return a.ID < b.ID
 	})
 
+	if filter.Offset > 0 {
+		if filter.Offset >= len(out) {
+			return []pets.Pet{}, nil
+		}
+		out = out[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(out) {
+		out = out[:filter.Limit]
+	}
 	return out, nil
 }
+
+// Delete quita la mascota; los eventos y grants (en sus propios repos) se conservan.
+func (r *petRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byID[id]; !exists {
+		return ErrNotFound
+	}
+	delete(r.byID, id)
+	return nil
+}
+
+func (r *petRepo) ExistsByMicrochip(ctx context.Context, microchip string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if strings.TrimSpace(microchip) == "" {
+		return false, nil
+	}
+	for _, p := range r.byID {
+		if p.Microchip == microchip {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *petRepo) GetByMicrochip(ctx context.Context, tenantID, microchip string) (pets.Pet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if strings.TrimSpace(microchip) == "" {
+		return pets.Pet{}, ErrNotFound
+	}
+	for _, p := range r.byID {
+		if p.Microchip == microchip && p.TenantID == tenantID {
+			return p, nil
+		}
+	}
+	return pets.Pet{}, ErrNotFound
+}
+
+// microchipTaken indica si otra mascota ya tiene el microchip de p (mismo criterio que el
+// índice único de postgres). Requiere r.mu tomado.
+func (r *petRepo) microchipTaken(p pets.Pet) bool {
+	if p.Microchip == "" {
+		return false
+	}
+	for id, other := range r.byID {
+		if id != p.ID && other.Microchip == p.Microchip {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type vaccinesRepo struct {
+	mu      sync.RWMutex
+	byEvent map[string]details.Vaccine
+
+	// events resuelve pet/tipo/status del evento (equivalente al JOIN de Postgres).
+	events events.Repository
+}
+
+func NewVaccinesRepo(eventsRepo events.Repository) events.VaccineRepository {
+	return &vaccinesRepo{
+		byEvent: make(map[string]details.Vaccine),
+		events:  eventsRepo,
+	}
+}
+
+func (r *vaccinesRepo) Create(ctx context.Context, d details.Vaccine) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if d.EventID == "" {
+		return errors.New("vaccine event id required")
+	}
+	if _, exists := r.byEvent[d.EventID]; exists {
+		return errors.New("vaccine detail already exists")
+	}
+	r.byEvent[d.EventID] = d
+	return nil
+}
+
+func (r *vaccinesRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Vaccine, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]details.Vaccine, len(eventIDs))
+	for _, id := range eventIDs {
+		if d, ok := r.byEvent[id]; ok {
+			out[id] = d
+		}
+	}
+	return out, nil
+}
+
+func (r *vaccinesRepo) ListDue(ctx context.Context, petIDs []string, from, to time.Time, excludePrivate bool) ([]events.DueItem, error) {
+	wanted := make(map[string]struct{}, len(petIDs))
+	for _, id := range petIDs {
+		wanted[id] = struct{}{}
+	}
+
+	// Snapshot para no consultar el repo de eventos con el lock tomado.
+	r.mu.RLock()
+	candidates := make([]details.Vaccine, 0)
+	for _, d := range r.byEvent {
+		if d.NextDue == nil || d.NextDue.Before(from) || d.NextDue.After(to) {
+			continue
+		}
+		candidates = append(candidates, d)
+	}
+	r.mu.RUnlock()
+
+	out := make([]events.DueItem, 0, len(candidates))
+	for _, d := range candidates {
+		e, err := r.events.GetByID(ctx, d.EventID)
+		if err != nil {
+			continue
+		}
+		if _, ok := wanted[e.PetID]; !ok {
+			continue
+		}
+		if e.Status != events.EventStatusActive {
+			continue
+		}
+		if excludePrivate && e.Visibility == events.VisibilityPrivate {
+			continue
+		}
+		out = append(out, events.DueItem{
+			PetID:     e.PetID,
+			EventID:   e.ID,
+			EventType: e.Type,
+			Kind:      events.DueKindVaccine,
+			Label:     d.Name,
+			DueAt:     *d.NextDue,
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].DueAt.Before(out[j].DueAt)
+	})
+	return out, nil
+}
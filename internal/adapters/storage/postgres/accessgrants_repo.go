@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strings"
 	"time"
 
@@ -10,34 +11,100 @@ import (
 )
 
 type AccessGrantsRepo struct {
-	db *sql.DB
+	db  *sql.DB
+	cfg repoConfig
 }
 
-func NewAccessGrantsRepo(db *sql.DB) *AccessGrantsRepo {
-	return &AccessGrantsRepo{db: db}
+func NewAccessGrantsRepo(db *sql.DB, opts ...RepoOption) *AccessGrantsRepo {
+	return &AccessGrantsRepo{db: db, cfg: newRepoConfig(opts)}
+}
+
+// grantColumns mantiene el mismo orden que scanGrant.
+const grantColumns = `
+			id, pet_id, owner_user_id, grantee_user_id, tenant_id,
+			scopes, status,
+			created_at, updated_at, revoked_at,
+			delegated_by_user_id, parent_grant_id,
+			expires_at, message, last_used_at,
+			invite_expires_at`
+
+func scanGrant(row rowScanner) (accessgrants.Grant, error) {
+	var g accessgrants.Grant
+	var status string
+	var scopes []string
+	var revokedAt sql.NullTime
+	var expiresAt sql.NullTime
+	var lastUsedAt sql.NullTime
+	var inviteExpiresAt sql.NullTime
+
+	if err := row.Scan(
+		&g.ID,
+		&g.PetID,
+		&g.OwnerUserID,
+		&g.GranteeUserID,
+		&g.TenantID,
+		&scopes,
+		&status,
+		&g.CreatedAt,
+		&g.UpdatedAt,
+		&revokedAt,
+		&g.DelegatedByUserID,
+		&g.ParentGrantID,
+		&expiresAt,
+		&g.Message,
+		&lastUsedAt,
+		&inviteExpiresAt,
+	); err != nil {
+		return accessgrants.Grant{}, err
+	}
+
+	g.Status = accessgrants.Status(status)
+	g.Scopes = textArrayToScopes(scopes)
+	if revokedAt.Valid {
+		t := revokedAt.Time
+		g.RevokedAt = &t
+	}
+	if expiresAt.Valid {
+		t := expiresAt.Time
+		g.ExpiresAt = &t
+	}
+	if lastUsedAt.Valid {
+		t := lastUsedAt.Time
+		g.LastUsedAt = &t
+	}
+	if inviteExpiresAt.Valid {
+		t := inviteExpiresAt.Time
+		g.InviteExpiresAt = &t
+	}
+	return g, nil
 }
 
 func (r *AccessGrantsRepo) Create(ctx context.Context, g accessgrants.Grant) error {
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO access_grants (
-			id, pet_id, owner_user_id, grantee_user_id,
-			scopes, status,
-			created_at, updated_at, revoked_at
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+		INSERT INTO access_grants (`+grantColumns+`
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)
 	`,
 		g.ID,
 		g.PetID,
 		g.OwnerUserID,
 		g.GranteeUserID,
+		g.TenantID,
 		scopesToTextArray(g.Scopes),
 		string(g.Status),
 		g.CreatedAt,
 		g.UpdatedAt,
 		toNullTime(g.RevokedAt),
+		g.DelegatedByUserID,
+		g.ParentGrantID,
+		toNullTime(g.ExpiresAt),
+		g.Message,
+		toNullTime(g.LastUsedAt),
+		toNullTime(g.InviteExpiresAt),
 	)
 	return err
 }
 
+// Update no toca last_used_at: lo escribe solo MarkUsed.
 func (r *AccessGrantsRepo) Update(ctx context.Context, g accessgrants.Grant) error {
 	res, err := r.db.ExecContext(ctx, `
 		UPDATE access_grants
@@ -45,7 +112,13 @@ func (r *AccessGrantsRepo) Update(ctx context.Context, g accessgrants.Grant) err
 			scopes = $2,
 			status = $3,
 			updated_at = $4,
-			revoked_at = $5
+			revoked_at = $5,
+			delegated_by_user_id = $6,
+			parent_grant_id = $7,
+			expires_at = $8,
+			owner_user_id = $9,
+			message = $10,
+			invite_expires_at = $11
 		WHERE id = $1
 	`,
 		g.ID,
@@ -53,6 +126,12 @@ func (r *AccessGrantsRepo) Update(ctx context.Context, g accessgrants.Grant) err
 		string(g.Status),
 		g.UpdatedAt,
 		toNullTime(g.RevokedAt),
+		g.DelegatedByUserID,
+		g.ParentGrantID,
+		toNullTime(g.ExpiresAt),
+		g.OwnerUserID,
+		g.Message,
+		toNullTime(g.InviteExpiresAt),
 	)
 	if err != nil {
 		return err
@@ -64,207 +143,166 @@ func (r *AccessGrantsRepo) Update(ctx context.Context, g accessgrants.Grant) err
 	return nil
 }
 
-func (r *AccessGrantsRepo) GetByID(ctx context.Context, id string) (accessgrants.Grant, error) {
+func (r *AccessGrantsRepo) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE access_grants
+		SET last_used_at = $2
+		WHERE id = $1
+	`, id, at)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *AccessGrantsRepo) GetByID(ctx context.Context, tenantID, id string) (accessgrants.Grant, error) {
 	id = strings.TrimSpace(id)
 	if id == "" {
 		return accessgrants.Grant{}, ErrNotFound
 	}
 
-	row := r.db.QueryRowContext(ctx, `
-		SELECT
-			id, pet_id, owner_user_id, grantee_user_id,
-			scopes, status,
-			created_at, updated_at, revoked_at
-		FROM access_grants
-		WHERE id = $1
-	`, id)
-
-	var g accessgrants.Grant
-	var status string
-	var scopes []string
-	var revokedAt sql.NullTime
-
-	if err := row.Scan(
-		&g.ID,
-		&g.PetID,
-		&g.OwnerUserID,
-		&g.GranteeUserID,
-		&scopes,
-		&status,
-		&g.CreatedAt,
-		&g.UpdatedAt,
-		&revokedAt,
-	); err != nil {
-		if err == sql.ErrNoRows {
-			return accessgrants.Grant{}, ErrNotFound
+	return retryRead(ctx, r.cfg.readRetries, func() (accessgrants.Grant, error) {
+		row := r.db.QueryRowContext(ctx, `
+			SELECT`+grantColumns+`
+			FROM access_grants
+			WHERE id = $1 AND tenant_id = $2
+		`, id, tenantID)
+
+		g, err := scanGrant(row)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return accessgrants.Grant{}, ErrNotFound
+			}
+			return accessgrants.Grant{}, err
 		}
-		return accessgrants.Grant{}, err
-	}
-
-	g.Status = accessgrants.Status(status)
-	g.Scopes = textArrayToScopes(scopes)
-	if revokedAt.Valid {
-		t := revokedAt.Time
-		g.RevokedAt = &t
-	}
-
-	return g, nil
+		return g, nil
+	})
 }
 
-func (r *AccessGrantsRepo) ListByPet(ctx context.Context, petID string) ([]accessgrants.Grant, error) {
+func (r *AccessGrantsRepo) ListByPet(ctx context.Context, tenantID, petID string) ([]accessgrants.Grant, error) {
 	petID = strings.TrimSpace(petID)
 	if petID == "" {
 		return nil, nil
 	}
 
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT
-			id, pet_id, owner_user_id, grantee_user_id,
-			scopes, status,
-			created_at, updated_at, revoked_at
-		FROM access_grants
-		WHERE pet_id = $1
-		ORDER BY created_at ASC
-	`, petID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	out := make([]accessgrants.Grant, 0)
-	for rows.Next() {
-		var g accessgrants.Grant
-		var status string
-		var scopes []string
-		var revokedAt sql.NullTime
-
-		if err := rows.Scan(
-			&g.ID,
-			&g.PetID,
-			&g.OwnerUserID,
-			&g.GranteeUserID,
-			&scopes,
-			&status,
-			&g.CreatedAt,
-			&g.UpdatedAt,
-			&revokedAt,
-		); err != nil {
+	return retryRead(ctx, r.cfg.readRetries, func() ([]accessgrants.Grant, error) {
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT`+grantColumns+`
+			FROM access_grants
+			WHERE pet_id = $1 AND tenant_id = $2
+			ORDER BY created_at ASC
+		`, petID, tenantID)
+		if err != nil {
 			return nil, err
 		}
-
-		g.Status = accessgrants.Status(status)
-		g.Scopes = textArrayToScopes(scopes)
-		if revokedAt.Valid {
-			t := revokedAt.Time
-			g.RevokedAt = &t
-		}
-
-		out = append(out, g)
-	}
-
-	return out, rows.Err()
+		return collectGrants(rows)
+	})
 }
 
-func (r *AccessGrantsRepo) GetActiveGrant(ctx context.Context, petID, granteeUserID string) (accessgrants.Grant, error) {
+func (r *AccessGrantsRepo) GetActiveGrant(ctx context.Context, tenantID, petID, granteeUserID string) (accessgrants.Grant, error) {
 	petID = strings.TrimSpace(petID)
 	granteeUserID = strings.TrimSpace(granteeUserID)
 	if petID == "" || granteeUserID == "" {
 		return accessgrants.Grant{}, ErrNotFound
 	}
 
-	row := r.db.QueryRowContext(ctx, `
-		SELECT
-			id, pet_id, owner_user_id, grantee_user_id,
-			scopes, status,
-			created_at, updated_at, revoked_at
-		FROM access_grants
-		WHERE pet_id = $1
-		  AND grantee_user_id = $2
-		  AND status = 'active'
-		ORDER BY updated_at DESC
-		LIMIT 1
-	`, petID, granteeUserID)
-
-	var g accessgrants.Grant
-	var status string
-	var scopes []string
-	var revokedAt sql.NullTime
-
-	if err := row.Scan(
-		&g.ID,
-		&g.PetID,
-		&g.OwnerUserID,
-		&g.GranteeUserID,
-		&scopes,
-		&status,
-		&g.CreatedAt,
-		&g.UpdatedAt,
-		&revokedAt,
-	); err != nil {
-		if err == sql.ErrNoRows {
-			return accessgrants.Grant{}, ErrNotFound
+	return retryRead(ctx, r.cfg.readRetries, func() (accessgrants.Grant, error) {
+		row := r.db.QueryRowContext(ctx, `
+			SELECT`+grantColumns+`
+			FROM access_grants
+			WHERE pet_id = $1
+			  AND grantee_user_id = $2
+			  AND status = 'active'
+			  AND tenant_id = $3
+			ORDER BY updated_at DESC
+			LIMIT 1
+		`, petID, granteeUserID, tenantID)
+
+		g, err := scanGrant(row)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return accessgrants.Grant{}, ErrNotFound
+			}
+			return accessgrants.Grant{}, err
 		}
-		return accessgrants.Grant{}, err
-	}
-
-	g.Status = accessgrants.Status(status)
-	g.Scopes = textArrayToScopes(scopes)
-	if revokedAt.Valid {
-		t := revokedAt.Time
-		g.RevokedAt = &t
-	}
-
-	return g, nil
+		return g, nil
+	})
 }
 
-func (r *AccessGrantsRepo) ListByGrantee(ctx context.Context, granteeUserID string) ([]accessgrants.Grant, error) {
+// ListByGrantee filtra por estado en SQL (status = ANY) y aprovecha
+// idx_access_grants_grantee_status (grantee_user_id, tenant_id, status, updated_at DESC).
+// La paginación es keyset sobre (updated_at, id), con id como desempate.
+func (r *AccessGrantsRepo) ListByGrantee(ctx context.Context, tenantID, granteeUserID string, filter accessgrants.GranteeFilter) ([]accessgrants.Grant, error) {
 	granteeUserID = strings.TrimSpace(granteeUserID)
 	if granteeUserID == "" {
 		return nil, nil
 	}
 
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT
-			id, pet_id, owner_user_id, grantee_user_id,
-			scopes, status,
-			created_at, updated_at, revoked_at
-		FROM access_grants
-		WHERE grantee_user_id = $1
-		ORDER BY updated_at DESC
-	`, granteeUserID)
-	if err != nil {
-		return nil, err
+	q := `
+			SELECT` + grantColumns + `
+			FROM access_grants
+			WHERE grantee_user_id = $1 AND tenant_id = $2`
+	args := []any{granteeUserID, tenantID}
+	if len(filter.Statuses) > 0 {
+		names := make([]string, 0, len(filter.Statuses))
+		for _, s := range filter.Statuses {
+			names = append(names, string(s))
+		}
+		args = append(args, names)
+		q += fmt.Sprintf(` AND status = ANY($%d)`, len(args))
+	}
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.UpdatedAt, filter.Cursor.ID)
+		q += fmt.Sprintf(` AND (updated_at, id) < ($%d, $%d)`, len(args)-1, len(args))
+	}
+	q += `
+			ORDER BY updated_at DESC, id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		q += fmt.Sprintf(`
+			LIMIT $%d`, len(args))
 	}
-	defer rows.Close()
-
-	out := make([]accessgrants.Grant, 0)
-	for rows.Next() {
-		var g accessgrants.Grant
-		var status string
-		var scopes []string
-		var revokedAt sql.NullTime
 
-		if err := rows.Scan(
-			&g.ID,
-			&g.PetID,
-			&g.OwnerUserID,
-			&g.GranteeUserID,
-			&scopes,
-			&status,
-			&g.CreatedAt,
-			&g.UpdatedAt,
-			&revokedAt,
-		); err != nil {
+	return retryRead(ctx, r.cfg.readRetries, func() ([]accessgrants.Grant, error) {
+		rows, err := r.db.QueryContext(ctx, q, args...)
+		if err != nil {
 			return nil, err
 		}
+		return collectGrants(rows)
+	})
+}
 
-		g.Status = accessgrants.Status(status)
-		g.Scopes = textArrayToScopes(scopes)
-		if revokedAt.Valid {
-			t := revokedAt.Time
-			g.RevokedAt = &t
+// ListInvitesExpiredBefore no filtra por tenant: es para el barrido de invitaciones
+// vencidas (idx_access_grants_invite_expires, parcial sobre status = 'invited').
+func (r *AccessGrantsRepo) ListInvitesExpiredBefore(ctx context.Context, before time.Time) ([]accessgrants.Grant, error) {
+	return retryRead(ctx, r.cfg.readRetries, func() ([]accessgrants.Grant, error) {
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT`+grantColumns+`
+			FROM access_grants
+			WHERE status = 'invited' AND invite_expires_at <= $1
+			ORDER BY invite_expires_at ASC
+		`, before)
+		if err != nil {
+			return nil, err
 		}
+		return collectGrants(rows)
+	})
+}
+
+// collectGrants escanea todas las filas y cierra rows.
+func collectGrants(rows *sql.Rows) ([]accessgrants.Grant, error) {
+	defer rows.Close()
 
+	out := make([]accessgrants.Grant, 0)
+	for rows.Next() {
+		g, err := scanGrant(rows)
+		if err != nil {
+			return nil, err
+		}
 		out = append(out, g)
 	}
 
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"pet-clinical-history/internal/domain/accesslog"
+)
+
+type AccessLogRepo struct {
+	db *sql.DB
+}
+
+func NewAccessLogRepo(db *sql.DB) *AccessLogRepo {
+	return &AccessLogRepo{db: db}
+}
+
+func (r *AccessLogRepo) Create(ctx context.Context, e accesslog.Entry) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO pet_access_log (
+			id, pet_id, grantee_user_id,
+			resource, at
+		) VALUES ($1,$2,$3,$4,$5)
+	`,
+		e.ID,
+		e.PetID,
+		e.GranteeUserID,
+		string(e.Resource),
+		e.At,
+	)
+	return err
+}
+
+func (r *AccessLogRepo) ListByPet(ctx context.Context, petID string, limit int) ([]accesslog.Entry, error) {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id, pet_id, grantee_user_id,
+			resource, at
+		FROM pet_access_log
+		WHERE pet_id = $1
+		ORDER BY at DESC
+		LIMIT $2
+	`, petID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]accesslog.Entry, 0)
+	for rows.Next() {
+		var e accesslog.Entry
+		var resource string
+		if err := rows.Scan(
+			&e.ID,
+			&e.PetID,
+			&e.GranteeUserID,
+			&resource,
+			&e.At,
+		); err != nil {
+			return nil, err
+		}
+		e.Resource = accesslog.Resource(resource)
+		out = append(out, e)
+	}
+
+	return out, rows.Err()
+}
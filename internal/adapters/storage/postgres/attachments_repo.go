@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type AttachmentsRepo struct {
+	db *sql.DB
+}
+
+func NewAttachmentsRepo(db *sql.DB) *AttachmentsRepo {
+	return &AttachmentsRepo{db: db}
+}
+
+func (r *AttachmentsRepo) Create(ctx context.Context, a details.Attachment) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO event_attachments (
+			id, event_id, pet_id,
+			file_name, content_type, url, size_bytes,
+			added_by, created_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	`,
+		a.ID,
+		a.EventID,
+		a.PetID,
+		a.FileName,
+		a.ContentType,
+		a.URL,
+		a.SizeBytes,
+		a.AddedBy,
+		a.CreatedAt,
+	)
+	return err
+}
+
+func (r *AttachmentsRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string][]details.Attachment, error) {
+	out := make(map[string][]details.Attachment, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id, event_id, pet_id,
+			file_name, content_type, url, size_bytes,
+			added_by, created_at
+		FROM event_attachments
+		WHERE event_id = ANY($1)
+		ORDER BY created_at ASC, id ASC
+	`, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a details.Attachment
+		if err := rows.Scan(
+			&a.ID,
+			&a.EventID,
+			&a.PetID,
+			&a.FileName,
+			&a.ContentType,
+			&a.URL,
+			&a.SizeBytes,
+			&a.AddedBy,
+			&a.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out[a.EventID] = append(out[a.EventID], a)
+	}
+
+	return out, rows.Err()
+}
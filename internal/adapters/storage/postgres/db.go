@@ -4,6 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -11,20 +15,132 @@ import (
 
 var (
 	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidPoolConfig: un valor del pool no parsea o MaxIdleConns supera a MaxOpenConns.
+	ErrInvalidPoolConfig = errors.New("invalid pool config")
 )
 
-// Open abre una conexión pool a Postgres usando pgx (database/sql).
+// PoolConfig son los parámetros del pool de database/sql. Un campo en cero toma el valor de
+// DefaultPoolConfig; uno negativo se pasa tal cual (semántica de database/sql: MaxOpenConns
+// ilimitado, MaxIdleConns sin conexiones ociosas, duraciones sin vencimiento).
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxIdleTime time.Duration
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultPoolConfig son los defaults del MVP (pensados para una instancia chica).
+var DefaultPoolConfig = PoolConfig{
+	MaxOpenConns:    10,
+	MaxIdleConns:    5,
+	ConnMaxIdleTime: 5 * time.Minute,
+	ConnMaxLifetime: 30 * time.Minute,
+}
+
+// withDefaults completa los campos en cero con DefaultPoolConfig.
+func (c PoolConfig) withDefaults() PoolConfig {
+	if c.MaxOpenConns == 0 {
+		c.MaxOpenConns = DefaultPoolConfig.MaxOpenConns
+	}
+	if c.MaxIdleConns == 0 {
+		c.MaxIdleConns = DefaultPoolConfig.MaxIdleConns
+	}
+	if c.ConnMaxIdleTime == 0 {
+		c.ConnMaxIdleTime = DefaultPoolConfig.ConnMaxIdleTime
+	}
+	if c.ConnMaxLifetime == 0 {
+		c.ConnMaxLifetime = DefaultPoolConfig.ConnMaxLifetime
+	}
+	return c
+}
+
+// Validate exige MaxIdleConns <= MaxOpenConns cuando el pool tiene tope (database/sql
+// recortaría las ociosas en silencio; mejor fallar y que se corrija la config).
+func (c PoolConfig) Validate() error {
+	c = c.withDefaults()
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		return fmt.Errorf("%w: DB_MAX_IDLE_CONNS (%d) > DB_MAX_OPEN_CONNS (%d)", ErrInvalidPoolConfig, c.MaxIdleConns, c.MaxOpenConns)
+	}
+	return nil
+}
+
+// PoolConfigFromEnv lee DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS (enteros), DB_CONN_MAX_IDLE_TIME y
+// DB_CONN_MAX_LIFETIME (duraciones, p.ej. "5m"). Los no seteados quedan con DefaultPoolConfig;
+// un valor que no parsea o idle > open devuelve ErrInvalidPoolConfig.
+func PoolConfigFromEnv() (PoolConfig, error) {
+	cfg := DefaultPoolConfig
+
+	ints := []struct {
+		env string
+		dst *int
+	}{
+		{"DB_MAX_OPEN_CONNS", &cfg.MaxOpenConns},
+		{"DB_MAX_IDLE_CONNS", &cfg.MaxIdleConns},
+	}
+	for _, v := range ints {
+		raw := strings.TrimSpace(os.Getenv(v.env))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return PoolConfig{}, fmt.Errorf("%w: %s=%q", ErrInvalidPoolConfig, v.env, raw)
+		}
+		*v.dst = n
+	}
+
+	durations := []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"DB_CONN_MAX_IDLE_TIME", &cfg.ConnMaxIdleTime},
+		{"DB_CONN_MAX_LIFETIME", &cfg.ConnMaxLifetime},
+	}
+	for _, v := range durations {
+		raw := strings.TrimSpace(os.Getenv(v.env))
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return PoolConfig{}, fmt.Errorf("%w: %s=%q", ErrInvalidPoolConfig, v.env, raw)
+		}
+		*v.dst = d
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return PoolConfig{}, err
+	}
+	return cfg, nil
+}
+
+// Open abre una conexión pool a Postgres usando pgx (database/sql), con el pool
+// configurado por env (ver PoolConfigFromEnv).
 func Open(dsn string) (*sql.DB, error) {
+	cfg, err := PoolConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return OpenWithConfig(dsn, cfg)
+}
+
+// OpenWithConfig es Open con el pool configurado por código.
+func OpenWithConfig(dsn string, cfg PoolConfig) (*sql.DB, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg = cfg.withDefaults()
+
 	db, err := sql.Open("pgx", dsn)
 	if err != nil {
 		return nil, err
 	}
 
-	// defaults razonables para MVP (ajustable luego)
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxIdleTime(5 * time.Minute)
-	db.SetConnMaxLifetime(30 * time.Minute)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
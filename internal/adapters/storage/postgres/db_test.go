@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPoolConfigFromEnv_Defaults(t *testing.T) {
+	for _, env := range []string{"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_IDLE_TIME", "DB_CONN_MAX_LIFETIME"} {
+		t.Setenv(env, "")
+	}
+
+	cfg, err := PoolConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg != DefaultPoolConfig {
+		t.Fatalf("expected defaults %+v, got %+v", DefaultPoolConfig, cfg)
+	}
+}
+
+func TestPoolConfigFromEnv_Overrides(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "50")
+	t.Setenv("DB_MAX_IDLE_CONNS", " 20 ")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME", "1m")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "")
+
+	cfg, err := PoolConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := PoolConfig{MaxOpenConns: 50, MaxIdleConns: 20, ConnMaxIdleTime: time.Minute, ConnMaxLifetime: DefaultPoolConfig.ConnMaxLifetime}
+	if cfg != want {
+		t.Fatalf("expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestPoolConfigFromEnv_Invalid(t *testing.T) {
+	cases := []map[string]string{
+		{"DB_MAX_OPEN_CONNS": "ten"},
+		{"DB_CONN_MAX_LIFETIME": "30"},
+		// idle > open
+		{"DB_MAX_OPEN_CONNS": "4"},
+		{"DB_MAX_OPEN_CONNS": "10", "DB_MAX_IDLE_CONNS": "11"},
+	}
+	for _, env := range cases {
+		for _, k := range []string{"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_IDLE_TIME", "DB_CONN_MAX_LIFETIME"} {
+			t.Setenv(k, env[k])
+		}
+		if _, err := PoolConfigFromEnv(); !errors.Is(err, ErrInvalidPoolConfig) {
+			t.Fatalf("expected ErrInvalidPoolConfig for %v, got %v", env, err)
+		}
+	}
+}
+
+func TestPoolConfig_Validate(t *testing.T) {
+	cases := []struct {
+		cfg PoolConfig
+		ok  bool
+	}{
+		{PoolConfig{}, true}, // defaults: 5 <= 10
+		{PoolConfig{MaxOpenConns: 5, MaxIdleConns: 5}, true},
+		{PoolConfig{MaxOpenConns: 3}, false}, // idle default 5 > 3
+		{PoolConfig{MaxOpenConns: 3, MaxIdleConns: 4}, false},
+		{PoolConfig{MaxOpenConns: -1, MaxIdleConns: 100}, true}, // sin tope de abiertas
+	}
+	for _, tc := range cases {
+		err := tc.cfg.Validate()
+		if tc.ok && err != nil {
+			t.Fatalf("expected %+v valid, got %v", tc.cfg, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrInvalidPoolConfig) {
+			t.Fatalf("expected ErrInvalidPoolConfig for %+v, got %v", tc.cfg, err)
+		}
+	}
+
+	if _, err := OpenWithConfig("postgres://invalid", PoolConfig{MaxOpenConns: 1, MaxIdleConns: 2}); !errors.Is(err, ErrInvalidPoolConfig) {
+		t.Fatalf("expected OpenWithConfig to reject idle > open before connecting, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pet-clinical-history/internal/domain/events"
+)
+
+type EventBatchStore struct {
+	db *sql.DB
+}
+
+func NewEventBatchStore(db *sql.DB) *EventBatchStore {
+	return &EventBatchStore{db: db}
+}
+
+// CreateBatch inserta los eventos y sus detalles en una sola transacción: o quedan todos o ninguno.
+func (s *EventBatchStore) CreateBatch(ctx context.Context, items []events.PetEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, e := range items {
+		if err := insertEvent(ctx, tx, e); err != nil {
+			return err
+		}
+		if e.Preventive != nil {
+			if err := insertPreventive(ctx, tx, *e.Preventive); err != nil {
+				return err
+			}
+		}
+		if e.Measurement != nil {
+			if err := insertMeasurement(ctx, tx, *e.Measurement); err != nil {
+				return err
+			}
+		}
+		if e.Medication != nil {
+			if err := insertMedication(ctx, tx, *e.Medication); err != nil {
+				return err
+			}
+		}
+		if e.Vaccine != nil {
+			if err := insertVaccine(ctx, tx, *e.Vaccine); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
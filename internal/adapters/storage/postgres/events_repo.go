@@ -5,65 +5,45 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"pet-clinical-history/internal/domain/events"
 )
 
 type EventsRepo struct {
-	db *sql.DB
+	db  *sql.DB
+	cfg repoConfig
 }
 
-func NewEventsRepo(db *sql.DB) *EventsRepo {
-	return &EventsRepo{db: db}
+func NewEventsRepo(db *sql.DB, opts ...RepoOption) *EventsRepo {
+	return &EventsRepo{db: db, cfg: newRepoConfig(opts)}
 }
 
-func (r *EventsRepo) Create(ctx context.Context, e events.PetEvent) error {
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO pet_events (
+// eventColumns mantiene el mismo orden que scanEvent.
+const eventColumns = `
 			id, pet_id,
 			type, occurred_at, recorded_at,
 			title, notes,
-			actor_type, actor_id,
+			actor_type, actor_id, actor_email,
 			source, visibility,
-			status
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
-	`,
-		e.ID,
-		e.PetID,
-		string(e.Type),
-		e.OccurredAt,
-		e.RecordedAt,
-		e.Title,
-		e.Notes,
-		string(e.Actor.Type),
-		e.Actor.ID,
-		string(e.Source),
-		string(e.Visibility),
-		string(e.Status),
-	)
-	return err
-}
+			status,
+			origin_clinic_id, origin_system,
+			voided_by, voided_by_email, voided_at, void_reason`
 
-func (r *EventsRepo) GetByID(ctx context.Context, id string) (events.PetEvent, error) {
-	id = strings.TrimSpace(id)
-	if id == "" {
-		return events.PetEvent{}, ErrNotFound
-	}
+// rowScanner cubre *sql.Row y *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
-	row := r.db.QueryRowContext(ctx, `
-		SELECT
-			id, pet_id,
-			type, occurred_at, recorded_at,
-			title, notes,
-			actor_type, actor_id,
-			source, visibility,
-			status
-		FROM pet_events
-		WHERE id = $1
-	`, id)
+// execer cubre *sql.DB y *sql.Tx: los inserts se reusan dentro de una transacción.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
 
+func scanEvent(row rowScanner) (events.PetEvent, error) {
 	var e events.PetEvent
 	var typ, actorType, source, vis, status string
+	var voidedAt sql.NullTime
 	if err := row.Scan(
 		&e.ID,
 		&e.PetID,
@@ -74,13 +54,17 @@ func (r *EventsRepo) GetByID(ctx context.Context, id string) (events.PetEvent, e
 		&e.Notes,
 		&actorType,
 		&e.Actor.ID,
+		&e.Actor.Email,
 		&source,
 		&vis,
 		&status,
+		&e.OriginClinicID,
+		&e.OriginSystem,
+		&e.VoidedBy,
+		&e.VoidedByEmail,
+		&voidedAt,
+		&e.VoidReason,
 	); err != nil {
-		if err == sql.ErrNoRows {
-			return events.PetEvent{}, ErrNotFound
-		}
 		return events.PetEvent{}, err
 	}
 
@@ -89,32 +73,160 @@ func (r *EventsRepo) GetByID(ctx context.Context, id string) (events.PetEvent, e
 	e.Source = events.Source(source)
 	e.Visibility = events.Visibility(vis)
 	e.Status = events.EventStatus(status)
+	if voidedAt.Valid {
+		t := voidedAt.Time
+		e.VoidedAt = &t
+	}
 
 	return e, nil
 }
 
+func (r *EventsRepo) Create(ctx context.Context, e events.PetEvent) error {
+	return insertEvent(ctx, r.db, e)
+}
+
+func insertEvent(ctx context.Context, ex execer, e events.PetEvent) error {
+	_, err := ex.ExecContext(ctx, `
+		INSERT INTO pet_events (`+eventColumns+`
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)
+	`,
+		e.ID,
+		e.PetID,
+		string(e.Type),
+		e.OccurredAt,
+		e.RecordedAt,
+		e.Title,
+		e.Notes,
+		string(e.Actor.Type),
+		e.Actor.ID,
+		e.Actor.Email,
+		string(e.Source),
+		string(e.Visibility),
+		string(e.Status),
+		e.OriginClinicID,
+		e.OriginSystem,
+		e.VoidedBy,
+		e.VoidedByEmail,
+		toNullTime(e.VoidedAt),
+		e.VoidReason,
+	)
+	return err
+}
+
+func (r *EventsRepo) GetByID(ctx context.Context, id string) (events.PetEvent, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return events.PetEvent{}, ErrNotFound
+	}
+
+	return retryRead(ctx, r.cfg.readRetries, func() (events.PetEvent, error) {
+		row := r.db.QueryRowContext(ctx, `
+			SELECT`+eventColumns+`
+			FROM pet_events
+			WHERE id = $1
+		`, id)
+
+		e, err := scanEvent(row)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return events.PetEvent{}, ErrNotFound
+			}
+			return events.PetEvent{}, err
+		}
+		return e, nil
+	})
+}
+
 func (r *EventsRepo) ListByPet(ctx context.Context, petID string, filter events.ListFilter) ([]events.PetEvent, error) {
 	petID = strings.TrimSpace(petID)
 	if petID == "" {
 		return nil, nil
 	}
 
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	// +1: el service pide una fila extra para saber si hay página siguiente.
+	if limit > events.MaxListLimit+1 {
+		limit = events.MaxListLimit + 1
+	}
+
+	// Cada intento arranca de cero: un corte a mitad de las filas no deja la página a medias.
+	return retryRead(ctx, r.cfg.readRetries, func() ([]events.PetEvent, error) {
+		out := make([]events.PetEvent, 0)
+		err := r.queryByPet(ctx, petID, filter, limit, func(e events.PetEvent) error {
+			out = append(out, e)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return out, nil
+	})
+}
+
+// StreamByPet no reintenta: fn ya pudo haber emitido filas (p.ej. al export en curso).
+func (r *EventsRepo) StreamByPet(ctx context.Context, petID string, filter events.ListFilter, fn func(events.PetEvent) error) error {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return nil
+	}
+	return r.queryByPet(ctx, petID, filter, filter.Limit, fn)
+}
+
+// eventSortOrders: dirección SQL y comparación del cursor por SortOrder ("" => desc).
+var eventSortOrders = map[events.SortOrder]struct{ dir, after string }{
+	"":               {"DESC", "<"},
+	events.OrderDesc: {"DESC", "<"},
+	events.OrderAsc:  {"ASC", ">"},
+}
+
+// eventSearchDocument es el texto indexado para la búsqueda con Rank; debe coincidir con la
+// expresión de idx_pet_events_search (migración 026) para que el índice se use.
+const eventSearchDocument = `to_tsvector('simple', title || ' ' || notes)`
+
+// eventSearchHeadline son las opciones de ts_headline: un fragmento con los términos
+// marcados con events.SnippetStart / SnippetStop.
+var eventSearchHeadline = fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxFragments=1, MaxWords=17, MinWords=5`,
+	events.SnippetStart, events.SnippetStop)
+
+// rankedRow agrega rank y snippet (columnas extra del SELECT con Rank) al scan de scanEvent.
+type rankedRow struct {
+	row   rowScanner
+	match *events.SearchMatch
+}
+
+func (r rankedRow) Scan(dest ...any) error {
+	return r.row.Scan(append(dest, &r.match.Rank, &r.match.Snippet)...)
+}
+
+// queryByPet arma el SELECT filtrado y llama fn por cada fila. limit <= 0 => sin LIMIT.
+func (r *EventsRepo) queryByPet(ctx context.Context, petID string, filter events.ListFilter, limit int, fn func(events.PetEvent) error) error {
+	rank := filter.Rank && strings.TrimSpace(filter.Query) != ""
+
 	// Base query
 	sb := strings.Builder{}
-	sb.WriteString(`
-		SELECT
-			id, pet_id,
-			type, occurred_at, recorded_at,
-			title, notes,
-			actor_type, actor_id,
-			source, visibility,
-			status
+	args := []any{petID}
+	argN := 2
+	if rank {
+		// $2 es la consulta; plainto_tsquery exige todos los términos.
+		sb.WriteString(`
+		SELECT` + eventColumns + `,
+			ts_rank(` + eventSearchDocument + `, query) AS search_rank,
+			ts_headline('simple', title || ' ' || notes, query, $3)
+		FROM pet_events, plainto_tsquery('simple', $2) AS query
+		WHERE pet_id = $1 AND ` + eventSearchDocument + ` @@ query
+	`)
+		args = append(args, strings.TrimSpace(filter.Query), eventSearchHeadline)
+		argN = 4
+	} else {
+		sb.WriteString(`
+		SELECT` + eventColumns + `
 		FROM pet_events
 		WHERE pet_id = $1
 	`)
-
-	args := []any{petID}
-	argN := 2
+	}
 
 	// types filter
 	if len(filter.Types) > 0 {
@@ -139,76 +251,226 @@ func (r *EventsRepo) ListByPet(ctx context.Context, petID string, filter events.
 		argN++
 	}
 
-	// q: búsqueda simple en title + notes
-	if strings.TrimSpace(filter.Query) != "" {
+	// q: búsqueda simple en title + notes (con Rank ya filtró el @@ de arriba)
+	if !rank && strings.TrimSpace(filter.Query) != "" {
 		sb.WriteString(fmt.Sprintf(" AND (title ILIKE $%d OR notes ILIKE $%d)", argN, argN))
 		args = append(args, "%"+strings.TrimSpace(filter.Query)+"%")
 		argN++
 	}
 
-	limit := filter.Limit
-	if limit <= 0 {
-		limit = 50
+	// visibilidad: los delegados no ven eventos privados
+	if filter.ExcludePrivate {
+		sb.WriteString(fmt.Sprintf(" AND visibility <> $%d", argN))
+		args = append(args, string(events.VisibilityPrivate))
+		argN++
+	}
+
+	// estado / actor
+	// (por defecto solo active; IncludeVoided trae también los anulados)
+	if filter.Status != nil {
+		sb.WriteString(fmt.Sprintf(" AND status = $%d", argN))
+		args = append(args, string(*filter.Status))
+		argN++
+	} else if !filter.IncludeVoided {
+		sb.WriteString(fmt.Sprintf(" AND status = $%d", argN))
+		args = append(args, string(events.EventStatusActive))
+		argN++
+	}
+	if filter.ActorID != "" {
+		sb.WriteString(fmt.Sprintf(" AND actor_id = $%d", argN))
+		args = append(args, filter.ActorID)
+		argN++
 	}
-	if limit > 200 {
-		limit = 200
+
+	// orden: solo valores del allow-list llegan al SQL
+	order, ok := eventSortOrders[filter.Order]
+	if !ok {
+		order = eventSortOrders[events.OrderDesc]
 	}
 
-	sb.WriteString(" ORDER BY occurred_at DESC")
-	sb.WriteString(fmt.Sprintf(" LIMIT $%d", argN))
-	args = append(args, limit)
+	// cursor: keyset estable ante inserts concurrentes
+	if filter.Cursor != nil {
+		sb.WriteString(fmt.Sprintf(" AND (occurred_at, id) %s ($%d, $%d)", order.after, argN, argN+1))
+		args = append(args, filter.Cursor.OccurredAt, filter.Cursor.ID)
+		argN += 2
+	}
+
+	if rank {
+		sb.WriteString(" ORDER BY search_rank DESC, occurred_at DESC, id DESC")
+	} else {
+		sb.WriteString(" ORDER BY occurred_at " + order.dir + ", id " + order.dir)
+	}
+	if limit > 0 {
+		sb.WriteString(fmt.Sprintf(" LIMIT $%d", argN))
+		args = append(args, limit)
+	}
 
 	rows, err := r.db.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row rowScanner = rows
+		var match events.SearchMatch
+		if rank {
+			row = rankedRow{row: rows, match: &match}
+		}
+		e, err := scanEvent(row)
+		if err != nil {
+			return err
+		}
+		if rank {
+			e.Match = &match
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *EventsRepo) CountTypesByPet(ctx context.Context, petID string, filter events.ListFilter) ([]events.TypeCount, error) {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return []events.TypeCount{}, nil
+	}
+
+	q := `
+		SELECT type, COUNT(*)
+		FROM pet_events
+		WHERE pet_id = $1
+	`
+	args := []any{petID}
+	if !filter.IncludeVoided {
+		q += ` AND status = 'active'`
+	}
+	// visibilidad: los delegados no ven eventos privados
+	if filter.ExcludePrivate {
+		args = append(args, string(events.VisibilityPrivate))
+		q += fmt.Sprintf(` AND visibility <> $%d`, len(args))
+	}
+	q += ` GROUP BY type ORDER BY type`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := make([]events.PetEvent, 0)
+	out := make([]events.TypeCount, 0)
 	for rows.Next() {
-		var e events.PetEvent
-		var typ, actorType, source, vis, status string
-
-		if err := rows.Scan(
-			&e.ID,
-			&e.PetID,
-			&typ,
-			&e.OccurredAt,
-			&e.RecordedAt,
-			&e.Title,
-			&e.Notes,
-			&actorType,
-			&e.Actor.ID,
-			&source,
-			&vis,
-			&status,
-		); err != nil {
+		var typ string
+		var n int
+		if err := rows.Scan(&typ, &n); err != nil {
 			return nil, err
 		}
+		out = append(out, events.TypeCount{Type: events.EventType(typ), Count: n})
+	}
+	return out, rows.Err()
+}
 
-		e.Type = events.EventType(typ)
-		e.Actor.Type = events.ActorType(actorType)
-		e.Source = events.Source(source)
-		e.Visibility = events.Visibility(vis)
-		e.Status = events.EventStatus(status)
+func (r *EventsRepo) SummarizeByPet(ctx context.Context, petID string, excludePrivate bool) ([]events.TypeSummary, error) {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return []events.TypeSummary{}, nil
+	}
 
-		out = append(out, e)
+	q := `
+		SELECT type, COUNT(*), MAX(occurred_at)
+		FROM pet_events
+		WHERE pet_id = $1 AND status = 'active'
+	`
+	args := []any{petID}
+	// visibilidad: los delegados no ven eventos privados
+	if excludePrivate {
+		q += ` AND visibility <> $2`
+		args = append(args, string(events.VisibilityPrivate))
 	}
+	q += ` GROUP BY type ORDER BY type`
 
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]events.TypeSummary, 0)
+	for rows.Next() {
+		var typ string
+		var n int
+		var last time.Time
+		if err := rows.Scan(&typ, &n, &last); err != nil {
+			return nil, err
+		}
+		out = append(out, events.TypeSummary{Type: events.EventType(typ), Count: n, LastOccurredAt: last})
+	}
 	return out, rows.Err()
 }
 
-func (r *EventsRepo) Void(ctx context.Context, id string) error {
+func (r *EventsRepo) CountActiveByPet(ctx context.Context, petID string) (int, error) {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return 0, nil
+	}
+
+	var n int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM pet_events
+		WHERE pet_id = $1 AND status = 'active'
+	`, petID).Scan(&n)
+	return n, err
+}
+
+func (r *EventsRepo) LastOccurredByPets(ctx context.Context, petIDs []string) (map[string]time.Time, error) {
+	out := map[string]time.Time{}
+	if len(petIDs) == 0 {
+		return out, nil
+	}
+
+	// Usa idx_events_pet_occurred_at (pet_id, occurred_at DESC).
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT pet_id, MAX(occurred_at)
+		FROM pet_events
+		WHERE pet_id = ANY($1)
+		  AND status = 'active'
+		GROUP BY pet_id
+	`, petIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var petID string
+		var last time.Time
+		if err := rows.Scan(&petID, &last); err != nil {
+			return nil, err
+		}
+		out[petID] = last
+	}
+	return out, rows.Err()
+}
+
+func (r *EventsRepo) Void(ctx context.Context, id string, audit events.VoidAudit) error {
 	id = strings.TrimSpace(id)
 	if id == "" {
 		return ErrNotFound
 	}
 
+	// Si ya estaba voided se conserva la auditoría original (reintento idempotente).
 	res, err := r.db.ExecContext(ctx, `
 		UPDATE pet_events
-		SET status = 'voided'
+		SET status = 'voided',
+		    voided_by = CASE WHEN status = 'voided' THEN voided_by ELSE $2 END,
+		    voided_by_email = CASE WHEN status = 'voided' THEN voided_by_email ELSE $5 END,
+		    voided_at = CASE WHEN status = 'voided' THEN voided_at ELSE $3 END,
+		    void_reason = CASE WHEN status = 'voided' THEN void_reason ELSE $4 END
 		WHERE id = $1
-	`, id)
+	`, id, audit.By, audit.At, audit.Reason, audit.ByEmail)
 	if err != nil {
 		return err
 	}
@@ -219,3 +481,67 @@ func (r *EventsRepo) Void(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+func (r *EventsRepo) VoidIfActive(ctx context.Context, id string, audit events.VoidAudit) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrNotFound
+	}
+
+	// Update condicional: solo gana quien lo encuentra active.
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE pet_events
+		SET status = 'voided', voided_by = $2, voided_by_email = $5, voided_at = $3, void_reason = $4
+		WHERE id = $1 AND status = 'active'
+	`, id, audit.By, audit.At, audit.Reason, audit.ByEmail)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	// 0 filas: distinguir "no existe" de "ya no estaba active".
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM pet_events WHERE id = $1)
+	`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return events.ErrAlreadyVoided
+}
+
+func (r *EventsRepo) Restore(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrNotFound
+	}
+
+	// Update condicional: solo se restaura lo que está voided.
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE pet_events
+		SET status = 'active', voided_by = '', voided_by_email = '', voided_at = NULL, void_reason = ''
+		WHERE id = $1 AND status = 'voided'
+	`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	// 0 filas: distinguir "no existe" de "no estaba voided".
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM pet_events WHERE id = $1)
+	`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return events.ErrNotVoided
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pet-clinical-history/internal/domain/idempotency"
+)
+
+type IdempotencyRepo struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepo(db *sql.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{db: db}
+}
+
+func (r *IdempotencyRepo) Get(ctx context.Context, scope, userID, key string) (idempotency.Record, bool, error) {
+	rec := idempotency.Record{Scope: scope, UserID: userID, Key: key}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT resource_id, created_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND user_id = $2 AND key = $3
+	`, scope, userID, key).Scan(&rec.ResourceID, &rec.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return idempotency.Record{}, false, nil
+		}
+		return idempotency.Record{}, false, err
+	}
+	return rec, true, nil
+}
+
+// Put reemplaza el registro previo de la misma key (vencido o de un recurso que ya no existe).
+func (r *IdempotencyRepo) Put(ctx context.Context, rec idempotency.Record) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (scope, user_id, key, resource_id, created_at)
+		VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT (scope, user_id, key)
+		DO UPDATE SET resource_id = EXCLUDED.resource_id, created_at = EXCLUDED.created_at
+	`,
+		rec.Scope,
+		rec.UserID,
+		rec.Key,
+		rec.ResourceID,
+		rec.CreatedAt,
+	)
+	return err
+}
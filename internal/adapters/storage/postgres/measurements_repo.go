@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type MeasurementsRepo struct {
+	db *sql.DB
+}
+
+func NewMeasurementsRepo(db *sql.DB) *MeasurementsRepo {
+	return &MeasurementsRepo{db: db}
+}
+
+func (r *MeasurementsRepo) Create(ctx context.Context, d details.Measurement) error {
+	return insertMeasurement(ctx, r.db, d)
+}
+
+func insertMeasurement(ctx context.Context, ex execer, d details.Measurement) error {
+	_, err := ex.ExecContext(ctx, `
+		INSERT INTO event_measurements (
+			id, event_id,
+			kind, value, unit
+		) VALUES ($1,$2,$3,$4,$5)
+	`,
+		d.ID,
+		d.EventID,
+		string(d.Kind),
+		d.Value,
+		d.Unit,
+	)
+	return err
+}
+
+func (r *MeasurementsRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Measurement, error) {
+	out := make(map[string]details.Measurement, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id, event_id,
+			kind, value, unit
+		FROM event_measurements
+		WHERE event_id = ANY($1)
+	`, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d details.Measurement
+		var kind string
+		if err := rows.Scan(
+			&d.ID,
+			&d.EventID,
+			&kind,
+			&d.Value,
+			&d.Unit,
+		); err != nil {
+			return nil, err
+		}
+		d.Kind = details.MeasurementKind(kind)
+		out[d.EventID] = d
+	}
+
+	return out, rows.Err()
+}
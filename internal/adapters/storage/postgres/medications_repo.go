@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type MedicationsRepo struct {
+	db *sql.DB
+}
+
+func NewMedicationsRepo(db *sql.DB) *MedicationsRepo {
+	return &MedicationsRepo{db: db}
+}
+
+const medicationColumns = `
+			d.id, d.event_id,
+			d.name, d.dosage, d.dose_unit, d.frequency,
+			d.start_date, d.end_date, d.notes`
+
+func (r *MedicationsRepo) Create(ctx context.Context, d details.Medication) error {
+	return insertMedication(ctx, r.db, d)
+}
+
+func insertMedication(ctx context.Context, ex execer, d details.Medication) error {
+	_, err := ex.ExecContext(ctx, `
+		INSERT INTO event_medications (
+			id, event_id,
+			name, dosage, dose_unit, frequency,
+			start_date, end_date, notes
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	`,
+		d.ID,
+		d.EventID,
+		d.Name,
+		d.Dosage,
+		d.DoseUnit,
+		d.Frequency,
+		d.StartDate,
+		toNullTime(d.EndDate),
+		d.Notes,
+	)
+	return err
+}
+
+func (r *MedicationsRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Medication, error) {
+	out := make(map[string]details.Medication, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+medicationColumns+`
+		FROM event_medications d
+		WHERE d.event_id = ANY($1)
+	`, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d, err := scanMedication(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[d.EventID] = d
+	}
+
+	return out, rows.Err()
+}
+
+func (r *MedicationsRepo) ListActive(ctx context.Context, petID string, at time.Time, excludePrivate bool) ([]details.Medication, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+medicationColumns+`
+		FROM event_medications d
+		JOIN pet_events e ON e.id = d.event_id
+		WHERE e.pet_id = $1
+		  AND e.status = 'active'
+		  AND (d.end_date IS NULL OR d.end_date > $2)
+		  AND (NOT $3 OR e.visibility <> 'private')
+		ORDER BY d.start_date DESC
+	`, petID, at, excludePrivate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]details.Medication, 0)
+	for rows.Next() {
+		d, err := scanMedication(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+
+	return out, rows.Err()
+}
+
+func scanMedication(row rowScanner) (details.Medication, error) {
+	var d details.Medication
+	var end sql.NullTime
+	if err := row.Scan(
+		&d.ID,
+		&d.EventID,
+		&d.Name,
+		&d.Dosage,
+		&d.DoseUnit,
+		&d.Frequency,
+		&d.StartDate,
+		&end,
+		&d.Notes,
+	); err != nil {
+		return details.Medication{}, err
+	}
+	if end.Valid {
+		t := end.Time
+		d.EndDate = &t
+	}
+	return d, nil
+}
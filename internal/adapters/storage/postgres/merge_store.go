@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/pets"
+)
+
+type PetMergeStore struct {
+	db *sql.DB
+}
+
+func NewPetMergeStore(db *sql.DB) *PetMergeStore {
+	return &PetMergeStore{db: db}
+}
+
+// MergePets mueve eventos (con sus adjuntos) y grants vigentes (según accessgrants.PlanMerge)
+// y archiva el origen en una sola transacción.
+func (s *PetMergeStore) MergePets(ctx context.Context, sourceID, targetID string, at time.Time) (pets.MergeResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pets.MergeResult{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Archivar primero: si el origen ya estaba archivado (merge concurrente) no se mueve nada.
+	res, err := tx.ExecContext(ctx, `
+		UPDATE pets
+		SET archived_at = $2, updated_at = $2
+		WHERE id = $1 AND archived_at IS NULL
+	`, sourceID, at)
+	if err != nil {
+		return pets.MergeResult{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return pets.MergeResult{}, ErrNotFound
+	}
+
+	var out pets.MergeResult
+
+	res, err = tx.ExecContext(ctx, `
+		UPDATE pet_events SET pet_id = $2 WHERE pet_id = $1
+	`, sourceID, targetID)
+	if err != nil {
+		return pets.MergeResult{}, err
+	}
+	n, _ := res.RowsAffected()
+	out.EventsMoved = int(n)
+
+	// Los adjuntos guardan pet_id desnormalizado (migración 020): siguen a sus eventos.
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE event_attachments SET pet_id = $2 WHERE pet_id = $1
+	`, sourceID, targetID); err != nil {
+		return pets.MergeResult{}, err
+	}
+
+	sourceGrants, err := lockOpenGrants(ctx, tx, sourceID)
+	if err != nil {
+		return pets.MergeResult{}, err
+	}
+	targetGrants, err := lockOpenGrants(ctx, tx, targetID)
+	if err != nil {
+		return pets.MergeResult{}, err
+	}
+	plan := accessgrants.PlanMerge(sourceGrants, targetGrants)
+
+	if len(plan.Move) > 0 {
+		res, err = tx.ExecContext(ctx, `
+			UPDATE access_grants
+			SET pet_id = $2, updated_at = $3
+			WHERE id = ANY($1)
+		`, plan.Move, targetID, at)
+		if err != nil {
+			return pets.MergeResult{}, err
+		}
+		n, _ = res.RowsAffected()
+		out.GrantsMoved = int(n)
+	}
+	if len(plan.Revoke) > 0 {
+		res, err = tx.ExecContext(ctx, `
+			UPDATE access_grants
+			SET status = 'revoked', revoked_at = $2, updated_at = $2
+			WHERE id = ANY($1)
+		`, plan.Revoke, at)
+		if err != nil {
+			return pets.MergeResult{}, err
+		}
+		n, _ = res.RowsAffected()
+		out.GrantsRevoked = int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return pets.MergeResult{}, err
+	}
+	return out, nil
+}
+
+// lockOpenGrants trae (y bloquea hasta el fin de tx) los grants vigentes de la mascota.
+func lockOpenGrants(ctx context.Context, tx *sql.Tx, petID string) ([]accessgrants.Grant, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT`+grantColumns+`
+		FROM access_grants
+		WHERE pet_id = $1 AND status IN ('invited', 'active')
+		FOR UPDATE
+	`, petID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]accessgrants.Grant, 0)
+	for rows.Next() {
+		g, err := scanGrant(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
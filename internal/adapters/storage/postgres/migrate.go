@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"pet-clinical-history/internal/db/migrations"
+)
+
+// migrateLockID es la clave del advisory lock que serializa Migrate entre instancias.
+const migrateLockID = 7427001
+
+// Migrate aplica las migraciones embebidas (internal/db/migrations) que aún no figuren en
+// schema_migrations, en orden de nombre. Es seguro llamarlo en cada arranque y desde
+// varias instancias a la vez (advisory lock).
+func Migrate(ctx context.Context, db *sql.DB) error {
+	return migrate(ctx, db, migrations.FS)
+}
+
+func migrate(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	// Una sola conexión: el advisory lock es por sesión.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrateLockID); err != nil {
+		return fmt.Errorf("migrate: lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrateLockID)
+	}()
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    text PRIMARY KEY,
+			applied_at timestamptz NOT NULL DEFAULT now()
+		)
+	`); err != nil {
+		return fmt.Errorf("migrate: schema_migrations: %w", err)
+	}
+
+	applied := map[string]bool{}
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+		if applied[version] {
+			continue
+		}
+
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		// Sin argumentos pgx usa el protocolo simple: admite varias sentencias y el
+		// BEGIN/COMMIT propio de cada archivo.
+		if _, err := conn.ExecContext(ctx, string(script)); err != nil {
+			return fmt.Errorf("migrate: %s: %w", name, err)
+		}
+		if _, err := conn.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			return fmt.Errorf("migrate: record %s: %w", name, err)
+		}
+	}
+	return nil
+}
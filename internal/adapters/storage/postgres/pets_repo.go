@@ -3,31 +3,87 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"pet-clinical-history/internal/domain/pets"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// petsMicrochipIndex es el índice único parcial de microchip (migración 021).
+const petsMicrochipIndex = "uq_pets_microchip"
+
+// mapMicrochipConflict traduce la violación del índice único de microchip a pets.ErrMicrochipTaken.
+func mapMicrochipConflict(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == petsMicrochipIndex {
+		return pets.ErrMicrochipTaken
+	}
+	return err
+}
+
 type PetsRepo struct {
-	db *sql.DB
+	db  *sql.DB
+	cfg repoConfig
 }
 
-func NewPetsRepo(db *sql.DB) *PetsRepo {
-	return &PetsRepo{db: db}
+func NewPetsRepo(db *sql.DB, opts ...RepoOption) *PetsRepo {
+	return &PetsRepo{db: db, cfg: newRepoConfig(opts)}
 }
 
-func (r *PetsRepo) Create(ctx context.Context, p pets.Pet) error {
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO pets (
-			id, owner_user_id,
+// petColumns mantiene el mismo orden que scanPet.
+const petColumns = `
+			id, owner_user_id, tenant_id,
 			name, species, breed, sex,
 			birth_date, microchip, notes,
-			created_at, updated_at
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+			created_at, updated_at, archived_at, status`
+
+func scanPet(row rowScanner) (pets.Pet, error) {
+	var p pets.Pet
+	var bd sql.NullTime
+	var archivedAt sql.NullTime
+	if err := row.Scan(
+		&p.ID,
+		&p.OwnerUserID,
+		&p.TenantID,
+		&p.Name,
+		&p.Species,
+		&p.Breed,
+		&p.Sex,
+		&bd,
+		&p.Microchip,
+		&p.Notes,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&archivedAt,
+		&p.Status,
+	); err != nil {
+		return pets.Pet{}, err
+	}
+
+	if bd.Valid {
+		t := bd.Time
+		// ojo: birth_date es date, pgx lo puede mapear a time.Time midnight UTC
+		p.BirthDate = &t
+	}
+	if archivedAt.Valid {
+		t := archivedAt.Time
+		p.ArchivedAt = &t
+	}
+	return p, nil
+}
+
+func (r *PetsRepo) Create(ctx context.Context, p pets.Pet) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO pets (`+petColumns+`
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)
 	`,
 		p.ID,
 		p.OwnerUserID,
+		p.TenantID,
 		p.Name,
 		p.Species,
 		p.Breed,
@@ -37,8 +93,10 @@ func (r *PetsRepo) Create(ctx context.Context, p pets.Pet) error {
 		p.Notes,
 		p.CreatedAt,
 		p.UpdatedAt,
+		toNullTime(p.ArchivedAt),
+		string(p.EffectiveStatus()),
 	)
-	return err
+	return mapMicrochipConflict(err)
 }
 
 func (r *PetsRepo) Update(ctx context.Context, p pets.Pet) error {
@@ -52,8 +110,11 @@ func (r *PetsRepo) Update(ctx context.Context, p pets.Pet) error {
 			birth_date = $6,
 			microchip = $7,
 			notes = $8,
-			updated_at = $9
-		WHERE id = $1
+			updated_at = $9,
+			archived_at = $10,
+			owner_user_id = $11,
+			status = $12
+		WHERE id = $1 AND deleted_at IS NULL
 	`,
 		p.ID,
 		p.Name,
@@ -64,9 +125,12 @@ func (r *PetsRepo) Update(ctx context.Context, p pets.Pet) error {
 		p.Microchip,
 		p.Notes,
 		p.UpdatedAt,
+		toNullTime(p.ArchivedAt),
+		p.OwnerUserID,
+		string(p.EffectiveStatus()),
 	)
 	if err != nil {
-		return err
+		return mapMicrochipConflict(err)
 	}
 	n, _ := res.RowsAffected()
 	if n == 0 {
@@ -75,104 +139,159 @@ func (r *PetsRepo) Update(ctx context.Context, p pets.Pet) error {
 	return nil
 }
 
-func (r *PetsRepo) GetByID(ctx context.Context, id string) (pets.Pet, error) {
+// GetByID no distingue "no existe" de "es de otro tenant": ambos son ErrNotFound.
+func (r *PetsRepo) GetByID(ctx context.Context, tenantID, id string) (pets.Pet, error) {
 	id = strings.TrimSpace(id)
 	if id == "" {
 		return pets.Pet{}, ErrNotFound
 	}
 
-	row := r.db.QueryRowContext(ctx, `
-		SELECT
-			id, owner_user_id,
-			name, species, breed, sex,
-			birth_date, microchip, notes,
-			created_at, updated_at
-		FROM pets
-		WHERE id = $1
-	`, id)
+	return retryRead(ctx, r.cfg.readRetries, func() (pets.Pet, error) {
+		row := r.db.QueryRowContext(ctx, `
+			SELECT`+petColumns+`
+			FROM pets
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`, id, tenantID)
 
-	var p pets.Pet
-	var bd sql.NullTime
-	if err := row.Scan(
-		&p.ID,
-		&p.OwnerUserID,
-		&p.Name,
-		&p.Species,
-		&p.Breed,
-		&p.Sex,
-		&bd,
-		&p.Microchip,
-		&p.Notes,
-		&p.CreatedAt,
-		&p.UpdatedAt,
-	); err != nil {
-		if err == sql.ErrNoRows {
-			return pets.Pet{}, ErrNotFound
+		p, err := scanPet(row)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return pets.Pet{}, ErrNotFound
+			}
+			return pets.Pet{}, err
 		}
-		return pets.Pet{}, err
-	}
+		return p, nil
+	})
+}
 
-	if bd.Valid {
-		t := bd.Time
-		// ojo: birth_date es date, pgx lo puede mapear a time.Time midnight UTC
-		p.BirthDate = &t
-	} else {
-		p.BirthDate = nil
-	}
+// likeEscaper neutraliza los comodines de LIKE (el escape por defecto es la barra invertida).
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 
-	return p, nil
+// petSortColumns mapea los órdenes permitidos de ListByOwner a columnas.
+var petSortColumns = map[pets.ListSort]string{
+	pets.SortCreatedAt: "created_at",
+	pets.SortName:      "name",
+	pets.SortUpdatedAt: "updated_at",
 }
 
-func (r *PetsRepo) ListByOwner(ctx context.Context, ownerUserID string) ([]pets.Pet, error) {
+// ListByOwner excluye mascotas fusionadas (archived_at, origen de un merge) y borradas; las
+// archivadas por el owner (status) solo con filter.IncludeArchived.
+func (r *PetsRepo) ListByOwner(ctx context.Context, tenantID, ownerUserID string, filter pets.ListFilter) ([]pets.Pet, error) {
 	ownerUserID = strings.TrimSpace(ownerUserID)
 	if ownerUserID == "" {
 		return nil, nil
 	}
 
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT
-			id, owner_user_id,
-			name, species, breed, sex,
-			birth_date, microchip, notes,
-			created_at, updated_at
+	q := `
+		SELECT` + petColumns + `
 		FROM pets
-		WHERE owner_user_id = $1
-		ORDER BY created_at ASC
-	`, ownerUserID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	out := make([]pets.Pet, 0)
-	for rows.Next() {
-		var p pets.Pet
-		var bd sql.NullTime
-		if err := rows.Scan(
-			&p.ID,
-			&p.OwnerUserID,
-			&p.Name,
-			&p.Species,
-			&p.Breed,
-			&p.Sex,
-			&bd,
-			&p.Microchip,
-			&p.Notes,
-			&p.CreatedAt,
-			&p.UpdatedAt,
-		); err != nil {
+		WHERE owner_user_id = $1 AND tenant_id = $2 AND archived_at IS NULL AND deleted_at IS NULL`
+	args := []any{ownerUserID, tenantID}
+
+	if !filter.IncludeArchived {
+		args = append(args, string(pets.PetStatusArchived))
+		q += fmt.Sprintf(" AND status <> $%d", len(args))
+	}
+	if filter.Species != "" {
+		args = append(args, string(filter.Species))
+		q += fmt.Sprintf(" AND species = $%d", len(args))
+	}
+	if filter.Query != "" {
+		// Escapamos los comodines para que q= sea un substring literal.
+		args = append(args, "%"+likeEscaper.Replace(filter.Query)+"%")
+		q += fmt.Sprintf(" AND name ILIKE $%d", len(args))
+	}
+	// ORDER BY sale de una allow-list; nunca se interpola el input.
+	col, ok := petSortColumns[filter.Sort]
+	if !ok {
+		col = petSortColumns[pets.SortCreatedAt]
+	}
+	dir := "ASC"
+	if filter.Desc {
+		dir = "DESC"
+	}
+	q += " ORDER BY " + col + " " + dir + ", id " + dir
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		q += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		q += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return retryRead(ctx, r.cfg.readRetries, func() ([]pets.Pet, error) {
+		rows, err := r.db.QueryContext(ctx, q, args...)
+		if err != nil {
 			return nil, err
 		}
+		defer rows.Close()
 
-		if bd.Valid {
-			t := bd.Time
-			p.BirthDate = &t
+		out := make([]pets.Pet, 0)
+		for rows.Next() {
+			p, err := scanPet(rows)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, p)
 		}
 
-		out = append(out, p)
+		return out, rows.Err()
+	})
+}
+
+func (r *PetsRepo) ExistsByMicrochip(ctx context.Context, microchip string) (bool, error) {
+	microchip = strings.TrimSpace(microchip)
+	if microchip == "" {
+		return false, nil
 	}
 
-	return out, rows.Err()
+	return retryRead(ctx, r.cfg.readRetries, func() (bool, error) {
+		var exists bool
+		err := r.db.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM pets WHERE microchip = $1 AND deleted_at IS NULL)
+		`, microchip).Scan(&exists)
+		return exists, err
+	})
+}
+
+func (r *PetsRepo) GetByMicrochip(ctx context.Context, tenantID, microchip string) (pets.Pet, error) {
+	microchip = strings.TrimSpace(microchip)
+	if microchip == "" {
+		return pets.Pet{}, ErrNotFound
+	}
+
+	return retryRead(ctx, r.cfg.readRetries, func() (pets.Pet, error) {
+		row := r.db.QueryRowContext(ctx, `
+			SELECT`+petColumns+`
+			FROM pets
+			WHERE microchip = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`, microchip, tenantID)
+		p, err := scanPet(row)
+		if err == sql.ErrNoRows {
+			return pets.Pet{}, ErrNotFound
+		}
+		return p, err
+	})
+}
+
+// Delete es un borrado lógico (deleted_at): las FK de pet_events / access_grants son
+// ON DELETE CASCADE y un DELETE físico se llevaría el historial (anulado) con la mascota.
+// Una mascota borrada deja de existir para GetByID / ListByOwner / ExistsByMicrochip.
+func (r *PetsRepo) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE pets
+		SET deleted_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, strings.TrimSpace(id))
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
 
 // birth_date es DATE, lo pasamos como NullTime para simplificar
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type PreventiveRepo struct {
+	db *sql.DB
+}
+
+func NewPreventiveRepo(db *sql.DB) *PreventiveRepo {
+	return &PreventiveRepo{db: db}
+}
+
+func (r *PreventiveRepo) Create(ctx context.Context, d details.PreventiveTreatment) error {
+	return insertPreventive(ctx, r.db, d)
+}
+
+func insertPreventive(ctx context.Context, ex execer, d details.PreventiveTreatment) error {
+	_, err := ex.ExecContext(ctx, `
+		INSERT INTO event_preventive (
+			id, event_id,
+			kind, product, dose,
+			next_due, notes
+		) VALUES ($1,$2,$3,$4,$5,$6,$7)
+	`,
+		d.ID,
+		d.EventID,
+		string(d.Kind),
+		d.Product,
+		d.Dose,
+		toNullTime(d.NextDue),
+		d.Notes,
+	)
+	return err
+}
+
+func (r *PreventiveRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.PreventiveTreatment, error) {
+	out := make(map[string]details.PreventiveTreatment, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id, event_id,
+			kind, product, dose,
+			next_due, notes
+		FROM event_preventive
+		WHERE event_id = ANY($1)
+	`, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d details.PreventiveTreatment
+		var kind string
+		var nextDue sql.NullTime
+		if err := rows.Scan(
+			&d.ID,
+			&d.EventID,
+			&kind,
+			&d.Product,
+			&d.Dose,
+			&nextDue,
+			&d.Notes,
+		); err != nil {
+			return nil, err
+		}
+		d.Kind = details.PreventiveKind(kind)
+		if nextDue.Valid {
+			t := nextDue.Time
+			d.NextDue = &t
+		}
+		out[d.EventID] = d
+	}
+
+	return out, rows.Err()
+}
+
+func (r *PreventiveRepo) ListDue(ctx context.Context, petIDs []string, from, to time.Time, excludePrivate bool) ([]events.DueItem, error) {
+	if len(petIDs) == 0 {
+		return []events.DueItem{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			e.pet_id, e.id, e.type,
+			d.kind, d.product, d.next_due
+		FROM event_preventive d
+		JOIN pet_events e ON e.id = d.event_id
+		WHERE e.pet_id = ANY($1)
+		  AND e.status = 'active'
+		  AND d.next_due BETWEEN $2 AND $3
+		  AND (NOT $4 OR e.visibility <> 'private')
+		ORDER BY d.next_due ASC
+	`, petIDs, from, to, excludePrivate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]events.DueItem, 0)
+	for rows.Next() {
+		var it events.DueItem
+		var typ string
+		if err := rows.Scan(
+			&it.PetID,
+			&it.EventID,
+			&typ,
+			&it.Kind,
+			&it.Label,
+			&it.DueAt,
+		); err != nil {
+			return nil, err
+		}
+		it.EventType = events.EventType(typ)
+		out = append(out, it)
+	}
+
+	return out, rows.Err()
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DefaultReadRetries es cuántas veces se reintenta una lectura (además del primer intento)
+// ante un error transitorio.
+const DefaultReadRetries = 2
+
+// readRetryBackoff es la espera antes del primer reintento; crece lineal (x1, x2, ...).
+var readRetryBackoff = 50 * time.Millisecond
+
+// RepoOption configura los repos que reintentan lecturas (PetsRepo, EventsRepo, AccessGrantsRepo).
+type RepoOption func(*repoConfig)
+
+type repoConfig struct {
+	readRetries int
+}
+
+// WithReadRetries fija los reintentos de lecturas ante errores transitorios (default
+// DefaultReadRetries); <= 0 => sin reintentos.
+func WithReadRetries(n int) RepoOption {
+	return func(c *repoConfig) { c.readRetries = n }
+}
+
+func newRepoConfig(opts []RepoOption) repoConfig {
+	c := repoConfig{readRetries: DefaultReadRetries}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.readRetries < 0 {
+		c.readRetries = 0
+	}
+	return c
+}
+
+// retryRead corre fn hasta 1+retries veces mientras falle con un error transitorio
+// (isTransient), con un backoff corto que respeta ctx. Solo para lecturas: una escritura
+// reintentada a ciegas podría aplicarse dos veces si el primer intento llegó a commitear.
+func retryRead[T any](ctx context.Context, retries int, fn func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		v, err := fn()
+		if err == nil || attempt >= retries || !isTransient(err) || ctx.Err() != nil {
+			return v, err
+		}
+
+		t := time.NewTimer(readRetryBackoff * time.Duration(attempt+1))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return v, err
+		case <-t.C:
+		}
+	}
+}
+
+// isTransient detecta errores en los que reintentar una lectura tiene sentido: conexión
+// caída o reseteada, y conflictos de concurrencia de Postgres (serialization_failure,
+// deadlock_detected, clase 08 connection_exception). Los errores de contexto no lo son.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) || pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01" || strings.HasPrefix(pgErr.Code, "08")
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return strings.Contains(err.Error(), "conn closed")
+}
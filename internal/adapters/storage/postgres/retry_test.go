@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRetryRead_TransientThenSuccess(t *testing.T) {
+	old := readRetryBackoff
+	readRetryBackoff = time.Millisecond
+	defer func() { readRetryBackoff = old }()
+
+	for _, transient := range []error{
+		driver.ErrBadConn,
+		fmt.Errorf("query: %w", &pgconn.PgError{Code: "40001"}),
+		&pgconn.PgError{Code: "08006"},
+		errors.New("conn closed"),
+	} {
+		calls := 0
+		got, err := retryRead(context.Background(), 2, func() (string, error) {
+			calls++
+			if calls == 1 {
+				return "", transient
+			}
+			return "ok", nil
+		})
+		if err != nil || got != "ok" || calls != 2 {
+			t.Fatalf("%v: expected success on 2nd attempt, got %q err=%v calls=%d", transient, got, err, calls)
+		}
+	}
+}
+
+func TestRetryRead_LimitsAndNonTransient(t *testing.T) {
+	old := readRetryBackoff
+	readRetryBackoff = time.Millisecond
+	defer func() { readRetryBackoff = old }()
+
+	// Siempre transitorio: 1 + retries intentos y devuelve el último error
+	calls := 0
+	_, err := retryRead(context.Background(), 2, func() (int, error) {
+		calls++
+		return 0, driver.ErrBadConn
+	})
+	if !errors.Is(err, driver.ErrBadConn) || calls != 3 {
+		t.Fatalf("expected 3 attempts ending in ErrBadConn, got calls=%d err=%v", calls, err)
+	}
+
+	// Errores no transitorios (not found, violación de constraint) no se reintentan
+	for _, permanent := range []error{ErrNotFound, &pgconn.PgError{Code: "23505"}} {
+		calls = 0
+		_, err = retryRead(context.Background(), 2, func() (int, error) {
+			calls++
+			return 0, permanent
+		})
+		if calls != 1 || err == nil {
+			t.Fatalf("%v: expected a single attempt, got calls=%d err=%v", permanent, calls, err)
+		}
+	}
+
+	// Sin reintentos configurados
+	calls = 0
+	_, _ = retryRead(context.Background(), newRepoConfig([]RepoOption{WithReadRetries(-1)}).readRetries, func() (int, error) {
+		calls++
+		return 0, driver.ErrBadConn
+	})
+	if calls != 1 {
+		t.Fatalf("expected no retries with WithReadRetries(-1), got %d calls", calls)
+	}
+
+	// Contexto cancelado: no se reintenta
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	_, _ = retryRead(ctx, 2, func() (int, error) {
+		calls++
+		return 0, driver.ErrBadConn
+	})
+	if calls != 1 {
+		t.Fatalf("expected no retries with canceled context, got %d calls", calls)
+	}
+}
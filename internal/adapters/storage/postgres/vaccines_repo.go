@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+type VaccinesRepo struct {
+	db *sql.DB
+}
+
+func NewVaccinesRepo(db *sql.DB) *VaccinesRepo {
+	return &VaccinesRepo{db: db}
+}
+
+func (r *VaccinesRepo) Create(ctx context.Context, d details.Vaccine) error {
+	return insertVaccine(ctx, r.db, d)
+}
+
+func insertVaccine(ctx context.Context, ex execer, d details.Vaccine) error {
+	_, err := ex.ExecContext(ctx, `
+		INSERT INTO event_vaccines (
+			id, event_id,
+			name, lot, next_due
+		) VALUES ($1,$2,$3,$4,$5)
+	`,
+		d.ID,
+		d.EventID,
+		d.Name,
+		d.Lot,
+		toNullTime(d.NextDue),
+	)
+	return err
+}
+
+func (r *VaccinesRepo) ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Vaccine, error) {
+	out := make(map[string]details.Vaccine, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id, event_id,
+			name, lot, next_due
+		FROM event_vaccines
+		WHERE event_id = ANY($1)
+	`, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d details.Vaccine
+		var nextDue sql.NullTime
+		if err := rows.Scan(
+			&d.ID,
+			&d.EventID,
+			&d.Name,
+			&d.Lot,
+			&nextDue,
+		); err != nil {
+			return nil, err
+		}
+		if nextDue.Valid {
+			t := nextDue.Time
+			d.NextDue = &t
+		}
+		out[d.EventID] = d
+	}
+
+	return out, rows.Err()
+}
+
+func (r *VaccinesRepo) ListDue(ctx context.Context, petIDs []string, from, to time.Time, excludePrivate bool) ([]events.DueItem, error) {
+	if len(petIDs) == 0 {
+		return []events.DueItem{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			e.pet_id, e.id, e.type,
+			d.name, d.next_due
+		FROM event_vaccines d
+		JOIN pet_events e ON e.id = d.event_id
+		WHERE e.pet_id = ANY($1)
+		  AND e.status = 'active'
+		  AND d.next_due BETWEEN $2 AND $3
+		  AND (NOT $4 OR e.visibility <> 'private')
+		ORDER BY d.next_due ASC
+	`, petIDs, from, to, excludePrivate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]events.DueItem, 0)
+	for rows.Next() {
+		var it events.DueItem
+		var typ string
+		if err := rows.Scan(
+			&it.PetID,
+			&it.EventID,
+			&typ,
+			&it.Label,
+			&it.DueAt,
+		); err != nil {
+			return nil, err
+		}
+		it.EventType = events.EventType(typ)
+		it.Kind = events.DueKindVaccine
+		out = append(out, it)
+	}
+
+	return out, rows.Err()
+}
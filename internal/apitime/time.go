@@ -0,0 +1,79 @@
+// Package apitime define cómo se serializan los timestamps en las respuestas de la API.
+// El almacenamiento sigue usando time.Time; solo cambia la representación JSON.
+package apitime
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format es la representación JSON de los timestamps.
+type Format string
+
+const (
+	// FormatRFC3339 es el default: igual al marshaling estándar de time.Time.
+	FormatRFC3339 Format = "rfc3339"
+	// FormatEpochMillis emite milisegundos desde epoch (número JSON).
+	FormatEpochMillis Format = "epoch_ms"
+)
+
+// ParseFormat normaliza un formato recibido por env o header. ok=false si no se reconoce.
+func ParseFormat(raw string) (Format, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "rfc3339":
+		return FormatRFC3339, true
+	case "epoch_ms", "epoch-ms", "epochms", "unix_ms":
+		return FormatEpochMillis, true
+	default:
+		return "", false
+	}
+}
+
+// Time es un timestamp de respuesta que se serializa según su Format.
+type Time struct {
+	t      time.Time
+	format Format
+}
+
+// New crea un Time con el formato indicado (vacío => RFC3339).
+func New(t time.Time, f Format) Time {
+	return Time{t: t, format: f}
+}
+
+// NewPtr es New para campos opcionales: nil => nil (para omitempty).
+func NewPtr(t *time.Time, f Format) *Time {
+	if t == nil {
+		return nil
+	}
+	v := New(*t, f)
+	return &v
+}
+
+// Time devuelve el time.Time subyacente.
+func (t Time) Time() time.Time {
+	return t.t
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.format == FormatEpochMillis {
+		return strconv.AppendInt(nil, t.t.UnixMilli(), 10), nil
+	}
+	return t.t.MarshalJSON()
+}
+
+type ctxKey struct{}
+
+// WithFormat guarda el formato de respuesta en el context del request.
+func WithFormat(ctx context.Context, f Format) context.Context {
+	return context.WithValue(ctx, ctxKey{}, f)
+}
+
+// FromContext devuelve el formato del request (RFC3339 si no se configuró).
+func FromContext(ctx context.Context) Format {
+	if f, ok := ctx.Value(ctxKey{}).(Format); ok && f != "" {
+		return f
+	}
+	return FormatRFC3339
+}
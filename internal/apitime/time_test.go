@@ -0,0 +1,49 @@
+package apitime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTime_MarshalJSON_Formats(t *testing.T) {
+	known := time.Date(2025, 12, 22, 10, 30, 0, 123_000_000, time.UTC)
+
+	cases := []struct {
+		format Format
+		want   string
+	}{
+		{FormatRFC3339, `"2025-12-22T10:30:00.123Z"`},
+		{"", `"2025-12-22T10:30:00.123Z"`},
+		{FormatEpochMillis, `1766399400123`},
+	}
+
+	for _, tc := range cases {
+		b, err := json.Marshal(New(known, tc.format))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.format, err)
+		}
+		if string(b) != tc.want {
+			t.Fatalf("%q: expected %s, got %s", tc.format, tc.want, string(b))
+		}
+	}
+
+	// Campos opcionales: nil se omite con omitempty.
+	var out struct {
+		At *Time `json:"at,omitempty"`
+	}
+	out.At = NewPtr(nil, FormatEpochMillis)
+	b, _ := json.Marshal(out)
+	if string(b) != `{}` {
+		t.Fatalf("expected nil pointer omitted, got %s", string(b))
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, ok := ParseFormat(" EPOCH-MS "); !ok || f != FormatEpochMillis {
+		t.Fatalf("expected epoch_ms, got %q ok=%v", f, ok)
+	}
+	if _, ok := ParseFormat("unix"); ok {
+		t.Fatalf("expected unknown format to be rejected")
+	}
+}
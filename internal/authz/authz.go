@@ -0,0 +1,188 @@
+// Package authz centraliza la decisión de acceso de los handlers que exponen recursos de una
+// mascota (401 / 404 / 403), para que todos la tomen en el mismo orden y respondan igual.
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/httpjson"
+	"pet-clinical-history/internal/ports/auth"
+)
+
+// ErrPetNotFound: HasAccess no encontró la mascota (o no es visible para el tenant del ctx).
+var ErrPetNotFound = errors.New("pet not found")
+
+// Outcome es el resultado de Authorize.
+type Outcome int
+
+const (
+	// Allow: el caller es el dueño o un delegado con grant activo y el scope requerido.
+	Allow Outcome = iota
+	// Unauthorized: el request no trae claims válidos (401).
+	Unauthorized
+	// NotFound: la mascota no existe (o no es visible para el tenant del caller) (404).
+	NotFound
+	// Forbidden: la mascota existe pero el caller no tiene acceso (403 con error.reason).
+	Forbidden
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Allow:
+		return "allow"
+	case Unauthorized:
+		return "unauthorized"
+	case NotFound:
+		return "not_found"
+	case Forbidden:
+		return "forbidden"
+	}
+	return "unknown"
+}
+
+// ScopeChecker resuelve si un delegado tiene un grant activo con un scope (accessgrants.Service).
+type ScopeChecker interface {
+	HasActiveScope(ctx context.Context, petID, granteeUserID string, scope accessgrants.Scope) (accessgrants.Grant, accessgrants.DenyReason, error)
+}
+
+// OwnerLookup resuelve el dueño de una mascota (pets.Service.OwnerOf); error => no existe.
+type OwnerLookup interface {
+	OwnerOf(ctx context.Context, petID string) (string, error)
+}
+
+// PetRef identifica la mascota a la que se accede y su dueño.
+type PetRef struct {
+	ID          string
+	OwnerUserID string
+}
+
+// PetLookup carga la mascota del request; found=false si no existe. Se llama solo si hay
+// claims, así un request anónimo no averigua qué mascotas existen.
+type PetLookup func(ctx context.Context) (pet PetRef, found bool)
+
+// Decision es la decisión de acceso de un request.
+type Decision struct {
+	Outcome Outcome
+
+	// Claims del caller (vacío si Unauthorized).
+	Claims auth.Claims
+	// IsOwner indica que el caller es el dueño (Allow sin grant).
+	IsOwner bool
+	// Grant es el grant con el que pasó un delegado (solo Allow de delegado).
+	Grant accessgrants.Grant
+	// Reason explica un Forbidden de delegado (no_grant, grant_not_active, missing_scope:<scope>, ...).
+	Reason accessgrants.DenyReason
+}
+
+// Allowed indica si el acceso está permitido.
+func (d Decision) Allowed() bool { return d.Outcome == Allow }
+
+// IsDelegate indica un acceso permitido por grant (no por ser dueño).
+func (d Decision) IsDelegate() bool { return d.Outcome == Allow && !d.IsOwner }
+
+// Concealed convierte un Forbidden en NotFound, para endpoints que no deben revelar
+// que el recurso existe (p.ej. la búsqueda por microchip).
+func (d Decision) Concealed() Decision {
+	if d.Outcome == Forbidden {
+		return Decision{Outcome: NotFound, Claims: d.Claims}
+	}
+	return d
+}
+
+// Authorize decide el acceso del caller de r a la mascota que carga lookup, siempre en este orden:
+//  1. sin claims (o sin UserID) => Unauthorized;
+//  2. lookup no la encuentra => NotFound;
+//  3. el caller es el dueño => Allow;
+//  4. scope vacío (solo dueño) => Forbidden;
+//  5. delegado: Allow si tiene un grant activo con scope, si no Forbidden con la razón.
+//
+// error solo se devuelve si falla la consulta de grants (el handler responde 500).
+func Authorize(r *http.Request, grants ScopeChecker, lookup PetLookup, scope accessgrants.Scope) (Decision, error) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok || strings.TrimSpace(claims.UserID) == "" {
+		return Decision{Outcome: Unauthorized}, nil
+	}
+
+	pet, found := lookup(r.Context())
+	if !found {
+		return Decision{Outcome: NotFound, Claims: claims}, nil
+	}
+	return decide(r.Context(), grants, pet, claims, scope)
+}
+
+// HasAccess aplica la misma regla que Authorize fuera de un request HTTP (jobs, otros
+// servicios): true si userID es el dueño de petID, que tiene implícitamente todos los scopes,
+// o un delegado con un grant activo que incluya required. required vacío => solo el dueño.
+// Si owners no encuentra la mascota devuelve ErrPetNotFound.
+func HasAccess(ctx context.Context, owners OwnerLookup, grants ScopeChecker, petID, userID string, required accessgrants.Scope) (bool, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return false, nil
+	}
+	ownerID, err := owners.OwnerOf(ctx, petID)
+	if err != nil || strings.TrimSpace(ownerID) == "" {
+		return false, ErrPetNotFound
+	}
+
+	d, err := decide(ctx, grants, PetRef{ID: petID, OwnerUserID: ownerID}, auth.Claims{UserID: userID}, required)
+	if err != nil {
+		return false, err
+	}
+	return d.Allowed(), nil
+}
+
+// decide son los pasos 3-5 de Authorize: la mascota existe y el caller está identificado.
+func decide(ctx context.Context, grants ScopeChecker, pet PetRef, claims auth.Claims, scope accessgrants.Scope) (Decision, error) {
+	if pet.OwnerUserID == claims.UserID {
+		return Decision{Outcome: Allow, Claims: claims, IsOwner: true}, nil
+	}
+	if scope == "" {
+		return Decision{Outcome: Forbidden, Claims: claims}, nil
+	}
+
+	g, reason, err := grants.HasActiveScope(ctx, pet.ID, claims.UserID, scope)
+	if err != nil {
+		return Decision{}, err
+	}
+	if reason != "" {
+		return Decision{Outcome: Forbidden, Claims: claims, Reason: reason}, nil
+	}
+	return Decision{Outcome: Allow, Claims: claims, Grant: g}, nil
+}
+
+// WriteDenied responde el rechazo de d (401, 404 "pet not found" o 403 con error.reason), o
+// 500 si err != nil. Devuelve true si respondió: el handler debe cortar ahí.
+func WriteDenied(w http.ResponseWriter, d Decision, err error) bool {
+	if err != nil {
+		httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+		return true
+	}
+
+	switch d.Outcome {
+	case Allow:
+		return false
+	case Unauthorized:
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+	case NotFound:
+		httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+	default:
+		WriteForbidden(w, d.Reason)
+	}
+	return true
+}
+
+// WriteForbidden responde 403 indicando por qué se negó el acceso al delegado
+// (no_grant, grant_not_active, missing_scope:<scope>); sin razón, solo "forbidden".
+func WriteForbidden(w http.ResponseWriter, reason accessgrants.DenyReason) {
+	httpjson.WriteJSON(w, http.StatusForbidden, httpjson.ErrorBody{Error: httpjson.ErrorDetail{
+		Code:    httpjson.CodeForbidden,
+		Message: "forbidden",
+		Reason:  string(reason),
+	}})
+}
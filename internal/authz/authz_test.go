@@ -0,0 +1,222 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/httpjson"
+)
+
+// fakeGrants responde HasActiveScope con valores fijos y registra si se consultó.
+type fakeGrants struct {
+	grant  accessgrants.Grant
+	reason accessgrants.DenyReason
+	err    error
+	called bool
+}
+
+func (f *fakeGrants) HasActiveScope(_ context.Context, _, _ string, _ accessgrants.Scope) (accessgrants.Grant, accessgrants.DenyReason, error) {
+	f.called = true
+	return f.grant, f.reason, f.err
+}
+
+// authorize corre Authorize detrás de AuthContext en modo dev (userID "" => sin claims).
+func authorize(t *testing.T, userID string, pet *PetRef, grants ScopeChecker, scope accessgrants.Scope) (Decision, error) {
+	t.Helper()
+	var d Decision
+	var err error
+	h := middleware.AuthContext(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d, err = Authorize(r, grants, func(context.Context) (PetRef, bool) {
+			if pet == nil {
+				return PetRef{}, false
+			}
+			return *pet, true
+		}, scope)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/pets/pet-1", nil)
+	if userID != "" {
+		req.Header.Set("X-Debug-User-ID", userID)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return d, err
+}
+
+func TestAuthorize_Decisions(t *testing.T) {
+	pet := &PetRef{ID: "pet-1", OwnerUserID: "owner-1"}
+	grant := accessgrants.Grant{ID: "grant-1"}
+
+	cases := []struct {
+		name       string
+		userID     string
+		pet        *PetRef
+		grants     *fakeGrants
+		scope      accessgrants.Scope
+		want       Outcome
+		wantReason accessgrants.DenyReason
+		wantOwner  bool
+		wantGrant  string
+		wantCheck  bool
+	}{
+		{name: "sin claims", userID: "", pet: pet, grants: &fakeGrants{}, scope: accessgrants.ScopePetRead, want: Unauthorized},
+		{name: "mascota inexistente", userID: "vet-1", pet: nil, grants: &fakeGrants{}, scope: accessgrants.ScopePetRead, want: NotFound},
+		{name: "dueño", userID: "owner-1", pet: pet, grants: &fakeGrants{}, scope: accessgrants.ScopePetRead, want: Allow, wantOwner: true},
+		{name: "solo dueño", userID: "vet-1", pet: pet, grants: &fakeGrants{}, scope: "", want: Forbidden},
+		{name: "delegado con scope", userID: "vet-1", pet: pet, grants: &fakeGrants{grant: grant}, scope: accessgrants.ScopePetRead, want: Allow, wantGrant: "grant-1", wantCheck: true},
+		{name: "delegado sin scope", userID: "vet-1", pet: pet, grants: &fakeGrants{reason: accessgrants.DenyMissingScope(accessgrants.ScopePetRead)}, scope: accessgrants.ScopePetRead, want: Forbidden, wantReason: "missing_scope:pet:read", wantCheck: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := authorize(t, tc.userID, tc.pet, tc.grants, tc.scope)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d.Outcome != tc.want || d.Reason != tc.wantReason || d.IsOwner != tc.wantOwner || d.Grant.ID != tc.wantGrant {
+				t.Fatalf("expected %v (reason %q, owner %v, grant %q), got %+v", tc.want, tc.wantReason, tc.wantOwner, tc.wantGrant, d)
+			}
+			if tc.grants.called != tc.wantCheck {
+				t.Fatalf("expected grants lookup=%v, got %v", tc.wantCheck, tc.grants.called)
+			}
+			if d.Allowed() && d.IsDelegate() == tc.wantOwner {
+				t.Fatalf("expected IsDelegate=%v, got %v", !tc.wantOwner, d.IsDelegate())
+			}
+		})
+	}
+}
+
+func TestAuthorize_LookupOnlyWithClaims(t *testing.T) {
+	looked := false
+	req := httptest.NewRequest(http.MethodGet, "/pets/pet-1", nil)
+	d, err := Authorize(req, &fakeGrants{}, func(context.Context) (PetRef, bool) {
+		looked = true
+		return PetRef{}, false
+	}, accessgrants.ScopePetRead)
+	if err != nil || d.Outcome != Unauthorized {
+		t.Fatalf("expected Unauthorized, got %+v err=%v", d, err)
+	}
+	if looked {
+		t.Fatalf("expected no pet lookup without claims")
+	}
+}
+
+func TestAuthorize_GrantsError(t *testing.T) {
+	boom := errors.New("db down")
+	_, err := authorize(t, "vet-1", &PetRef{ID: "pet-1", OwnerUserID: "owner-1"}, &fakeGrants{err: boom}, accessgrants.ScopePetRead)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected grants error, got %v", err)
+	}
+}
+
+func TestDecision_Concealed(t *testing.T) {
+	d := Decision{Outcome: Forbidden, Reason: accessgrants.DenyNoGrant}.Concealed()
+	if d.Outcome != NotFound || d.Reason != "" {
+		t.Fatalf("expected Forbidden concealed as NotFound, got %+v", d)
+	}
+	if d := (Decision{Outcome: Allow, IsOwner: true}).Concealed(); d.Outcome != Allow {
+		t.Fatalf("expected Allow unchanged, got %+v", d)
+	}
+}
+
+func TestWriteDenied(t *testing.T) {
+	cases := []struct {
+		name       string
+		d          Decision
+		err        error
+		wantStatus int
+		wantCode   string
+		wantReason string
+	}{
+		{name: "allow", d: Decision{Outcome: Allow}, wantStatus: 0},
+		{name: "unauthorized", d: Decision{Outcome: Unauthorized}, wantStatus: http.StatusUnauthorized, wantCode: httpjson.CodeUnauthorized},
+		{name: "not found", d: Decision{Outcome: NotFound}, wantStatus: http.StatusNotFound, wantCode: httpjson.CodeNotFound},
+		{name: "forbidden", d: Decision{Outcome: Forbidden, Reason: accessgrants.DenyGrantNotActive}, wantStatus: http.StatusForbidden, wantCode: httpjson.CodeForbidden, wantReason: "grant_not_active"},
+		{name: "error", d: Decision{}, err: errors.New("db down"), wantStatus: http.StatusInternalServerError, wantCode: httpjson.CodeInternal},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			wrote := WriteDenied(rec, tc.d, tc.err)
+			if tc.wantStatus == 0 {
+				if wrote || rec.Body.Len() != 0 {
+					t.Fatalf("expected nothing written for Allow, got %d %s", rec.Code, rec.Body.String())
+				}
+				return
+			}
+			if !wrote || rec.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got wrote=%v %d", tc.wantStatus, wrote, rec.Code)
+			}
+			var body httpjson.ErrorBody
+			_ = json.Unmarshal(rec.Body.Bytes(), &body)
+			if body.Error.Code != tc.wantCode || body.Error.Reason != tc.wantReason {
+				t.Fatalf("expected code %q reason %q, got %+v", tc.wantCode, tc.wantReason, body.Error)
+			}
+			if tc.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Fatalf("expected WWW-Authenticate on 401")
+			}
+		})
+	}
+}
+
+// fakeOwners resuelve el dueño desde un mapa petID => ownerID.
+type fakeOwners map[string]string
+
+func (f fakeOwners) OwnerOf(_ context.Context, petID string) (string, error) {
+	owner, ok := f[petID]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return owner, nil
+}
+
+func TestHasAccess(t *testing.T) {
+	owners := fakeOwners{"pet-1": "owner-1"}
+
+	cases := []struct {
+		name      string
+		userID    string
+		grants    *fakeGrants
+		scope     accessgrants.Scope
+		want      bool
+		wantCheck bool
+	}{
+		{name: "dueño (todos los scopes implícitos)", userID: "owner-1", grants: &fakeGrants{}, scope: accessgrants.ScopeEventsCreate, want: true},
+		{name: "dueño en acción solo dueño", userID: "owner-1", grants: &fakeGrants{}, scope: "", want: true},
+		{name: "delegado con scope", userID: "vet-1", grants: &fakeGrants{grant: accessgrants.Grant{ID: "grant-1"}}, scope: accessgrants.ScopePetRead, want: true, wantCheck: true},
+		{name: "delegado sin scope", userID: "vet-1", grants: &fakeGrants{reason: accessgrants.DenyMissingScope(accessgrants.ScopeEventsCreate)}, scope: accessgrants.ScopeEventsCreate, want: false, wantCheck: true},
+		{name: "sin grant", userID: "stranger", grants: &fakeGrants{reason: accessgrants.DenyNoGrant}, scope: accessgrants.ScopePetRead, want: false, wantCheck: true},
+		{name: "delegado en acción solo dueño", userID: "vet-1", grants: &fakeGrants{grant: accessgrants.Grant{ID: "grant-1"}}, scope: "", want: false},
+		{name: "sin usuario", userID: "", grants: &fakeGrants{}, scope: accessgrants.ScopePetRead, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := HasAccess(context.Background(), owners, tc.grants, "pet-1", tc.userID, tc.scope)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+			if tc.grants.called != tc.wantCheck {
+				t.Fatalf("expected grants lookup=%v, got %v", tc.wantCheck, tc.grants.called)
+			}
+		})
+	}
+}
+
+func TestHasAccess_Errors(t *testing.T) {
+	owners := fakeOwners{"pet-1": "owner-1"}
+
+	if _, err := HasAccess(context.Background(), owners, &fakeGrants{}, "missing", "owner-1", accessgrants.ScopePetRead); !errors.Is(err, ErrPetNotFound) {
+		t.Fatalf("expected ErrPetNotFound, got %v", err)
+	}
+
+	boom := errors.New("db down")
+	if ok, err := HasAccess(context.Background(), owners, &fakeGrants{err: boom}, "pet-1", "vet-1", accessgrants.ScopePetRead); ok || !errors.Is(err, boom) {
+		t.Fatalf("expected grants error, got ok=%v err=%v", ok, err)
+	}
+}
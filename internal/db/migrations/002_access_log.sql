@@ -0,0 +1,17 @@
+-- 002_access_log.sql
+-- Registro de lecturas de delegados (perfil / timeline) por mascota
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS pet_access_log (
+  id              text PRIMARY KEY,
+  pet_id          text NOT NULL REFERENCES pets(id) ON DELETE CASCADE,
+  grantee_user_id text NOT NULL,
+
+  resource text NOT NULL,
+  at       timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_log_pet_at ON pet_access_log(pet_id, at DESC);
+
+COMMIT;
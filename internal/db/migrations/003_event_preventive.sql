@@ -0,0 +1,22 @@
+-- 003_event_preventive.sql
+-- Detalle de tratamientos preventivos (desparasitación / antipulgas), 1:1 con pet_events
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS event_preventive (
+  id       text PRIMARY KEY,
+  event_id text NOT NULL UNIQUE REFERENCES pet_events(id) ON DELETE CASCADE,
+
+  kind     text NOT NULL,
+  product  text NOT NULL DEFAULT '',
+  dose     text NOT NULL DEFAULT '',
+  next_due timestamptz NULL,
+  notes    text NOT NULL DEFAULT ''
+);
+
+-- recordatorios: próximos vencimientos
+CREATE INDEX IF NOT EXISTS idx_event_preventive_next_due
+  ON event_preventive(next_due)
+  WHERE next_due IS NOT NULL;
+
+COMMIT;
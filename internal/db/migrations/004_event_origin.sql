@@ -0,0 +1,10 @@
+-- 004_event_origin.sql
+-- Origen de eventos cargados por integraciones (clínica / sistema externo)
+
+BEGIN;
+
+ALTER TABLE pet_events
+  ADD COLUMN IF NOT EXISTS origin_clinic_id text NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS origin_system    text NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- 005_grant_subdelegation.sql
+-- Sub-delegación: un delegado con grants:delegate puede otorgar grants derivados del suyo
+
+BEGIN;
+
+ALTER TABLE access_grants
+  ADD COLUMN IF NOT EXISTS delegated_by_user_id text NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS parent_grant_id      text NOT NULL DEFAULT '';
+
+-- cascada de revocación: hijos de un grant
+CREATE INDEX IF NOT EXISTS idx_grants_parent ON access_grants(parent_grant_id)
+  WHERE parent_grant_id <> '';
+
+COMMIT;
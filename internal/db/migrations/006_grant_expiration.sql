@@ -0,0 +1,9 @@
+-- 006_grant_expiration.sql
+-- Vencimiento opcional de grants: pasado expires_at el grant deja de estar activo
+
+BEGIN;
+
+ALTER TABLE access_grants
+  ADD COLUMN IF NOT EXISTS expires_at timestamptz NULL;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- 007_pet_microchip_index.sql
+-- Lookup por microchip (chequeo de disponibilidad antes de crear)
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_pets_microchip ON pets(microchip)
+  WHERE microchip <> '';
+
+COMMIT;
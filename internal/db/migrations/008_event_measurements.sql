@@ -0,0 +1,15 @@
+-- 008_event_measurements.sql
+-- Mediciones estructuradas (peso de WEIGHT_RECORDED), 1:1 con pet_events
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS event_measurements (
+  id       text PRIMARY KEY,
+  event_id text NOT NULL UNIQUE REFERENCES pet_events(id) ON DELETE CASCADE,
+
+  kind     text NOT NULL,
+  value    double precision NOT NULL,
+  unit     text NOT NULL
+);
+
+COMMIT;
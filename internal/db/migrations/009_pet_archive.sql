@@ -0,0 +1,9 @@
+-- 009_pet_archive.sql
+-- Mascotas archivadas (origen de un merge de duplicados); no aparecen en listados
+
+BEGIN;
+
+ALTER TABLE pets
+  ADD COLUMN IF NOT EXISTS archived_at timestamptz NULL;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- 010_event_attachments.sql
+-- Adjuntos de eventos (N por evento): referencia a un archivo en storage externo
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS event_attachments (
+  id           text PRIMARY KEY,
+  event_id     text NOT NULL REFERENCES pet_events(id) ON DELETE CASCADE,
+
+  file_name    text NOT NULL,
+  content_type text NOT NULL DEFAULT '',
+  url          text NOT NULL,
+  size_bytes   bigint NOT NULL DEFAULT 0,
+
+  added_by     text NOT NULL,
+  created_at   timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_attachments_event
+  ON event_attachments (event_id, created_at);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- 011_grant_status_lookup.sql
+-- Lookup de grants por mascota + delegado + estado (invite / accept / HasActiveScope)
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_grants_pet_grantee_status
+  ON access_grants(pet_id, grantee_user_id, status);
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- 012_pet_soft_delete.sql
+-- Borrado lógico de mascotas: conserva eventos (anulados) y grants (revocados),
+-- que se perderían por ON DELETE CASCADE con un DELETE físico
+
+BEGIN;
+
+ALTER TABLE pets
+  ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- 013_event_medications.sql
+-- Detalle de medicación de MEDICATION_PRESCRIBED, 1:1 con pet_events
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS event_medications (
+  id         text PRIMARY KEY,
+  event_id   text NOT NULL UNIQUE REFERENCES pet_events(id) ON DELETE CASCADE,
+
+  name       text NOT NULL,
+  dosage     text NOT NULL DEFAULT '',
+  dose_unit  text NOT NULL DEFAULT '',
+  frequency  text NOT NULL DEFAULT '',
+  start_date timestamptz NOT NULL,
+  end_date   timestamptz NULL,
+  notes      text NOT NULL DEFAULT ''
+);
+
+COMMIT;
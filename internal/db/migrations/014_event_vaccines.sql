@@ -0,0 +1,20 @@
+-- 014_event_vaccines.sql
+-- Detalle de vacunación de VACCINE (nombre, lote, próximo refuerzo), 1:1 con pet_events
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS event_vaccines (
+  id       text PRIMARY KEY,
+  event_id text NOT NULL UNIQUE REFERENCES pet_events(id) ON DELETE CASCADE,
+
+  name     text NOT NULL DEFAULT '',
+  lot      text NOT NULL DEFAULT '',
+  next_due timestamptz NULL
+);
+
+-- recordatorios: próximos refuerzos
+CREATE INDEX IF NOT EXISTS idx_event_vaccines_next_due
+  ON event_vaccines(next_due)
+  WHERE next_due IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- 015_tenant_isolation.sql
+-- Aislamiento multi-tenant: pets y access_grants guardan el tenant del caller que los creó.
+-- Los datos existentes quedan en el tenant por defecto ('').
+
+BEGIN;
+
+ALTER TABLE pets
+  ADD COLUMN IF NOT EXISTS tenant_id text NOT NULL DEFAULT '';
+
+ALTER TABLE access_grants
+  ADD COLUMN IF NOT EXISTS tenant_id text NOT NULL DEFAULT '';
+
+-- listado de mascotas del owner dentro de su tenant
+CREATE INDEX IF NOT EXISTS idx_pets_tenant_owner
+  ON pets(tenant_id, owner_user_id);
+
+COMMIT;
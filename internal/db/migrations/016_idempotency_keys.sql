@@ -0,0 +1,21 @@
+-- 016_idempotency_keys.sql
+-- Idempotency-Key de POST /pets y POST /pets/{petID}/events: key -> recurso creado,
+-- acotada por endpoint (scope) y usuario
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  scope       text NOT NULL,
+  user_id     text NOT NULL,
+  key         text NOT NULL,
+  resource_id text NOT NULL,
+  created_at  timestamptz NOT NULL,
+
+  PRIMARY KEY (scope, user_id, key)
+);
+
+-- limpieza de keys vencidas
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at
+  ON idempotency_keys(created_at);
+
+COMMIT;
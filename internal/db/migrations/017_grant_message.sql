@@ -0,0 +1,8 @@
+-- Mensaje opcional de la invitación (motivo que el owner le explica al delegado)
+
+BEGIN;
+
+ALTER TABLE access_grants
+  ADD COLUMN IF NOT EXISTS message text NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Último uso de un grant por el delegado (reporte de grants sin uso)
+
+BEGIN;
+
+ALTER TABLE access_grants
+  ADD COLUMN IF NOT EXISTS last_used_at timestamptz NULL;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Auditoría del void de eventos: quién lo anuló, cuándo y por qué
+
+BEGIN;
+
+ALTER TABLE pet_events
+  ADD COLUMN IF NOT EXISTS voided_by text NOT NULL DEFAULT '',
+  ADD COLUMN IF NOT EXISTS voided_at timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS void_reason text NOT NULL DEFAULT '';
+
+COMMIT;
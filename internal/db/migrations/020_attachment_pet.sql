@@ -0,0 +1,16 @@
+-- 020_attachment_pet.sql
+-- Los adjuntos guardan también la mascota (para listarlos/auditarlos sin pasar por el evento)
+
+BEGIN;
+
+ALTER TABLE event_attachments ADD COLUMN IF NOT EXISTS pet_id text NOT NULL DEFAULT '';
+
+UPDATE event_attachments a
+   SET pet_id = e.pet_id
+  FROM pet_events e
+ WHERE a.event_id = e.id AND a.pet_id = '';
+
+CREATE INDEX IF NOT EXISTS idx_event_attachments_pet
+  ON event_attachments (pet_id, created_at);
+
+COMMIT;
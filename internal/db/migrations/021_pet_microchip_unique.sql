@@ -0,0 +1,13 @@
+-- 021_pet_microchip_unique.sql
+-- Un microchip identifica a un único animal: no puede repetirse entre mascotas vigentes
+-- (las borradas lógicamente liberan el suyo). Reemplaza al índice no único de 007.
+-- Si ya hay duplicados la creación falla: resolverlos antes (p.ej. merge de mascotas).
+
+BEGIN;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pets_microchip ON pets(microchip)
+  WHERE microchip <> '' AND deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_pets_microchip;
+
+COMMIT;
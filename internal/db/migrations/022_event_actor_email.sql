@@ -0,0 +1,9 @@
+-- 022_event_actor_email.sql
+-- Auditoría: email del actor (claims del token) al crear / anular un evento
+
+BEGIN;
+
+ALTER TABLE pet_events ADD COLUMN IF NOT EXISTS actor_email text NOT NULL DEFAULT '';
+ALTER TABLE pet_events ADD COLUMN IF NOT EXISTS voided_by_email text NOT NULL DEFAULT '';
+
+COMMIT;
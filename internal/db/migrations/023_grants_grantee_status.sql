@@ -0,0 +1,10 @@
+-- 023_grants_grantee_status.sql
+-- GET /me/grants filtra por estado en SQL: índice por delegado + tenant + estado,
+-- en el orden del listado (updated_at DESC)
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_access_grants_grantee_status
+  ON access_grants (grantee_user_id, tenant_id, status, updated_at DESC);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- 024_pet_status.sql
+-- Archivado por el owner (mascota fallecida o dada en adopción): no aparece en GET /pets
+-- salvo ?include_archived=true, pero conserva su historial y sigue accesible por ID.
+-- Es independiente de archived_at (origen de un merge).
+
+BEGIN;
+
+ALTER TABLE pets
+  ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'active';
+
+COMMIT;
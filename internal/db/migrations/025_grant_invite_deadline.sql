@@ -0,0 +1,15 @@
+-- 025_grant_invite_deadline.sql
+-- Plazo para aceptar una invitación: pasado invite_expires_at no se puede aceptar y el
+-- barrido (ExpireStaleInvites) la pasa a status 'expired'. NULL => sin plazo (invitaciones
+-- previas a esta migración).
+
+BEGIN;
+
+ALTER TABLE access_grants
+  ADD COLUMN IF NOT EXISTS invite_expires_at timestamptz NULL;
+
+CREATE INDEX IF NOT EXISTS idx_access_grants_invite_expires
+  ON access_grants (invite_expires_at)
+  WHERE status = 'invited';
+
+COMMIT;
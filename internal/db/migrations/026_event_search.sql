@@ -0,0 +1,10 @@
+-- 026_event_search.sql
+-- Búsqueda con relevancia en GET /pets/{petID}/events?q=...&rank=true (to_tsvector /
+-- plainto_tsquery + ts_rank). La expresión debe coincidir con eventSearchDocument del repo.
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_pet_events_search
+  ON pet_events USING gin (to_tsvector('simple', title || ' ' || notes));
+
+COMMIT;
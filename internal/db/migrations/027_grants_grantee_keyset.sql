@@ -0,0 +1,10 @@
+-- 027_grants_grantee_keyset.sql
+-- GET /me/grants y GET /me/pets paginan por keyset (updated_at, id) DESC: índice por
+-- delegado + tenant en ese orden para el listado sin filtro de estado
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_access_grants_grantee_keyset
+  ON access_grants (grantee_user_id, tenant_id, updated_at DESC, id DESC);
+
+COMMIT;
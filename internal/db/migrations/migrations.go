@@ -0,0 +1,9 @@
+// Package migrations embebe el esquema Postgres (archivos NNN_nombre.sql, aplicados en orden).
+// Cada archivo debe ser re-ejecutable (IF NOT EXISTS): el runner registra la versión
+// después de aplicarlo, así que un corte entre ambos pasos lo vuelve a correr.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,78 @@
+package accessgrants
+
+import (
+	"net/http"
+	"strings"
+
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/httpjson"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// accessListEntry es un delegado (o invitado) de la mascota, resumido para mostrar.
+type accessListEntry struct {
+	GrantID       string        `json:"grant_id"`
+	GranteeUserID string        `json:"grantee_user_id"`
+	Status        Status        `json:"status"`
+	Scopes        []Scope       `json:"scopes"`
+	CreatedAt     apitime.Time  `json:"created_at" swaggertype:"string" format:"date-time"`
+	LastUsedAt    *apitime.Time `json:"last_used_at,omitempty" swaggertype:"string" format:"date-time"` // nil => nunca usado
+}
+
+// accessListHandler godoc
+// @Summary Quién puede ver esta mascota
+// @Description Vista consolidada para el owner de todos los delegados de la mascota: grants activos, invitaciones pendientes y revocados (al final, rechazados y vencidos), en ese orden y el más reciente primero dentro de cada estado. A diferencia de `GET /pets/{petID}/grants`, devuelve un resumen por grant (delegado, estado, scopes, alta y último uso). Solo el owner. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags accessgrants
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {array} accessListEntry
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/access-list [get]
+func accessListHandler(svc *Service, petOwners PetOwnerLookup) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		petID := chi.URLParam(r, "petID")
+
+		ownerID, err := petOwners.OwnerOf(r.Context(), petID)
+		if err != nil || strings.TrimSpace(ownerID) == "" {
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+			return
+		}
+		if ownerID != claims.UserID {
+			httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			return
+		}
+
+		items, err := svc.AccessList(r.Context(), petID)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		tf := apitime.FromContext(r.Context())
+		out := make([]accessListEntry, 0, len(items))
+		for _, g := range items {
+			out = append(out, accessListEntry{
+				GrantID:       g.ID,
+				GranteeUserID: g.GranteeUserID,
+				Status:        g.Status,
+				Scopes:        g.Scopes,
+				CreatedAt:     apitime.New(g.CreatedAt, tf),
+				LastUsedAt:    apitime.NewPtr(g.LastUsedAt, tf),
+			})
+		}
+		httpjson.WriteJSON(w, http.StatusOK, out)
+	}
+}
@@ -0,0 +1,49 @@
+package accessgrants
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor: el cursor de paginación no es uno emitido por la API.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor es la posición (keyset) del último grant de una página: los grants del delegado se
+// ordenan por (updated_at, id) desc, y la página siguiente empieza estrictamente después de él.
+type Cursor struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+// Encode devuelve el cursor opaco (base64 url-safe de "updated_at|id").
+func (c Cursor) Encode() string {
+	raw := c.UpdatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// After indica si g va después del cursor en el orden del listado (updated_at desc, id desc).
+func (c Cursor) After(g Grant) bool {
+	if !g.UpdatedAt.Equal(c.UpdatedAt) {
+		return g.UpdatedAt.Before(c.UpdatedAt)
+	}
+	return g.ID < c.ID
+}
+
+// ParseCursor decodifica un cursor emitido por Encode.
+func ParseCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(b), "|")
+	if !ok || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{UpdatedAt: t, ID: id}, nil
+}
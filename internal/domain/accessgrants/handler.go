@@ -3,11 +3,16 @@ package accessgrants
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"pet-clinical-history/internal/apitime"
 	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/httpjson"
+	"pet-clinical-history/internal/ports/auth"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -17,16 +22,26 @@ type PetOwnerLookup interface {
 	OwnerOf(ctx context.Context, petID string) (string, error)
 }
 
-func RegisterRoutes(r chi.Router, svc *Service, petOwners PetOwnerLookup) {
+// RegisterRoutes registra las rutas de grants. grantees es opcional: si es nil,
+// las invitaciones por grantee_email responden 501.
+func RegisterRoutes(r chi.Router, svc *Service, petOwners PetOwnerLookup, grantees auth.GranteeResolver) {
 	// Owner actions scoped by pet
 	r.Route("/pets/{petID}/grants", func(gr chi.Router) {
-		gr.Post("/", inviteGrantHandler(svc, petOwners))
+		gr.Post("/", inviteGrantHandler(svc, petOwners, grantees))
 		gr.Get("/", listGrantsByPetHandler(svc, petOwners))
+		gr.Post("/revoke-all", revokeAllGrantsHandler(svc, petOwners))
 	})
+	r.Get("/pets/{petID}/access-list", accessListHandler(svc, petOwners))
+
+	// Validación de scopes sin efectos (cualquier usuario autenticado)
+	r.Post("/grants/scopes/validate", validateScopesHandler(svc))
 
 	// Grantee/Owner actions scoped by grant id
 	r.Route("/grants/{grantID}", func(gr chi.Router) {
+		gr.Patch("/", updateGrantScopesHandler(svc))
 		gr.Post("/accept", acceptGrantHandler(svc))
+		gr.Post("/decline", declineGrantHandler(svc))
+		gr.Post("/leave", leaveGrantHandler(svc))
 		gr.Post("/revoke", revokeGrantHandler(svc))
 	})
 
@@ -38,26 +53,55 @@ func RegisterRoutes(r chi.Router, svc *Service, petOwners PetOwnerLookup) {
 
 // inviteGrantRequest es el cuerpo de la solicitud para invitar a un delegado a una mascota.
 type inviteGrantRequest struct {
-	GranteeUserID string  `json:"grantee_user_id"`
+	GranteeUserID string  `json:"grantee_user_id"` // uno de grantee_user_id / grantee_email
+	GranteeEmail  string  `json:"grantee_email"`   // se resuelve a user_id; si vienen ambos gana grantee_user_id
 	Scopes        []Scope `json:"scopes"`
+	ExpiresAt     string  `json:"expires_at,omitempty"`              // RFC3339 opcional; debe ser futuro
+	Message       string  `json:"message,omitempty" maxLength:"280"` // opcional: motivo para el delegado
 }
 
 // grantResponse representa un grant de acceso delegado en las respuestas de la API.
 type grantResponse struct {
-	ID            string     `json:"id"`
-	PetID         string     `json:"pet_id"`
-	OwnerUserID   string     `json:"owner_user_id"`
-	GranteeUserID string     `json:"grantee_user_id"`
-	Scopes        []Scope    `json:"scopes"`
-	Status        Status     `json:"status"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
-	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	ID            string        `json:"id"`
+	PetID         string        `json:"pet_id"`
+	OwnerUserID   string        `json:"owner_user_id"`
+	GranteeUserID string        `json:"grantee_user_id"`
+	Scopes        []Scope       `json:"scopes"`
+	Status        Status        `json:"status"`
+	CreatedAt     apitime.Time  `json:"created_at" swaggertype:"string" format:"date-time"`
+	UpdatedAt     apitime.Time  `json:"updated_at" swaggertype:"string" format:"date-time"`
+	RevokedAt     *apitime.Time `json:"revoked_at,omitempty" swaggertype:"string" format:"date-time"`
+	ExpiresAt     *apitime.Time `json:"expires_at,omitempty" swaggertype:"string" format:"date-time"`
+	Message       string        `json:"message,omitempty"`
+	LastUsedAt    *apitime.Time `json:"last_used_at,omitempty" swaggertype:"string" format:"date-time"` // nil => nunca usado
+
+	DelegatedByUserID string `json:"delegated_by_user_id,omitempty"`
+	ParentGrantID     string `json:"parent_grant_id,omitempty"`
+
+	// Plazo para aceptar la invitación (omitido si no tiene).
+	InviteExpiresAt *apitime.Time `json:"invite_expires_at,omitempty" swaggertype:"string" format:"date-time"`
+}
+
+// updateGrantScopesRequest es el cuerpo para reemplazar los scopes de un grant.
+type updateGrantScopesRequest struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+// validateScopesRequest es el cuerpo para validar un set de scopes propuesto.
+type validateScopesRequest struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+// validateScopesResponse indica qué scopes son válidos y cuáles no se reconocen.
+type validateScopesResponse struct {
+	Valid      bool    `json:"valid"`
+	Normalized []Scope `json:"normalized"`
+	Invalid    []Scope `json:"invalid"`
 }
 
 // inviteGrantHandler godoc
 // @Summary Invitar delegado a una mascota
-// @Description Crea una invitación (grant) para que otro usuario acceda a la mascota. Solo el owner de la mascota puede invitar. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Crea una invitación (grant) para que otro usuario acceda a la mascota. El owner siempre puede invitar. Un delegado con grant activo y scope `grants:delegate` puede sub-delegar, solo con un subconjunto de sus propios scopes; el owner puede revocar todo el árbol. Re-invitar a un grantee con grant vigente actualiza sus scopes, pero para cambiarlos se recomienda `PATCH /grants/{grantID}`. El delegado se indica por `grantee_user_id` o por `grantee_email` (se resuelve contra el IAM). Cada scope otorgado debe estar habilitado en el plan del owner (capability `pet:grants:<scope>`); sin resolver configurado no se valida (modo dev). Una mascota admite hasta 20 grants vigentes (invitados o activos; configurable): pasado el máximo una invitación nueva responde 409, pero re-invitar a un grantee existente sigue funcionando. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags accessgrants
 // @Accept json
 // @Produce json
@@ -66,17 +110,21 @@ type grantResponse struct {
 // @Param petID path string true "ID de la mascota compartida"
 // @Param payload body inviteGrantRequest true "Datos de la invitación (usuario delegado y scopes otorgados)"
 // @Success 201 {object} grantResponse
-// @Failure 400 {string} string "invalid json / invalid input / grantee_user_id requerido"
-// @Failure 401 {string} string "unauthorized"
-// @Failure 403 {string} string "forbidden"
-// @Failure 404 {string} string "pet not found"
-// @Failure 500 {string} string "internal error"
+// @Failure 400 {object} httpjson.ErrorBody "invalid json / invalid input / grantee_user_id o grantee_email requerido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 402 {object} httpjson.ErrorBody "error.code: capability_missing (el plan del owner no permite otorgar alguno de los scopes; el mensaje los lista)"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden / scopes exceed delegator's grant"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found / grantee not found (email sin usuario)"
+// @Failure 409 {object} httpjson.ErrorBody "too many grants for pet (la mascota ya tiene el máximo de grants vigentes)"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Failure 501 {object} httpjson.ErrorBody "grantee_email not supported (sin resolver configurado)"
+// @Failure 503 {object} httpjson.ErrorBody "capabilities unavailable"
 // @Router /pets/{petID}/grants [post]
-func inviteGrantHandler(svc *Service, petOwners PetOwnerLookup) http.HandlerFunc {
+func inviteGrantHandler(svc *Service, petOwners PetOwnerLookup, grantees auth.GranteeResolver) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetClaims(r.Context())
 		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
 			return
 		}
 
@@ -84,64 +132,146 @@ func inviteGrantHandler(svc *Service, petOwners PetOwnerLookup) http.HandlerFunc
 
 		ownerID, err := petOwners.OwnerOf(r.Context(), petID)
 		if err != nil || strings.TrimSpace(ownerID) == "" {
-			http.Error(w, "pet not found", http.StatusNotFound)
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
 			return
 		}
+
+		// No-owner: se intenta como sub-delegación (el service valida grants:delegate y scopes).
+		delegatorID := ""
 		if ownerID != claims.UserID {
-			http.Error(w, "forbidden", http.StatusForbidden)
-			return
+			delegatorID = claims.UserID
 		}
 
 		var req inviteGrantRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid json", http.StatusBadRequest)
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
 			return
 		}
-		if strings.TrimSpace(req.GranteeUserID) == "" {
-			http.Error(w, "grantee_user_id required", http.StatusBadRequest)
-			return
+		granteeID := strings.TrimSpace(req.GranteeUserID)
+		if granteeID == "" {
+			email := strings.TrimSpace(req.GranteeEmail)
+			if email == "" {
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "grantee_user_id or grantee_email required")
+				return
+			}
+			if grantees == nil {
+				httpjson.WriteError(w, http.StatusNotImplemented, httpjson.CodeNotImplemented, "grantee_email not supported")
+				return
+			}
+			granteeID, err = grantees.ResolveByEmail(r.Context(), email)
+			if err != nil {
+				if errors.Is(err, auth.ErrUserNotFound) {
+					httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "grantee not found")
+					return
+				}
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+				return
+			}
+		}
+		var expiresAt *time.Time
+		if strings.TrimSpace(req.ExpiresAt) != "" {
+			t, err := time.Parse(time.RFC3339, req.ExpiresAt)
+			if err != nil {
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "expires_at must be RFC3339")
+				return
+			}
+			expiresAt = &t
 		}
 
 		g, err := svc.Invite(r.Context(), InviteInput{
-			PetID:         petID,
-			OwnerUserID:   claims.UserID,
-			GranteeUserID: strings.TrimSpace(req.GranteeUserID),
-			Scopes:        req.Scopes,
+			PetID:           petID,
+			OwnerUserID:     ownerID,
+			GranteeUserID:   granteeID,
+			Scopes:          req.Scopes,
+			DelegatorUserID: delegatorID,
+			ExpiresAt:       expiresAt,
+			Message:         req.Message,
 		})
 		if err != nil {
+			var notInPlan *ScopesNotInPlanError
+			if errors.As(err, &notInPlan) {
+				httpjson.WriteError(w, http.StatusPaymentRequired, httpjson.CodeCapabilityMissing, err.Error())
+				return
+			}
 			switch err {
-			case ErrInvalidInput:
-				http.Error(w, err.Error(), http.StatusBadRequest)
+			case ErrInvalidInput, ErrMessageTooLong:
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			case ErrForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			case ErrScopesExceedDelegator:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, err.Error())
+			case ErrCapabilitiesUnavailable:
+				httpjson.WriteError(w, http.StatusServiceUnavailable, httpjson.CodeUnavailable, err.Error())
+			case ErrTooManyGrants:
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, err.Error())
 			default:
-				http.Error(w, "internal error", http.StatusInternalServerError)
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			}
 			return
 		}
 
-		writeJSON(w, http.StatusCreated, toGrantResponse(g))
+		httpjson.WriteJSON(w, http.StatusCreated, toGrantResponse(g, apitime.FromContext(r.Context())))
+	}
+}
+
+// validateScopesHandler godoc
+// @Summary Validar un set de scopes
+// @Description Valida un set de scopes propuesto con las mismas reglas que la invitación, sin crear nada. Devuelve los scopes normalizados (sin duplicados) y los no reconocidos, para que la UI pueda marcarlos antes de invitar. Cualquier usuario autenticado puede usarlo. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags accessgrants
+// @Accept json
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param payload body validateScopesRequest true "Scopes a validar"
+// @Success 200 {object} validateScopesResponse
+// @Failure 400 {object} httpjson.ErrorBody "invalid json"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Router /grants/scopes/validate [post]
+func validateScopesHandler(svc *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		var req validateScopesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
+			return
+		}
+
+		res := svc.ValidateScopes(req.Scopes)
+		httpjson.WriteJSON(w, http.StatusOK, validateScopesResponse{
+			Valid:      res.Valid,
+			Normalized: res.Normalized,
+			Invalid:    res.Invalid,
+		})
 	}
 }
 
 // listGrantsByPetHandler godoc
 // @Summary Listar grants por mascota
-// @Description Lista todos los grants asociados a una mascota. Solo el owner de la mascota puede verlos. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Lista todos los grants asociados a una mascota. Solo el owner de la mascota puede verlos. Cada grant trae `last_used_at` (último uso del delegado; ausente si nunca lo usó). Con `stale_days=N` devuelve solo los grants activos sin uso en los últimos N días (o nunca usados), para limpiar delegados que no usan su acceso. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags accessgrants
 // @Accept json
 // @Produce json
 // @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
 // @Param Authorization header string false "Bearer token en producción"
 // @Param petID path string true "ID de la mascota"
+// @Param stale_days query int false "Solo grants activos sin uso en los últimos N días (N > 0)"
 // @Success 200 {array} grantResponse
-// @Failure 401 {string} string "unauthorized"
-// @Failure 403 {string} string "forbidden"
-// @Failure 404 {string} string "pet not found"
-// @Failure 500 {string} string "internal error"
+// @Failure 400 {object} httpjson.ErrorBody "stale_days inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
 // @Router /pets/{petID}/grants [get]
 func listGrantsByPetHandler(svc *Service, petOwners PetOwnerLookup) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetClaims(r.Context())
 		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
 			return
 		}
 
@@ -149,80 +279,180 @@ func listGrantsByPetHandler(svc *Service, petOwners PetOwnerLookup) http.Handler
 
 		ownerID, err := petOwners.OwnerOf(r.Context(), petID)
 		if err != nil || strings.TrimSpace(ownerID) == "" {
-			http.Error(w, "pet not found", http.StatusNotFound)
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
 			return
 		}
 		if ownerID != claims.UserID {
-			http.Error(w, "forbidden", http.StatusForbidden)
+			httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
 			return
 		}
 
-		items, err := svc.ListByPet(r.Context(), petID)
-		if err != nil {
-			http.Error(w, "internal error", http.StatusInternalServerError)
-			return
+		var items []Grant
+		if raw := strings.TrimSpace(r.URL.Query().Get("stale_days")); raw != "" {
+			days, err := strconv.Atoi(raw)
+			if err != nil || days <= 0 {
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "stale_days must be a positive integer")
+				return
+			}
+			items, err = svc.ListStale(r.Context(), petID, time.Duration(days)*24*time.Hour)
+			if err != nil {
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+				return
+			}
+		} else {
+			items, err = svc.ListByPet(r.Context(), petID)
+			if err != nil {
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+				return
+			}
 		}
 
 		out := make([]grantResponse, 0, len(items))
 		for _, g := range items {
-			out = append(out, toGrantResponse(g))
+			out = append(out, toGrantResponse(g, apitime.FromContext(r.Context())))
+		}
+		httpjson.WriteJSON(w, http.StatusOK, out)
+	}
+}
+
+// revokeAllGrantsResponse resume una revocación masiva de grants de una mascota.
+type revokeAllGrantsResponse struct {
+	PetID         string `json:"pet_id"`
+	GrantsRevoked int    `json:"grants_revoked"`
+}
+
+// revokeAllGrantsHandler godoc
+// @Summary Revocar todos los grants de una mascota
+// @Description Revoca de una vez todos los grants vigentes (invited / active) de la mascota, incluidas las sub-delegaciones. Solo el owner. Es idempotente: una segunda llamada devuelve `grants_revoked: 0`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags accessgrants
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {object} revokeAllGrantsResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/grants/revoke-all [post]
+func revokeAllGrantsHandler(svc *Service, petOwners PetOwnerLookup) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		petID := chi.URLParam(r, "petID")
+
+		ownerID, err := petOwners.OwnerOf(r.Context(), petID)
+		if err != nil || strings.TrimSpace(ownerID) == "" {
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+			return
+		}
+		if ownerID != claims.UserID {
+			httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			return
+		}
+
+		n, err := svc.RevokeAllForPet(r.Context(), petID, claims.UserID)
+		if err != nil {
+			switch err {
+			case ErrForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
 		}
-		writeJSON(w, http.StatusOK, out)
+
+		httpjson.WriteJSON(w, http.StatusOK, revokeAllGrantsResponse{PetID: petID, GrantsRevoked: n})
 	}
 }
 
 // listMyGrantsHandler godoc
 // @Summary Listar mis grants como delegado
-// @Description Lista los grants donde el usuario autenticado es el delegado (grantee). Opcionalmente filtra por estado mediante `status=invited,active`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Lista los grants donde el usuario autenticado es el delegado (grantee). Opcionalmente filtra por estado mediante `status=invited,active`. Una invitación cuyo plazo para aceptar (`invite_expires_at`) ya pasó figura como `expired`. Paginado por cursor (orden updated_at desc, id desc): si hay más grants la respuesta trae `has_more: true` e incluye `next_cursor`, que se pasa como `cursor` para pedir la página siguiente (con el mismo `status`). Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags accessgrants
 // @Accept json
 // @Produce json
 // @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
 // @Param Authorization header string false "Bearer token en producción"
-// @Param status query string false "Lista CSV de estados permitidos (ej: invited,active)"
-// @Success 200 {array} grantResponse
-// @Failure 401 {string} string "unauthorized"
-// @Failure 500 {string} string "internal error"
+// @Param status query string false "Lista CSV de estados permitidos: invited, active, revoked, declined, expired (ej: invited,active)"
+// @Param limit query int false "Máximo de grants por página (default 50, máx 200)"
+// @Param cursor query string false "Cursor opaco de la página siguiente (next_cursor de la respuesta anterior)"
+// @Success 200 {object} grantListResponse
+// @Failure 400 {object} httpjson.ErrorBody "cursor inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
 // @Router /me/grants [get]
 func listMyGrantsHandler(svc *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetClaims(r.Context())
 		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
 			return
 		}
 
-		// status=invited,active (CSV opcional)
-		allowed := parseStatusFilter(r.URL.Query().Get("status"))
-
-		items, err := svc.ListByGrantee(r.Context(), claims.UserID)
+		filter, err := ParseGranteeFilter(r)
 		if err != nil {
-			http.Error(w, "internal error", http.StatusInternalServerError)
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
 			return
 		}
+		// status=invited,active (CSV opcional); el filtro lo aplica el repo
+		filter.Statuses = parseStatusFilter(r.URL.Query().Get("status"))
 
-		// Filtrar por status si se especificó
-		if len(allowed) > 0 {
-			filtered := make([]Grant, 0, len(items))
-			for _, g := range items {
-				if _, ok := allowed[g.Status]; ok {
-					filtered = append(filtered, g)
-				}
-			}
-			items = filtered
+		items, next, err := svc.ListByGranteePage(r.Context(), claims.UserID, filter)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
 		}
 
 		out := make([]grantResponse, 0, len(items))
 		for _, g := range items {
-			out = append(out, toGrantResponse(g))
+			out = append(out, toGrantResponse(g, apitime.FromContext(r.Context())))
+		}
+		httpjson.WriteJSON(w, http.StatusOK, grantListResponse{
+			Items:      out,
+			Count:      len(out),
+			Limit:      filter.Limit,
+			HasMore:    next != "",
+			NextCursor: next,
+		})
+	}
+}
+
+// grantListResponse es una página de GET /me/grants; next_cursor se omite en la última página.
+type grantListResponse struct {
+	Items      []grantResponse `json:"items"`
+	Count      int             `json:"count"`    // grants en esta página
+	Limit      int             `json:"limit"`    // límite efectivo aplicado
+	HasMore    bool            `json:"has_more"` // true => hay next_cursor
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// ParseGranteeFilter lee limit (default 50; fuera de 1..MaxGranteeListLimit => default) y
+// cursor de los listados paginados del delegado (GET /me/grants, GET /me/pets).
+func ParseGranteeFilter(r *http.Request) (GranteeFilter, error) {
+	filter := GranteeFilter{Limit: 50}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= MaxGranteeListLimit {
+			filter.Limit = n
+		}
+	}
+	if v := strings.TrimSpace(r.URL.Query().Get("cursor")); v != "" {
+		c, err := ParseCursor(v)
+		if err != nil {
+			return GranteeFilter{}, err
 		}
-		writeJSON(w, http.StatusOK, out)
+		filter.Cursor = &c
 	}
+	return filter, nil
 }
 
 // acceptGrantHandler godoc
 // @Summary Aceptar una invitación de grant
-// @Description Acepta una invitación pendiente para que el usuario autenticado se convierta en delegado de una mascota. Solo el grantee puede aceptar su invitación. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Acepta una invitación pendiente para que el usuario autenticado se convierta en delegado de una mascota. Solo el grantee puede aceptar su invitación, y solo antes de su `invite_expires_at` (después → 409; el owner debe re-invitar). Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags accessgrants
 // @Accept json
 // @Produce json
@@ -230,18 +460,18 @@ func listMyGrantsHandler(svc *Service) http.HandlerFunc {
 // @Param Authorization header string false "Bearer token en producción"
 // @Param grantID path string true "ID del grant a aceptar"
 // @Success 200 {object} grantResponse
-// @Failure 400 {string} string "invalid input"
-// @Failure 401 {string} string "unauthorized"
-// @Failure 403 {string} string "forbidden"
-// @Failure 404 {string} string "not found"
-// @Failure 409 {string} string "bad state para aceptar (ej: ya aceptado/revocado)"
-// @Failure 500 {string} string "internal error"
+// @Failure 400 {object} httpjson.ErrorBody "invalid input"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden"
+// @Failure 404 {object} httpjson.ErrorBody "not found"
+// @Failure 409 {object} httpjson.ErrorBody "bad state para aceptar (ej: revocado, rechazado o invitación vencida)"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
 // @Router /grants/{grantID}/accept [post]
 func acceptGrantHandler(svc *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetClaims(r.Context())
 		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
 			return
 		}
 
@@ -250,26 +480,184 @@ func acceptGrantHandler(svc *Service) http.HandlerFunc {
 		if err != nil {
 			switch err {
 			case ErrInvalidInput:
-				http.Error(w, err.Error(), http.StatusBadRequest)
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			case ErrForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			case ErrNotFound:
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "not found")
+			case ErrBadState:
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, err.Error())
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, toGrantResponse(g, apitime.FromContext(r.Context())))
+	}
+}
+
+// updateGrantScopesHandler godoc
+// @Summary Modificar los scopes de un grant
+// @Description Reemplaza los scopes de un grant existente (invitado o activo). Solo el owner de la mascota puede hacerlo. Los scopes se validan estrictamente; si el grant es una sub-delegación no puede exceder los scopes del delegador, y los grants sub-delegados a partir de él se recortan. Es la forma explícita de cambiar scopes (re-invitar sigue actualizándolos, pero no se recomienda). Los scopes deben estar habilitados en el plan del owner (capability `pet:grants:<scope>`). Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags accessgrants
+// @Accept json
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param grantID path string true "ID del grant"
+// @Param payload body updateGrantScopesRequest true "Scopes nuevos (reemplazan a los actuales)"
+// @Success 200 {object} grantResponse
+// @Failure 400 {object} httpjson.ErrorBody "invalid json / invalid input (scope no soportado o vacío)"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 402 {object} httpjson.ErrorBody "error.code: capability_missing (el plan del owner no permite otorgar alguno de los scopes; el mensaje los lista)"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden / scopes exceed delegator's grant"
+// @Failure 404 {object} httpjson.ErrorBody "not found"
+// @Failure 409 {object} httpjson.ErrorBody "invalid state (grant revocado o rechazado)"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Failure 503 {object} httpjson.ErrorBody "capabilities unavailable"
+// @Router /grants/{grantID} [patch]
+func updateGrantScopesHandler(svc *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		var req updateGrantScopesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
+			return
+		}
+
+		grantID := chi.URLParam(r, "grantID")
+		g, err := svc.UpdateScopes(r.Context(), grantID, claims.UserID, req.Scopes)
+		if err != nil {
+			var notInPlan *ScopesNotInPlanError
+			if errors.As(err, &notInPlan) {
+				httpjson.WriteError(w, http.StatusPaymentRequired, httpjson.CodeCapabilityMissing, err.Error())
+				return
+			}
+			switch err {
+			case ErrInvalidInput:
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			case ErrForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			case ErrScopesExceedDelegator:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, err.Error())
+			case ErrCapabilitiesUnavailable:
+				httpjson.WriteError(w, http.StatusServiceUnavailable, httpjson.CodeUnavailable, err.Error())
+			case ErrNotFound:
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "not found")
+			case ErrBadState:
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, err.Error())
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, toGrantResponse(g, apitime.FromContext(r.Context())))
+	}
+}
+
+// declineGrantHandler godoc
+// @Summary Rechazar una invitación de grant
+// @Description Rechaza una invitación pendiente; el grant queda en `declined` y no otorga acceso. Solo el grantee puede rechazar su invitación. Es idempotente si ya estaba rechazada. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags accessgrants
+// @Accept json
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param grantID path string true "ID del grant a rechazar"
+// @Success 200 {object} grantResponse
+// @Failure 400 {object} httpjson.ErrorBody "invalid input"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden"
+// @Failure 404 {object} httpjson.ErrorBody "not found"
+// @Failure 409 {object} httpjson.ErrorBody "bad state para rechazar (ej: ya aceptado/revocado)"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /grants/{grantID}/decline [post]
+func declineGrantHandler(svc *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		grantID := chi.URLParam(r, "grantID")
+		g, err := svc.Decline(r.Context(), grantID, claims.UserID)
+		if err != nil {
+			switch err {
+			case ErrInvalidInput:
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			case ErrForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			case ErrNotFound:
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "not found")
+			case ErrBadState:
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, err.Error())
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, toGrantResponse(g, apitime.FromContext(r.Context())))
+	}
+}
+
+// leaveGrantHandler godoc
+// @Summary Abandonar un grant (delegado)
+// @Description El delegado renuncia a su propio grant (invitado o activo): queda `revoked` y pierde el acceso de inmediato; sus sub-delegaciones se revocan en cascada. Solo el grantee puede hacerlo (el owner usa `/revoke`). Es idempotente si ya estaba revocado. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags accessgrants
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param grantID path string true "ID del grant a abandonar"
+// @Success 200 {object} grantResponse
+// @Failure 400 {object} httpjson.ErrorBody "invalid input"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden"
+// @Failure 404 {object} httpjson.ErrorBody "not found"
+// @Failure 409 {object} httpjson.ErrorBody "bad state (ej: invitación rechazada)"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /grants/{grantID}/leave [post]
+func leaveGrantHandler(svc *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		grantID := chi.URLParam(r, "grantID")
+		g, err := svc.Leave(r.Context(), grantID, claims.UserID)
+		if err != nil {
+			switch err {
+			case ErrInvalidInput:
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
 			case ErrForbidden:
-				http.Error(w, "forbidden", http.StatusForbidden)
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
 			case ErrNotFound:
-				http.Error(w, "not found", http.StatusNotFound)
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "not found")
 			case ErrBadState:
-				http.Error(w, err.Error(), http.StatusConflict)
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, err.Error())
 			default:
-				http.Error(w, "internal error", http.StatusInternalServerError)
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			}
 			return
 		}
 
-		writeJSON(w, http.StatusOK, toGrantResponse(g))
+		httpjson.WriteJSON(w, http.StatusOK, toGrantResponse(g, apitime.FromContext(r.Context())))
 	}
 }
 
 // revokeGrantHandler godoc
 // @Summary Revocar un grant
-// @Description Revoca un grant existente. Puede ser ejecutado por el owner o, según la lógica de negocio, por el delegado cuando corresponda. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Revoca un grant existente y, en cascada, todos los grants sub-delegados a partir de él. Puede ejecutarlo el owner de la mascota o el delegado que otorgó el grant (sub-delegación). Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags accessgrants
 // @Accept json
 // @Produce json
@@ -277,41 +665,42 @@ func acceptGrantHandler(svc *Service) http.HandlerFunc {
 // @Param Authorization header string false "Bearer token en producción"
 // @Param grantID path string true "ID del grant a revocar"
 // @Success 200 {object} grantResponse
-// @Failure 400 {string} string "invalid input"
-// @Failure 401 {string} string "unauthorized"
-// @Failure 403 {string} string "forbidden"
-// @Failure 404 {string} string "not found"
-// @Failure 500 {string} string "internal error"
+// @Failure 400 {object} httpjson.ErrorBody "invalid input"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden"
+// @Failure 404 {object} httpjson.ErrorBody "not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
 // @Router /grants/{grantID}/revoke [post]
 func revokeGrantHandler(svc *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetClaims(r.Context())
 		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
 			return
 		}
 
 		grantID := chi.URLParam(r, "grantID")
-		g, err := svc.Revoke(r.Context(), grantID, claims.UserID)
+		// El outcome (revoked / already_revoked) no cambia la respuesta: siempre 200.
+		g, _, err := svc.Revoke(r.Context(), grantID, claims.UserID)
 		if err != nil {
 			switch err {
 			case ErrInvalidInput:
-				http.Error(w, err.Error(), http.StatusBadRequest)
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
 			case ErrForbidden:
-				http.Error(w, "forbidden", http.StatusForbidden)
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
 			case ErrNotFound:
-				http.Error(w, "not found", http.StatusNotFound)
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "not found")
 			default:
-				http.Error(w, "internal error", http.StatusInternalServerError)
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			}
 			return
 		}
 
-		writeJSON(w, http.StatusOK, toGrantResponse(g))
+		httpjson.WriteJSON(w, http.StatusOK, toGrantResponse(g, apitime.FromContext(r.Context())))
 	}
 }
 
-func toGrantResponse(g Grant) grantResponse {
+func toGrantResponse(g Grant, tf apitime.Format) grantResponse {
 	return grantResponse{
 		ID:            g.ID,
 		PetID:         g.PetID,
@@ -319,34 +708,41 @@ func toGrantResponse(g Grant) grantResponse {
 		GranteeUserID: g.GranteeUserID,
 		Scopes:        g.Scopes,
 		Status:        g.Status,
-		CreatedAt:     g.CreatedAt,
-		UpdatedAt:     g.UpdatedAt,
-		RevokedAt:     g.RevokedAt,
+		CreatedAt:     apitime.New(g.CreatedAt, tf),
+		UpdatedAt:     apitime.New(g.UpdatedAt, tf),
+		RevokedAt:     apitime.NewPtr(g.RevokedAt, tf),
+		ExpiresAt:     apitime.NewPtr(g.ExpiresAt, tf),
+		Message:       g.Message,
+
+		InviteExpiresAt: apitime.NewPtr(g.InviteExpiresAt, tf),
+		LastUsedAt:      apitime.NewPtr(g.LastUsedAt, tf),
+
+		DelegatedByUserID: g.DelegatedByUserID,
+		ParentGrantID:     g.ParentGrantID,
 	}
 }
 
-func parseStatusFilter(raw string) map[Status]struct{} {
+// parseStatusFilter interpreta el CSV de ?status= (invited, active, revoked, declined, expired).
+// Valores desconocidos se conservan: simplemente no matchean ningún grant.
+// Devuelve los estados sin repetir, en el orden recibido (nil si no vino ninguno).
+func parseStatusFilter(raw string) []Status {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
 		return nil
 	}
 	parts := strings.Split(raw, ",")
-	out := map[Status]struct{}{}
+	seen := map[Status]struct{}{}
+	var out []Status
 	for _, p := range parts {
-		s := Status(strings.TrimSpace(p))
+		s := Status(strings.ToLower(strings.TrimSpace(p)))
 		if s == "" {
 			continue
 		}
-		out[s] = struct{}{}
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
 	}
 	return out
 }
-
-// writeJSON está duplicado intencionalmente en handlers de distintos módulos
-// para evitar crear paquetes/helpers compartidos demasiado pronto.
-// Si más adelante se repite en más módulos, recién conviene extraerlo a un helper común.
-func writeJSON(w http.ResponseWriter, status int, v any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(v)
-}
@@ -0,0 +1,68 @@
+package accessgrants
+
+// MergePlan es lo que pasa con los grants al fusionar una mascota duplicada (origen) en otra
+// (destino). Lo calcula PlanMerge y lo aplica el MergeStore dentro de su transacción.
+type MergePlan struct {
+	Move   []string // grants vigentes del origen que pasan al destino
+	Revoke []string // grants (del origen o del destino) que se revocan
+}
+
+// PlanMerge decide el destino de los grants al fusionar source en target sin romper la regla
+// de un grant vigente por (mascota, delegado):
+//   - los grants vigentes (invitados o activos) del origen se mueven al destino;
+//   - si el delegado ya tiene uno vigente en el destino queda uno solo: el activo antes que la
+//     invitación y, a igual estado, el del destino; el otro se revoca;
+//   - las sub-delegaciones de un grant revocado se revocan en cascada, como en Revoke.
+//
+// Los grants cerrados (revocados, rechazados, vencidos) se ignoran: quedan en el origen como historial.
+func PlanMerge(source, target []Grant) MergePlan {
+	kept := map[string]Grant{} // grantee => grant vigente del destino
+	for _, g := range target {
+		if !closedStatus(g.Status) {
+			kept[g.GranteeUserID] = g
+		}
+	}
+
+	revoked := map[string]bool{}
+	var plan MergePlan
+	var moved []Grant
+	for _, g := range source {
+		if closedStatus(g.Status) {
+			continue
+		}
+		other, ok := kept[g.GranteeUserID]
+		if ok && (other.Status == StatusActive || g.Status != StatusActive) {
+			revoked[g.ID] = true
+			continue
+		}
+		if ok {
+			revoked[other.ID] = true
+		}
+		kept[g.GranteeUserID] = g
+		moved = append(moved, g)
+	}
+
+	// Cascada: un grant cuyo delegador se revoca (directa o indirectamente) también cae.
+	all := append(append([]Grant(nil), source...), target...)
+	for changed := true; changed; {
+		changed = false
+		for _, g := range all {
+			if !revoked[g.ID] && !closedStatus(g.Status) && g.ParentGrantID != "" && revoked[g.ParentGrantID] {
+				revoked[g.ID] = true
+				changed = true
+			}
+		}
+	}
+
+	for _, g := range moved {
+		if !revoked[g.ID] {
+			plan.Move = append(plan.Move, g.ID)
+		}
+	}
+	for _, g := range all {
+		if revoked[g.ID] {
+			plan.Revoke = append(plan.Revoke, g.ID)
+		}
+	}
+	return plan
+}
@@ -18,8 +18,30 @@ const (
 	ScopeEventsVoid Scope = "events:void"
 	// ScopeAttachmentsAdd permite adjuntar archivos u otros recursos al historial.
 	ScopeAttachmentsAdd Scope = "attachments:add"
+	// ScopePetExport permite descargar el bundle completo de la mascota (export.json).
+	ScopePetExport Scope = "pet:export"
+	// ScopeGrantsDelegate permite al delegado otorgar grants a terceros (p.ej. su staff)
+	// con un subconjunto de sus propios scopes.
+	ScopeGrantsDelegate Scope = "grants:delegate"
 )
 
+// AllScopes devuelve todos los scopes soportados, en orden de declaración.
+func AllScopes() []Scope {
+	return []Scope{
+		ScopePetRead,
+		ScopePetEditProfile,
+		ScopeEventsRead,
+		ScopeEventsCreate,
+		ScopeEventsVoid,
+		ScopeAttachmentsAdd,
+		ScopePetExport,
+		ScopeGrantsDelegate,
+	}
+}
+
+// MaxMessageLength es el largo máximo (en caracteres) del mensaje de invitación.
+const MaxMessageLength = 280
+
 // Status representa el estado de un grant de acceso delegado.
 type Status string
 
@@ -30,6 +52,10 @@ const (
 	StatusActive Status = "active"
 	// StatusRevoked indica que el grant fue revocado.
 	StatusRevoked Status = "revoked"
+	// StatusDeclined indica que el delegado rechazó la invitación.
+	StatusDeclined Status = "declined"
+	// StatusExpired indica que la invitación venció (InviteExpiresAt) sin ser aceptada.
+	StatusExpired Status = "expired"
 )
 
 // Grant representa una delegación de acceso de un owner hacia un usuario delegado sobre una mascota.
@@ -41,10 +67,43 @@ type Grant struct {
 	OwnerUserID   string // quien comparte
 	GranteeUserID string // delegado
 
+	// TenantID es el de la mascota (el caller que invitó); fuera de ese tenant el grant no existe.
+	TenantID string
+
 	Scopes []Scope
 	Status Status
 
+	// Sub-delegación: vacíos si el grant lo otorgó el owner directamente.
+	DelegatedByUserID string // delegado (con grants:delegate) que otorgó este grant
+	ParentGrantID     string // grant del delegador; al revocarlo se revoca este también
+
+	// ExpiresAt (opcional) acota el acceso en el tiempo: pasado ese instante el grant
+	// deja de considerarse activo sin necesidad de revocarlo.
+	ExpiresAt *time.Time
+
+	// InviteExpiresAt es el plazo para aceptar la invitación (nil => sin plazo). Se fija al
+	// invitar (y se renueva al re-invitar); no limita el acceso una vez aceptado el grant.
+	InviteExpiresAt *time.Time
+
+	// Message (opcional) es el motivo que el owner le explica al delegado al invitarlo.
+	Message string
+
+	// LastUsedAt es la última vez que el delegado usó el grant en un request (nil => nunca).
+	// Se actualiza con Repository.MarkUsed, a lo sumo una vez por UsageDebounce.
+	LastUsedAt *time.Time
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	RevokedAt *time.Time
 }
+
+// ExpiredAt indica si el grant tiene vencimiento y ya pasó en now.
+func (g Grant) ExpiredAt(now time.Time) bool {
+	return g.ExpiresAt != nil && !g.ExpiresAt.After(now)
+}
+
+// InviteLapsedAt indica si el grant es una invitación pendiente cuyo plazo para aceptar ya
+// pasó en now (aunque el barrido todavía no la haya pasado a StatusExpired).
+func (g Grant) InviteLapsedAt(now time.Time) bool {
+	return g.Status == StatusInvited && g.InviteExpiresAt != nil && !g.InviteExpiresAt.After(now)
+}
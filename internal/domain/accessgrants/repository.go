@@ -1,16 +1,40 @@
 package accessgrants
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// MaxGranteeListLimit es el máximo de grants por página de GET /me/grants y GET /me/pets.
+const MaxGranteeListLimit = 200
+
+// GranteeFilter acota ListByGrantee. Statuses vacío => todos; Limit <= 0 => sin límite;
+// Cursor (opcional) => solo los grants posteriores a él en el orden del listado.
+type GranteeFilter struct {
+	Statuses []Status
+	Limit    int
+	Cursor   *Cursor
+}
+
+// Repository: las lecturas se acotan a tenantID; un grant de otro tenant es not found.
 type Repository interface {
 	Create(ctx context.Context, g Grant) error
 	Update(ctx context.Context, g Grant) error
-	GetByID(ctx context.Context, id string) (Grant, error)
-	ListByPet(ctx context.Context, petID string) ([]Grant, error)
+	GetByID(ctx context.Context, tenantID, id string) (Grant, error)
+	ListByPet(ctx context.Context, tenantID, petID string) ([]Grant, error)
 
 	// Para delegación
-	GetActiveGrant(ctx context.Context, petID, granteeUserID string) (Grant, error)
+	GetActiveGrant(ctx context.Context, tenantID, petID, granteeUserID string) (Grant, error)
+
+	// Para que el delegado vea sus invitaciones / grants, en orden (updated_at desc, id desc)
+	// y paginado por keyset según filter.
+	ListByGrantee(ctx context.Context, tenantID, granteeUserID string, filter GranteeFilter) ([]Grant, error)
+
+	// ListInvitesExpiredBefore devuelve, de todos los tenants, las invitaciones pendientes
+	// (StatusInvited) con InviteExpiresAt <= before. Lo usa el barrido ExpireStaleInvites.
+	ListInvitesExpiredBefore(ctx context.Context, before time.Time) ([]Grant, error)
 
-	// Para que el delegado vea sus invitaciones / grants
-	ListByGrantee(ctx context.Context, granteeUserID string) ([]Grant, error)
+	// MarkUsed actualiza solo LastUsedAt (Update no lo pisa), para no revertir un cambio de
+	// estado o scopes hecho en paralelo al request que usó el grant.
+	MarkUsed(ctx context.Context, id string, at time.Time) error
 }
@@ -3,10 +3,19 @@ package accessgrants
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/google/uuid"
+	"pet-clinical-history/internal/ids"
+	"pet-clinical-history/internal/platform/logger"
+	"pet-clinical-history/internal/platform/metrics"
+	"pet-clinical-history/internal/ports/auth"
+	"pet-clinical-history/internal/ports/capabilities"
+	"pet-clinical-history/internal/ports/notifications"
 )
 
 var (
@@ -14,18 +23,175 @@ var (
 	ErrForbidden    = errors.New("forbidden")
 	ErrNotFound     = errors.New("not found")
 	ErrBadState     = errors.New("invalid state")
+
+	// ErrScopesExceedDelegator: una sub-delegación pidió scopes que el delegador no tiene.
+	ErrScopesExceedDelegator = errors.New("scopes exceed delegator's grant")
+
+	// ErrMessageTooLong: el mensaje de invitación supera MaxMessageLength caracteres.
+	ErrMessageTooLong = errors.New("message too long")
+
+	// ErrCapabilitiesUnavailable: no se pudo consultar el plan del owner (plans-features caído).
+	ErrCapabilitiesUnavailable = errors.New("capabilities unavailable")
+
+	// ErrTooManyGrants: la mascota ya tiene el máximo de grants vigentes (ver WithMaxGrantsPerPet).
+	ErrTooManyGrants = errors.New("too many grants for pet")
 )
 
+// ScopeCapability es la capability del plan del owner que permite otorgar scope a un delegado,
+// p.ej. "pet:grants:events:create".
+func ScopeCapability(scope Scope) string {
+	return "pet:grants:" + string(scope)
+}
+
+// ScopesNotInPlanError: el plan del owner no permite otorgar Scopes.
+type ScopesNotInPlanError struct {
+	Scopes []Scope
+}
+
+func (e *ScopesNotInPlanError) Error() string {
+	names := make([]string, 0, len(e.Scopes))
+	for _, sc := range e.Scopes {
+		names = append(names, string(sc))
+	}
+	return "plan does not allow scopes: " + strings.Join(names, ", ")
+}
+
+// DefaultInviteScopes son los scopes de una invitación sin scopes cuando el Service no
+// configura otros (WithDefaultScopes): ver perfil + ver timeline.
+var DefaultInviteScopes = []Scope{ScopePetRead, ScopeEventsRead}
+
+// MetricGrantEvents cuenta las transiciones de grants por type (grant.invited | grant.accepted | grant.revoked).
+const MetricGrantEvents = "grant_events_total"
+
+// DefaultInviteTTL es el plazo para aceptar una invitación (ver WithInviteTTL).
+const DefaultInviteTTL = 7 * 24 * time.Hour
+
+// DefaultMaxGrantsPerPet es el máximo de grants vigentes (invitados o activos) por mascota
+// (ver WithMaxGrantsPerPet).
+const DefaultMaxGrantsPerPet = 20
+
+// UsageDebounce es el intervalo mínimo entre dos escrituras de LastUsedAt de un mismo grant
+// (evita una escritura por cada request del delegado).
+const UsageDebounce = time.Minute
+
+// notifyTimeout acota cada notificación asíncrona para no acumular goroutines si el destino se cuelga.
+const notifyTimeout = 10 * time.Second
+
 type Service struct {
-	repo Repository
-	now  func() time.Time
+	repo     Repository
+	notifier notifications.GrantNotifier       // opcional: nil => sin notificaciones
+	log      logger.Logger                     // opcional: nil => los errores de notificación se descartan
+	caps     capabilities.CapabilitiesResolver // opcional: nil => no se valida el plan del owner (dev)
+	metrics  *metrics.Registry                 // opcional: nil => sin métricas
+	now      func() time.Time
+	ids      ids.Generator
+
+	// defaultScopes se aplican a las invitaciones sin scopes (validados al construir).
+	defaultScopes []Scope
+
+	// inviteTTL es el plazo para aceptar cada invitación; <= 0 => sin plazo.
+	inviteTTL time.Duration
+
+	// maxGrantsPerPet acota los grants vigentes por mascota; <= 0 => sin límite.
+	maxGrantsPerPet int
+}
+
+// Option configura dependencias opcionales del Service.
+type Option func(*Service)
+
+// WithIDGenerator reemplaza el generador de IDs (default UUIDv4).
+func WithIDGenerator(g ids.Generator) Option {
+	return func(s *Service) { s.ids = g }
+}
+
+// WithNotifier avisa a notifier cuando un grant se invita, se acepta o se revoca.
+func WithNotifier(n notifications.GrantNotifier) Option {
+	return func(s *Service) { s.notifier = n }
 }
 
-func NewService(repo Repository) *Service {
-	return &Service{
-		repo: repo,
-		now:  time.Now,
+// WithLogger registra los errores de notificación.
+func WithLogger(l logger.Logger) Option {
+	return func(s *Service) { s.log = l }
+}
+
+// WithCapabilities limita los scopes otorgables a los que habilita el plan del owner
+// (ver ScopeCapability).
+func WithCapabilities(c capabilities.CapabilitiesResolver) Option {
+	return func(s *Service) { s.caps = c }
+}
+
+// WithMetrics cuenta las invitaciones, aceptaciones y revocaciones (MetricGrantEvents).
+func WithMetrics(m *metrics.Registry) Option {
+	return func(s *Service) { s.metrics = m }
+}
+
+// WithDefaultScopes reemplaza los scopes de las invitaciones sin scopes
+// (default DefaultInviteScopes), p.ej. solo lectura o lectura + alta de eventos.
+// Se validan en NewServiceWithOptions.
+func WithDefaultScopes(scopes ...Scope) Option {
+	return func(s *Service) { s.defaultScopes = scopes }
+}
+
+// WithInviteTTL reemplaza el plazo para aceptar una invitación (default DefaultInviteTTL);
+// <= 0 => las invitaciones no vencen.
+func WithInviteTTL(d time.Duration) Option {
+	return func(s *Service) { s.inviteTTL = d }
+}
+
+// WithMaxGrantsPerPet reemplaza el máximo de grants vigentes por mascota (default
+// DefaultMaxGrantsPerPet); <= 0 => sin límite.
+func WithMaxGrantsPerPet(n int) Option {
+	return func(s *Service) { s.maxGrantsPerPet = n }
+}
+
+// NewService arma el Service. Una configuración inválida (p.ej. WithDefaultScopes con un scope
+// no soportado) es un error de programación y entra en pánico; para manejar el error (config
+// que viene de env) usar NewServiceWithOptions.
+func NewService(repo Repository, opts ...Option) *Service {
+	s, err := NewServiceWithOptions(repo, opts...)
+	if err != nil {
+		panic("accessgrants: " + err.Error())
 	}
+	return s
+}
+
+// NewServiceWithOptions es NewService pero valida la configuración: los scopes de
+// WithDefaultScopes pasan por las mismas reglas que una invitación (ErrInvalidInput si
+// alguno no es soportado o quedan vacíos).
+func NewServiceWithOptions(repo Repository, opts ...Option) (*Service, error) {
+	s := &Service{
+		repo:          repo,
+		now:           time.Now,
+		ids:           ids.UUIDv4(),
+		defaultScopes: DefaultInviteScopes,
+		inviteTTL:     DefaultInviteTTL,
+
+		maxGrantsPerPet: DefaultMaxGrantsPerPet,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	defaults, err := NormalizeDefaultScopes(s.defaultScopes)
+	if err != nil {
+		return nil, err
+	}
+	s.defaultScopes = defaults
+	return s, nil
+}
+
+// NormalizeDefaultScopes valida los scopes por defecto de las invitaciones con las mismas
+// reglas que una invitación y los devuelve normalizados (trim + dedup). Un scope no soportado
+// o la lista vacía => ErrInvalidInput. Sirve para rechazar la config al arrancar.
+func NormalizeDefaultScopes(scopes []Scope) ([]Scope, error) {
+	defaults, err := normalizeScopesStrict(scopes)
+	if err == nil && len(defaults) == 0 {
+		err = ErrInvalidInput
+	}
+	if err != nil {
+		return nil, fmt.Errorf("default scopes: %w", err)
+	}
+	return defaults, nil
 }
 
 type InviteInput struct {
@@ -33,6 +199,18 @@ type InviteInput struct {
 	OwnerUserID   string
 	GranteeUserID string
 	Scopes        []Scope
+
+	// DelegatorUserID: si viene y no es el owner, la invitación es una sub-delegación.
+	// El delegador necesita un grant activo con grants:delegate y solo puede otorgar
+	// un subconjunto de sus propios scopes.
+	DelegatorUserID string
+
+	// ExpiresAt opcional; debe ser futuro. Re-invitar reemplaza el vencimiento anterior.
+	// En una sub-delegación es obligatorio si el grant del delegador vence, y no puede ser posterior.
+	ExpiresAt *time.Time
+
+	// Message opcional (máx. MaxMessageLength caracteres). Re-invitar reemplaza el anterior.
+	Message string
 }
 
 func (s *Service) Invite(ctx context.Context, in InviteInput) (Grant, error) {
@@ -46,14 +224,18 @@ func (s *Service) Invite(ctx context.Context, in InviteInput) (Grant, error) {
 	if ownerID == granteeID {
 		return Grant{}, ErrInvalidInput
 	}
+	message := strings.TrimSpace(in.Message)
+	if utf8.RuneCountInString(message) > MaxMessageLength {
+		return Grant{}, ErrMessageTooLong
+	}
 
 	// Scopes:
-	// - Si viene vacío: default útil (ver perfil + ver timeline)
+	// - Si viene vacío: los defaults del Service (DefaultInviteScopes salvo WithDefaultScopes)
 	// - Si viene con valores: validación estricta (solo scopes soportados)
 	var scopes []Scope
 	var err error
 	if len(in.Scopes) == 0 {
-		scopes = []Scope{ScopePetRead, ScopeEventsRead}
+		scopes = append([]Scope(nil), s.defaultScopes...)
 	} else {
 		scopes, err = normalizeScopesStrict(in.Scopes)
 		if err != nil {
@@ -63,11 +245,41 @@ func (s *Service) Invite(ctx context.Context, in InviteInput) (Grant, error) {
 			return Grant{}, ErrInvalidInput
 		}
 	}
+	if err := s.checkPlanScopes(ctx, ownerID, scopes); err != nil {
+		return Grant{}, err
+	}
+
+	// Sub-delegación: el grant nuevo cuelga del grant activo del delegador.
+	delegatorID := strings.TrimSpace(in.DelegatorUserID)
+	var parent Grant
+	if delegatorID != "" && delegatorID != ownerID {
+		if delegatorID == granteeID {
+			return Grant{}, ErrInvalidInput
+		}
+		parent, err = s.activeGrant(ctx, petID, delegatorID)
+		if err != nil || !HasScope(parent, ScopeGrantsDelegate) {
+			return Grant{}, ErrForbidden
+		}
+		for _, sc := range scopes {
+			if !HasScope(parent, sc) {
+				return Grant{}, ErrScopesExceedDelegator
+			}
+		}
+	} else {
+		delegatorID = ""
+	}
 
 	now := s.now()
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(now) {
+		return Grant{}, ErrInvalidInput
+	}
+	// Un sub-delegado tampoco puede durar más que su delegador.
+	if parent.ExpiresAt != nil && (in.ExpiresAt == nil || in.ExpiresAt.After(*parent.ExpiresAt)) {
+		return Grant{}, ErrInvalidInput
+	}
 
 	// 1) Buscar si ya existe un grant para (petID, ownerID, granteeID) que NO esté revoked.
-	items, err := s.repo.ListByPet(ctx, petID)
+	items, err := s.repo.ListByPet(ctx, auth.TenantFromContext(ctx), petID)
 	if err == nil {
 		var winner Grant
 		hasWinner := false
@@ -94,21 +306,39 @@ func (s *Service) Invite(ctx context.Context, in InviteInput) (Grant, error) {
 			}
 		}
 
-		// Si hay winner y NO está revoked: lo “re-invitamos” actualizando scopes (sin crear otro).
-		if hasWinner && winner.ID != "" && winner.Status != StatusRevoked {
+		// Si hay winner y NO está revoked/declined/expired: lo “re-invitamos” actualizando scopes (sin crear otro).
+		// Una invitación rechazada o vencida no se reabre: se crea una nueva.
+		// Para cambiar scopes de forma explícita usar UpdateScopes (PATCH /grants/{grantID}).
+		if hasWinner && winner.ID != "" && !closedStatus(winner.Status) {
+			// Un delegador solo puede re-invitar grants que él mismo otorgó; el owner puede todo.
+			if delegatorID != "" && winner.DelegatedByUserID != delegatorID {
+				return Grant{}, ErrForbidden
+			}
+
 			winner.Scopes = scopes
+			winner.ExpiresAt = in.ExpiresAt
+			winner.Message = message
 			winner.UpdatedAt = now
+			if winner.Status == StatusInvited {
+				// Re-invitar renueva el plazo para aceptar.
+				winner.InviteExpiresAt = s.inviteDeadline(now)
+			}
+			winner.DelegatedByUserID = delegatorID
+			winner.ParentGrantID = parent.ID
 
 			if err := s.repo.Update(ctx, winner); err != nil {
 				return Grant{}, err
 			}
 
+			// Los sub-delegados de este grant nunca pueden quedar por encima de él.
+			_ = s.clampDescendants(ctx, winner, now)
+
 			// best-effort: revoca otros matches no revocados para mantener 1 “vigente”
 			for _, g := range matches {
 				if g.ID == "" || g.ID == winner.ID {
 					continue
 				}
-				if g.Status == StatusRevoked {
+				if closedStatus(g.Status) {
 					continue
 				}
 				g.Status = StatusRevoked
@@ -117,26 +347,46 @@ func (s *Service) Invite(ctx context.Context, in InviteInput) (Grant, error) {
 				_ = s.repo.Update(ctx, g)
 			}
 
+			s.notify(ctx, notifications.GrantInvited, winner, inviterID(winner))
 			return winner, nil
 		}
 	}
 
-	// 2) Si no hay uno vigente, crea un nuevo invite
+	// 2) Si no hay uno vigente, crea un nuevo invite, salvo que la mascota ya esté en el máximo.
+	// Re-invitar a un delegado existente (paso 1) no suma y no se limita.
+	if s.maxGrantsPerPet > 0 {
+		if err != nil {
+			return Grant{}, err
+		}
+		if countOpenGrants(items, now) >= s.maxGrantsPerPet {
+			return Grant{}, ErrTooManyGrants
+		}
+	}
+
 	g := Grant{
-		ID:            uuid.NewString(),
+		ID:            s.ids.NewID(),
 		PetID:         petID,
 		OwnerUserID:   ownerID,
 		GranteeUserID: granteeID,
+		TenantID:      auth.TenantFromContext(ctx),
 		Scopes:        scopes,
 		Status:        StatusInvited,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 		RevokedAt:     nil,
+		ExpiresAt:     in.ExpiresAt,
+		Message:       message,
+
+		InviteExpiresAt: s.inviteDeadline(now),
+
+		DelegatedByUserID: delegatorID,
+		ParentGrantID:     parent.ID,
 	}
 
 	if err := s.repo.Create(ctx, g); err != nil {
 		return Grant{}, err
 	}
+	s.notify(ctx, notifications.GrantInvited, g, inviterID(g))
 	return g, nil
 }
 
@@ -148,7 +398,7 @@ func (s *Service) Accept(ctx context.Context, grantID, granteeUserID string) (Gr
 		return Grant{}, ErrInvalidInput
 	}
 
-	g, err := s.repo.GetByID(ctx, grantID)
+	g, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), grantID)
 	if err != nil {
 		return Grant{}, ErrNotFound
 	}
@@ -171,6 +421,11 @@ func (s *Service) Accept(ctx context.Context, grantID, granteeUserID string) (Gr
 	if g.Status != StatusInvited {
 		return Grant{}, ErrBadState
 	}
+	// Una invitación vencida (acceso o plazo para aceptar) ya no puede aceptarse:
+	// el owner debe re-invitar.
+	if g.ExpiredAt(now) || g.InviteLapsedAt(now) {
+		return Grant{}, ErrBadState
+	}
 
 	g.Status = StatusActive
 	g.UpdatedAt = now
@@ -182,29 +437,122 @@ func (s *Service) Accept(ctx context.Context, grantID, granteeUserID string) (Gr
 	// Cierra loop: al activar uno, revoca cualquier otro grant no-revocado para el mismo pet+grantee.
 	_ = s.revokeOtherByPetAndGrantee(ctx, g.ID, g.PetID, g.GranteeUserID, now)
 
+	s.notify(ctx, notifications.GrantAccepted, g, granteeUserID)
 	return g, nil
 }
 
-func (s *Service) Revoke(ctx context.Context, grantID, ownerUserID string) (Grant, error) {
+// Decline rechaza una invitación pendiente. Solo el grantee puede hacerlo.
+// Es idempotente si ya estaba declined; un grant active o revoked devuelve ErrBadState.
+func (s *Service) Decline(ctx context.Context, grantID, granteeUserID string) (Grant, error) {
 	grantID = strings.TrimSpace(grantID)
-	ownerUserID = strings.TrimSpace(ownerUserID)
+	granteeUserID = strings.TrimSpace(granteeUserID)
 
-	if grantID == "" || ownerUserID == "" {
+	if grantID == "" || granteeUserID == "" {
 		return Grant{}, ErrInvalidInput
 	}
 
-	g, err := s.repo.GetByID(ctx, grantID)
+	g, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), grantID)
 	if err != nil {
 		return Grant{}, ErrNotFound
 	}
 
-	if g.OwnerUserID != ownerUserID {
+	if g.GranteeUserID != granteeUserID {
 		return Grant{}, ErrForbidden
 	}
 
+	switch g.Status {
+	case StatusDeclined:
+		return g, nil
+	case StatusInvited:
+	default:
+		return Grant{}, ErrBadState
+	}
+
+	g.Status = StatusDeclined
+	g.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, g); err != nil {
+		return Grant{}, err
+	}
+	return g, nil
+}
+
+// Leave permite al grantee renunciar a su propio grant (invited o active): queda revoked,
+// igual que si lo revocara el owner, y se revocan en cascada sus sub-delegaciones.
+// Solo el grantee puede hacerlo. Es idempotente si ya estaba revoked; declined => ErrBadState.
+func (s *Service) Leave(ctx context.Context, grantID, granteeUserID string) (Grant, error) {
+	grantID = strings.TrimSpace(grantID)
+	granteeUserID = strings.TrimSpace(granteeUserID)
+
+	if grantID == "" || granteeUserID == "" {
+		return Grant{}, ErrInvalidInput
+	}
+
+	g, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), grantID)
+	if err != nil {
+		return Grant{}, ErrNotFound
+	}
+
+	if g.GranteeUserID != granteeUserID {
+		return Grant{}, ErrForbidden
+	}
+
+	switch g.Status {
+	case StatusRevoked:
+		return g, nil
+	case StatusInvited, StatusActive:
+	default:
+		return Grant{}, ErrBadState
+	}
+
+	now := s.now()
+	g.Status = StatusRevoked
+	g.UpdatedAt = now
+	g.RevokedAt = &now
+
+	if err := s.repo.Update(ctx, g); err != nil {
+		return Grant{}, err
+	}
+
+	// MVP: cascada best-effort (sin transacción), como en Revoke.
+	_ = s.revokeDescendants(ctx, g, now)
+
+	s.notify(ctx, notifications.GrantRevoked, g, granteeUserID)
+	return g, nil
+}
+
+// RevokeOutcome indica si Revoke produjo una transición real o el grant ya estaba revocado.
+type RevokeOutcome string
+
+const (
+	RevokeOutcomeRevoked        RevokeOutcome = "revoked"
+	RevokeOutcomeAlreadyRevoked RevokeOutcome = "already_revoked"
+)
+
+// Revoke revoca un grant y, en cascada, todos los grants sub-delegados a partir de él.
+// Puede hacerlo el owner de la mascota o el delegador que otorgó el grant.
+// Es idempotente: el outcome distingue la revocación real de una repetida
+// (p.ej. para notificar solo una vez).
+func (s *Service) Revoke(ctx context.Context, grantID, ownerUserID string) (Grant, RevokeOutcome, error) {
+	grantID = strings.TrimSpace(grantID)
+	ownerUserID = strings.TrimSpace(ownerUserID)
+
+	if grantID == "" || ownerUserID == "" {
+		return Grant{}, "", ErrInvalidInput
+	}
+
+	g, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), grantID)
+	if err != nil {
+		return Grant{}, "", ErrNotFound
+	}
+
+	if g.OwnerUserID != ownerUserID && g.DelegatedByUserID != ownerUserID {
+		return Grant{}, "", ErrForbidden
+	}
+
 	// Idempotente
 	if g.Status == StatusRevoked {
-		return g, nil
+		return g, RevokeOutcomeAlreadyRevoked, nil
 	}
 
 	now := s.now()
@@ -213,17 +561,304 @@ func (s *Service) Revoke(ctx context.Context, grantID, ownerUserID string) (Gran
 	g.RevokedAt = &now
 
 	if err := s.repo.Update(ctx, g); err != nil {
+		return Grant{}, "", err
+	}
+
+	// MVP: cascada best-effort (sin transacción).
+	_ = s.revokeDescendants(ctx, g, now)
+
+	s.notify(ctx, notifications.GrantRevoked, g, ownerUserID)
+	return g, RevokeOutcomeRevoked, nil
+}
+
+// UpdateScopes reemplaza los scopes de un grant (solo el owner de la mascota). Un grant revocado
+// o rechazado devuelve ErrBadState. Si el grant es una sub-delegación, los scopes nuevos deben
+// seguir siendo un subconjunto de los del delegador; los sub-delegados del grant se recortan.
+func (s *Service) UpdateScopes(ctx context.Context, grantID, ownerUserID string, scopes []Scope) (Grant, error) {
+	grantID = strings.TrimSpace(grantID)
+	ownerUserID = strings.TrimSpace(ownerUserID)
+	if grantID == "" || ownerUserID == "" {
+		return Grant{}, ErrInvalidInput
+	}
+
+	normalized, err := normalizeScopesStrict(scopes)
+	if err != nil {
 		return Grant{}, err
 	}
+	if len(normalized) == 0 {
+		return Grant{}, ErrInvalidInput
+	}
+
+	g, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), grantID)
+	if err != nil {
+		return Grant{}, ErrNotFound
+	}
+	if g.OwnerUserID != ownerUserID {
+		return Grant{}, ErrForbidden
+	}
+	if closedStatus(g.Status) {
+		return Grant{}, ErrBadState
+	}
+	if err := s.checkPlanScopes(ctx, g.OwnerUserID, normalized); err != nil {
+		return Grant{}, err
+	}
+
+	if g.ParentGrantID != "" {
+		parent, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), g.ParentGrantID)
+		if err != nil {
+			return Grant{}, err
+		}
+		for _, sc := range normalized {
+			if !HasScope(parent, sc) {
+				return Grant{}, ErrScopesExceedDelegator
+			}
+		}
+	}
+
+	now := s.now()
+	g.Scopes = normalized
+	g.UpdatedAt = now
+	if err := s.repo.Update(ctx, g); err != nil {
+		return Grant{}, err
+	}
+
+	// Los sub-delegados de este grant nunca pueden quedar por encima de él.
+	_ = s.clampDescendants(ctx, g, now)
+
 	return g, nil
 }
 
+// RevokeAllForPet revoca todos los grants vigentes (invited / active) de la mascota,
+// p.ej. al borrarla o cuando el owner corta todo acceso compartido. Los declined quedan
+// como están. Es idempotente: una segunda llamada revoca 0. La propiedad de la mascota
+// la verifica quien llama (PetOwnerLookup); acá solo se exige que los grants vigentes
+// sean de ownerUserID. Devuelve cuántos revocó.
+func (s *Service) RevokeAllForPet(ctx context.Context, petID, ownerUserID string) (int, error) {
+	petID = strings.TrimSpace(petID)
+	ownerUserID = strings.TrimSpace(ownerUserID)
+	if petID == "" || ownerUserID == "" {
+		return 0, ErrInvalidInput
+	}
+
+	items, err := s.repo.ListByPet(ctx, auth.TenantFromContext(ctx), petID)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := make([]Grant, 0, len(items))
+	for _, g := range items {
+		if closedStatus(g.Status) {
+			continue
+		}
+		if g.OwnerUserID != ownerUserID {
+			return 0, ErrForbidden
+		}
+		pending = append(pending, g)
+	}
+
+	now := s.now()
+	n := 0
+	for _, g := range pending {
+		g.Status = StatusRevoked
+		g.UpdatedAt = now
+		g.RevokedAt = &now
+		if err := s.repo.Update(ctx, g); err != nil {
+			return n, err
+		}
+		s.notify(ctx, notifications.GrantRevoked, g, ownerUserID)
+		n++
+	}
+	return n, nil
+}
+
+// TransferPet ajusta los grants de una mascota que cambia de owner: revoca los grants
+// vigentes donde el nuevo owner era delegado (y su cascada de sub-delegación), para que
+// no sea a la vez owner y delegado, y reasigna los vigentes restantes al nuevo owner. Devuelve cuántos revocó.
+func (s *Service) TransferPet(ctx context.Context, petID, newOwnerUserID string) (int, error) {
+	petID = strings.TrimSpace(petID)
+	newOwnerUserID = strings.TrimSpace(newOwnerUserID)
+	if petID == "" || newOwnerUserID == "" {
+		return 0, ErrInvalidInput
+	}
+
+	items, err := s.repo.ListByPet(ctx, auth.TenantFromContext(ctx), petID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	revoked := map[string]bool{}
+	n := 0
+	for _, g := range items {
+		if g.GranteeUserID != newOwnerUserID || closedStatus(g.Status) {
+			continue
+		}
+		g.Status = StatusRevoked
+		g.UpdatedAt = now
+		g.RevokedAt = &now
+		if err := s.repo.Update(ctx, g); err != nil {
+			return n, err
+		}
+		revoked[g.ID] = true
+		n++
+
+		desc, err := s.descendants(ctx, g)
+		if err != nil {
+			return n, err
+		}
+		for _, d := range desc {
+			if closedStatus(d.Status) || revoked[d.ID] {
+				continue
+			}
+			d.Status = StatusRevoked
+			d.UpdatedAt = now
+			d.RevokedAt = &now
+			if err := s.repo.Update(ctx, d); err != nil {
+				return n, err
+			}
+			revoked[d.ID] = true
+			n++
+		}
+	}
+
+	// Los grants cerrados (o recién revocados) conservan el owner que los otorgó.
+	for _, g := range items {
+		if revoked[g.ID] || closedStatus(g.Status) || g.OwnerUserID == newOwnerUserID {
+			continue
+		}
+		g.OwnerUserID = newOwnerUserID
+		g.UpdatedAt = now
+		if err := s.repo.Update(ctx, g); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// descendants devuelve los grants sub-delegados (directa o indirectamente) a partir de root.
+func (s *Service) descendants(ctx context.Context, root Grant) ([]Grant, error) {
+	items, err := s.repo.ListByPet(ctx, auth.TenantFromContext(ctx), root.PetID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[string][]Grant{}
+	for _, g := range items {
+		if g.ParentGrantID != "" {
+			children[g.ParentGrantID] = append(children[g.ParentGrantID], g)
+		}
+	}
+
+	out := make([]Grant, 0)
+	queue := []string{root.ID}
+	seen := map[string]struct{}{root.ID: {}}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, c := range children[id] {
+			if _, ok := seen[c.ID]; ok {
+				continue
+			}
+			seen[c.ID] = struct{}{}
+			out = append(out, c)
+			queue = append(queue, c.ID)
+		}
+	}
+	return out, nil
+}
+
+// revokeDescendants revoca best-effort todo el árbol sub-delegado bajo root.
+func (s *Service) revokeDescendants(ctx context.Context, root Grant, now time.Time) error {
+	items, err := s.descendants(ctx, root)
+	if err != nil {
+		return err
+	}
+	for _, g := range items {
+		if g.Status == StatusRevoked {
+			continue
+		}
+		g.Status = StatusRevoked
+		g.UpdatedAt = now
+		g.RevokedAt = &now
+		_ = s.repo.Update(ctx, g) // best-effort (MVP)
+	}
+	return nil
+}
+
+// clampDescendants recorta los scopes de los sub-delegados de root para que nunca
+// excedan los de su delegador (p.ej. tras re-invitar a root con menos scopes).
+func (s *Service) clampDescendants(ctx context.Context, root Grant, now time.Time) error {
+	items, err := s.descendants(ctx, root)
+	if err != nil {
+		return err
+	}
+
+	// descendants sale en orden BFS: el padre siempre se procesa antes que sus hijos.
+	scopesByID := map[string][]Scope{root.ID: root.Scopes}
+	for _, g := range items {
+		allowed := scopesByID[g.ParentGrantID]
+		kept := make([]Scope, 0, len(g.Scopes))
+		for _, sc := range g.Scopes {
+			if HasScope(Grant{Scopes: allowed}, sc) {
+				kept = append(kept, sc)
+			}
+		}
+		scopesByID[g.ID] = kept
+
+		if len(kept) == len(g.Scopes) || g.Status == StatusRevoked {
+			continue
+		}
+		g.Scopes = kept
+		g.UpdatedAt = now
+		_ = s.repo.Update(ctx, g) // best-effort (MVP)
+	}
+	return nil
+}
+
 func (s *Service) ListByPet(ctx context.Context, petID string) ([]Grant, error) {
 	petID = strings.TrimSpace(petID)
 	if petID == "" {
 		return nil, ErrInvalidInput
 	}
-	return s.repo.ListByPet(ctx, petID)
+	return s.repo.ListByPet(ctx, auth.TenantFromContext(ctx), petID)
+}
+
+// accessListOrder es el orden de estados de AccessList; los no listados van al final.
+var accessListOrder = map[Status]int{StatusActive: 0, StatusInvited: 1, StatusRevoked: 2}
+
+// AccessList devuelve todos los grants de la mascota para la vista "quién puede verla" del
+// owner: primero los activos, luego las invitaciones pendientes, luego los revocados (y al
+// final rechazados / vencidos); dentro de cada estado, el más reciente primero. Una invitación
+// con el plazo vencido se informa como StatusExpired, igual que en ListByGrantee.
+func (s *Service) AccessList(ctx context.Context, petID string) ([]Grant, error) {
+	items, err := s.ListByPet(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	for i := range items {
+		if items[i].InviteLapsedAt(now) {
+			items[i].Status = StatusExpired
+		}
+	}
+	rank := func(st Status) int {
+		if n, ok := accessListOrder[st]; ok {
+			return n
+		}
+		return len(accessListOrder)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		ri, rj := rank(items[i].Status), rank(items[j].Status)
+		if ri != rj {
+			return ri < rj
+		}
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
 }
 
 func (s *Service) GetActiveGrant(ctx context.Context, petID, granteeUserID string) (Grant, error) {
@@ -233,19 +868,278 @@ func (s *Service) GetActiveGrant(ctx context.Context, petID, granteeUserID strin
 	if petID == "" || granteeUserID == "" {
 		return Grant{}, ErrInvalidInput
 	}
-	g, err := s.repo.GetActiveGrant(ctx, petID, granteeUserID)
+	g, err := s.activeGrant(ctx, petID, granteeUserID)
 	if err != nil {
 		return Grant{}, ErrNotFound
 	}
+	s.markUsed(ctx, &g)
 	return g, nil
 }
 
-func (s *Service) ListByGrantee(ctx context.Context, granteeUserID string) ([]Grant, error) {
+// markUsed registra que el grant se usó en un request, salvo que ya se haya registrado hace
+// menos de UsageDebounce. Best-effort: si falla, el request sigue.
+func (s *Service) markUsed(ctx context.Context, g *Grant) {
+	now := s.now()
+	if g.LastUsedAt != nil && now.Sub(*g.LastUsedAt) < UsageDebounce {
+		return
+	}
+	if err := s.repo.MarkUsed(ctx, g.ID, now); err == nil {
+		g.LastUsedAt = &now
+	}
+}
+
+// ListStale devuelve los grants activos (no vencidos) de la mascota que no se usan hace más
+// de olderThan, o que nunca se usaron: candidatos a revocar.
+func (s *Service) ListStale(ctx context.Context, petID string, olderThan time.Duration) ([]Grant, error) {
+	if olderThan <= 0 {
+		return nil, ErrInvalidInput
+	}
+	items, err := s.ListByPet(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	cutoff := now.Add(-olderThan)
+	out := make([]Grant, 0)
+	for _, g := range items {
+		if g.Status != StatusActive || g.ExpiredAt(now) {
+			continue
+		}
+		if g.LastUsedAt == nil || g.LastUsedAt.Before(cutoff) {
+			out = append(out, g)
+		}
+	}
+	return out, nil
+}
+
+// activeGrant es repo.GetActiveGrant descartando grants vencidos (ExpiresAt <= now) y
+// sub-delegaciones cuya cadena de delegadores ya no está vigente.
+func (s *Service) activeGrant(ctx context.Context, petID, granteeUserID string) (Grant, error) {
+	g, err := s.repo.GetActiveGrant(ctx, auth.TenantFromContext(ctx), petID, granteeUserID)
+	if err != nil {
+		return Grant{}, err
+	}
+	if g.ExpiredAt(s.now()) {
+		return Grant{}, ErrNotFound
+	}
+	if s.parentChainDeny(ctx, g) != "" {
+		return Grant{}, ErrNotFound
+	}
+	return g, nil
+}
+
+// parentChainDeny recorre los delegadores de g (ParentGrantID) y devuelve por qué ya no
+// sostienen al sub-delegado: alguno no está activo, no existe o venció. "" si toda la cadena
+// está vigente.
+func (s *Service) parentChainDeny(ctx context.Context, g Grant) DenyReason {
+	now := s.now()
+	seen := map[string]struct{}{g.ID: {}}
+	for id := g.ParentGrantID; id != ""; {
+		if _, ok := seen[id]; ok {
+			return DenyGrantNotActive
+		}
+		seen[id] = struct{}{}
+
+		parent, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), id)
+		if err != nil || parent.Status != StatusActive {
+			return DenyGrantNotActive
+		}
+		if parent.ExpiredAt(now) {
+			return DenyGrantExpired
+		}
+		id = parent.ParentGrantID
+	}
+	return ""
+}
+
+// ListByGrantee lista los grants del delegado; con statuses solo los de esos estados.
+// Las invitaciones con el plazo vencido se devuelven como StatusExpired aunque el barrido
+// (ExpireStaleInvites) todavía no las haya persistido así.
+func (s *Service) ListByGrantee(ctx context.Context, granteeUserID string, statuses ...Status) ([]Grant, error) {
+	items, _, err := s.listByGrantee(ctx, granteeUserID, GranteeFilter{Statuses: statuses})
+	return items, err
+}
+
+// ListByGranteePage es ListByGrantee paginado por keyset (updated_at desc, id desc): devuelve
+// hasta filter.Limit grants (<= 0 o > MaxGranteeListLimit => MaxGranteeListLimit) y el
+// cursor de la página siguiente ("" si no hay más). El cursor sale de la última fila del repo,
+// así que una página puede traer menos de Limit grants si alguna invitación vencida quedó
+// fuera del filtro de estados, pero nunca se saltea ni repite un grant.
+func (s *Service) ListByGranteePage(ctx context.Context, granteeUserID string, filter GranteeFilter) ([]Grant, string, error) {
+	if filter.Limit <= 0 || filter.Limit > MaxGranteeListLimit {
+		filter.Limit = MaxGranteeListLimit
+	}
+	return s.listByGrantee(ctx, granteeUserID, filter)
+}
+
+func (s *Service) listByGrantee(ctx context.Context, granteeUserID string, filter GranteeFilter) ([]Grant, string, error) {
 	granteeUserID = strings.TrimSpace(granteeUserID)
 	if granteeUserID == "" {
-		return nil, ErrInvalidInput
+		return nil, "", ErrInvalidInput
+	}
+
+	// Una invitación "expired" puede seguir guardada como invited: hay que pedirla al repo.
+	statuses := filter.Statuses
+	query := filter
+	if slices.Contains(statuses, StatusExpired) && !slices.Contains(statuses, StatusInvited) {
+		query.Statuses = append(slices.Clone(statuses), StatusInvited)
+	}
+	limit := filter.Limit
+	if limit > 0 {
+		query.Limit = limit + 1
 	}
-	return s.repo.ListByGrantee(ctx, granteeUserID)
+	items, err := s.repo.ListByGrantee(ctx, auth.TenantFromContext(ctx), granteeUserID, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+		last := items[limit-1]
+		next = Cursor{UpdatedAt: last.UpdatedAt, ID: last.ID}.Encode()
+	}
+
+	now := s.now()
+	out := items[:0]
+	for _, g := range items {
+		if g.InviteLapsedAt(now) {
+			g.Status = StatusExpired
+		}
+		if len(statuses) > 0 && !slices.Contains(statuses, g.Status) {
+			continue
+		}
+		out = append(out, g)
+	}
+	return out, next, nil
+}
+
+// ExpireStaleInvites pasa a StatusExpired (terminal) las invitaciones pendientes de todos
+// los tenants cuyo plazo para aceptar venció antes de before (normalmente now). Pensado para
+// que el caller lo programe; devuelve cuántas venció. MVP: best-effort, sin transacción:
+// ante un error se corta y el próximo barrido retoma las que quedaron.
+func (s *Service) ExpireStaleInvites(ctx context.Context, before time.Time) (int, error) {
+	items, err := s.repo.ListInvitesExpiredBefore(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	n := 0
+	for _, g := range items {
+		if !g.InviteLapsedAt(before) {
+			continue
+		}
+		g.Status = StatusExpired
+		g.UpdatedAt = now
+		if err := s.repo.Update(ctx, g); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// inviteDeadline es el InviteExpiresAt de una invitación hecha en now (nil si no hay plazo).
+func (s *Service) inviteDeadline(now time.Time) *time.Time {
+	if s.inviteTTL <= 0 {
+		return nil
+	}
+	t := now.Add(s.inviteTTL)
+	return &t
+}
+
+// closedStatus indica los estados terminales: un grant así no se re-invita ni se reabre.
+func closedStatus(st Status) bool {
+	return st == StatusRevoked || st == StatusDeclined || st == StatusExpired
+}
+
+// countOpenGrants cuenta los grants vigentes (invitados o activos), incluidas las
+// sub-delegaciones; una invitación con el plazo vencido ya no cuenta.
+func countOpenGrants(items []Grant, now time.Time) int {
+	n := 0
+	for _, g := range items {
+		if !closedStatus(g.Status) && !g.InviteLapsedAt(now) {
+			n++
+		}
+	}
+	return n
+}
+
+// ScopeValidation es el resultado de validar un set de scopes sin efectos secundarios.
+type ScopeValidation struct {
+	Valid      bool
+	Normalized []Scope // scopes soportados, sin duplicados, en orden de aparición
+	Invalid    []Scope // scopes no reconocidos
+}
+
+// ValidateScopes aplica las mismas reglas que Invite (normalizeScopesStrict) pero reporta
+// qué scopes no son válidos en vez de fallar. Un set vacío (tras normalizar) no es válido.
+func (s *Service) ValidateScopes(in []Scope) ScopeValidation {
+	normalized, invalid := splitScopes(in)
+	return ScopeValidation{
+		Valid:      len(invalid) == 0 && len(normalized) > 0,
+		Normalized: normalized,
+		Invalid:    invalid,
+	}
+}
+
+// DenyReason explica por qué un delegado no puede ejecutar una acción sobre una mascota.
+type DenyReason string
+
+const (
+	// DenyNoGrant: el usuario nunca recibió un grant para la mascota.
+	DenyNoGrant DenyReason = "no_grant"
+	// DenyGrantNotActive: existe un grant pero no está vigente (invitado sin aceptar o revocado).
+	DenyGrantNotActive DenyReason = "grant_not_active"
+	// DenyGrantExpired: el grant estaba activo pero su expires_at ya pasó.
+	DenyGrantExpired DenyReason = "grant_expired"
+)
+
+// DenyMissingScope arma la razón para un grant activo al que le falta el scope requerido,
+// p.ej. "missing_scope:events:create".
+func DenyMissingScope(scope Scope) DenyReason {
+	return DenyReason("missing_scope:" + string(scope))
+}
+
+// HasActiveScope resuelve si granteeUserID tiene un grant activo con scope sobre petID.
+// Devuelve el grant y una razón vacía si está permitido; si no, la razón del rechazo.
+// error solo se devuelve ante fallas del repositorio (no por falta de permisos).
+// No aplica el bypass de owner: eso lo decide el handler.
+func (s *Service) HasActiveScope(ctx context.Context, petID, granteeUserID string, scope Scope) (Grant, DenyReason, error) {
+	petID = strings.TrimSpace(petID)
+	granteeUserID = strings.TrimSpace(granteeUserID)
+	if petID == "" || granteeUserID == "" {
+		return Grant{}, DenyNoGrant, nil
+	}
+
+	g, err := s.repo.GetActiveGrant(ctx, auth.TenantFromContext(ctx), petID, granteeUserID)
+	if err == nil {
+		if g.ExpiredAt(s.now()) {
+			return Grant{}, DenyGrantExpired, nil
+		}
+		if reason := s.parentChainDeny(ctx, g); reason != "" {
+			return Grant{}, reason, nil
+		}
+		if !HasScope(g, scope) {
+			return g, DenyMissingScope(scope), nil
+		}
+		s.markUsed(ctx, &g)
+		return g, "", nil
+	}
+
+	// Sin grant activo: distinguimos "nunca invitado" de "invitado/revocado".
+	items, err := s.repo.ListByPet(ctx, auth.TenantFromContext(ctx), petID)
+	if err != nil {
+		return Grant{}, "", err
+	}
+	for _, it := range items {
+		if it.GranteeUserID == granteeUserID {
+			return Grant{}, DenyGrantNotActive, nil
+		}
+	}
+	return Grant{}, DenyNoGrant, nil
 }
 
 // HasScope valida si el grant incluye un scope.
@@ -261,7 +1155,7 @@ func HasScope(g Grant, scope Scope) bool {
 // revokeOtherByPetAndGrantee revoca best-effort cualquier otro grant no revocado para (petID, granteeID),
 // excepto keepID. Esto evita múltiples "activos" para el mismo delegado.
 func (s *Service) revokeOtherByPetAndGrantee(ctx context.Context, keepID, petID, granteeID string, now time.Time) error {
-	items, err := s.repo.ListByPet(ctx, petID)
+	items, err := s.repo.ListByPet(ctx, auth.TenantFromContext(ctx), petID)
 	if err != nil {
 		return err
 	}
@@ -273,7 +1167,7 @@ func (s *Service) revokeOtherByPetAndGrantee(ctx context.Context, keepID, petID,
 		if g.PetID != petID || g.GranteeUserID != granteeID {
 			continue
 		}
-		if g.Status == StatusRevoked {
+		if closedStatus(g.Status) {
 			continue
 		}
 
@@ -286,33 +1180,115 @@ func (s *Service) revokeOtherByPetAndGrantee(ctx context.Context, keepID, petID,
 	return nil
 }
 
+// checkPlanScopes verifica que el plan de ownerUserID permita otorgar cada scope.
+// Devuelve *ScopesNotInPlanError con los no permitidos. Sin resolver no se valida (dev).
+func (s *Service) checkPlanScopes(ctx context.Context, ownerUserID string, scopes []Scope) error {
+	if s.caps == nil {
+		return nil
+	}
+	var denied []Scope
+	for _, sc := range scopes {
+		ok, err := s.caps.HasFeature(ctx, capabilities.CapabilityCheck{
+			ProjectKey: capabilities.ProjectKey,
+			TenantID:   auth.TenantFromContext(ctx),
+			UserID:     ownerUserID,
+			FeatureKey: ScopeCapability(sc),
+		})
+		if err != nil {
+			return ErrCapabilitiesUnavailable
+		}
+		if !ok {
+			denied = append(denied, sc)
+		}
+	}
+	if len(denied) > 0 {
+		return &ScopesNotInPlanError{Scopes: denied}
+	}
+	return nil
+}
+
 func normalizeScopesStrict(in []Scope) ([]Scope, error) {
-	allowed := map[Scope]struct{}{
-		ScopePetRead:        {},
-		ScopePetEditProfile: {},
-		ScopeEventsRead:     {},
-		ScopeEventsCreate:   {},
-		ScopeEventsVoid:     {},
-		ScopeAttachmentsAdd: {},
+	out, invalid := splitScopes(in)
+	if len(invalid) > 0 {
+		return nil, ErrInvalidInput
+	}
+	return out, nil
+}
+
+// splitScopes normaliza (trim + dedup, preservando orden) y separa los scopes no soportados.
+func splitScopes(in []Scope) (normalized []Scope, invalid []Scope) {
+	allowed := map[Scope]struct{}{}
+	for _, s := range AllScopes() {
+		allowed[s] = struct{}{}
 	}
 
 	seen := map[Scope]struct{}{}
-	out := make([]Scope, 0, len(in))
+	normalized = make([]Scope, 0, len(in))
+	invalid = make([]Scope, 0)
 
 	for _, raw := range in {
 		s := Scope(strings.TrimSpace(string(raw)))
 		if s == "" {
 			continue
 		}
-		if _, ok := allowed[s]; !ok {
-			return nil, ErrInvalidInput
-		}
 		if _, ok := seen[s]; ok {
 			continue
 		}
 		seen[s] = struct{}{}
-		out = append(out, s)
+
+		if _, ok := allowed[s]; !ok {
+			invalid = append(invalid, s)
+			continue
+		}
+		normalized = append(normalized, s)
 	}
 
-	return out, nil
+	return normalized, invalid
+}
+
+// inviterID es quien otorgó el grant: el delegador en una sub-delegación, si no el owner.
+func inviterID(g Grant) string {
+	if g.DelegatedByUserID != "" {
+		return g.DelegatedByUserID
+	}
+	return g.OwnerUserID
+}
+
+// notify cuenta la transición y avisa al notifier de forma asíncrona y best-effort, después
+// de persistir el cambio: nunca bloquea ni falla la operación; los errores solo se loguean.
+func (s *Service) notify(ctx context.Context, typ notifications.GrantEventType, g Grant, actorUserID string) {
+	s.metrics.Inc(MetricGrantEvents, "type", string(typ))
+
+	if s.notifier == nil {
+		return
+	}
+
+	scopes := make([]string, 0, len(g.Scopes))
+	for _, sc := range g.Scopes {
+		scopes = append(scopes, string(sc))
+	}
+	ev := notifications.GrantEvent{
+		Type:          typ,
+		GrantID:       g.ID,
+		PetID:         g.PetID,
+		TenantID:      g.TenantID,
+		OwnerUserID:   g.OwnerUserID,
+		GranteeUserID: g.GranteeUserID,
+		Scopes:        scopes,
+		ActorUserID:   actorUserID,
+		OccurredAt:    g.UpdatedAt,
+	}
+
+	go func() {
+		// Contexto propio (conserva los valores del request): el del request se cancela al responder.
+		nctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+		defer cancel()
+		if err := s.notifier.Notify(nctx, ev); err != nil && s.log != nil {
+			s.log.Warn("grant notification failed", map[string]any{
+				"type":     string(ev.Type),
+				"grant_id": ev.GrantID,
+				"error":    err.Error(),
+			})
+		}
+	}()
 }
@@ -3,8 +3,14 @@ package accessgrants
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
 	"time"
+
+	"pet-clinical-history/internal/ids"
+	"pet-clinical-history/internal/ports/notifications"
 )
 
 // -------------------------
@@ -15,6 +21,8 @@ var errRepoNotFound = errors.New("repo: not found")
 
 type testRepo struct {
 	byID map[string]Grant
+
+	markUsedCalls int
 }
 
 func newTestRepo() *testRepo {
@@ -43,30 +51,41 @@ func (r *testRepo) Update(ctx context.Context, g Grant) error {
 	return nil
 }
 
-func (r *testRepo) GetByID(ctx context.Context, id string) (Grant, error) {
+func (r *testRepo) MarkUsed(ctx context.Context, id string, at time.Time) error {
 	g, ok := r.byID[id]
 	if !ok {
+		return errRepoNotFound
+	}
+	r.markUsedCalls++
+	g.LastUsedAt = &at
+	r.byID[id] = g
+	return nil
+}
+
+func (r *testRepo) GetByID(ctx context.Context, tenantID, id string) (Grant, error) {
+	g, ok := r.byID[id]
+	if !ok || g.TenantID != tenantID {
 		return Grant{}, errRepoNotFound
 	}
 	return g, nil
 }
 
-func (r *testRepo) ListByPet(ctx context.Context, petID string) ([]Grant, error) {
+func (r *testRepo) ListByPet(ctx context.Context, tenantID, petID string) ([]Grant, error) {
 	out := make([]Grant, 0)
 	for _, g := range r.byID {
-		if g.PetID == petID {
+		if g.PetID == petID && g.TenantID == tenantID {
 			out = append(out, g)
 		}
 	}
 	return out, nil
 }
 
-func (r *testRepo) GetActiveGrant(ctx context.Context, petID, granteeUserID string) (Grant, error) {
+func (r *testRepo) GetActiveGrant(ctx context.Context, tenantID, petID, granteeUserID string) (Grant, error) {
 	var winner Grant
 	has := false
 
 	for _, g := range r.byID {
-		if g.PetID != petID {
+		if g.PetID != petID || g.TenantID != tenantID {
 			continue
 		}
 		if g.GranteeUserID != granteeUserID {
@@ -96,16 +115,42 @@ func (r *testRepo) GetActiveGrant(ctx context.Context, petID, granteeUserID stri
 	return winner, nil
 }
 
-func (r *testRepo) ListByGrantee(ctx context.Context, granteeUserID string) ([]Grant, error) {
+func (r *testRepo) ListInvitesExpiredBefore(ctx context.Context, before time.Time) ([]Grant, error) {
 	out := make([]Grant, 0)
 	for _, g := range r.byID {
-		if g.GranteeUserID == granteeUserID {
+		if g.InviteLapsedAt(before) {
 			out = append(out, g)
 		}
 	}
 	return out, nil
 }
 
+func (r *testRepo) ListByGrantee(ctx context.Context, tenantID, granteeUserID string, filter GranteeFilter) ([]Grant, error) {
+	out := make([]Grant, 0)
+	for _, g := range r.byID {
+		if g.GranteeUserID != granteeUserID || g.TenantID != tenantID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, g.Status) {
+			continue
+		}
+		if filter.Cursor != nil && !filter.Cursor.After(g) {
+			continue
+		}
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].UpdatedAt.Equal(out[j].UpdatedAt) {
+			return out[i].UpdatedAt.After(out[j].UpdatedAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
 // -------------------------
 // Tests
 // -------------------------
@@ -138,6 +183,63 @@ func TestService_Invite_DefaultScopes_WhenEmpty(t *testing.T) {
 	}
 }
 
+func TestService_Invite_ConfiguredDefaultScopes(t *testing.T) {
+	svc, err := NewServiceWithOptions(newTestRepo(), WithDefaultScopes(ScopePetRead, ScopeEventsRead, ScopeEventsCreate, " events:create "))
+	if err != nil {
+		t.Fatalf("NewServiceWithOptions returned error: %v", err)
+	}
+
+	g, err := svc.Invite(context.Background(), InviteInput{
+		PetID:         "pet-1",
+		OwnerUserID:   "owner-1",
+		GranteeUserID: "delegate-1",
+	})
+	if err != nil {
+		t.Fatalf("Invite returned error: %v", err)
+	}
+	// Normalizados al construir (trim + dedup)
+	want := []Scope{ScopePetRead, ScopeEventsRead, ScopeEventsCreate}
+	if len(g.Scopes) != len(want) {
+		t.Fatalf("expected configured default scopes %v, got %v", want, g.Scopes)
+	}
+	for i := range want {
+		if g.Scopes[i] != want[i] {
+			t.Fatalf("expected configured default scopes %v, got %v", want, g.Scopes)
+		}
+	}
+
+	// Scopes explícitos siguen mandando
+	g, err = svc.Invite(context.Background(), InviteInput{
+		PetID:         "pet-2",
+		OwnerUserID:   "owner-1",
+		GranteeUserID: "delegate-1",
+		Scopes:        []Scope{ScopePetRead},
+	})
+	if err != nil || len(g.Scopes) != 1 || g.Scopes[0] != ScopePetRead {
+		t.Fatalf("expected explicit scopes to win, got %v err=%v", g.Scopes, err)
+	}
+}
+
+func TestNewServiceWithOptions_RejectsBadDefaultScopes(t *testing.T) {
+	for _, scopes := range [][]Scope{
+		{ScopePetRead, Scope("bad:scope")},
+		{Scope(" ")},
+		nil,
+	} {
+		if _, err := NewServiceWithOptions(newTestRepo(), WithDefaultScopes(scopes...)); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for defaults %v, got %v", scopes, err)
+		}
+	}
+
+	// NewService no cae a los defaults en silencio: una config inválida entra en pánico.
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected NewService to panic on invalid default scopes")
+		}
+	}()
+	NewService(newTestRepo(), WithDefaultScopes(Scope("bad:scope")))
+}
+
 func TestService_Invite_StrictScopes_RejectsUnknown(t *testing.T) {
 	repo := newTestRepo()
 	svc := NewService(repo)
@@ -282,3 +384,685 @@ func TestService_Accept_LeavesOnlyOneActive_ForPetAndGrantee(t *testing.T) {
 		t.Fatalf("expected exactly 1 active grant, got %d", activeCount)
 	}
 }
+
+func TestService_HasActiveScope_Reasons(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo)
+
+	now := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	seed := func(id, grantee string, status Status, scopes ...Scope) {
+		_ = repo.Create(context.Background(), Grant{
+			ID:            id,
+			PetID:         "pet-1",
+			OwnerUserID:   "owner-1",
+			GranteeUserID: grantee,
+			Scopes:        scopes,
+			Status:        status,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	seed("g-invited", "invited-1", StatusInvited, ScopeEventsCreate)
+	seed("g-revoked", "revoked-1", StatusRevoked, ScopeEventsCreate)
+	seed("g-reader", "reader-1", StatusActive, ScopePetRead, ScopeEventsRead)
+	seed("g-writer", "writer-1", StatusActive, ScopeEventsCreate)
+
+	cases := []struct {
+		grantee string
+		want    DenyReason
+	}{
+		{"stranger-1", DenyNoGrant},
+		{"invited-1", DenyGrantNotActive},
+		{"revoked-1", DenyGrantNotActive},
+		{"reader-1", DenyReason("missing_scope:events:create")},
+		{"writer-1", ""},
+	}
+
+	for _, tc := range cases {
+		_, reason, err := svc.HasActiveScope(context.Background(), "pet-1", tc.grantee, ScopeEventsCreate)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.grantee, err)
+		}
+		if reason != tc.want {
+			t.Fatalf("%s: expected reason %q, got %q", tc.grantee, tc.want, reason)
+		}
+	}
+}
+
+func TestService_Invite_UsesInjectedIDGenerator(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo, WithIDGenerator(ids.Sequence("grant-")))
+
+	for i, grantee := range []string{"delegate-1", "delegate-2"} {
+		g, err := svc.Invite(context.Background(), InviteInput{
+			PetID:         "pet-1",
+			OwnerUserID:   "owner-1",
+			GranteeUserID: grantee,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"grant-000001", "grant-000002"}[i]
+		if g.ID != want {
+			t.Fatalf("expected id %s, got %s", want, g.ID)
+		}
+	}
+}
+
+func TestService_Invite_SubDelegation(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo)
+
+	now := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Owner => guardería (delegado con grants:delegate)
+	boarding, err := svc.Invite(ctx, InviteInput{
+		PetID:         "pet-1",
+		OwnerUserID:   "owner-1",
+		GranteeUserID: "boarding-1",
+		Scopes:        []Scope{ScopePetRead, ScopeEventsRead, ScopeEventsCreate, ScopeGrantsDelegate},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Accept(ctx, boarding.ID, "boarding-1"); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+
+	// 1) Dentro de sus scopes => ok, cuelga de su grant
+	staff, err := svc.Invite(ctx, InviteInput{
+		PetID:           "pet-1",
+		OwnerUserID:     "owner-1",
+		GranteeUserID:   "staff-1",
+		Scopes:          []Scope{ScopePetRead, ScopeEventsCreate},
+		DelegatorUserID: "boarding-1",
+	})
+	if err != nil {
+		t.Fatalf("expected sub-delegation within scopes to succeed, got %v", err)
+	}
+	if staff.ParentGrantID != boarding.ID || staff.DelegatedByUserID != "boarding-1" || staff.OwnerUserID != "owner-1" {
+		t.Fatalf("unexpected sub-grant linkage: %+v", staff)
+	}
+
+	// 2) Excediendo sus scopes => ErrScopesExceedDelegator
+	_, err = svc.Invite(ctx, InviteInput{
+		PetID:           "pet-1",
+		OwnerUserID:     "owner-1",
+		GranteeUserID:   "staff-2",
+		Scopes:          []Scope{ScopePetRead, ScopeEventsVoid},
+		DelegatorUserID: "boarding-1",
+	})
+	if !errors.Is(err, ErrScopesExceedDelegator) {
+		t.Fatalf("expected ErrScopesExceedDelegator, got %v", err)
+	}
+
+	// 3) Un sub-delegado sin grants:delegate no puede seguir delegando
+	if _, err := svc.Accept(ctx, staff.ID, "staff-1"); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	_, err = svc.Invite(ctx, InviteInput{
+		PetID:           "pet-1",
+		OwnerUserID:     "owner-1",
+		GranteeUserID:   "staff-3",
+		Scopes:          []Scope{ScopePetRead},
+		DelegatorUserID: "staff-1",
+	})
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden without grants:delegate, got %v", err)
+	}
+
+	// 4) Owner reduce scopes de la guardería => el sub-grant se recorta
+	if _, err := svc.Invite(ctx, InviteInput{
+		PetID:         "pet-1",
+		OwnerUserID:   "owner-1",
+		GranteeUserID: "boarding-1",
+		Scopes:        []Scope{ScopePetRead, ScopeGrantsDelegate},
+	}); err != nil {
+		t.Fatalf("re-invite: %v", err)
+	}
+	clamped, _ := repo.GetByID(ctx, "", staff.ID)
+	if HasScope(clamped, ScopeEventsCreate) || !HasScope(clamped, ScopePetRead) {
+		t.Fatalf("expected sub-grant clamped to [pet:read], got %v", clamped.Scopes)
+	}
+
+	// 5) Owner revoca la guardería => cae todo el árbol
+	if _, _, err := svc.Revoke(ctx, boarding.ID, "owner-1"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	revoked, _ := repo.GetByID(ctx, "", staff.ID)
+	if revoked.Status != StatusRevoked {
+		t.Fatalf("expected sub-grant revoked in cascade, got %s", revoked.Status)
+	}
+}
+
+func TestService_Invite_SubDelegationBoundedByParentExpiry(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo)
+
+	now := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Cuidador por 48h con grants:delegate
+	sitterUntil := now.Add(48 * time.Hour)
+	sitter, err := svc.Invite(ctx, InviteInput{
+		PetID:         "pet-1",
+		OwnerUserID:   "owner-1",
+		GranteeUserID: "sitter-1",
+		Scopes:        []Scope{ScopePetRead, ScopeGrantsDelegate},
+		ExpiresAt:     &sitterUntil,
+	})
+	if err != nil {
+		t.Fatalf("invite sitter: %v", err)
+	}
+	if _, err := svc.Accept(ctx, sitter.ID, "sitter-1"); err != nil {
+		t.Fatalf("accept sitter: %v", err)
+	}
+
+	sub := func(expiresAt *time.Time) (Grant, error) {
+		return svc.Invite(ctx, InviteInput{
+			PetID:           "pet-1",
+			OwnerUserID:     "owner-1",
+			GranteeUserID:   "staff-1",
+			Scopes:          []Scope{ScopePetRead},
+			DelegatorUserID: "sitter-1",
+			ExpiresAt:       expiresAt,
+		})
+	}
+
+	// Sin vencimiento o después del delegador => rechazado
+	if _, err := sub(nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for sub-grant without expires_at, got %v", err)
+	}
+	later := sitterUntil.Add(time.Hour)
+	if _, err := sub(&later); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for sub-grant outliving delegator, got %v", err)
+	}
+
+	// Dentro del vencimiento del delegador => ok
+	staffUntil := now.Add(24 * time.Hour)
+	staff, err := sub(&staffUntil)
+	if err != nil {
+		t.Fatalf("expected bounded sub-grant to succeed, got %v", err)
+	}
+	if _, err := svc.Accept(ctx, staff.ID, "staff-1"); err != nil {
+		t.Fatalf("accept staff: %v", err)
+	}
+
+	// El owner acorta el grant del cuidador por debajo del del staff
+	sitterShort := now.Add(12 * time.Hour)
+	if _, err := svc.Invite(ctx, InviteInput{
+		PetID:         "pet-1",
+		OwnerUserID:   "owner-1",
+		GranteeUserID: "sitter-1",
+		Scopes:        []Scope{ScopePetRead, ScopeGrantsDelegate},
+		ExpiresAt:     &sitterShort,
+	}); err != nil {
+		t.Fatalf("re-invite sitter: %v", err)
+	}
+	if _, reason, _ := svc.HasActiveScope(ctx, "pet-1", "staff-1", ScopePetRead); reason != "" {
+		t.Fatalf("expected staff access while sitter is valid, got reason %q", reason)
+	}
+
+	// Vence el cuidador: el staff pierde el acceso aunque su propio grant siga vigente
+	now = sitterShort
+	if _, reason, _ := svc.HasActiveScope(ctx, "pet-1", "staff-1", ScopePetRead); reason != DenyGrantExpired {
+		t.Fatalf("expected reason %q after delegator expired, got %q", DenyGrantExpired, reason)
+	}
+	if _, err := svc.GetActiveGrant(ctx, "pet-1", "staff-1"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for sub-grant of expired delegator, got %v", err)
+	}
+}
+
+func TestService_Revoke_ReportsOutcome(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo)
+
+	now := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	g, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "delegate-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, first, err := svc.Revoke(ctx, g.ID, "owner-1")
+	if err != nil {
+		t.Fatalf("first revoke: %v", err)
+	}
+	if first != RevokeOutcomeRevoked {
+		t.Fatalf("expected first revoke outcome %q, got %q", RevokeOutcomeRevoked, first)
+	}
+
+	again, second, err := svc.Revoke(ctx, g.ID, "owner-1")
+	if err != nil {
+		t.Fatalf("repeat revoke: %v", err)
+	}
+	if second != RevokeOutcomeAlreadyRevoked {
+		t.Fatalf("expected repeat revoke outcome %q, got %q", RevokeOutcomeAlreadyRevoked, second)
+	}
+	if again.Status != StatusRevoked {
+		t.Fatalf("expected grant to stay revoked, got %s", again.Status)
+	}
+}
+
+func TestService_Invite_ExpiresAt(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo)
+
+	now := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	past := now.Add(-time.Minute)
+	if _, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "sitter-1", ExpiresAt: &past}); err != ErrInvalidInput {
+		t.Fatalf("expected ErrInvalidInput for past expires_at, got %v", err)
+	}
+
+	until := now.Add(48 * time.Hour)
+	g, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "sitter-1", ExpiresAt: &until})
+	if err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	if _, err := svc.Accept(ctx, g.ID, "sitter-1"); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+
+	if _, reason, _ := svc.HasActiveScope(ctx, "pet-1", "sitter-1", ScopePetRead); reason != "" {
+		t.Fatalf("expected access before expiration, got reason %q", reason)
+	}
+
+	// Pasado el vencimiento: deja de estar activo sin revocar
+	now = until
+	if _, err := svc.GetActiveGrant(ctx, "pet-1", "sitter-1"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for expired grant, got %v", err)
+	}
+	if _, reason, _ := svc.HasActiveScope(ctx, "pet-1", "sitter-1", ScopePetRead); reason != DenyGrantExpired {
+		t.Fatalf("expected reason %q, got %q", DenyGrantExpired, reason)
+	}
+}
+
+func TestService_Invite_Message(t *testing.T) {
+	svc := NewService(newTestRepo())
+	ctx := context.Background()
+
+	long := strings.Repeat("ñ", MaxMessageLength+1)
+	if _, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "vet-1", Message: long}); err != ErrMessageTooLong {
+		t.Fatalf("expected ErrMessageTooLong, got %v", err)
+	}
+
+	// El límite es en caracteres, no en bytes
+	g, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "vet-1", Message: long[:len(long)-len("ñ")]})
+	if err != nil {
+		t.Fatalf("invite at max length: %v", err)
+	}
+
+	// Re-invitar reemplaza el mensaje
+	g2, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "vet-1", Message: "  semana de cirugía  "})
+	if err != nil {
+		t.Fatalf("re-invite: %v", err)
+	}
+	if g2.ID != g.ID || g2.Message != "semana de cirugía" {
+		t.Fatalf("expected same grant with replaced message, got %+v", g2)
+	}
+}
+
+func TestService_InviteDeadline_AcceptWindow(t *testing.T) {
+	svc := NewService(newTestRepo(), WithInviteTTL(48*time.Hour))
+	ctx := context.Background()
+
+	t0 := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return t0 }
+
+	g, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "vet-1"})
+	if err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	if g.InviteExpiresAt == nil || !g.InviteExpiresAt.Equal(t0.Add(48*time.Hour)) {
+		t.Fatalf("expected invite deadline t0+48h, got %v", g.InviteExpiresAt)
+	}
+
+	// Dentro del plazo se acepta
+	svc.now = func() time.Time { return t0.Add(47 * time.Hour) }
+	if _, err := svc.Accept(ctx, g.ID, "vet-1"); err != nil {
+		t.Fatalf("accept within window: %v", err)
+	}
+
+	// Pasado el plazo no
+	svc.now = func() time.Time { return t0 }
+	late, err := svc.Invite(ctx, InviteInput{PetID: "pet-2", OwnerUserID: "owner-1", GranteeUserID: "vet-1"})
+	if err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	svc.now = func() time.Time { return t0.Add(48 * time.Hour) }
+	if _, err := svc.Accept(ctx, late.ID, "vet-1"); err != ErrBadState {
+		t.Fatalf("expected ErrBadState accepting lapsed invite, got %v", err)
+	}
+
+	// Sin barrido todavía, /me/grants ya la informa como expired (y filtra por ese estado)
+	items, err := svc.ListByGrantee(ctx, "vet-1", StatusExpired)
+	if err != nil || len(items) != 1 || items[0].ID != late.ID || items[0].Status != StatusExpired {
+		t.Fatalf("expected lapsed invite listed as expired, got %+v err=%v", items, err)
+	}
+	if items, _ := svc.ListByGrantee(ctx, "vet-1", StatusInvited); len(items) != 0 {
+		t.Fatalf("expected no pending invites, got %+v", items)
+	}
+
+	// Re-invitar renueva el plazo
+	svc.now = func() time.Time { return t0.Add(24 * time.Hour) }
+	renewed, err := svc.Invite(ctx, InviteInput{PetID: "pet-2", OwnerUserID: "owner-1", GranteeUserID: "vet-1"})
+	if err != nil || renewed.ID != late.ID || !renewed.InviteExpiresAt.Equal(t0.Add(72*time.Hour)) {
+		t.Fatalf("expected same invite with renewed deadline, got %+v err=%v", renewed, err)
+	}
+
+	// TTL <= 0 => sin plazo
+	noTTL := NewService(newTestRepo(), WithInviteTTL(0))
+	g, err = noTTL.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "vet-1"})
+	if err != nil || g.InviteExpiresAt != nil {
+		t.Fatalf("expected no invite deadline, got %v err=%v", g.InviteExpiresAt, err)
+	}
+}
+
+func TestService_ExpireStaleInvites(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo, WithInviteTTL(24*time.Hour))
+	ctx := context.Background()
+
+	t0 := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return t0 }
+
+	stale, _ := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "vet-1"})
+	accepted, _ := svc.Invite(ctx, InviteInput{PetID: "pet-2", OwnerUserID: "owner-1", GranteeUserID: "vet-1"})
+	if _, err := svc.Accept(ctx, accepted.ID, "vet-1"); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	svc.now = func() time.Time { return t0.Add(12 * time.Hour) }
+	fresh, _ := svc.Invite(ctx, InviteInput{PetID: "pet-3", OwnerUserID: "owner-1", GranteeUserID: "vet-1"})
+
+	sweepAt := t0.Add(25 * time.Hour)
+	svc.now = func() time.Time { return sweepAt }
+	n, err := svc.ExpireStaleInvites(ctx, sweepAt)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 invite expired, got %d err=%v", n, err)
+	}
+
+	if g := repo.byID[stale.ID]; g.Status != StatusExpired || !g.UpdatedAt.Equal(sweepAt) {
+		t.Fatalf("expected stale invite expired at sweep time, got %+v", g)
+	}
+	if g := repo.byID[accepted.ID]; g.Status != StatusActive {
+		t.Fatalf("expected accepted grant untouched, got %s", g.Status)
+	}
+	if g := repo.byID[fresh.ID]; g.Status != StatusInvited {
+		t.Fatalf("expected fresh invite untouched, got %s", g.Status)
+	}
+
+	// Terminal: no se acepta ni se reabre; re-invitar crea una invitación nueva
+	if _, err := svc.Accept(ctx, stale.ID, "vet-1"); err != ErrBadState {
+		t.Fatalf("expected ErrBadState accepting expired invite, got %v", err)
+	}
+	again, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "vet-1"})
+	if err != nil || again.ID == stale.ID || again.Status != StatusInvited {
+		t.Fatalf("expected a new invite after expiry, got %+v err=%v", again, err)
+	}
+
+	// Idempotente: un segundo barrido no encuentra nada
+	if n, err := svc.ExpireStaleInvites(ctx, sweepAt); err != nil || n != 0 {
+		t.Fatalf("expected second sweep to expire 0, got %d err=%v", n, err)
+	}
+}
+
+func TestService_LastUsedAt_DebouncedAndListStale(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo)
+
+	now := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	accepted := func(grantee string) Grant {
+		t.Helper()
+		g, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: grantee})
+		if err != nil {
+			t.Fatalf("invite %s: %v", grantee, err)
+		}
+		if _, err := svc.Accept(ctx, g.ID, grantee); err != nil {
+			t.Fatalf("accept %s: %v", grantee, err)
+		}
+		return g
+	}
+	used := accepted("vet-1")
+	idle := accepted("sitter-1")
+	if _, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "pending-1"}); err != nil {
+		t.Fatalf("invite pending: %v", err)
+	}
+
+	// Un acceso real registra el uso; otro dentro del minuto no vuelve a escribir
+	if _, reason, _ := svc.HasActiveScope(ctx, "pet-1", "vet-1", ScopePetRead); reason != "" {
+		t.Fatalf("expected access, got %q", reason)
+	}
+	now = now.Add(30 * time.Second)
+	if _, err := svc.GetActiveGrant(ctx, "pet-1", "vet-1"); err != nil {
+		t.Fatalf("get active grant: %v", err)
+	}
+	if repo.markUsedCalls != 1 {
+		t.Fatalf("expected 1 write within the debounce window, got %d", repo.markUsedCalls)
+	}
+	// Un acceso denegado por scope no cuenta como uso
+	if _, reason, _ := svc.HasActiveScope(ctx, "pet-1", "sitter-1", ScopeEventsVoid); reason == "" {
+		t.Fatalf("expected missing scope")
+	}
+	if g := repo.byID[idle.ID]; g.LastUsedAt != nil {
+		t.Fatalf("expected denied access not to mark usage, got %v", g.LastUsedAt)
+	}
+
+	// Pasado el minuto se vuelve a registrar
+	now = now.Add(time.Minute)
+	if _, reason, _ := svc.HasActiveScope(ctx, "pet-1", "vet-1", ScopeEventsRead); reason != "" {
+		t.Fatalf("expected access, got %q", reason)
+	}
+	if repo.markUsedCalls != 2 || !repo.byID[used.ID].LastUsedAt.Equal(now) {
+		t.Fatalf("expected LastUsedAt refreshed to %s, got %v (%d writes)", now, repo.byID[used.ID].LastUsedAt, repo.markUsedCalls)
+	}
+
+	// Stale: solo activos sin uso reciente (el pendiente no cuenta)
+	stale, err := svc.ListStale(ctx, "pet-1", 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("list stale: %v", err)
+	}
+	if len(stale) != 1 || stale[0].ID != idle.ID {
+		t.Fatalf("expected only the never-used grant, got %+v", stale)
+	}
+
+	now = now.Add(31 * 24 * time.Hour)
+	stale, _ = svc.ListStale(ctx, "pet-1", 30*24*time.Hour)
+	if len(stale) != 2 {
+		t.Fatalf("expected both active grants stale after 31 days, got %d", len(stale))
+	}
+
+	if _, err := svc.ListStale(ctx, "pet-1", 0); err != ErrInvalidInput {
+		t.Fatalf("expected ErrInvalidInput for zero threshold, got %v", err)
+	}
+}
+
+func TestService_UpdateScopes_OwnerOnlyStrictAndNotRevoked(t *testing.T) {
+	repo := newTestRepo()
+	svc := NewService(repo)
+
+	now1 := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now1 }
+
+	g, err := svc.Invite(context.Background(), InviteInput{
+		PetID:         "pet-1",
+		OwnerUserID:   "owner-1",
+		GranteeUserID: "delegate-1",
+		Scopes:        []Scope{ScopePetRead},
+	})
+	if err != nil {
+		t.Fatalf("Invite returned error: %v", err)
+	}
+
+	now2 := now1.Add(time.Hour)
+	svc.now = func() time.Time { return now2 }
+
+	updated, err := svc.UpdateScopes(context.Background(), g.ID, "owner-1", []Scope{ScopePetRead, " events:read ", ScopePetRead})
+	if err != nil {
+		t.Fatalf("UpdateScopes returned error: %v", err)
+	}
+	if len(updated.Scopes) != 2 || updated.Scopes[0] != ScopePetRead || updated.Scopes[1] != ScopeEventsRead {
+		t.Fatalf("expected normalized scopes [pet:read events:read], got %v", updated.Scopes)
+	}
+	if !updated.UpdatedAt.Equal(now2) || updated.Status != StatusInvited {
+		t.Fatalf("expected UpdatedAt bumped and status unchanged, got %+v", updated)
+	}
+
+	if _, err := svc.UpdateScopes(context.Background(), g.ID, "delegate-1", []Scope{ScopePetRead}); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden for non-owner, got %v", err)
+	}
+	if _, err := svc.UpdateScopes(context.Background(), g.ID, "owner-1", []Scope{"pets:fly"}); err != ErrInvalidInput {
+		t.Fatalf("expected ErrInvalidInput for unsupported scope, got %v", err)
+	}
+	if _, err := svc.UpdateScopes(context.Background(), g.ID, "owner-1", nil); err != ErrInvalidInput {
+		t.Fatalf("expected ErrInvalidInput for empty scopes, got %v", err)
+	}
+	if _, err := svc.UpdateScopes(context.Background(), "missing", "owner-1", []Scope{ScopePetRead}); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, _, err := svc.Revoke(context.Background(), g.ID, "owner-1"); err != nil {
+		t.Fatalf("Revoke returned error: %v", err)
+	}
+	if _, err := svc.UpdateScopes(context.Background(), g.ID, "owner-1", []Scope{ScopePetRead}); err != ErrBadState {
+		t.Fatalf("expected ErrBadState for revoked grant, got %v", err)
+	}
+}
+
+// recordingNotifier guarda los eventos recibidos (Notify corre en otra goroutine).
+type recordingNotifier struct {
+	events chan notifications.GrantEvent
+	err    error
+}
+
+func newRecordingNotifier() *recordingNotifier {
+	return &recordingNotifier{events: make(chan notifications.GrantEvent, 16)}
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, ev notifications.GrantEvent) error {
+	n.events <- ev
+	return n.err
+}
+
+func (n *recordingNotifier) next(t *testing.T) notifications.GrantEvent {
+	t.Helper()
+	select {
+	case ev := <-n.events:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected a grant notification")
+		return notifications.GrantEvent{}
+	}
+}
+
+func (n *recordingNotifier) none(t *testing.T) {
+	t.Helper()
+	select {
+	case ev := <-n.events:
+		t.Fatalf("unexpected notification %s for grant %s", ev.Type, ev.GrantID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestService_Notifier_InviteAcceptRevoke(t *testing.T) {
+	repo := newTestRepo()
+	rec := newRecordingNotifier()
+	rec.err = errors.New("webhook down") // un fallo del notifier no afecta la operación
+	svc := NewService(repo, WithNotifier(rec))
+	ctx := context.Background()
+
+	g, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "delegate-1"})
+	if err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+	ev := rec.next(t)
+	if ev.Type != notifications.GrantInvited || ev.GrantID != g.ID || ev.ActorUserID != "owner-1" ||
+		ev.GranteeUserID != "delegate-1" || len(ev.Scopes) != 2 {
+		t.Fatalf("unexpected invite notification: %+v", ev)
+	}
+
+	if _, err := svc.Accept(ctx, g.ID, "delegate-1"); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if ev := rec.next(t); ev.Type != notifications.GrantAccepted || ev.ActorUserID != "delegate-1" {
+		t.Fatalf("unexpected accept notification: %+v", ev)
+	}
+
+	// Aceptar de nuevo es idempotente: no hay transición, no se notifica.
+	if _, err := svc.Accept(ctx, g.ID, "delegate-1"); err != nil {
+		t.Fatalf("repeat accept: %v", err)
+	}
+	rec.none(t)
+
+	if _, _, err := svc.Revoke(ctx, g.ID, "owner-1"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if ev := rec.next(t); ev.Type != notifications.GrantRevoked || ev.GrantID != g.ID || ev.ActorUserID != "owner-1" {
+		t.Fatalf("unexpected revoke notification: %+v", ev)
+	}
+
+	// Revocar de nuevo tampoco notifica.
+	if _, _, err := svc.Revoke(ctx, g.ID, "owner-1"); err != nil {
+		t.Fatalf("repeat revoke: %v", err)
+	}
+	rec.none(t)
+
+	// Una operación fallida no notifica.
+	if _, err := svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: "owner-1"}); err == nil {
+		t.Fatalf("expected invalid self-invite")
+	}
+	rec.none(t)
+}
+
+func TestService_Invite_MaxGrantsPerPet(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newTestRepo(), WithMaxGrantsPerPet(2))
+
+	invite := func(grantee string) (Grant, error) {
+		return svc.Invite(ctx, InviteInput{PetID: "pet-1", OwnerUserID: "owner-1", GranteeUserID: grantee})
+	}
+
+	first, err := invite("vet-1")
+	if err != nil {
+		t.Fatalf("invite vet-1: %v", err)
+	}
+	if _, err := invite("vet-2"); err != nil {
+		t.Fatalf("invite vet-2: %v", err)
+	}
+
+	// El tercero supera el máximo
+	if _, err := invite("vet-3"); !errors.Is(err, ErrTooManyGrants) {
+		t.Fatalf("expected ErrTooManyGrants, got %v", err)
+	}
+
+	// Re-invitar a un grantee existente no suma: sigue funcionando en el máximo
+	again, err := invite("vet-1")
+	if err != nil || again.ID != first.ID {
+		t.Fatalf("expected re-invite of vet-1 to reuse %s, got %+v err=%v", first.ID, again, err)
+	}
+
+	// Otra mascota tiene su propio cupo
+	if _, err := svc.Invite(ctx, InviteInput{PetID: "pet-2", OwnerUserID: "owner-1", GranteeUserID: "vet-3"}); err != nil {
+		t.Fatalf("invite on other pet: %v", err)
+	}
+
+	// Revocar libera un lugar
+	if _, _, err := svc.Revoke(ctx, first.ID, "owner-1"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := invite("vet-3"); err != nil {
+		t.Fatalf("expected invite after revoke to fit, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package accesslog
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/httpjson"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PetOwnerLookup evita importar el paquete pets (rompe ciclos).
+type PetOwnerLookup interface {
+	OwnerOf(ctx context.Context, petID string) (string, error)
+}
+
+func RegisterRoutes(r chi.Router, svc *Service, petOwners PetOwnerLookup) {
+	// Owner-only: quién (delegado) leyó qué y cuándo
+	r.Get("/pets/{petID}/access-log", listAccessLogHandler(svc, petOwners))
+}
+
+// accessLogEntryResponse representa una lectura de un delegado en las respuestas de la API.
+type accessLogEntryResponse struct {
+	ID            string       `json:"id"`
+	PetID         string       `json:"pet_id"`
+	GranteeUserID string       `json:"grantee_user_id"`
+	Resource      Resource     `json:"resource" enums:"pet_profile,events_list,event_detail,events_export"`
+	At            apitime.Time `json:"at" swaggertype:"string" format:"date-time"`
+}
+
+// listAccessLogHandler godoc
+// @Summary Listar lecturas de delegados sobre una mascota
+// @Description Devuelve el registro de lecturas (perfil y listado de eventos) hechas por delegados sobre la mascota, más recientes primero. Las lecturas del propio owner no se registran. Solo el owner puede consultarlo. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags accesslog
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param limit query int false "Máximo de entradas a devolver (1-200). Por defecto 50"
+// @Success 200 {array} accessLogEntryResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/access-log [get]
+func listAccessLogHandler(svc *Service, petOwners PetOwnerLookup) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		petID := chi.URLParam(r, "petID")
+
+		ownerID, err := petOwners.OwnerOf(r.Context(), petID)
+		if err != nil || strings.TrimSpace(ownerID) == "" {
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+			return
+		}
+		if ownerID != claims.UserID {
+			httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			return
+		}
+
+		limit := 50
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 200 {
+				limit = n
+			}
+		}
+
+		items, err := svc.ListByPet(r.Context(), petID, limit)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		out := make([]accessLogEntryResponse, 0, len(items))
+		for _, e := range items {
+			out = append(out, toAccessLogEntryResponse(e, apitime.FromContext(r.Context())))
+		}
+		httpjson.WriteJSON(w, http.StatusOK, out)
+	}
+}
+
+func toAccessLogEntryResponse(e Entry, tf apitime.Format) accessLogEntryResponse {
+	return accessLogEntryResponse{
+		ID:            e.ID,
+		PetID:         e.PetID,
+		GranteeUserID: e.GranteeUserID,
+		Resource:      e.Resource,
+		At:            apitime.New(e.At, tf),
+	}
+}
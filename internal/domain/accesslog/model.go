@@ -0,0 +1,28 @@
+package accesslog
+
+import "time"
+
+// Resource identifica qué recurso de la mascota fue leído por un delegado.
+type Resource string
+
+const (
+	// ResourcePetProfile indica una lectura del perfil de la mascota.
+	ResourcePetProfile Resource = "pet_profile"
+	// ResourceEventsList indica una lectura del listado de eventos de la mascota.
+	ResourceEventsList Resource = "events_list"
+	// ResourceEventDetail indica una lectura de un evento puntual de la mascota.
+	ResourceEventDetail Resource = "event_detail"
+	// ResourceEventsExport indica una descarga del timeline de la mascota (CSV).
+	ResourceEventsExport Resource = "events_export"
+)
+
+// Entry representa una lectura exitosa de un delegado sobre los datos de una mascota.
+type Entry struct {
+	ID string
+
+	PetID         string
+	GranteeUserID string
+
+	Resource Resource
+	At       time.Time
+}
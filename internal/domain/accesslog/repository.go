@@ -0,0 +1,10 @@
+package accesslog
+
+import "context"
+
+type Repository interface {
+	Create(ctx context.Context, e Entry) error
+
+	// ListByPet devuelve las entradas más recientes primero.
+	ListByPet(ctx context.Context, petID string, limit int) ([]Entry, error)
+}
@@ -0,0 +1,86 @@
+package accesslog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"pet-clinical-history/internal/ids"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// recordTimeout acota la escritura asíncrona para no acumular goroutines si el repo se cuelga.
+const recordTimeout = 5 * time.Second
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+	ids  ids.Generator
+}
+
+// Option configura dependencias opcionales del Service.
+type Option func(*Service)
+
+// WithIDGenerator reemplaza el generador de IDs (default UUIDv4).
+func WithIDGenerator(g ids.Generator) Option {
+	return func(s *Service) { s.ids = g }
+}
+
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{
+		repo: repo,
+		now:  time.Now,
+		ids:  ids.UUIDv4(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Record registra de forma asíncrona y best-effort una lectura de un delegado.
+// Nunca bloquea ni falla el request que lo origina: los errores del repo se descartan.
+// Un Service nil es válido y no registra nada (logging deshabilitado).
+func (s *Service) Record(petID, granteeUserID string, resource Resource) {
+	if s == nil || s.repo == nil {
+		return
+	}
+	petID = strings.TrimSpace(petID)
+	granteeUserID = strings.TrimSpace(granteeUserID)
+	if petID == "" || granteeUserID == "" || resource == "" {
+		return
+	}
+
+	e := Entry{
+		ID:            s.ids.NewID(),
+		PetID:         petID,
+		GranteeUserID: granteeUserID,
+		Resource:      resource,
+		At:            s.now(),
+	}
+
+	go func() {
+		// Contexto propio: el del request se cancela al responder.
+		ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+		defer cancel()
+		_ = s.repo.Create(ctx, e)
+	}()
+}
+
+func (s *Service) ListByPet(ctx context.Context, petID string, limit int) ([]Entry, error) {
+	if s == nil || s.repo == nil {
+		return []Entry{}, nil
+	}
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return nil, ErrInvalidInput
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.repo.ListByPet(ctx, petID, limit)
+}
@@ -0,0 +1,285 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/events/details"
+	"pet-clinical-history/internal/domain/pets"
+	"pet-clinical-history/internal/platform/httpjson"
+	"pet-clinical-history/internal/ports/capabilities"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// FeatureAttachmentsAdd es la capability del plan que habilita adjuntar archivos.
+	FeatureAttachmentsAdd = "pet:attachments:add"
+
+	maxAttachmentFileName = 255
+)
+
+// ErrAttachmentsDisabled: el Service no tiene AttachmentRepository configurado.
+var ErrAttachmentsDisabled = errors.New("attachments not configured")
+
+// AttachmentInput es la referencia a un archivo ya subido a storage externo.
+type AttachmentInput struct {
+	FileName    string
+	ContentType string
+	URL         string
+	SizeBytes   int64
+}
+
+// AddAttachment adjunta un archivo a un evento active y registra un evento ATTACHMENT_ADDED
+// en la historia de la mascota. El caller ya validó permisos y que el evento pertenece a la mascota.
+func (s *Service) AddAttachment(ctx context.Context, e PetEvent, actor Actor, in AttachmentInput) (details.Attachment, error) {
+	if s.attachments == nil {
+		return details.Attachment{}, ErrAttachmentsDisabled
+	}
+	if e.Status != EventStatusActive {
+		return details.Attachment{}, ErrAlreadyVoided
+	}
+
+	name := strings.TrimSpace(in.FileName)
+	if name == "" || len(name) > maxAttachmentFileName {
+		return details.Attachment{}, ErrInvalidInput
+	}
+	u, err := url.Parse(strings.TrimSpace(in.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return details.Attachment{}, ErrInvalidInput
+	}
+	if in.SizeBytes < 0 {
+		return details.Attachment{}, ErrInvalidInput
+	}
+
+	a := details.Attachment{
+		ID:          s.ids.NewID(),
+		EventID:     e.ID,
+		PetID:       e.PetID,
+		FileName:    name,
+		ContentType: strings.ToLower(strings.TrimSpace(in.ContentType)),
+		URL:         u.String(),
+		SizeBytes:   in.SizeBytes,
+		AddedBy:     actor.ID,
+		CreatedAt:   s.now().UTC(),
+	}
+	if err := s.attachments.Create(ctx, a); err != nil {
+		return details.Attachment{}, err
+	}
+
+	// Evento del sistema (sin tope por mascota). Hereda la visibilidad del evento adjuntado
+	// para no exponer a delegados adjuntos de eventos privados.
+	if err := s.repo.Create(ctx, PetEvent{
+		ID:         s.ids.NewID(),
+		PetID:      e.PetID,
+		Type:       EventTypeAttachmentAdded,
+		OccurredAt: a.CreatedAt,
+		RecordedAt: a.CreatedAt,
+		Title:      "Adjunto: " + a.FileName,
+		Notes:      "event_id=" + e.ID,
+		Actor:      actor,
+		Source:     SourceManual,
+		Visibility: e.Visibility,
+		Status:     EventStatusActive,
+	}); err != nil {
+		return details.Attachment{}, err
+	}
+	return a, nil
+}
+
+// ListAttachments devuelve los adjuntos de un evento, en orden de creación
+// (vacío si los adjuntos no están configurados).
+func (s *Service) ListAttachments(ctx context.Context, eventID string) ([]details.Attachment, error) {
+	out := []details.Attachment{}
+	if s.attachments == nil {
+		return out, nil
+	}
+	byEvent, err := s.attachments.ListByEventIDs(ctx, []string{eventID})
+	if err != nil {
+		return nil, err
+	}
+	return append(out, byEvent[eventID]...), nil
+}
+
+// addAttachmentRequest referencia un archivo ya subido a storage externo.
+type addAttachmentRequest struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	URL         string `json:"url"` // http(s)
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+// attachmentResponse es un adjunto de un evento.
+type attachmentResponse struct {
+	ID          string       `json:"id"`
+	EventID     string       `json:"event_id"`
+	PetID       string       `json:"pet_id"`
+	FileName    string       `json:"file_name"`
+	ContentType string       `json:"content_type,omitempty"`
+	URL         string       `json:"url"`
+	SizeBytes   int64        `json:"size_bytes"`
+	AddedBy     string       `json:"added_by"`
+	CreatedAt   apitime.Time `json:"created_at" swaggertype:"string" format:"date-time"`
+}
+
+// addAttachmentHandler godoc
+// @Summary Adjuntar archivo a un evento
+// @Description Registra un adjunto (referencia a un archivo ya subido a storage externo; solo metadata y URL) en un evento activo de la mascota y agrega un evento `ATTACHMENT_ADDED` a su historia. El dueño siempre puede adjuntar; un delegado necesita un grant activo con scope `attachments:add`. Además, el plan del dueño de la mascota debe incluir la capability `pet:attachments:add` (plans-features); sin resolver configurado se permite (modo dev). Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param eventID path string true "ID del evento"
+// @Param body body addAttachmentRequest true "Datos del adjunto"
+// @Success 201 {object} attachmentResponse
+// @Failure 400 {object} httpjson.ErrorBody "invalid json / file_name o url inválidos"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 402 {object} httpjson.ErrorBody "error.code: capability_missing (el plan no incluye pet:attachments:add)"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | grant_expired | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found / event not found"
+// @Failure 409 {object} httpjson.ErrorBody "event is voided"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Failure 503 {object} httpjson.ErrorBody "capabilities unavailable"
+// @Router /pets/{petID}/events/{eventID}/attachments [post]
+func addAttachmentHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service, caps capabilities.CapabilitiesResolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+		eventID := chi.URLParam(r, "eventID")
+
+		// Permisos:
+		// - Owner: siempre permitido
+		// - Delegado: requiere grant activo con ScopeAttachmentsAdd
+		p, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeAttachmentsAdd)
+		if !ok {
+			return
+		}
+		claims := access.Claims
+		actorType := ActorTypeOwnerUser
+		if access.IsDelegate() {
+			actorType = ActorTypeDelegateUser
+		}
+
+		// Capability del plan: se evalúa sobre el dueño (es su plan el que habilita la feature
+		// en sus mascotas, también cuando adjunta un delegado). caps nil => permitido (dev).
+		if caps != nil {
+			has, err := caps.HasFeature(r.Context(), capabilities.CapabilityCheck{
+				ProjectKey: capabilities.ProjectKey,
+				UserID:     p.OwnerUserID,
+				FeatureKey: FeatureAttachmentsAdd,
+			})
+			if err != nil {
+				httpjson.WriteError(w, http.StatusServiceUnavailable, httpjson.CodeUnavailable, "capabilities unavailable")
+				return
+			}
+			if !has {
+				httpjson.WriteError(w, http.StatusPaymentRequired, httpjson.CodeCapabilityMissing, "plan does not include "+FeatureAttachmentsAdd)
+				return
+			}
+		}
+
+		ev, err := svc.GetByID(r.Context(), eventID)
+		if err != nil || strings.TrimSpace(ev.ID) == "" || ev.PetID != petID {
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "event not found")
+			return
+		}
+
+		var req addAttachmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
+			return
+		}
+
+		a, err := svc.AddAttachment(r.Context(), ev, Actor{Type: actorType, ID: claims.UserID, Email: claims.Email}, AttachmentInput{
+			FileName:    req.FileName,
+			ContentType: req.ContentType,
+			URL:         req.URL,
+			SizeBytes:   req.SizeBytes,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrInvalidInput):
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "file_name and http(s) url required")
+			case errors.Is(err, ErrAlreadyVoided):
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, "event is voided")
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusCreated, toAttachmentResponse(a, apitime.FromContext(r.Context())))
+	}
+}
+
+func toAttachmentResponse(a details.Attachment, tf apitime.Format) attachmentResponse {
+	return attachmentResponse{
+		ID:          a.ID,
+		EventID:     a.EventID,
+		PetID:       a.PetID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		URL:         a.URL,
+		SizeBytes:   a.SizeBytes,
+		AddedBy:     a.AddedBy,
+		CreatedAt:   apitime.New(a.CreatedAt, tf),
+	}
+}
+
+// listAttachmentsHandler godoc
+// @Summary Listar adjuntos de un evento
+// @Description Devuelve los adjuntos (metadata y URL) de un evento de la mascota, en orden de creación. El dueño siempre puede verlos; un delegado necesita un grant activo con scope `events:read` y no ve los de eventos privados. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param eventID path string true "ID del evento"
+// @Success 200 {array} attachmentResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | grant_expired | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found / event not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/events/{eventID}/attachments [get]
+func listAttachmentsHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+		eventID := chi.URLParam(r, "eventID")
+
+		// Permisos:
+		// - Owner: siempre permitido
+		// - Delegado: requiere grant activo con ScopeEventsRead
+		p, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsRead)
+		if !ok {
+			return
+		}
+		isDelegate := access.IsDelegate()
+
+		ev, err := svc.GetByID(r.Context(), eventID)
+		if err != nil || strings.TrimSpace(ev.ID) == "" || ev.PetID != p.ID ||
+			(isDelegate && ev.Visibility == VisibilityPrivate) {
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "event not found")
+			return
+		}
+
+		items, err := svc.ListAttachments(r.Context(), ev.ID)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		tf := apitime.FromContext(r.Context())
+		out := make([]attachmentResponse, 0, len(items))
+		for _, a := range items {
+			out = append(out, toAttachmentResponse(a, tf))
+		}
+		httpjson.WriteJSON(w, http.StatusOK, out)
+	}
+}
@@ -0,0 +1,291 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/pets"
+	"pet-clinical-history/internal/platform/httpjson"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MaxBatchSize es el máximo de eventos por llamada a CreateBatch.
+const MaxBatchSize = 100
+
+// BatchMode define qué pasa si algún item del lote falla.
+type BatchMode string
+
+const (
+	// BatchModeAtomic: todo o nada; si algún item es inválido no se crea ninguno.
+	BatchModeAtomic BatchMode = "atomic"
+	// BatchModeBestEffort: se crean los items válidos y se informan los que fallaron.
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+// ErrBatchRejected: en modo atomic algún item no pasó la validación y no se creó ninguno.
+var ErrBatchRejected = errors.New("batch rejected")
+
+// BatchStore persiste varios eventos (con sus detalles) en una sola transacción.
+// Opcional: sin él, cada evento y sus detalles se guardan repo por repo.
+type BatchStore interface {
+	CreateBatch(ctx context.Context, items []PetEvent) error
+}
+
+// WithBatchStore habilita la creación transaccional de eventos y lotes (postgres).
+func WithBatchStore(b BatchStore) Option {
+	return func(s *Service) { s.batch = b }
+}
+
+// BatchResult es el resultado de un item del lote: Event si se creó, Err si falló.
+// En un lote atomic rechazado, los items válidos quedan sin Event ni Err.
+type BatchResult struct {
+	Index int
+	Event *PetEvent
+	Err   error
+}
+
+// CreateBatch valida y crea hasta MaxBatchSize eventos de una mascota con el mismo actor.
+// Devuelve un resultado por input, en el mismo orden.
+//   - atomic: si algún item es inválido devuelve ErrBatchRejected (con los errores por item) y no
+//     crea nada; si el lote no entra en la cuota, ErrQuotaExceeded. Con BatchStore es una transacción.
+//   - best_effort: crea los válidos mientras haya cuota; el resto lleva su error en el resultado.
+func (s *Service) CreateBatch(ctx context.Context, petID string, actor Actor, inputs []CreateInput, mode BatchMode) ([]BatchResult, error) {
+	if len(inputs) == 0 || len(inputs) > MaxBatchSize {
+		return nil, ErrInvalidInput
+	}
+	if mode == "" {
+		mode = BatchModeAtomic
+	}
+	if mode != BatchModeAtomic && mode != BatchModeBestEffort {
+		return nil, ErrInvalidInput
+	}
+
+	results := make([]BatchResult, len(inputs))
+	built := make([]PetEvent, 0, len(inputs))
+	valid := make([]int, 0, len(inputs))
+	for i, in := range inputs {
+		results[i].Index = i
+		e, err := s.buildEvent(petID, actor, in)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		built = append(built, e)
+		valid = append(valid, i)
+	}
+
+	if mode == BatchModeAtomic && len(valid) != len(inputs) {
+		return results, ErrBatchRejected
+	}
+
+	remaining, limited, err := s.quotaRemaining(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == BatchModeAtomic {
+		if limited && len(built) > remaining {
+			return results, ErrQuotaExceeded
+		}
+		if err := s.persistBatch(ctx, built); err != nil {
+			return nil, err
+		}
+		for k, i := range valid {
+			results[i].Event = &built[k]
+			s.metrics.Inc(MetricEventsCreated, "type", string(built[k].Type))
+		}
+		return results, nil
+	}
+
+	for k, i := range valid {
+		if limited && remaining <= 0 {
+			results[i].Err = ErrQuotaExceeded
+			continue
+		}
+		if err := s.persist(ctx, built[k]); err != nil {
+			results[i].Err = err
+			continue
+		}
+		remaining--
+		results[i].Event = &built[k]
+		s.metrics.Inc(MetricEventsCreated, "type", string(built[k].Type))
+	}
+	return results, nil
+}
+
+func (s *Service) persistBatch(ctx context.Context, items []PetEvent) error {
+	if s.batch != nil {
+		return s.batch.CreateBatch(ctx, items)
+	}
+	// Memoria: no hay transacción; si un item falla se anulan los ya guardados del lote.
+	for k, e := range items {
+		if err := s.persist(ctx, e); err != nil {
+			for _, prev := range items[:k] {
+				err = errors.Join(err, s.discard(ctx, prev))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// batchCreateRequest es el cuerpo de una importación de eventos en lote.
+type batchCreateRequest struct {
+	Events []createEventRequest `json:"events"`
+}
+
+// batchItemResponse es el resultado de un item del lote; index es su posición en el request.
+type batchItemResponse struct {
+	Index  int            `json:"index"`
+	Status string         `json:"status" enums:"created,failed,skipped"` // skipped: válido pero no creado (lote atomic rechazado)
+	Event  *eventResponse `json:"event,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// batchCreateResponse resume el lote: cuántos se crearon / fallaron y el detalle por item.
+type batchCreateResponse struct {
+	Mode    BatchMode           `json:"mode"`
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+	Results []batchItemResponse `json:"results"`
+}
+
+// createEventsBatchHandler godoc
+// @Summary Importar eventos en lote
+// @Description Crea hasta 100 eventos de una mascota en un solo request (p.ej. el historial de una clínica). Cada item tiene el mismo formato que `POST /pets/{petID}/events`. Con `mode=atomic` (default) es todo o nada: si algún item es inválido no se crea ninguno y la respuesta (400) indica los errores por `index`; con postgres se inserta en una transacción. Con `mode=best_effort` se crean los válidos y se informan los que fallaron (200). El dueño siempre puede importar; un delegado necesita un grant activo con scope `events:create`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param mode query string false "Qué hacer si falla algún item (default: atomic)" Enums(atomic, best_effort)
+// @Param payload body batchCreateRequest true "Eventos a crear (1-100)"
+// @Success 200 {object} batchCreateResponse "best_effort: resultado por item"
+// @Success 201 {object} batchCreateResponse "atomic: todos creados"
+// @Failure 400 {object} batchCreateResponse "atomic: lote rechazado (errores por index); o invalid json / mode / cantidad de eventos (texto)"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 402 {object} httpjson.ErrorBody "error.code: quota_exceeded (el lote no entra en el máximo de eventos)"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/events/batch [post]
+func createEventsBatchHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+
+		// Mismos permisos y actor que la creación individual
+		_, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsCreate)
+		if !ok {
+			return
+		}
+		claims := access.Claims
+		actorType := ActorTypeOwnerUser
+		if access.IsDelegate() {
+			actorType = ActorTypeDelegateUser
+		}
+		if claims.IsIntegration() {
+			actorType = ActorTypeExternalSystem
+		}
+
+		mode := BatchMode(strings.TrimSpace(r.URL.Query().Get("mode")))
+		switch mode {
+		case "":
+			mode = BatchModeAtomic
+		case BatchModeAtomic, BatchModeBestEffort:
+		default:
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "mode must be atomic or best_effort")
+			return
+		}
+
+		var req batchCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
+			return
+		}
+		if len(req.Events) == 0 || len(req.Events) > MaxBatchSize {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, fmt.Sprintf("events must have between 1 and %d items", MaxBatchSize))
+			return
+		}
+
+		// Errores de parseo (fechas, etc.) cuentan como items fallidos; al service van solo
+		// los parseados, y positions mapea su índice al del request.
+		results := make([]BatchResult, len(req.Events))
+		inputs := make([]CreateInput, 0, len(req.Events))
+		positions := make([]int, 0, len(req.Events))
+		for i, item := range req.Events {
+			results[i].Index = i
+			in, err := toCreateInput(item, claims)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			inputs = append(inputs, in)
+			positions = append(positions, i)
+		}
+
+		actor := Actor{Type: actorType, ID: claims.UserID, Email: claims.Email}
+		tf := apitime.FromContext(r.Context())
+
+		if len(positions) != len(req.Events) && mode == BatchModeAtomic {
+			httpjson.WriteJSON(w, http.StatusBadRequest, toBatchCreateResponse(mode, results, tf))
+			return
+		}
+
+		if len(inputs) > 0 {
+			created, err := svc.CreateBatch(r.Context(), petID, actor, inputs, mode)
+			switch {
+			case err == nil, errors.Is(err, ErrBatchRejected):
+				for k, res := range created {
+					res.Index = positions[k]
+					results[positions[k]] = res
+				}
+				if err != nil {
+					httpjson.WriteJSON(w, http.StatusBadRequest, toBatchCreateResponse(mode, results, tf))
+					return
+				}
+			case errors.Is(err, ErrQuotaExceeded):
+				httpjson.WriteError(w, http.StatusPaymentRequired, httpjson.CodeQuotaExceeded, err.Error())
+				return
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+				return
+			}
+		}
+
+		status := http.StatusOK
+		if mode == BatchModeAtomic {
+			status = http.StatusCreated
+		}
+		httpjson.WriteJSON(w, status, toBatchCreateResponse(mode, results, tf))
+	}
+}
+
+func toBatchCreateResponse(mode BatchMode, results []BatchResult, tf apitime.Format) batchCreateResponse {
+	out := batchCreateResponse{Mode: mode, Results: make([]batchItemResponse, 0, len(results))}
+	for _, res := range results {
+		item := batchItemResponse{Index: res.Index}
+		switch {
+		case res.Event != nil:
+			ev := toEventResponse(*res.Event, tf)
+			item.Status = "created"
+			item.Event = &ev
+			out.Created++
+		case res.Err != nil:
+			item.Status = "failed"
+			item.Error = res.Err.Error()
+			out.Failed++
+		default:
+			item.Status = "skipped"
+		}
+		out.Results = append(out.Results, item)
+	}
+	return out
+}
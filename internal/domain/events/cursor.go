@@ -0,0 +1,56 @@
+package events
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor: el cursor de paginación no es uno emitido por la API.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor es la posición (keyset) del último evento de una página: los eventos se ordenan por
+// (occurred_at, id) en la dirección del listado, y la página siguiente empieza estrictamente después de él.
+type Cursor struct {
+	OccurredAt time.Time
+	ID         string
+}
+
+// Encode devuelve el cursor opaco (base64 url-safe de "occurred_at|id").
+func (c Cursor) Encode() string {
+	raw := c.OccurredAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// After indica si e va después del cursor en el orden del timeline (occurred_at, id en order;
+// "" => desc).
+func (c Cursor) After(e PetEvent, order SortOrder) bool {
+	if order == OrderAsc {
+		if !e.OccurredAt.Equal(c.OccurredAt) {
+			return e.OccurredAt.After(c.OccurredAt)
+		}
+		return e.ID > c.ID
+	}
+	if !e.OccurredAt.Equal(c.OccurredAt) {
+		return e.OccurredAt.Before(c.OccurredAt)
+	}
+	return e.ID < c.ID
+}
+
+// ParseCursor decodifica un cursor emitido por Encode.
+func ParseCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(b), "|")
+	if !ok || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{OccurredAt: t, ID: id}, nil
+}
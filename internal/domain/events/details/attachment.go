@@ -0,0 +1,19 @@
+package details
+
+import "time"
+
+// Attachment es un archivo adjunto a un evento (N por evento). El archivo vive fuera
+// del servicio (storage externo); aquí solo se guarda la referencia y su metadata.
+type Attachment struct {
+	ID      string
+	EventID string
+	PetID   string
+
+	FileName    string
+	ContentType string
+	URL         string
+	SizeBytes   int64
+
+	AddedBy   string // user id de quien lo adjuntó
+	CreatedAt time.Time
+}
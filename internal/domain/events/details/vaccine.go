@@ -0,0 +1,14 @@
+package details
+
+import "time"
+
+// Vaccine modela el detalle de una vacunación asociada a un evento VACCINE.
+type Vaccine struct {
+	ID      string
+	EventID string
+
+	Name string
+	Lot  string // lote del frasco, para trazabilidad
+
+	NextDue *time.Time // próximo refuerzo (opcional)
+}
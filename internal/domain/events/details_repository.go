@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+// PreventiveRepository persiste el detalle de tratamientos preventivos (DEWORMING / FLEA_TREATMENT),
+// 1:1 con el evento (keyed por event_id).
+type PreventiveRepository interface {
+	Create(ctx context.Context, d details.PreventiveTreatment) error
+	ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.PreventiveTreatment, error)
+
+	// ListDue devuelve los próximos vencimientos (next_due en [from, to]) de eventos activos
+	// de las mascotas indicadas, ordenados por fecha ascendente. excludePrivate omite los de
+	// eventos con VisibilityPrivate (lecturas de delegados).
+	ListDue(ctx context.Context, petIDs []string, from, to time.Time, excludePrivate bool) ([]DueItem, error)
+}
+
+// VaccineRepository persiste el detalle de vacunación de VACCINE, 1:1 con el evento (keyed por event_id).
+type VaccineRepository interface {
+	Create(ctx context.Context, d details.Vaccine) error
+	ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Vaccine, error)
+
+	// ListDue: mismo contrato que PreventiveRepository.ListDue, para próximos refuerzos.
+	ListDue(ctx context.Context, petIDs []string, from, to time.Time, excludePrivate bool) ([]DueItem, error)
+}
+
+// MeasurementRepository persiste mediciones estructuradas (p.ej. peso de WEIGHT_RECORDED),
+// 1:1 con el evento (keyed por event_id).
+type MeasurementRepository interface {
+	Create(ctx context.Context, d details.Measurement) error
+	ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Measurement, error)
+}
+
+// MedicationRepository persiste el detalle de medicación de MEDICATION_PRESCRIBED,
+// 1:1 con el evento (keyed por event_id).
+type MedicationRepository interface {
+	Create(ctx context.Context, d details.Medication) error
+	ListByEventIDs(ctx context.Context, eventIDs []string) (map[string]details.Medication, error)
+
+	// ListActive devuelve las medicaciones vigentes en at (end_date nil o posterior) de eventos
+	// activos de la mascota, ordenadas por start_date desc. excludePrivate omite las de eventos
+	// con VisibilityPrivate (lecturas de delegados).
+	ListActive(ctx context.Context, petID string, at time.Time, excludePrivate bool) ([]details.Medication, error)
+}
+
+// AttachmentRepository persiste adjuntos de eventos (N por evento), ordenados por created_at.
+type AttachmentRepository interface {
+	Create(ctx context.Context, a details.Attachment) error
+	ListByEventIDs(ctx context.Context, eventIDs []string) (map[string][]details.Attachment, error)
+}
+
+// DueKindVaccine es el Kind de los DueItem que vienen de vacunas (los preventivos usan su PreventiveKind).
+const DueKindVaccine = "vaccine"
+
+// DueItem es un vencimiento pendiente (p.ej. próxima desparasitación o refuerzo de vacuna)
+// derivado del detalle de un evento.
+type DueItem struct {
+	PetID     string
+	EventID   string
+	EventType EventType
+
+	Kind  string // details.PreventiveKind o DueKindVaccine
+	Label string // producto / nombre de la vacuna
+	DueAt time.Time
+}
@@ -0,0 +1,230 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/pets"
+	"pet-clinical-history/internal/platform/httpjson"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// exportFlushEvery es cada cuántos eventos se hace flush del stream al cliente.
+const exportFlushEvery = streamChunk
+
+// petExport es el perfil de la mascota dentro del bundle de export.
+type petExport struct {
+	ID          string        `json:"id"`
+	OwnerUserID string        `json:"owner_user_id"`
+	Name        string        `json:"name"`
+	Species     pets.Species  `json:"species"`
+	Breed       string        `json:"breed"`
+	Sex         pets.Sex      `json:"sex"`
+	BirthDate   *apitime.Time `json:"birth_date,omitempty" swaggertype:"string" format:"date-time"`
+	Microchip   string        `json:"microchip"`
+	Notes       string        `json:"notes"`
+	CreatedAt   apitime.Time  `json:"created_at" swaggertype:"string" format:"date-time"`
+	UpdatedAt   apitime.Time  `json:"updated_at" swaggertype:"string" format:"date-time"`
+}
+
+// grantExport es una entrada del historial de grants dentro del bundle de export.
+type grantExport struct {
+	ID            string               `json:"id"`
+	GranteeUserID string               `json:"grantee_user_id"`
+	Scopes        []accessgrants.Scope `json:"scopes"`
+	Status        accessgrants.Status  `json:"status"`
+	CreatedAt     apitime.Time         `json:"created_at" swaggertype:"string" format:"date-time"`
+	UpdatedAt     apitime.Time         `json:"updated_at" swaggertype:"string" format:"date-time"`
+	RevokedAt     *apitime.Time        `json:"revoked_at,omitempty" swaggertype:"string" format:"date-time"`
+}
+
+// petExportBundle documenta la forma del export (el handler lo escribe en streaming, no con este struct).
+type petExportBundle struct {
+	ExportedAt apitime.Time    `json:"exported_at" swaggertype:"string" format:"date-time"`
+	Pet        petExport       `json:"pet"`
+	Events     []eventResponse `json:"events"`
+	Grants     []grantExport   `json:"grants,omitempty"`
+}
+
+// exportPetHandler godoc
+// @Summary Exportar el historial completo de una mascota (JSON)
+// @Description Descarga en un único documento el perfil, todos los eventos (incluidos los anulados) y el historial de grants de la mascota, para portabilidad de datos. El dueño siempre puede exportar. Un delegado necesita un grant activo con scope `pet:export`; en ese caso no se incluyen el historial de grants ni los eventos privados. La respuesta se envía en streaming (chunked, sin Content-Length). Si la mascota supera el tope de eventos del export, se responde 413 salvo que el cliente confirme con `confirm_full=true`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param confirm_full query bool false "Confirma el export completo aunque supere el tope de eventos"
+// @Success 200 {object} petExportBundle
+// @Failure 400 {object} httpjson.ErrorBody "confirm_full inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 413 {object} httpjson.ErrorBody "error.code: export_too_large (reintentar con confirm_full=true)"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/export.json [get]
+func exportPetHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+
+		// Permisos:
+		// - Owner: siempre permitido (bundle completo)
+		// - Delegado: requiere grant activo con ScopePetExport (sin grants ni eventos privados)
+		p, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopePetExport)
+		if !ok {
+			return
+		}
+		isOwner := access.IsOwner
+
+		confirmFull := false
+		if raw := strings.TrimSpace(r.URL.Query().Get("confirm_full")); raw != "" {
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid confirm_full")
+				return
+			}
+			confirmFull = v
+		}
+
+		// Todo lo que puede fallar "limpio" va antes de escribir el status.
+		if !confirmFull {
+			if err := svc.CheckExportSize(r.Context(), petID); err != nil {
+				if errors.Is(err, ErrExportTooLarge) {
+					httpjson.WriteError(w, http.StatusRequestEntityTooLarge, httpjson.CodeExportTooLarge, "export exceeds the event limit; retry with confirm_full=true")
+					return
+				}
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+				return
+			}
+		}
+
+		var grants []accessgrants.Grant
+		if isOwner {
+			var err error
+			grants, err = grantsSvc.ListByPet(r.Context(), petID)
+			if err != nil {
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="pet-`+p.ID+`.json"`)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		// El tamaño no se conoce de antemano: sin Content-Length, net/http usa chunked.
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusOK)
+
+		// Flush periódico para que el cliente reciba datos mientras se recorre el historial
+		// (y el ResponseWriter no acumule el documento entero).
+		flusher, _ := w.(http.Flusher)
+		flush := func() {
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		// Desde aquí ya no se puede cambiar el status: ante un error cortamos el stream
+		// y el cliente recibe un JSON truncado (inválido), lo que es detectable.
+		enc := json.NewEncoder(w)
+		tf := apitime.FromContext(r.Context())
+		write := func(s string) error {
+			_, err := w.Write([]byte(s))
+			return err
+		}
+
+		if write(`{"exported_at":`) != nil || enc.Encode(apitime.New(svc.now().UTC(), tf)) != nil {
+			return
+		}
+		if write(`,"pet":`) != nil || enc.Encode(toPetExport(p, tf)) != nil {
+			return
+		}
+		if write(`,"events":[`) != nil {
+			return
+		}
+
+		first := true
+		written := 0
+		err := svc.StreamByPet(r.Context(), petID, ListFilter{IncludeVoided: true}, func(e PetEvent) error {
+			if !isOwner && e.Visibility == VisibilityPrivate {
+				return nil
+			}
+			if !first {
+				if err := write(","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if err := enc.Encode(toEventResponse(e, tf)); err != nil {
+				return err
+			}
+			written++
+			if written%exportFlushEvery == 0 {
+				flush()
+			}
+			return nil
+		})
+		if err != nil {
+			return
+		}
+		if write("]") != nil {
+			return
+		}
+
+		if isOwner {
+			if write(`,"grants":[`) != nil {
+				return
+			}
+			for i, g := range grants {
+				if i > 0 && write(",") != nil {
+					return
+				}
+				if enc.Encode(toGrantExport(g, tf)) != nil {
+					return
+				}
+			}
+			if write("]") != nil {
+				return
+			}
+		}
+
+		if write("}\n") == nil {
+			flush()
+		}
+	}
+}
+
+func toPetExport(p pets.Pet, tf apitime.Format) petExport {
+	return petExport{
+		ID:          p.ID,
+		OwnerUserID: p.OwnerUserID,
+		Name:        p.Name,
+		Species:     p.Species,
+		Breed:       p.Breed,
+		Sex:         p.Sex,
+		BirthDate:   apitime.NewPtr(p.BirthDate, tf),
+		Microchip:   p.Microchip,
+		Notes:       p.Notes,
+		CreatedAt:   apitime.New(p.CreatedAt, tf),
+		UpdatedAt:   apitime.New(p.UpdatedAt, tf),
+	}
+}
+
+func toGrantExport(g accessgrants.Grant, tf apitime.Format) grantExport {
+	return grantExport{
+		ID:            g.ID,
+		GranteeUserID: g.GranteeUserID,
+		Scopes:        g.Scopes,
+		Status:        g.Status,
+		CreatedAt:     apitime.New(g.CreatedAt, tf),
+		UpdatedAt:     apitime.New(g.UpdatedAt, tf),
+		RevokedAt:     apitime.NewPtr(g.RevokedAt, tf),
+	}
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/accesslog"
+	"pet-clinical-history/internal/domain/pets"
+	"pet-clinical-history/internal/platform/httpjson"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ExportFormatCSV es el único formato de GET /pets/{petID}/events/export (y el default).
+const ExportFormatCSV = "csv"
+
+// csvExportHeader son las columnas del export CSV, en orden.
+var csvExportHeader = []string{"id", "type", "occurred_at", "title", "notes", "actor", "status"}
+
+// exportEventsHandler godoc
+// @Summary Exportar los eventos de una mascota (CSV)
+// @Description Descarga el timeline de la mascota como CSV (columnas id, type, occurred_at, title, notes, actor, status), con los mismos filtros y permisos que el listado: el dueño siempre puede exportar; un delegado necesita un grant activo con scope `events:read` y no recibe los eventos `private`. Se exportan todos los eventos que cumplen el filtro (`limit`, `cursor` y `rank` no aplican). La respuesta se envía en streaming (chunked, sin Content-Length). `actor` es el email de quien registró el evento, o su ID si no se conoce. Las celdas que empiezan con `=`, `+`, `-` o `@` se prefijan con `'` para que una planilla no las evalúe como fórmulas. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce text/csv
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param format query string false "Formato del export (default: csv)" Enums(csv)
+// @Param types query string false "Lista CSV de tipos de evento a incluir (ej: MEDICAL_VISIT,BATH)"
+// @Param from query string false "Fecha/hora mínima occurred_at (RFC3339)"
+// @Param to query string false "Fecha/hora máxima occurred_at (RFC3339)"
+// @Param q query string false "Texto de búsqueda libre en título/notas"
+// @Param status query string false "Estado de los eventos (default: active; all incluye los anulados)" Enums(active, voided, all)
+// @Param actor_id query string false "Solo eventos registrados por este usuario"
+// @Param order query string false "Orden por occurred_at (default: desc, más reciente primero)" Enums(asc, desc)
+// @Success 200 {string} string "CSV con header"
+// @Failure 400 {object} httpjson.ErrorBody "format no soportado / parámetros de filtro inválidos"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/events/export [get]
+func exportEventsHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service, accessLog *accesslog.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+
+		// Permisos: los mismos que el listado (owner, o delegado con ScopeEventsRead)
+		p, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsRead)
+		if !ok {
+			return
+		}
+		claims := access.Claims
+		isDelegate := access.IsDelegate()
+
+		switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))) {
+		case "", ExportFormatCSV:
+		default:
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "format must be csv")
+			return
+		}
+
+		filter, err := parseListFilter(r)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			return
+		}
+		// El export no pagina: todo lo que cumple el filtro, en orden de occurred_at.
+		filter.Limit = 0
+		filter.Cursor = nil
+		filter.Rank = false
+		filter.ExcludePrivate = isDelegate
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="pet-`+p.ID+`-events.csv"`)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusOK)
+
+		// Desde aquí ya no se puede cambiar el status: ante un error se corta el stream
+		// (el cliente recibe un CSV truncado).
+		cw := csv.NewWriter(w)
+		flusher, _ := w.(http.Flusher)
+		flush := func() error {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		}
+
+		if cw.Write(csvExportHeader) != nil {
+			return
+		}
+
+		written := 0
+		err = svc.StreamByPet(r.Context(), petID, filter, func(e PetEvent) error {
+			if err := cw.Write(csvExportRow(e)); err != nil {
+				return err
+			}
+			written++
+			if written%exportFlushEvery == 0 {
+				return flush()
+			}
+			return nil
+		})
+		if err != nil || flush() != nil {
+			return
+		}
+
+		// Descarga exitosa de delegado: queda en el access log (async, best-effort)
+		if isDelegate {
+			accessLog.Record(petID, claims.UserID, accesslog.ResourceEventsExport)
+		}
+	}
+}
+
+// csvExportRow arma la fila de e en el orden de csvExportHeader.
+func csvExportRow(e PetEvent) []string {
+	actor := e.Actor.Email
+	if actor == "" {
+		actor = e.Actor.ID
+	}
+	return []string{
+		e.ID,
+		string(e.Type),
+		e.OccurredAt.UTC().Format(time.RFC3339),
+		csvSafe(e.Title),
+		csvSafe(e.Notes),
+		csvSafe(actor),
+		string(e.Status),
+	}
+}
+
+// csvSafe neutraliza la inyección de fórmulas: una planilla evalúa las celdas que
+// empiezan con =, +, - o @, así que se les antepone una comilla simple.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
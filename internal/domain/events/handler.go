@@ -1,28 +1,72 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/authz"
 	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/accesslog"
+	"pet-clinical-history/internal/domain/events/details"
 	"pet-clinical-history/internal/domain/pets"
-	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/httpjson"
+	"pet-clinical-history/internal/ports/auth"
+	"pet-clinical-history/internal/ports/capabilities"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func RegisterRoutes(r chi.Router, svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) {
+// RegisterRoutes monta las rutas de eventos. caps (opcional) consulta las capabilities del
+// plan (plans-features); nil => todo permitido (dev).
+func RegisterRoutes(r chi.Router, svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service, accessLog *accesslog.Service, caps capabilities.CapabilitiesResolver) {
 	r.Route("/pets/{petID}/events", func(er chi.Router) {
 		er.Post("/", createEventHandler(svc, petsSvc, grantsSvc))
-		er.Get("/", listEventsHandler(svc, petsSvc, grantsSvc))
+		er.Get("/", listEventsHandler(svc, petsSvc, grantsSvc, accessLog))
+
+		// Importación en lote (owner o delegado con events:create)
+		er.Post("/batch", createEventsBatchHandler(svc, petsSvc, grantsSvc))
+
+		// Tipos presentes en el timeline de la mascota (para filtros)
+		er.Get("/used-types", listUsedTypesHandler(svc, petsSvc, grantsSvc))
+
+		// Resumen para dashboards: cantidad por tipo y último evento (owner o delegado con events:read)
+		er.Get("/summary", eventsSummaryHandler(svc, petsSvc, grantsSvc))
+
+		// Descarga del timeline filtrado en CSV (owner o delegado con events:read)
+		er.Get("/export", exportEventsHandler(svc, petsSvc, grantsSvc, accessLog))
+
+		// Detalle de un evento (owner o delegado con events:read)
+		er.Get("/{eventID}", getEventHandler(svc, petsSvc, grantsSvc, accessLog))
 
 		// Anular (void) evento (owner o delegado con events:void)
 		er.Post("/{eventID}/void", voidEventHandler(svc, petsSvc, grantsSvc))
+
+		// Restaurar un evento anulado por error (owner o delegado con events:void)
+		er.Post("/{eventID}/restore", restoreEventHandler(svc, petsSvc, grantsSvc))
+
+		// Adjuntos: alta (owner o delegado con attachments:add, y capability del plan) y listado (events:read)
+		er.Post("/{eventID}/attachments", addAttachmentHandler(svc, petsSvc, grantsSvc, caps))
+		er.Get("/{eventID}/attachments", listAttachmentsHandler(svc, petsSvc, grantsSvc))
 	})
+
+	// Export completo de la mascota (owner o delegado con pet:export)
+	r.Get("/pets/{petID}/export.json", exportPetHandler(svc, petsSvc, grantsSvc))
+
+	// Medicaciones vigentes (owner o delegado con events:read)
+	r.Get("/pets/{petID}/medications/active", listActiveMedicationsHandler(svc, petsSvc, grantsSvc))
+
+	// Recordatorios de una mascota (owner o delegado con events:read)
+	r.Get("/pets/{petID}/reminders", listPetRemindersHandler(svc, petsSvc, grantsSvc))
+
+	// Recordatorios de todas mis mascotas (owner)
+	r.Get("/me/reminders", listMyRemindersHandler(svc, petsSvc))
 }
 
 // createEventRequest es el cuerpo de la solicitud para registrar un nuevo evento clínico.
@@ -33,104 +77,304 @@ type createEventRequest struct {
 	Notes      string     `json:"notes"`
 	Source     Source     `json:"source"`     // opcional
 	Visibility Visibility `json:"visibility"` // opcional
+
+	// Solo para integraciones (token de integración); se ignoran para usuarios.
+	OriginClinicID string `json:"origin_clinic_id,omitempty"`
+	OriginSystem   string `json:"origin_system,omitempty"` // default: sistema del token
+	RecordedAt     string `json:"recorded_at,omitempty"`   // RFC3339, importaciones (integración / admin): fecha de carga original (no futura)
+
+	// Solo para DEWORMING / FLEA_TREATMENT (opcional)
+	Preventive *preventiveRequest `json:"preventive,omitempty"`
+
+	// Solo para WEIGHT_RECORDED (obligatorio en ese tipo)
+	Measurement *measurementRequest `json:"measurement,omitempty"`
+
+	// Solo para MEDICATION_PRESCRIBED (opcional)
+	Medication *medicationRequest `json:"medication,omitempty"`
+
+	// Solo para VACCINE (opcional)
+	Vaccine *vaccineRequest `json:"vaccine,omitempty"`
+}
+
+// vaccineRequest es el detalle de una vacunación; sin name se usa el título del evento.
+type vaccineRequest struct {
+	Name    string `json:"name"`
+	Lot     string `json:"lot"`
+	NextDue string `json:"next_due"` // YYYY-MM-DD o RFC3339, opcional (próximo refuerzo)
+}
+
+// medicationRequest es el detalle de una medicación recetada; name y start_date son obligatorios.
+type medicationRequest struct {
+	Name      string `json:"name"`
+	Dosage    string `json:"dosage"`     // "2"
+	DoseUnit  string `json:"dose_unit"`  // "ml", "mg", etc.
+	Frequency string `json:"frequency"`  // "cada 12h"
+	StartDate string `json:"start_date"` // YYYY-MM-DD o RFC3339
+	EndDate   string `json:"end_date"`   // YYYY-MM-DD o RFC3339, opcional (sin fin => tratamiento continuo)
+	Notes     string `json:"notes"`
+}
+
+// measurementRequest es la medición de un evento WEIGHT_RECORDED.
+type measurementRequest struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit" enums:"kg,lb"`
+}
+
+// preventiveRequest es el detalle opcional de un tratamiento preventivo.
+type preventiveRequest struct {
+	Product string `json:"product"`
+	Dose    string `json:"dose"`
+	NextDue string `json:"next_due"` // YYYY-MM-DD o RFC3339, opcional
+	Notes   string `json:"notes"`
+}
+
+// preventiveResponse es el detalle de un tratamiento preventivo dentro de un evento.
+type preventiveResponse struct {
+	Kind    details.PreventiveKind `json:"kind"`
+	Product string                 `json:"product"`
+	Dose    string                 `json:"dose"`
+	NextDue *apitime.Time          `json:"next_due,omitempty" swaggertype:"string" format:"date-time"`
+	Notes   string                 `json:"notes"`
+}
+
+// measurementResponse es la medición estructurada dentro de un evento.
+type measurementResponse struct {
+	Kind  details.MeasurementKind `json:"kind"`
+	Value float64                 `json:"value"`
+	Unit  string                  `json:"unit"`
+}
+
+// medicationResponse es el detalle de medicación dentro de un evento.
+type medicationResponse struct {
+	Name      string        `json:"name"`
+	Dosage    string        `json:"dosage"`
+	DoseUnit  string        `json:"dose_unit"`
+	Frequency string        `json:"frequency"`
+	StartDate apitime.Time  `json:"start_date" swaggertype:"string" format:"date-time"`
+	EndDate   *apitime.Time `json:"end_date,omitempty" swaggertype:"string" format:"date-time"`
+	Notes     string        `json:"notes"`
+}
+
+// vaccineResponse es el detalle de vacunación dentro de un evento.
+type vaccineResponse struct {
+	Name    string        `json:"name"`
+	Lot     string        `json:"lot"`
+	NextDue *apitime.Time `json:"next_due,omitempty" swaggertype:"string" format:"date-time"`
+}
+
+// activeMedicationResponse es una medicación vigente con el evento que la registró.
+type activeMedicationResponse struct {
+	EventID string `json:"event_id"`
+	medicationResponse
 }
 
 // eventResponse representa un evento clínico de la mascota devuelto por la API.
 type eventResponse struct {
-	ID         string      `json:"id"`
-	PetID      string      `json:"pet_id"`
-	Type       EventType   `json:"type"`
-	OccurredAt time.Time   `json:"occurred_at"`
-	RecordedAt time.Time   `json:"recorded_at"`
-	Title      string      `json:"title"`
-	Notes      string      `json:"notes"`
-	ActorType  ActorType   `json:"actor_type"`
-	ActorID    string      `json:"actor_id"`
-	Source     Source      `json:"source"`
-	Visibility Visibility  `json:"visibility"`
-	Status     EventStatus `json:"status"`
+	ID         string       `json:"id"`
+	PetID      string       `json:"pet_id"`
+	Type       EventType    `json:"type"`
+	OccurredAt apitime.Time `json:"occurred_at" swaggertype:"string" format:"date-time"`
+	RecordedAt apitime.Time `json:"recorded_at" swaggertype:"string" format:"date-time"`
+	Title      string       `json:"title"`
+	Notes      string       `json:"notes"`
+	ActorType  ActorType    `json:"actor_type"`
+	ActorID    string       `json:"actor_id"`
+	ActorEmail string       `json:"actor_email,omitempty"`
+	Source     Source       `json:"source"`
+	Visibility Visibility   `json:"visibility"`
+	Status     EventStatus  `json:"status"`
+
+	OriginClinicID string `json:"origin_clinic_id,omitempty"`
+	OriginSystem   string `json:"origin_system,omitempty"`
+
+	// Solo presentes si el evento está anulado.
+	VoidedBy      string        `json:"voided_by,omitempty"`
+	VoidedByEmail string        `json:"voided_by_email,omitempty"`
+	VoidedAt      *apitime.Time `json:"voided_at,omitempty" swaggertype:"string" format:"date-time"`
+	VoidReason    string        `json:"void_reason,omitempty"`
+
+	Preventive  *preventiveResponse  `json:"preventive,omitempty"`
+	Measurement *measurementResponse `json:"measurement,omitempty"`
+	Medication  *medicationResponse  `json:"medication,omitempty"`
+	Vaccine     *vaccineResponse     `json:"vaccine,omitempty"`
+
+	Attachments []attachmentResponse `json:"attachments,omitempty"`
+
+	// Solo con rank=true: fragmento de title + notes con los términos entre ** (estilo markdown).
+	Snippet string `json:"snippet,omitempty"`
 }
 
 // createEventHandler godoc
 // @Summary Crear evento de mascota
-// @Description Crea un nuevo evento clínico para la mascota indicada. El dueño siempre puede crear eventos. Un delegado necesita un grant activo con scope `events:create`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Crea un nuevo evento clínico para la mascota indicada. El dueño siempre puede crear eventos. Un delegado necesita un grant activo con scope `events:create`. Si el token es de integración, el evento se registra como `EXTERNAL_SYSTEM` y se guardan `origin_clinic_id` / `origin_system`. Un token de integración o de admin puede enviar `recorded_at` (no futuro) para preservar la fecha de carga original al importar; para usuarios normales esos campos se ignoran. Con `Idempotency-Key`, un reintento del mismo usuario en la misma mascota devuelve el evento original (mismo 201) sin crear otro ni consumir cuota. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags events
 // @Accept json
 // @Produce json
 // @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
 // @Param Authorization header string false "Bearer token en producción"
+// @Param X-Debug-Integration-System header string false "Solo en modo dev, simula un token de integración del sistema indicado"
+// @Param Idempotency-Key header string false "Opcional (máx. 255): un reintento con la misma key devuelve el evento ya creado (201) en lugar de duplicarlo"
 // @Param petID path string true "ID de la mascota"
-// @Param payload body createEventRequest true "Datos del evento; occurred_at en formato RFC3339"
+// @Param payload body createEventRequest true "Datos del evento; occurred_at en formato RFC3339. `preventive` solo aplica a DEWORMING / FLEA_TREATMENT; `measurement` (unit kg|lb) es obligatorio y exclusivo de WEIGHT_RECORDED; `medication` (name y start_date obligatorios) solo aplica a MEDICATION_PRESCRIBED; `vaccine` (name, lot, next_due) solo aplica a VACCINE"
 // @Success 201 {object} eventResponse
-// @Failure 400 {string} string "invalid json / occurred_at inválido / reglas de negocio"
-// @Failure 401 {string} string "unauthorized"
-// @Failure 403 {string} string "forbidden"
-// @Failure 404 {string} string "pet not found"
+// @Failure 400 {object} httpjson.ErrorBody "invalid json / occurred_at inválido / campo obligatorio faltante (p.ej. VACCINE requires field \"title\")"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 402 {object} httpjson.ErrorBody "error.code: quota_exceeded (la mascota alcanzó el máximo de eventos)"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
 // @Router /pets/{petID}/events [post]
 func createEventHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		claims, ok := middleware.GetClaims(r.Context())
-		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
-			return
-		}
-
 		petID := chi.URLParam(r, "petID")
-		p, err := petsSvc.GetByID(r.Context(), petID)
-		if err != nil {
-			http.Error(w, "pet not found", http.StatusNotFound)
-			return
-		}
-
-		actorType := ActorTypeOwnerUser
 
 		// Permisos:
 		// - Owner: siempre permitido
 		// - Delegado: requiere grant activo con ScopeEventsCreate
-		if p.OwnerUserID != claims.UserID {
-			g, err := grantsSvc.GetActiveGrant(r.Context(), petID, claims.UserID)
-			if err != nil || !accessgrants.HasScope(g, accessgrants.ScopeEventsCreate) {
-				http.Error(w, "forbidden", http.StatusForbidden)
-				return
-			}
+		_, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsCreate)
+		if !ok {
+			return
+		}
+		claims := access.Claims
+		actorType := ActorTypeOwnerUser
+		if access.IsDelegate() {
 			actorType = ActorTypeDelegateUser
 		}
 
+		// Integraciones: mismos permisos que el usuario del token, pero el evento
+		// queda registrado como EXTERNAL_SYSTEM con su origen.
+		if claims.IsIntegration() {
+			actorType = ActorTypeExternalSystem
+		}
+
 		var req createEventRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid json", http.StatusBadRequest)
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
 			return
 		}
 
-		t, err := time.Parse(time.RFC3339, req.OccurredAt)
+		in, err := toCreateInput(req, claims)
 		if err != nil {
-			http.Error(w, "occurred_at must be RFC3339", http.StatusBadRequest)
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
 			return
 		}
+		in.IdempotencyKey = r.Header.Get("Idempotency-Key")
 
 		e, err := svc.Create(r.Context(), petID, Actor{
-			Type: actorType,
-			ID:   claims.UserID,
-		}, CreateInput{
-			Type:       req.Type,
-			OccurredAt: t,
-			Title:      req.Title,
-			Notes:      req.Notes,
-			Source:     req.Source,
-			Visibility: req.Visibility,
-		})
+			Type:  actorType,
+			ID:    claims.UserID,
+			Email: claims.Email,
+		}, in)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			if errors.Is(err, ErrQuotaExceeded) {
+				httpjson.WriteError(w, http.StatusPaymentRequired, httpjson.CodeQuotaExceeded, err.Error())
+				return
+			}
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
 			return
 		}
 
-		writeJSON(w, http.StatusCreated, toEventResponse(e))
+		httpjson.WriteJSON(w, http.StatusCreated, toEventResponse(e, apitime.FromContext(r.Context())))
+	}
+}
+
+// toCreateInput convierte el cuerpo de la API en CreateInput (parseo de fechas incluido).
+// Los campos de origen solo se toman de tokens de integración; recorded_at, también de admins.
+func toCreateInput(req createEventRequest, claims auth.Claims) (CreateInput, error) {
+	t, err := time.Parse(time.RFC3339, req.OccurredAt)
+	if err != nil {
+		return CreateInput{}, errors.New("occurred_at must be RFC3339")
+	}
+
+	var preventive *PreventiveInput
+	if req.Preventive != nil {
+		preventive = &PreventiveInput{
+			Product: req.Preventive.Product,
+			Dose:    req.Preventive.Dose,
+			Notes:   req.Preventive.Notes,
+		}
+		if strings.TrimSpace(req.Preventive.NextDue) != "" {
+			due, err := parseDate(req.Preventive.NextDue)
+			if err != nil {
+				return CreateInput{}, errors.New("preventive.next_due must be YYYY-MM-DD or RFC3339")
+			}
+			preventive.NextDue = &due
+		}
+	}
+
+	in := CreateInput{
+		Type:       req.Type,
+		OccurredAt: t,
+		Title:      req.Title,
+		Notes:      req.Notes,
+		Source:     req.Source,
+		Visibility: req.Visibility,
+		Preventive: preventive,
+	}
+	if req.Measurement != nil {
+		in.Measurement = &MeasurementInput{
+			Value: req.Measurement.Value,
+			Unit:  req.Measurement.Unit,
+		}
+	}
+	if req.Vaccine != nil {
+		in.Vaccine = &VaccineInput{
+			Name: req.Vaccine.Name,
+			Lot:  req.Vaccine.Lot,
+		}
+		if strings.TrimSpace(req.Vaccine.NextDue) != "" {
+			due, err := parseDate(req.Vaccine.NextDue)
+			if err != nil {
+				return CreateInput{}, errors.New("vaccine.next_due must be YYYY-MM-DD or RFC3339")
+			}
+			in.Vaccine.NextDue = &due
+		}
+	}
+	if req.Medication != nil {
+		in.Medication = &MedicationInput{
+			Name:      req.Medication.Name,
+			Dosage:    req.Medication.Dosage,
+			DoseUnit:  req.Medication.DoseUnit,
+			Frequency: req.Medication.Frequency,
+			Notes:     req.Medication.Notes,
+		}
+		if strings.TrimSpace(req.Medication.StartDate) != "" {
+			start, err := parseDate(req.Medication.StartDate)
+			if err != nil {
+				return CreateInput{}, errors.New("medication.start_date must be YYYY-MM-DD or RFC3339")
+			}
+			in.Medication.StartDate = &start
+		}
+		if strings.TrimSpace(req.Medication.EndDate) != "" {
+			end, err := parseDate(req.Medication.EndDate)
+			if err != nil {
+				return CreateInput{}, errors.New("medication.end_date must be YYYY-MM-DD or RFC3339")
+			}
+			in.Medication.EndDate = &end
+		}
+	}
+	if claims.CanBackfill() && strings.TrimSpace(req.RecordedAt) != "" {
+		recorded, err := time.Parse(time.RFC3339, req.RecordedAt)
+		if err != nil {
+			return CreateInput{}, errors.New("recorded_at must be RFC3339")
+		}
+		in.RecordedAt = &recorded
+	}
+	if claims.IsIntegration() {
+		in.OriginClinicID = req.OriginClinicID
+		in.OriginSystem = req.OriginSystem
+		if strings.TrimSpace(in.OriginSystem) == "" {
+			in.OriginSystem = claims.IntegrationSystem
+		}
+		if in.Source == "" {
+			in.Source = SourceIntegration
+		}
 	}
+	return in, nil
 }
 
 // listEventsHandler godoc
 // @Summary Listar eventos de una mascota
-// @Description Lista los eventos clínicos de una mascota. El dueño siempre puede verlos. Un delegado necesita un grant activo con scope `events:read`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod). Permite filtrar por tipos, rango de fechas y texto.
+// @Description Lista los eventos clínicos de una mascota. El dueño siempre puede verlos. Un delegado necesita un grant activo con scope `events:read` y no ve los eventos con visibilidad `private`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod). Permite filtrar por tipos, rango de fechas, texto, estado (por defecto solo `active`) y actor. Paginado por cursor (orden occurred_at desc, id desc): si hay más eventos la respuesta trae `has_more: true` e incluye `next_cursor`, que se pasa como `cursor` para pedir la página siguiente. `count` es la cantidad de eventos de la página y `limit` el límite efectivo. `q` busca por substring; con `rank=true` se ordena por relevancia (mejor coincidencia primero), sin paginar, y cada evento trae un `snippet` con los términos resaltados entre `**`.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -142,63 +386,226 @@ func createEventHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgr
 // @Param from query string false "Fecha/hora mínima occurred_at (RFC3339)"
 // @Param to query string false "Fecha/hora máxima occurred_at (RFC3339)"
 // @Param q query string false "Texto de búsqueda libre en título/notas"
-// @Success 200 {array} eventResponse
-// @Failure 400 {string} string "Parámetros de filtro inválidos"
-// @Failure 401 {string} string "unauthorized"
-// @Failure 403 {string} string "forbidden"
-// @Failure 404 {string} string "pet not found"
-// @Failure 500 {string} string "internal error"
+// @Param rank query bool false "Si es true ordena por relevancia respecto de q y agrega snippet (requiere q; no admite cursor ni order)"
+// @Param status query string false "Estado de los eventos (default: active; all incluye los anulados)" Enums(active, voided, all)
+// @Param include_voided query bool false "Si es true incluye los eventos anulados (equivale a status=all; status manda si viene)"
+// @Param actor_id query string false "Solo eventos registrados por este usuario"
+// @Param order query string false "Orden por occurred_at (default: desc, más reciente primero)" Enums(asc, desc)
+// @Param cursor query string false "Cursor opaco de la página siguiente (next_cursor de la respuesta anterior, con el mismo order)"
+// @Success 200 {object} eventListResponse
+// @Failure 400 {object} httpjson.ErrorBody "Parámetros de filtro inválidos / cursor inválido / rank sin q o con cursor/order"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
 // @Router /pets/{petID}/events [get]
-func listEventsHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+func listEventsHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service, accessLog *accesslog.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		claims, ok := middleware.GetClaims(r.Context())
-		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
-			return
-		}
-
 		petID := chi.URLParam(r, "petID")
-		p, err := petsSvc.GetByID(r.Context(), petID)
-		if err != nil {
-			http.Error(w, "pet not found", http.StatusNotFound)
-			return
-		}
 
 		// Permisos:
 		// - Owner: siempre permitido
 		// - Delegado: requiere grant activo con ScopeEventsRead
-		if p.OwnerUserID != claims.UserID {
-			g, err := grantsSvc.GetActiveGrant(r.Context(), petID, claims.UserID)
-			if err != nil || !accessgrants.HasScope(g, accessgrants.ScopeEventsRead) {
-				http.Error(w, "forbidden", http.StatusForbidden)
-				return
-			}
+		_, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsRead)
+		if !ok {
+			return
 		}
+		claims := access.Claims
+		isDelegate := access.IsDelegate()
 
 		filter, err := parseListFilter(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
 			return
 		}
+		// Los eventos privados son solo para el owner
+		filter.ExcludePrivate = isDelegate
 
-		items, err := svc.ListByPet(r.Context(), petID, filter)
+		items, next, err := svc.ListPage(r.Context(), petID, filter)
 		if err != nil {
-			http.Error(w, "internal error", http.StatusInternalServerError)
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			return
 		}
 
+		// Lectura exitosa de delegado: queda en el access log (async, best-effort)
+		if isDelegate {
+			accessLog.Record(petID, claims.UserID, accesslog.ResourceEventsList)
+		}
+
 		out := make([]eventResponse, 0, len(items))
 		for _, e := range items {
-			out = append(out, toEventResponse(e))
+			out = append(out, toEventResponse(e, apitime.FromContext(r.Context())))
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, eventListResponse{
+			Items:      out,
+			Count:      len(out),
+			Limit:      filter.Limit,
+			HasMore:    next != "",
+			NextCursor: next,
+		})
+	}
+}
+
+// eventListResponse es una página del timeline; next_cursor se omite en la última página.
+// has_more sale de pedir limit+1 filas al repo (ver Service.ListPage), no de un COUNT.
+type eventListResponse struct {
+	Items      []eventResponse `json:"items"`
+	Count      int             `json:"count"`    // eventos en esta página
+	Limit      int             `json:"limit"`    // límite efectivo aplicado
+	HasMore    bool            `json:"has_more"` // true => hay next_cursor
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// usedTypeResponse es un tipo de evento presente en el timeline de la mascota.
+type usedTypeResponse struct {
+	Type  EventType `json:"type"`
+	Count int       `json:"count"`
+}
+
+// listUsedTypesHandler godoc
+// @Summary Tipos de evento usados por una mascota
+// @Description Devuelve solo los tipos de evento que la mascota tiene registrados, con la cantidad de cada uno, ordenados por tipo. Pensado para armar filtros del timeline. Los eventos anulados no cuentan. El dueño siempre puede verlos. Un delegado necesita un grant activo con scope `events:read` y no cuenta los eventos con visibilidad `private`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {array} usedTypeResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/events/used-types [get]
+func listUsedTypesHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+
+		// Permisos: mismos que listar eventos (owner o delegado con ScopeEventsRead)
+		_, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsRead)
+		if !ok {
+			return
+		}
+
+		counts, err := svc.UsedTypes(r.Context(), petID, access.IsDelegate())
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
 		}
 
-		writeJSON(w, http.StatusOK, out)
+		out := make([]usedTypeResponse, 0, len(counts))
+		for _, c := range counts {
+			out = append(out, usedTypeResponse{Type: c.Type, Count: c.Count})
+		}
+		httpjson.WriteJSON(w, http.StatusOK, out)
 	}
 }
 
+// eventsSummaryResponse agrega los eventos active de la mascota.
+type eventsSummaryResponse struct {
+	ByType         map[EventType]int `json:"by_type"`
+	Total          int               `json:"total"`
+	LastOccurredAt *apitime.Time     `json:"last_occurred_at" swaggertype:"string" format:"date-time"` // null si no hay eventos
+}
+
+// eventsSummaryHandler godoc
+// @Summary Resumen de eventos de una mascota
+// @Description Devuelve la cantidad de eventos activos por tipo, el total y la fecha (`occurred_at`) del más reciente, sin descargar el timeline. Los eventos anulados no cuentan. El dueño siempre puede verlo. Un delegado necesita un grant activo con scope `events:read` y no cuenta los eventos con visibilidad `private`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {object} eventsSummaryResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/events/summary [get]
+func eventsSummaryHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+
+		// Permisos: mismos que listar eventos (owner o delegado con ScopeEventsRead)
+		_, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsRead)
+		if !ok {
+			return
+		}
+		isDelegate := access.IsDelegate()
+
+		sum, err := svc.Summarize(r.Context(), petID, isDelegate)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, eventsSummaryResponse{
+			ByType:         sum.ByType,
+			Total:          sum.Total,
+			LastOccurredAt: apitime.NewPtr(sum.LastOccurredAt, apitime.FromContext(r.Context())),
+		})
+	}
+}
+
+// getEventHandler godoc
+// @Summary Obtener un evento
+// @Description Devuelve un evento de la mascota con todos sus detalles. El dueño siempre puede verlo. Un delegado necesita un grant activo con scope `events:read` y no puede ver eventos con visibilidad `private` (responde 404). Si el evento pertenece a otra mascota responde 404. La respuesta trae un `ETag` débil; si el cliente lo reenvía en `If-None-Match` y el evento no cambió (p.ej. no se anuló) responde 304 sin body. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param If-None-Match header string false "ETag de una respuesta anterior"
+// @Param petID path string true "ID de la mascota"
+// @Param eventID path string true "ID del evento"
+// @Success 200 {object} eventResponse
+// @Header 200 {string} ETag "ETag débil de la respuesta"
+// @Success 304 "not modified"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found / event not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/events/{eventID} [get]
+func getEventHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service, accessLog *accesslog.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+		eventID := chi.URLParam(r, "eventID")
+
+		// Permisos (primero, para no filtrar si existe el evento)
+		// - Owner: siempre permitido
+		// - Delegado: requiere grant activo con ScopeEventsRead
+		p, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsRead)
+		if !ok {
+			return
+		}
+		claims := access.Claims
+		isDelegate := access.IsDelegate()
+
+		// Evento existe, pertenece al pet y (para delegados) no es privado.
+		// Todos los casos responden 404 para no revelar eventos de otras mascotas.
+		ev, err := svc.GetByID(r.Context(), eventID)
+		if err != nil || strings.TrimSpace(ev.ID) == "" || ev.PetID != p.ID ||
+			(isDelegate && ev.Visibility == VisibilityPrivate) {
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "event not found")
+			return
+		}
+
+		// Lectura exitosa de delegado: queda en el access log (async, best-effort)
+		if isDelegate {
+			accessLog.Record(petID, claims.UserID, accesslog.ResourceEventDetail)
+		}
+
+		httpjson.WriteJSONWithETag(w, r, toEventResponse(ev, apitime.FromContext(r.Context())))
+	}
+}
+
+// voidEventRequest es el body opcional de POST .../void.
+type voidEventRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
 // voidEventHandler godoc
 // @Summary Anular (void) un evento
-// @Description Anula un evento existente de la mascota. El dueño siempre puede anular. Un delegado necesita un grant activo con scope `events:void`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Anula un evento existente de la mascota. El dueño siempre puede anular. Un delegado necesita un grant activo con scope `events:void`. Si el evento ya estaba anulado responde 409, salvo con `idempotent=true` (conserva quién y por qué se anuló la primera vez). Queda registrado quién lo anuló (`voided_by` y, si el token lo trae, `voided_by_email`), cuándo (`voided_at`) y el `reason` opcional (máx. 500 caracteres) como `void_reason`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags events
 // @Accept json
 // @Produce json
@@ -206,67 +613,180 @@ func listEventsHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgra
 // @Param Authorization header string false "Bearer token en producción"
 // @Param petID path string true "ID de la mascota"
 // @Param eventID path string true "ID del evento"
+// @Param idempotent query bool false "Si es true, anular un evento ya anulado responde 200 en vez de 409"
+// @Param payload body voidEventRequest false "Motivo opcional del void"
 // @Success 200 {object} eventResponse
-// @Failure 401 {string} string "unauthorized"
-// @Failure 403 {string} string "forbidden"
-// @Failure 404 {string} string "event not found"
-// @Failure 500 {string} string "internal error"
+// @Failure 400 {object} httpjson.ErrorBody "invalid json / reason demasiado largo"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "event not found"
+// @Failure 409 {object} httpjson.ErrorBody "event already voided"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
 // @Router /pets/{petID}/events/{eventID}/void [post]
 func voidEventHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		claims, ok := middleware.GetClaims(r.Context())
-		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		petID := chi.URLParam(r, "petID")
+		eventID := chi.URLParam(r, "eventID")
+
+		// Permisos (primero, para no filtrar si existe el evento)
+		// - Owner: siempre permitido
+		// - Delegado: requiere grant activo con ScopeEventsVoid
+		_, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsVoid)
+		if !ok {
 			return
 		}
+		claims := access.Claims
 
-		petID := chi.URLParam(r, "petID")
-		eventID := chi.URLParam(r, "eventID")
+		// Evento existe y pertenece al pet
+		ev, err := svc.GetByID(r.Context(), eventID)
+		if err != nil || strings.TrimSpace(ev.ID) == "" || ev.PetID != petID {
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "event not found")
+			return
+		}
+
+		// Body opcional: sin body se anula sin motivo.
+		var req voidEventRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
+			return
+		}
 
-		// Pet existe
-		p, err := petsSvc.GetByID(r.Context(), petID)
+		// Por defecto el void es condicional (409 si ya estaba anulado);
+		// ?idempotent=true mantiene el comportamiento de reintento seguro.
+		void := svc.Void
+		if v, _ := strconv.ParseBool(r.URL.Query().Get("idempotent")); v {
+			void = svc.VoidIdempotent
+		}
+
+		updated, err := void(r.Context(), eventID, Actor{ID: claims.UserID, Email: claims.Email}, req.Reason)
 		if err != nil {
-			http.Error(w, "pet not found", http.StatusNotFound)
+			if errors.Is(err, ErrAlreadyVoided) {
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, "event already voided")
+				return
+			}
+			if errors.Is(err, ErrVoidReasonTooLong) {
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+				return
+			}
+			// MVP: tratamos "not found" como 404 (evita 500 innecesarios en memoria)
+			if strings.Contains(strings.ToLower(err.Error()), "not found") {
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "event not found")
+				return
+			}
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			return
 		}
 
+		httpjson.WriteJSON(w, http.StatusOK, toEventResponse(updated, apitime.FromContext(r.Context())))
+	}
+}
+
+// restoreEventHandler godoc
+// @Summary Restaurar un evento anulado
+// @Description Deshace un void: el evento vuelve a `active`. El dueño siempre puede restaurar. Un delegado necesita un grant activo con scope `events:void`. Si el evento ya estaba activo responde 409. Cuenta para la cuota de eventos activos (402 si se alcanzó). Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param eventID path string true "ID del evento"
+// @Success 200 {object} eventResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 402 {object} httpjson.ErrorBody "quota_exceeded"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "event not found"
+// @Failure 409 {object} httpjson.ErrorBody "event not voided"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/events/{eventID}/restore [post]
+func restoreEventHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+		eventID := chi.URLParam(r, "eventID")
+
 		// Permisos (primero, para no filtrar si existe el evento)
 		// - Owner: siempre permitido
-		// - Delegado: requiere grant activo con ScopeEventsVoid
-		if p.OwnerUserID != claims.UserID {
-			g, err := grantsSvc.GetActiveGrant(r.Context(), petID, claims.UserID)
-			if err != nil || !accessgrants.HasScope(g, accessgrants.ScopeEventsVoid) {
-				http.Error(w, "forbidden", http.StatusForbidden)
-				return
-			}
+		// - Delegado: requiere grant activo con ScopeEventsVoid (restaurar deshace un void)
+		_, _, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsVoid)
+		if !ok {
+			return
 		}
 
 		// Evento existe y pertenece al pet
 		ev, err := svc.GetByID(r.Context(), eventID)
 		if err != nil || strings.TrimSpace(ev.ID) == "" || ev.PetID != petID {
-			http.Error(w, "event not found", http.StatusNotFound)
+			httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "event not found")
 			return
 		}
 
-		updated, err := svc.Void(r.Context(), eventID)
+		restored, err := svc.Restore(r.Context(), eventID)
 		if err != nil {
-			// MVP: tratamos "not found" como 404 (evita 500 innecesarios en memoria)
-			if strings.Contains(strings.ToLower(err.Error()), "not found") {
-				http.Error(w, "event not found", http.StatusNotFound)
-				return
+			switch {
+			case errors.Is(err, ErrNotVoided):
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, "event not voided")
+			case errors.Is(err, ErrQuotaExceeded):
+				httpjson.WriteError(w, http.StatusPaymentRequired, httpjson.CodeQuotaExceeded, err.Error())
+			case strings.Contains(strings.ToLower(err.Error()), "not found"):
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "event not found")
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			}
-			http.Error(w, "internal error", http.StatusInternalServerError)
 			return
 		}
 
-		writeJSON(w, http.StatusOK, toEventResponse(updated))
+		httpjson.WriteJSON(w, http.StatusOK, toEventResponse(restored, apitime.FromContext(r.Context())))
+	}
+}
+
+// listActiveMedicationsHandler godoc
+// @Summary Listar medicaciones vigentes de una mascota
+// @Description Devuelve las medicaciones de eventos `MEDICATION_PRESCRIBED` activos cuya `end_date` es nula o futura, ordenadas por `start_date` desc (vista de "medicación actual" sin recorrer el timeline). El dueño siempre puede verlas. Un delegado necesita un grant activo con scope `events:read` y no ve las de eventos con visibilidad `private`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {array} activeMedicationResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/medications/active [get]
+func listActiveMedicationsHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+
+		// Permisos:
+		// - Owner: siempre permitido
+		// - Delegado: requiere grant activo con ScopeEventsRead
+		p, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsRead)
+		if !ok {
+			return
+		}
+		isDelegate := access.IsDelegate()
+
+		items, err := svc.ListActiveMedications(r.Context(), p.ID, isDelegate)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		tf := apitime.FromContext(r.Context())
+		out := make([]activeMedicationResponse, 0, len(items))
+		for _, m := range items {
+			out = append(out, activeMedicationResponse{
+				EventID:            m.EventID,
+				medicationResponse: toMedicationResponse(m, tf),
+			})
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, out)
 	}
 }
 
 func parseListFilter(r *http.Request) (ListFilter, error) {
 	limit := 50
 	if v := r.URL.Query().Get("limit"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 200 {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= MaxListLimit {
 			limit = n
 		}
 	}
@@ -310,31 +830,186 @@ func parseListFilter(r *http.Request) (ListFilter, error) {
 		filter.Query = v
 	}
 
+	// include_voided=true: active + anulados (equivale a status=all)
+	if v := strings.TrimSpace(r.URL.Query().Get("include_voided")); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return ListFilter{}, errors.New("include_voided must be true or false")
+		}
+		filter.IncludeVoided = b
+	}
+
+	// status: por defecto solo active; "all" incluye los anulados. Si viene, manda sobre include_voided
+	switch v := strings.TrimSpace(r.URL.Query().Get("status")); v {
+	case "":
+	case string(EventStatusActive):
+		st := EventStatusActive
+		filter.Status = &st
+	case string(EventStatusVoided):
+		st := EventStatusVoided
+		filter.Status = &st
+	case "all":
+		filter.IncludeVoided = true
+	default:
+		return ListFilter{}, errors.New("status must be active, voided or all")
+	}
+
+	// actor_id
+	if v := strings.TrimSpace(r.URL.Query().Get("actor_id")); v != "" {
+		filter.ActorID = v
+	}
+
+	// order=asc|desc (default desc: más reciente primero)
+	switch v := SortOrder(strings.ToLower(strings.TrimSpace(r.URL.Query().Get("order")))); v {
+	case "", OrderDesc:
+		filter.Order = OrderDesc
+	case OrderAsc:
+		filter.Order = OrderAsc
+	default:
+		return ListFilter{}, errors.New("order must be asc or desc")
+	}
+
+	// cursor (next_cursor de la página anterior)
+	if v := strings.TrimSpace(r.URL.Query().Get("cursor")); v != "" {
+		c, err := ParseCursor(v)
+		if err != nil {
+			return ListFilter{}, err
+		}
+		filter.Cursor = &c
+	}
+
+	// rank=true: orden por relevancia de q (sin cursor ni order)
+	if v := strings.TrimSpace(r.URL.Query().Get("rank")); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return ListFilter{}, errors.New("rank must be true or false")
+		}
+		filter.Rank = b
+	}
+	if filter.Rank {
+		switch {
+		case filter.Query == "":
+			return ListFilter{}, errors.New("rank requires q")
+		case filter.Cursor != nil:
+			return ListFilter{}, errors.New("cursor is not supported with rank=true")
+		case strings.TrimSpace(r.URL.Query().Get("order")) != "":
+			return ListFilter{}, errors.New("order is not supported with rank=true")
+		}
+	}
+
 	return filter, nil
 }
 
-func toEventResponse(e PetEvent) eventResponse {
+// parseDate acepta una fecha YYYY-MM-DD (medianoche UTC) o un timestamp RFC3339.
+func parseDate(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+func toEventResponse(e PetEvent, tf apitime.Format) eventResponse {
+	var preventive *preventiveResponse
+	if e.Preventive != nil {
+		preventive = &preventiveResponse{
+			Kind:    e.Preventive.Kind,
+			Product: e.Preventive.Product,
+			Dose:    e.Preventive.Dose,
+			NextDue: apitime.NewPtr(e.Preventive.NextDue, tf),
+			Notes:   e.Preventive.Notes,
+		}
+	}
+	var attachments []attachmentResponse
+	for _, a := range e.Attachments {
+		attachments = append(attachments, toAttachmentResponse(a, tf))
+	}
+	var measurement *measurementResponse
+	if e.Measurement != nil {
+		measurement = &measurementResponse{
+			Kind:  e.Measurement.Kind,
+			Value: e.Measurement.Value,
+			Unit:  e.Measurement.Unit,
+		}
+	}
+
+	var medication *medicationResponse
+	if e.Medication != nil {
+		m := toMedicationResponse(*e.Medication, tf)
+		medication = &m
+	}
+
+	var vaccine *vaccineResponse
+	if e.Vaccine != nil {
+		vaccine = &vaccineResponse{
+			Name:    e.Vaccine.Name,
+			Lot:     e.Vaccine.Lot,
+			NextDue: apitime.NewPtr(e.Vaccine.NextDue, tf),
+		}
+	}
+
+	var snippet string
+	if e.Match != nil {
+		snippet = e.Match.Snippet
+	}
+
 	return eventResponse{
 		ID:         e.ID,
 		PetID:      e.PetID,
 		Type:       e.Type,
-		OccurredAt: e.OccurredAt,
-		RecordedAt: e.RecordedAt,
+		OccurredAt: apitime.New(e.OccurredAt, tf),
+		RecordedAt: apitime.New(e.RecordedAt, tf),
 		Title:      e.Title,
 		Notes:      e.Notes,
 		ActorType:  e.Actor.Type,
 		ActorID:    e.Actor.ID,
+		ActorEmail: e.Actor.Email,
 		Source:     e.Source,
 		Visibility: e.Visibility,
 		Status:     e.Status,
+
+		OriginClinicID: e.OriginClinicID,
+		OriginSystem:   e.OriginSystem,
+
+		VoidedBy:      e.VoidedBy,
+		VoidedByEmail: e.VoidedByEmail,
+		VoidedAt:      apitime.NewPtr(e.VoidedAt, tf),
+		VoidReason:    e.VoidReason,
+
+		Preventive:  preventive,
+		Measurement: measurement,
+		Medication:  medication,
+		Vaccine:     vaccine,
+
+		Attachments: attachments,
+
+		Snippet: snippet,
 	}
 }
 
-// writeJSON está duplicado intencionalmente en handlers de distintos módulos
-// para evitar crear paquetes/helpers compartidos demasiado pronto.
-// Si más adelante se repite en más módulos, recién conviene extraerlo a un helper común.
-func writeJSON(w http.ResponseWriter, status int, v any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(v)
+func toMedicationResponse(m details.Medication, tf apitime.Format) medicationResponse {
+	return medicationResponse{
+		Name:      m.Name,
+		Dosage:    m.Dosage,
+		DoseUnit:  m.DoseUnit,
+		Frequency: m.Frequency,
+		StartDate: apitime.New(m.StartDate, tf),
+		EndDate:   apitime.NewPtr(m.EndDate, tf),
+		Notes:     m.Notes,
+	}
+}
+
+// authorizePet decide el acceso a la mascota petID con authz.Authorize (owner, o delegado con
+// scope). Si se niega ya respondió (401 / 404 / 403) y ok es false.
+func authorizePet(w http.ResponseWriter, r *http.Request, petsSvc *pets.Service, grantsSvc *accessgrants.Service, petID string, scope accessgrants.Scope) (pets.Pet, authz.Decision, bool) {
+	var p pets.Pet
+	access, err := authz.Authorize(r, grantsSvc, func(ctx context.Context) (authz.PetRef, bool) {
+		var err error
+		p, err = petsSvc.GetByID(ctx, petID)
+		return authz.PetRef{ID: p.ID, OwnerUserID: p.OwnerUserID}, err == nil
+	}, scope)
+	if authz.WriteDenied(w, access, err) {
+		return pets.Pet{}, access, false
+	}
+	return p, access, true
 }
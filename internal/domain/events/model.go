@@ -1,11 +1,17 @@
 package events
 
-import "time"
+import (
+	"time"
+
+	"pet-clinical-history/internal/domain/events/details"
+)
 
 // Actor representa quién originó un evento (owner, delegado u otro sistema).
 type Actor struct {
 	Type ActorType
 	ID   string
+	// Email del actor al momento de la acción (claims del token); vacío si no vino.
+	Email string
 }
 
 // PetEvent representa un evento clínico o de historial asociado a una mascota.
@@ -25,4 +31,27 @@ type PetEvent struct {
 	Source     Source
 	Visibility Visibility
 	Status     EventStatus
+
+	// Origen cuando el evento lo registra una integración (clínica / sistema externo).
+	// Vacío para eventos cargados por usuarios.
+	OriginClinicID string
+	OriginSystem   string
+
+	// Auditoría del void: quién lo anuló, cuándo y por qué. Vacíos mientras el evento está active.
+	VoidedBy      string
+	VoidedByEmail string
+	VoidedAt      *time.Time
+	VoidReason    string
+
+	// Detalle estructurado opcional según el tipo (nil si no aplica / no se envió).
+	Preventive  *details.PreventiveTreatment
+	Measurement *details.Measurement
+	Medication  *details.Medication
+	Vaccine     *details.Vaccine
+
+	// Adjuntos del evento (vacío si no tiene).
+	Attachments []details.Attachment
+
+	// Match es la relevancia del evento en una búsqueda con ListFilter.Rank (nil fuera de ella).
+	Match *SearchMatch
 }
@@ -0,0 +1,183 @@
+package events
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/pets"
+	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/httpjson"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultRemindersWithin = 30 * 24 * time.Hour
+	maxRemindersWithin     = 365 * 24 * time.Hour
+)
+
+// reminderResponse es un vencimiento pendiente de una mascota.
+type reminderResponse struct {
+	PetID     string       `json:"pet_id"`
+	PetName   string       `json:"pet_name"`
+	EventID   string       `json:"event_id"`
+	EventType EventType    `json:"event_type"`
+	Kind      string       `json:"kind"`
+	Label     string       `json:"label"`
+	DueAt     apitime.Time `json:"due_at" swaggertype:"string" format:"date-time"`
+}
+
+// listMyRemindersHandler godoc
+// @Summary Recordatorios de todas mis mascotas
+// @Description Devuelve los próximos vencimientos (p.ej. próxima desparasitación, antipulgas o refuerzo de vacuna) de todas las mascotas del usuario autenticado, dentro de la ventana `within`, ordenados por fecha ascendente. Solo considera mascotas propias y eventos activos. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param within query string false "Ventana hacia adelante: días (`30d`) o duración Go (`72h`). Por defecto 30d, máximo 365d"
+// @Success 200 {array} reminderResponse
+// @Failure 400 {object} httpjson.ErrorBody "within inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /me/reminders [get]
+func listMyRemindersHandler(svc *Service, petsSvc *pets.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		within, err := parseWithin(r.URL.Query().Get("within"))
+		if err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			return
+		}
+
+		// Solo mascotas propias: el owner es quien recibe los recordatorios.
+		owned, err := petsSvc.ListByOwner(r.Context(), claims.UserID, pets.ListFilter{})
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		names := make(map[string]string, len(owned))
+		petIDs := make([]string, 0, len(owned))
+		for _, p := range owned {
+			names[p.ID] = p.Name
+			petIDs = append(petIDs, p.ID)
+		}
+
+		items, err := svc.UpcomingDue(r.Context(), petIDs, within, false)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		tf := apitime.FromContext(r.Context())
+		out := make([]reminderResponse, 0, len(items))
+		for _, it := range items {
+			out = append(out, reminderResponse{
+				PetID:     it.PetID,
+				PetName:   names[it.PetID],
+				EventID:   it.EventID,
+				EventType: it.EventType,
+				Kind:      it.Kind,
+				Label:     it.Label,
+				DueAt:     apitime.New(it.DueAt, tf),
+			})
+		}
+		httpjson.WriteJSON(w, http.StatusOK, out)
+	}
+}
+
+// listPetRemindersHandler godoc
+// @Summary Recordatorios de una mascota
+// @Description Devuelve los vencimientos pendientes de la mascota (refuerzos de vacunas y tratamientos preventivos con `next_due`) dentro de la ventana `within`, ordenados por fecha ascendente. Solo considera eventos activos. El dueño siempre puede verlos. Un delegado necesita un grant activo con scope `events:read` y no ve los de eventos con visibilidad `private`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags events
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param within query string false "Ventana hacia adelante: días (`30d`) o duración Go (`72h`). Por defecto 30d, máximo 365d"
+// @Success 200 {array} reminderResponse
+// @Failure 400 {object} httpjson.ErrorBody "within inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/reminders [get]
+func listPetRemindersHandler(svc *Service, petsSvc *pets.Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		petID := chi.URLParam(r, "petID")
+
+		// Permisos:
+		// - Owner: siempre permitido
+		// - Delegado: requiere grant activo con ScopeEventsRead
+		p, access, ok := authorizePet(w, r, petsSvc, grantsSvc, petID, accessgrants.ScopeEventsRead)
+		if !ok {
+			return
+		}
+		isDelegate := access.IsDelegate()
+
+		within, err := parseWithin(r.URL.Query().Get("within"))
+		if err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			return
+		}
+
+		items, err := svc.UpcomingDue(r.Context(), []string{p.ID}, within, isDelegate)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		tf := apitime.FromContext(r.Context())
+		out := make([]reminderResponse, 0, len(items))
+		for _, it := range items {
+			out = append(out, reminderResponse{
+				PetID:     it.PetID,
+				PetName:   p.Name,
+				EventID:   it.EventID,
+				EventType: it.EventType,
+				Kind:      it.Kind,
+				Label:     it.Label,
+				DueAt:     apitime.New(it.DueAt, tf),
+			})
+		}
+		httpjson.WriteJSON(w, http.StatusOK, out)
+	}
+}
+
+// parseWithin acepta "30d" (días) o una duración Go ("72h"). Vacío => 30 días.
+func parseWithin(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return defaultRemindersWithin, nil
+	}
+
+	var d time.Duration
+	if strings.HasSuffix(raw, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(raw, "d"))
+		if err != nil {
+			return 0, errors.New("within must be like 30d or 72h")
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, errors.New("within must be like 30d or 72h")
+		}
+		d = parsed
+	}
+
+	if d <= 0 || d > maxRemindersWithin {
+		return 0, errors.New("within must be positive and at most 365d")
+	}
+	return d, nil
+}
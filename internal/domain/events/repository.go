@@ -9,13 +9,98 @@ type Repository interface {
 	Create(ctx context.Context, e PetEvent) error
 	GetByID(ctx context.Context, id string) (PetEvent, error)
 	ListByPet(ctx context.Context, petID string, filter ListFilter) ([]PetEvent, error)
-	Void(ctx context.Context, id string) error
+
+	// Void anula el evento sin importar su estado. Si ya estaba voided conserva la auditoría original.
+	Void(ctx context.Context, id string, audit VoidAudit) error
+
+	// VoidIfActive anula solo si el evento está active. Si existe pero no está active
+	// devuelve ErrAlreadyVoided; si no existe, el not found del adapter.
+	VoidIfActive(ctx context.Context, id string, audit VoidAudit) error
+
+	// Restore vuelve a active un evento solo si está voided (y limpia la auditoría del void).
+	// Si existe pero no está voided devuelve ErrNotVoided; si no existe, el not found del adapter.
+	Restore(ctx context.Context, id string) error
+
+	// StreamByPet recorre los eventos del pet (mismo orden y filtros que ListByPet: occurred_at desc, id desc)
+	// llamando fn por cada fila, sin acumular el resultado en memoria.
+	// filter.Limit <= 0 significa sin límite. Si fn devuelve error, se corta y se propaga.
+	StreamByPet(ctx context.Context, petID string, filter ListFilter, fn func(PetEvent) error) error
+
+	// CountTypesByPet devuelve los tipos presentes en los eventos active del pet con su cantidad,
+	// ordenados por tipo. Del filter solo se usan ExcludePrivate (lecturas de delegados) e
+	// IncludeVoided (cuenta también los anulados). Un pet sin eventos devuelve slice vacío.
+	CountTypesByPet(ctx context.Context, petID string, filter ListFilter) ([]TypeCount, error)
+
+	// LastOccurredByPets devuelve, por pet, el occurred_at más reciente de sus eventos active.
+	// Pets sin eventos no aparecen en el map.
+	LastOccurredByPets(ctx context.Context, petIDs []string) (map[string]time.Time, error)
+
+	// CountActiveByPet devuelve la cantidad de eventos active del pet (los voided no cuentan).
+	CountActiveByPet(ctx context.Context, petID string) (int, error)
+
+	// SummarizeByPet devuelve, por tipo, la cantidad de eventos active del pet y su occurred_at
+	// más reciente, ordenados por tipo. excludePrivate omite los eventos con VisibilityPrivate
+	// (lecturas de delegados). Un pet sin eventos devuelve slice vacío.
+	SummarizeByPet(ctx context.Context, petID string, excludePrivate bool) ([]TypeSummary, error)
+}
+
+// VoidAudit es lo que se registra al anular un evento.
+type VoidAudit struct {
+	By      string
+	ByEmail string
+	At      time.Time
+	Reason  string
+}
+
+// TypeSummary agrega los eventos active de un tipo para un pet.
+type TypeSummary struct {
+	Type           EventType
+	Count          int
+	LastOccurredAt time.Time
 }
 
+// TypeCount es la cantidad de eventos de un tipo para un pet.
+type TypeCount struct {
+	Type  EventType
+	Count int
+}
+
+// SortOrder es la dirección del listado de eventos.
+type SortOrder string
+
+const (
+	OrderDesc SortOrder = "desc"
+	OrderAsc  SortOrder = "asc"
+)
+
+// MaxListLimit es el máximo de eventos por página del listado.
+const MaxListLimit = 200
+
 type ListFilter struct {
 	Types []EventType
 	From  *time.Time
 	To    *time.Time
 	Query string
 	Limit int
+
+	// Rank (con Query) busca por términos en lugar de substring: deben aparecer todos, el
+	// orden pasa a ser por relevancia (desc, occurred_at e id desempatan) y cada evento trae
+	// Match. No admite Cursor ni Order.
+	Rank bool
+
+	// Order es el orden por occurred_at (id para desempatar); "" => OrderDesc (más reciente primero).
+	Order SortOrder
+
+	// Cursor (opcional) devuelve solo los eventos posteriores a esa posición (keyset) en Order.
+	Cursor *Cursor
+
+	// ExcludePrivate omite los eventos con VisibilityPrivate (lecturas de delegados).
+	ExcludePrivate bool
+
+	// Status (opcional) filtra por estado; nil => solo active, salvo IncludeVoided.
+	Status *EventStatus
+	// IncludeVoided (con Status nil) incluye los eventos anulados junto a los active.
+	IncludeVoided bool
+	// ActorID (opcional) filtra por quién registró el evento.
+	ActorID string
 }
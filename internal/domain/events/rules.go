@@ -0,0 +1,85 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingFieldError indica que falta un campo obligatorio para el tipo de evento.
+// errors.Is(err, ErrInvalidInput) sigue siendo true.
+type MissingFieldError struct {
+	Type  EventType
+	Field string
+}
+
+func (e *MissingFieldError) Error() string {
+	return fmt.Sprintf("%s requires field %q", e.Type, e.Field)
+}
+
+func (e *MissingFieldError) Unwrap() error { return ErrInvalidInput }
+
+// fieldCheckers define qué campos de CreateInput pueden exigirse y cómo saber si vinieron.
+// Las reglas por tipo referencian estos nombres (los mismos del JSON de entrada).
+var fieldCheckers = map[string]func(CreateInput) bool{
+	"title": func(in CreateInput) bool { return strings.TrimSpace(in.Title) != "" },
+	"notes": func(in CreateInput) bool { return strings.TrimSpace(in.Notes) != "" },
+	"preventive": func(in CreateInput) bool {
+		return in.Preventive != nil
+	},
+	"preventive.product": func(in CreateInput) bool {
+		return in.Preventive != nil && strings.TrimSpace(in.Preventive.Product) != ""
+	},
+	"preventive.next_due": func(in CreateInput) bool {
+		return in.Preventive != nil && in.Preventive.NextDue != nil
+	},
+	"measurement": func(in CreateInput) bool {
+		return in.Measurement != nil
+	},
+	"medication": func(in CreateInput) bool {
+		return in.Medication != nil
+	},
+}
+
+// DefaultRequiredFields son los campos obligatorios por tipo. Tipos ausentes (p.ej. NOTE, BATH)
+// no exigen nada extra. MEDICATION_PRESCRIBED exige el título, que es el nombre del medicamento:
+// el detalle `medication` solo se acepta con WithMedicationRepo, así que exigirlo por defecto
+// dejaría sin poder registrar medicaciones a un Service sin ese repo. Cuando el detalle viene,
+// su `name` es obligatorio (buildEvent); para exigirlo siempre, usar WithRequiredFields.
+var DefaultRequiredFields = map[EventType][]string{
+	EventTypeMedicalVisit:    {"title"},
+	EventTypeVaccine:         {"title"},
+	EventTypeWeightRecorded:  {"measurement"},
+	EventTypeMedicationPresc: {"title"},
+	EventTypeDeworming:       {"preventive.product"},
+	EventTypeFleaTreatment:   {"preventive.product"},
+}
+
+// WithRequiredFields reemplaza las reglas de campos obligatorios por tipo.
+// Un nombre de campo desconocido (fuera de fieldCheckers) es una regla mal configurada y hace
+// entrar en pánico a NewService, en vez de fallar recién al crear un evento de ese tipo.
+func WithRequiredFields(rules map[EventType][]string) Option {
+	return func(s *Service) { s.requiredFields = rules }
+}
+
+// checkRequiredFields valida in contra las reglas del tipo. Devuelve *MissingFieldError
+// con el primer campo faltante, en el orden de la regla.
+func checkRequiredFields(rules map[EventType][]string, in CreateInput) error {
+	for _, field := range rules[in.Type] {
+		if !fieldCheckers[field](in) {
+			return &MissingFieldError{Type: in.Type, Field: field}
+		}
+	}
+	return nil
+}
+
+// validateRequiredFields verifica que las reglas solo usen campos de fieldCheckers.
+func validateRequiredFields(rules map[EventType][]string) error {
+	for typ, fields := range rules {
+		for _, field := range fields {
+			if _, ok := fieldCheckers[field]; !ok {
+				return fmt.Errorf("unknown required field %q for %s", field, typ)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createOnlyRepo implementa solo Create; el resto no se usa en estos tests.
+type createOnlyRepo struct {
+	Repository
+	created []PetEvent
+}
+
+func (r *createOnlyRepo) Create(ctx context.Context, e PetEvent) error {
+	r.created = append(r.created, e)
+	return nil
+}
+
+func TestService_Create_RequiredFieldsPerType(t *testing.T) {
+	svc := NewService(&createOnlyRepo{})
+	actor := Actor{Type: ActorTypeOwnerUser, ID: "owner-1"}
+	at := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		typ   EventType
+		field string
+	}{
+		{EventTypeMedicalVisit, "title"},
+		{EventTypeVaccine, "title"},
+		{EventTypeWeightRecorded, "measurement"},
+		{EventTypeMedicationPresc, "title"},
+		{EventTypeDeworming, "preventive.product"},
+		{EventTypeFleaTreatment, "preventive.product"},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.Create(context.Background(), "pet-1", actor, CreateInput{Type: tc.typ, OccurredAt: at})
+
+		var missing *MissingFieldError
+		if !errors.As(err, &missing) {
+			t.Fatalf("%s: expected MissingFieldError, got %v", tc.typ, err)
+		}
+		if missing.Field != tc.field {
+			t.Fatalf("%s: expected missing %q, got %q", tc.typ, tc.field, missing.Field)
+		}
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("%s: expected error to wrap ErrInvalidInput", tc.typ)
+		}
+	}
+
+	// NOTE / BATH no exigen nada extra
+	for _, typ := range []EventType{EventTypeNote, EventTypeBath} {
+		if _, err := svc.Create(context.Background(), "pet-1", actor, CreateInput{Type: typ, OccurredAt: at}); err != nil {
+			t.Fatalf("%s: expected no required fields, got %v", typ, err)
+		}
+	}
+}
+
+func TestService_Create_CustomRequiredFields(t *testing.T) {
+	svc := NewService(&createOnlyRepo{}, WithRequiredFields(map[EventType][]string{
+		EventTypeNote: {"notes"},
+	}))
+	actor := Actor{Type: ActorTypeOwnerUser, ID: "owner-1"}
+	at := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+
+	_, err := svc.Create(context.Background(), "pet-1", actor, CreateInput{Type: EventTypeNote, OccurredAt: at, Title: "x"})
+	var missing *MissingFieldError
+	if !errors.As(err, &missing) || missing.Field != "notes" {
+		t.Fatalf("expected NOTE to require notes with custom rules, got %v", err)
+	}
+
+	// Las reglas custom reemplazan las default: VACCINE ya no exige título.
+	if _, err := svc.Create(context.Background(), "pet-1", actor, CreateInput{Type: EventTypeVaccine, OccurredAt: at}); err != nil {
+		t.Fatalf("expected VACCINE without rules to pass, got %v", err)
+	}
+}
+
+func TestNewService_UnknownRequiredFieldPanics(t *testing.T) {
+	defer func() {
+		r := recover()
+		msg, _ := r.(string)
+		if !strings.Contains(msg, `"medication.nme"`) {
+			t.Fatalf("expected a panic naming the unknown field, got %v", r)
+		}
+	}()
+	NewService(&createOnlyRepo{}, WithRequiredFields(map[EventType][]string{
+		EventTypeMedicationPresc: {"title", "medication.nme"},
+	}))
+}
@@ -0,0 +1,16 @@
+package events
+
+// SearchMatch es la relevancia de un evento para la búsqueda (ListFilter.Rank).
+// Rank solo sirve para comparar resultados de una misma búsqueda: postgres usa ts_rank y
+// memory frecuencia de términos, así que los valores no son comparables entre adapters.
+type SearchMatch struct {
+	Rank float64
+	// Snippet es un fragmento de title + notes con los términos encontrados entre
+	// SnippetStart y SnippetStop (estilo markdown, sin HTML).
+	Snippet string
+}
+
+const (
+	SnippetStart = "**"
+	SnippetStop  = "**"
+)
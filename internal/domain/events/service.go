@@ -3,26 +3,217 @@ package events
 import (
 	"context"
 	"errors"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
-	"github.com/google/uuid"
+	"pet-clinical-history/internal/domain/events/details"
+	"pet-clinical-history/internal/ids"
+	"pet-clinical-history/internal/platform/metrics"
 )
 
 var (
 	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrAlreadyVoided: el void condicional encontró el evento ya anulado (no estaba active).
+	ErrAlreadyVoided = errors.New("event already voided")
+
+	// ErrNotVoided: se pidió restaurar un evento que no estaba anulado.
+	ErrNotVoided = errors.New("event not voided")
+
+	// ErrVoidReasonTooLong: el motivo del void supera MaxVoidReasonLength caracteres.
+	ErrVoidReasonTooLong = errors.New("void reason too long")
+
+	// ErrQuotaExceeded: la mascota alcanzó el máximo de eventos active permitido.
+	ErrQuotaExceeded = errors.New("event quota exceeded")
+
+	// ErrExportTooLarge: el export supera el tope de eventos y el cliente no confirmó el export completo.
+	ErrExportTooLarge = errors.New("export too large")
 )
 
+// MaxVoidReasonLength es el máximo (en caracteres) del motivo opcional de un void.
+const MaxVoidReasonLength = 500
+
+// PetDeletedVoidReason es el motivo con el que se anulan los eventos al borrar la mascota.
+const PetDeletedVoidReason = "pet deleted"
+
+// IncompleteVoidReason es el motivo con el que se anula un evento cuyo detalle no se pudo guardar.
+const IncompleteVoidReason = "incomplete write"
+
+// DefaultExportMaxEvents es el tope de eventos de un export sin confirm_full.
+const DefaultExportMaxEvents = 5000
+
+// DefaultFutureTolerance es cuánto puede adelantarse occurred_at respecto de ahora
+// (tolera relojes desfasados de clientes/integraciones).
+const DefaultFutureTolerance = 24 * time.Hour
+
+// MetricEventsCreated cuenta los eventos creados por type (alta individual o en lote;
+// los replays de Idempotency-Key no cuentan).
+const MetricEventsCreated = "events_created_total"
+
+// EventCapResolver permite sobrescribir el máximo de eventos por mascota (p.ej. según el plan
+// del owner vía capabilities). Devolver 0 usa el default del Service.
+type EventCapResolver interface {
+	MaxEventsPerPet(ctx context.Context, petID string) (int, error)
+}
+
 type Service struct {
-	repo Repository
-	now  func() time.Time
+	repo         Repository
+	preventive   PreventiveRepository  // opcional: nil => no se persisten detalles preventivos
+	measurements MeasurementRepository // opcional: nil => no se persisten mediciones
+	attachments  AttachmentRepository  // opcional: nil => adjuntos deshabilitados
+	medications  MedicationRepository  // opcional: nil => no se persiste el detalle de medicación
+	vaccines     VaccineRepository     // opcional: nil => no se persiste el detalle de vacunación
+	batch        BatchStore            // opcional: nil => los lotes se guardan evento por evento
+	keys         IdempotencyStore      // opcional: nil => CreateInput.IdempotencyKey se ignora
+	metrics      *metrics.Registry     // opcional: nil => sin métricas
+	now          func() time.Time
+	ids          ids.Generator
+
+	// requiredFields: campos obligatorios por tipo (ver rules.go).
+	requiredFields map[EventType][]string
+
+	// maxEventsPerPet: tope de eventos active por mascota; <= 0 => ilimitado.
+	maxEventsPerPet int
+	capResolver     EventCapResolver // opcional: override por plan
+
+	// exportMaxEvents: tope de eventos de un export no confirmado; <= 0 => ilimitado.
+	exportMaxEvents int
+
+	// futureTolerance: máximo adelanto de occurred_at sobre s.now(); < 0 => sin límite.
+	futureTolerance time.Duration
+}
+
+// Option configura dependencias opcionales del Service.
+type Option func(*Service)
+
+// WithPreventiveRepo habilita la persistencia de detalles preventivos y los recordatorios.
+func WithPreventiveRepo(r PreventiveRepository) Option {
+	return func(s *Service) { s.preventive = r }
+}
+
+// WithMeasurementRepo habilita la persistencia de mediciones (peso) de WEIGHT_RECORDED.
+func WithMeasurementRepo(r MeasurementRepository) Option {
+	return func(s *Service) { s.measurements = r }
+}
+
+// WithMaxEventsPerPet fija el tope global de eventos active por mascota (<= 0 => ilimitado).
+func WithMaxEventsPerPet(n int) Option {
+	return func(s *Service) { s.maxEventsPerPet = n }
+}
+
+// WithEventCapResolver permite sobrescribir el tope por mascota (p.ej. por plan).
+func WithEventCapResolver(r EventCapResolver) Option {
+	return func(s *Service) { s.capResolver = r }
+}
+
+// WithMedicationRepo habilita el detalle de medicación de MEDICATION_PRESCRIBED
+// y el listado de medicaciones vigentes.
+func WithMedicationRepo(r MedicationRepository) Option {
+	return func(s *Service) { s.medications = r }
+}
+
+// WithVaccineRepo habilita el detalle de vacunación de VACCINE y sus recordatorios de refuerzo.
+func WithVaccineRepo(r VaccineRepository) Option {
+	return func(s *Service) { s.vaccines = r }
+}
+
+// WithAttachmentRepo habilita los adjuntos de eventos.
+func WithAttachmentRepo(r AttachmentRepository) Option {
+	return func(s *Service) { s.attachments = r }
+}
+
+// WithExportMaxEvents fija el tope de eventos de un export sin confirm_full (<= 0 => ilimitado).
+func WithExportMaxEvents(n int) Option {
+	return func(s *Service) { s.exportMaxEvents = n }
+}
+
+// WithFutureTolerance fija cuánto puede adelantarse occurred_at (default 24h; < 0 => sin límite).
+func WithFutureTolerance(d time.Duration) Option {
+	return func(s *Service) { s.futureTolerance = d }
+}
+
+// IdempotencyStore recuerda qué evento creó cada Idempotency-Key.
+// Lo implementa idempotency.Service; se define aquí para que el Service no dependa del módulo.
+type IdempotencyStore interface {
+	Lookup(ctx context.Context, scope, userID, key string) (resourceID string, ok bool, err error)
+	Remember(ctx context.Context, scope, userID, key, resourceID string) error
+}
+
+// WithIdempotencyStore habilita CreateInput.IdempotencyKey (reintentos sin duplicados).
+func WithIdempotencyStore(k IdempotencyStore) Option {
+	return func(s *Service) { s.keys = k }
+}
+
+// idempotencyScope acota las keys al endpoint de creación de eventos de una mascota:
+// la misma key en otra mascota es otra creación.
+func idempotencyScope(petID string) string {
+	return "events.create:" + petID
+}
+
+// WithIDGenerator reemplaza el generador de IDs (default UUIDv4).
+func WithIDGenerator(g ids.Generator) Option {
+	return func(s *Service) { s.ids = g }
 }
 
-func NewService(repo Repository) *Service {
-	return &Service{
+// WithMetrics cuenta los eventos creados (MetricEventsCreated).
+func WithMetrics(m *metrics.Registry) Option {
+	return func(s *Service) { s.metrics = m }
+}
+
+// NewService arma el Service. Una configuración inválida (p.ej. WithRequiredFields con un campo
+// desconocido) es un error de programación y entra en pánico.
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{
 		repo: repo,
 		now:  time.Now,
+		ids:  ids.UUIDv4(),
+
+		requiredFields:  DefaultRequiredFields,
+		exportMaxEvents: DefaultExportMaxEvents,
+		futureTolerance: DefaultFutureTolerance,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := validateRequiredFields(s.requiredFields); err != nil {
+		panic("events: " + err.Error())
 	}
+	return s
+}
+
+// PreventiveInput es el detalle opcional de un evento DEWORMING / FLEA_TREATMENT.
+type PreventiveInput struct {
+	Product string
+	Dose    string
+	NextDue *time.Time
+	Notes   string
+}
+
+// MedicationInput es el detalle opcional de un evento MEDICATION_PRESCRIBED.
+// Name y StartDate son obligatorios si se envía.
+type MedicationInput struct {
+	Name      string
+	Dosage    string
+	DoseUnit  string
+	Frequency string
+	StartDate *time.Time
+	EndDate   *time.Time
+	Notes     string
+}
+
+// VaccineInput es el detalle opcional de un evento VACCINE. Sin Name se usa el título del evento.
+type VaccineInput struct {
+	Name    string
+	Lot     string
+	NextDue *time.Time
+}
+
+// MeasurementInput es la medición de un evento WEIGHT_RECORDED.
+type MeasurementInput struct {
+	Value float64
+	Unit  string // "kg" o "lb"
 }
 
 type CreateInput struct {
@@ -32,9 +223,76 @@ type CreateInput struct {
 	Notes      string
 	Source     Source
 	Visibility Visibility
+
+	// Solo se respetan si el actor es EXTERNAL_SYSTEM; para usuarios se ignoran.
+	OriginClinicID string
+	OriginSystem   string
+	// RecordedAt preserva la fecha de carga original en importaciones (no puede ser futura).
+	// El handler solo la toma de integraciones y admins (auth.Claims.CanBackfill).
+	RecordedAt *time.Time
+
+	Preventive  *PreventiveInput
+	Measurement *MeasurementInput
+	Medication  *MedicationInput
+	Vaccine     *VaccineInput
+
+	// IdempotencyKey (opcional, solo Create): un reintento con la misma key del mismo actor
+	// en la misma mascota devuelve el evento ya creado en lugar de crear otro.
+	IdempotencyKey string
+}
+
+// preventiveKinds mapea los tipos de evento que aceptan detalle preventivo.
+var preventiveKinds = map[EventType]details.PreventiveKind{
+	EventTypeDeworming:     details.PreventiveKindDeworming,
+	EventTypeFleaTreatment: details.PreventiveKindFleaTreatment,
+}
+
+// measurementKinds mapea los tipos de evento que aceptan medición estructurada.
+var measurementKinds = map[EventType]details.MeasurementKind{
+	EventTypeWeightRecorded: details.MeasurementKindWeight,
+}
+
+// measurementUnits son las unidades aceptadas por tipo de medición.
+var measurementUnits = map[details.MeasurementKind][]string{
+	details.MeasurementKindWeight: {"kg", "lb"},
 }
 
 func (s *Service) Create(ctx context.Context, petID string, actor Actor, in CreateInput) (PetEvent, error) {
+	e, err := s.buildEvent(petID, actor, in)
+	if err != nil {
+		return PetEvent{}, err
+	}
+
+	// El replay va antes de la cuota: reintentar algo ya creado no consume otro lugar.
+	key := strings.TrimSpace(in.IdempotencyKey)
+	if key != "" && s.keys != nil {
+		id, ok, err := s.keys.Lookup(ctx, idempotencyScope(petID), actor.ID, key)
+		if err != nil {
+			return PetEvent{}, err
+		}
+		if ok {
+			if prev, err := s.GetByID(ctx, id); err == nil && prev.PetID == petID {
+				return prev, nil
+			}
+		}
+	}
+
+	if err := s.checkQuota(ctx, petID); err != nil {
+		return PetEvent{}, err
+	}
+	if err := s.persist(ctx, e); err != nil {
+		return PetEvent{}, err
+	}
+	s.metrics.Inc(MetricEventsCreated, "type", string(e.Type))
+	if key != "" && s.keys != nil {
+		// best-effort: el evento ya se creó; sin registro, un reintento crearía otro.
+		_ = s.keys.Remember(ctx, idempotencyScope(petID), actor.ID, key, e.ID)
+	}
+	return e, nil
+}
+
+// buildEvent valida el input y arma el evento (con sus detalles e IDs) sin persistir nada.
+func (s *Service) buildEvent(petID string, actor Actor, in CreateInput) (PetEvent, error) {
 	if strings.TrimSpace(petID) == "" {
 		return PetEvent{}, ErrInvalidInput
 	}
@@ -47,9 +305,17 @@ func (s *Service) Create(ctx context.Context, petID string, actor Actor, in Crea
 	if actor.Type == "" || strings.TrimSpace(actor.ID) == "" {
 		return PetEvent{}, ErrInvalidInput
 	}
+	if err := checkRequiredFields(s.requiredFields, in); err != nil {
+		return PetEvent{}, err
+	}
 
 	now := s.now()
 
+	// Fechas pasadas sin límite; futuras solo dentro de la tolerancia (clock skew)
+	if s.futureTolerance >= 0 && in.OccurredAt.After(now.Add(s.futureTolerance)) {
+		return PetEvent{}, ErrInvalidInput
+	}
+
 	src := in.Source
 	if src == "" {
 		src = SourceManual
@@ -60,7 +326,7 @@ func (s *Service) Create(ctx context.Context, petID string, actor Actor, in Crea
 	}
 
 	e := PetEvent{
-		ID:         uuid.NewString(),
+		ID:         s.ids.NewID(),
 		PetID:      petID,
 		Type:       in.Type,
 		OccurredAt: in.OccurredAt,
@@ -73,32 +339,699 @@ func (s *Service) Create(ctx context.Context, petID string, actor Actor, in Crea
 		Status:     EventStatusActive,
 	}
 
-	if err := s.repo.Create(ctx, e); err != nil {
-		return PetEvent{}, err
+	if actor.Type == ActorTypeExternalSystem {
+		e.OriginClinicID = strings.TrimSpace(in.OriginClinicID)
+		e.OriginSystem = strings.TrimSpace(in.OriginSystem)
 	}
+	if in.RecordedAt != nil {
+		if in.RecordedAt.After(now) {
+			return PetEvent{}, ErrInvalidInput
+		}
+		e.RecordedAt = *in.RecordedAt
+	}
+
+	// Detalle preventivo: se valida antes de persistir el evento.
+	if in.Preventive != nil {
+		kind, ok := preventiveKinds[in.Type]
+		if !ok || s.preventive == nil {
+			return PetEvent{}, ErrInvalidInput
+		}
+		e.Preventive = &details.PreventiveTreatment{
+			ID:      s.ids.NewID(),
+			EventID: e.ID,
+			Kind:    kind,
+			Product: strings.TrimSpace(in.Preventive.Product),
+			Dose:    strings.TrimSpace(in.Preventive.Dose),
+			NextDue: in.Preventive.NextDue,
+			Notes:   strings.TrimSpace(in.Preventive.Notes),
+		}
+	}
+
+	if in.Measurement != nil {
+		kind, ok := measurementKinds[in.Type]
+		if !ok || s.measurements == nil {
+			return PetEvent{}, ErrInvalidInput
+		}
+		unit := strings.ToLower(strings.TrimSpace(in.Measurement.Unit))
+		if in.Measurement.Value <= 0 || !validUnit(kind, unit) {
+			return PetEvent{}, ErrInvalidInput
+		}
+		e.Measurement = &details.Measurement{
+			ID:      s.ids.NewID(),
+			EventID: e.ID,
+			Kind:    kind,
+			Value:   in.Measurement.Value,
+			Unit:    unit,
+		}
+	}
+
+	if in.Medication != nil {
+		if in.Type != EventTypeMedicationPresc || s.medications == nil {
+			return PetEvent{}, ErrInvalidInput
+		}
+		name := strings.TrimSpace(in.Medication.Name)
+		if name == "" {
+			return PetEvent{}, &MissingFieldError{Type: in.Type, Field: "medication.name"}
+		}
+		if in.Medication.StartDate == nil || in.Medication.StartDate.IsZero() {
+			return PetEvent{}, &MissingFieldError{Type: in.Type, Field: "medication.start_date"}
+		}
+		if in.Medication.EndDate != nil && in.Medication.EndDate.Before(*in.Medication.StartDate) {
+			return PetEvent{}, ErrInvalidInput
+		}
+		e.Medication = &details.Medication{
+			ID:        s.ids.NewID(),
+			EventID:   e.ID,
+			Name:      name,
+			Dosage:    strings.TrimSpace(in.Medication.Dosage),
+			DoseUnit:  strings.TrimSpace(in.Medication.DoseUnit),
+			Frequency: strings.TrimSpace(in.Medication.Frequency),
+			StartDate: *in.Medication.StartDate,
+			EndDate:   in.Medication.EndDate,
+			Notes:     strings.TrimSpace(in.Medication.Notes),
+		}
+	}
+
+	if in.Vaccine != nil {
+		if in.Type != EventTypeVaccine || s.vaccines == nil {
+			return PetEvent{}, ErrInvalidInput
+		}
+		name := strings.TrimSpace(in.Vaccine.Name)
+		if name == "" {
+			name = e.Title
+		}
+		e.Vaccine = &details.Vaccine{
+			ID:      s.ids.NewID(),
+			EventID: e.ID,
+			Name:    name,
+			Lot:     strings.TrimSpace(in.Vaccine.Lot),
+			NextDue: in.Vaccine.NextDue,
+		}
+	}
+
 	return e, nil
 }
 
+// persist guarda el evento y sus detalles como una unidad. Con BatchStore (postgres) es una
+// transacción; en memoria, si falla un detalle se anula el evento recién creado, así no queda
+// un evento active sin su detalle y un reintento no lo duplica.
+func (s *Service) persist(ctx context.Context, e PetEvent) error {
+	if s.batch != nil {
+		return s.batch.CreateBatch(ctx, []PetEvent{e})
+	}
+	if err := s.repo.Create(ctx, e); err != nil {
+		return err
+	}
+	if err := s.persistDetails(ctx, e); err != nil {
+		return errors.Join(err, s.discard(ctx, e))
+	}
+	return nil
+}
+
+// persistDetails guarda los detalles de un evento ya creado (sin BatchStore).
+func (s *Service) persistDetails(ctx context.Context, e PetEvent) error {
+	if e.Preventive != nil {
+		if err := s.preventive.Create(ctx, *e.Preventive); err != nil {
+			return err
+		}
+	}
+	if e.Measurement != nil {
+		if err := s.measurements.Create(ctx, *e.Measurement); err != nil {
+			return err
+		}
+	}
+	if e.Medication != nil {
+		if err := s.medications.Create(ctx, *e.Medication); err != nil {
+			return err
+		}
+	}
+	if e.Vaccine != nil {
+		if err := s.vaccines.Create(ctx, *e.Vaccine); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discard anula un evento que no se pudo guardar completo (ver persist).
+func (s *Service) discard(ctx context.Context, e PetEvent) error {
+	audit := VoidAudit{By: e.Actor.ID, ByEmail: e.Actor.Email, At: s.now().UTC(), Reason: IncompleteVoidReason}
+	return s.repo.Void(ctx, e.ID, audit)
+}
+
+// ListActiveMedications devuelve las medicaciones vigentes de la mascota (end_date nil o futura)
+// de eventos activos. excludePrivate omite las de eventos privados (lecturas de delegados).
+func (s *Service) ListActiveMedications(ctx context.Context, petID string, excludePrivate bool) ([]details.Medication, error) {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return nil, ErrInvalidInput
+	}
+	if s.medications == nil {
+		return []details.Medication{}, nil
+	}
+	return s.medications.ListActive(ctx, petID, s.now(), excludePrivate)
+}
+
 func (s *Service) GetByID(ctx context.Context, id string) (PetEvent, error) {
 	id = strings.TrimSpace(id)
 	if id == "" {
 		return PetEvent{}, ErrInvalidInput
 	}
-	return s.repo.GetByID(ctx, id)
+	e, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return PetEvent{}, err
+	}
+	items := []PetEvent{e}
+	if err := s.attachDetails(ctx, items); err != nil {
+		return PetEvent{}, err
+	}
+	return items[0], nil
 }
 
 func (s *Service) ListByPet(ctx context.Context, petID string, filter ListFilter) ([]PetEvent, error) {
-	return s.repo.ListByPet(ctx, petID, filter)
+	items, err := s.repo.ListByPet(ctx, petID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachDetails(ctx, items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListPage lista una página del timeline y devuelve el cursor de la siguiente
+// ("" si no hay más). Pide una fila extra al repo para saber si la página continúa.
+// Con filter.Rank devuelve los limit más relevantes, sin cursor (el keyset es por occurred_at).
+func (s *Service) ListPage(ctx context.Context, petID string, filter ListFilter) ([]PetEvent, string, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+	if filter.Rank {
+		if strings.TrimSpace(filter.Query) == "" || filter.Cursor != nil {
+			return nil, "", ErrInvalidInput
+		}
+		filter.Limit = limit
+		items, err := s.ListByPet(ctx, petID, filter)
+		return items, "", err
+	}
+	filter.Limit = limit + 1
+
+	items, err := s.repo.ListByPet(ctx, petID, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[limit-1]
+		next = Cursor{OccurredAt: last.OccurredAt, ID: last.ID}.Encode()
+	}
+
+	if err := s.attachDetails(ctx, items); err != nil {
+		return nil, "", err
+	}
+	return items, next, nil
 }
 
-// Void marca el evento como voided (no se borra).
-func (s *Service) Void(ctx context.Context, id string) (PetEvent, error) {
+// streamChunk acota cuántos eventos se acumulan para cargar sus detalles en batch.
+const streamChunk = 100
+
+// StreamByPet recorre los eventos del pet sin bufferizarlos (exports).
+// Los detalles se cargan por bloques de streamChunk para evitar N+1 sin perder el streaming.
+func (s *Service) StreamByPet(ctx context.Context, petID string, filter ListFilter, fn func(PetEvent) error) error {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return ErrInvalidInput
+	}
+
+	buf := make([]PetEvent, 0, streamChunk)
+	flush := func() error {
+		if len(buf) == 0 {
+			return nil
+		}
+		if err := s.attachDetails(ctx, buf); err != nil {
+			return err
+		}
+		for _, e := range buf {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		buf = buf[:0]
+		return nil
+	}
+
+	err := s.repo.StreamByPet(ctx, petID, filter, func(e PetEvent) error {
+		buf = append(buf, e)
+		if len(buf) >= streamChunk {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// CheckExportSize rechaza con ErrExportTooLarge si el pet tiene más eventos (incluidos los
+// anulados) que el tope de export. El handler lo omite cuando el cliente confirma el export completo.
+func (s *Service) CheckExportSize(ctx context.Context, petID string) error {
+	if s.exportMaxEvents <= 0 {
+		return nil
+	}
+	counts, err := s.repo.CountTypesByPet(ctx, strings.TrimSpace(petID), ListFilter{IncludeVoided: true})
+	if err != nil {
+		return err
+	}
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+	if total > s.exportMaxEvents {
+		return ErrExportTooLarge
+	}
+	return nil
+}
+
+// UsedTypes devuelve los tipos de evento que realmente tiene el pet (con cantidades de
+// eventos active), para armar filtros acotados a esa mascota. excludePrivate omite los
+// eventos privados (lecturas de delegados).
+func (s *Service) UsedTypes(ctx context.Context, petID string, excludePrivate bool) ([]TypeCount, error) {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return nil, ErrInvalidInput
+	}
+	return s.repo.CountTypesByPet(ctx, petID, ListFilter{ExcludePrivate: excludePrivate})
+}
+
+// Summary resume los eventos active de una mascota: cantidad por tipo, total y fecha del
+// más reciente (nil si no tiene eventos).
+type Summary struct {
+	ByType         map[EventType]int
+	Total          int
+	LastOccurredAt *time.Time
+}
+
+// Summarize agrega los eventos active del pet sin traerlos. excludePrivate omite los
+// eventos privados (lecturas de delegados).
+func (s *Service) Summarize(ctx context.Context, petID string, excludePrivate bool) (Summary, error) {
+	petID = strings.TrimSpace(petID)
+	if petID == "" {
+		return Summary{}, ErrInvalidInput
+	}
+
+	rows, err := s.repo.SummarizeByPet(ctx, petID, excludePrivate)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	out := Summary{ByType: make(map[EventType]int, len(rows))}
+	for _, row := range rows {
+		out.ByType[row.Type] = row.Count
+		out.Total += row.Count
+		if out.LastOccurredAt == nil || row.LastOccurredAt.After(*out.LastOccurredAt) {
+			last := row.LastOccurredAt
+			out.LastOccurredAt = &last
+		}
+	}
+	return out, nil
+}
+
+// LastActivity devuelve la fecha del evento active más reciente de cada pet
+// (pets sin eventos no aparecen). Implementa pets.ActivityLookup.
+func (s *Service) LastActivity(ctx context.Context, petIDs []string) (map[string]time.Time, error) {
+	if len(petIDs) == 0 {
+		return map[string]time.Time{}, nil
+	}
+	return s.repo.LastOccurredByPets(ctx, petIDs)
+}
+
+// RecordProfileEvent registra un evento PROFILE_UPDATED del owner (p.ej. al fusionar mascotas).
+// Es un evento del sistema: no aplica reglas de campos obligatorios ni el tope por mascota.
+// Implementa pets.ProfileEventRecorder.
+func (s *Service) RecordProfileEvent(ctx context.Context, petID, ownerUserID, title, notes string) error {
+	petID = strings.TrimSpace(petID)
+	ownerUserID = strings.TrimSpace(ownerUserID)
+	if petID == "" || ownerUserID == "" {
+		return ErrInvalidInput
+	}
+
+	now := s.now()
+	return s.repo.Create(ctx, PetEvent{
+		ID:         s.ids.NewID(),
+		PetID:      petID,
+		Type:       EventTypeProfileUpdated,
+		OccurredAt: now,
+		RecordedAt: now,
+		Title:      strings.TrimSpace(title),
+		Notes:      strings.TrimSpace(notes),
+		Actor:      Actor{Type: ActorTypeOwnerUser, ID: ownerUserID},
+		Source:     SourceManual,
+		Visibility: VisibilityShared,
+		Status:     EventStatusActive,
+	})
+}
+
+// UpcomingDue devuelve los vencimientos (preventivos y refuerzos de vacunas) de las mascotas
+// indicadas entre ahora y ahora+within, ordenados por fecha ascendente. excludePrivate omite
+// los de eventos privados (lecturas de delegados).
+func (s *Service) UpcomingDue(ctx context.Context, petIDs []string, within time.Duration, excludePrivate bool) ([]DueItem, error) {
+	if within <= 0 {
+		return nil, ErrInvalidInput
+	}
+	out := []DueItem{}
+	if len(petIDs) == 0 {
+		return out, nil
+	}
+	now := s.now()
+
+	if s.preventive != nil {
+		items, err := s.preventive.ListDue(ctx, petIDs, now, now.Add(within), excludePrivate)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+	if s.vaccines != nil {
+		items, err := s.vaccines.ListDue(ctx, petIDs, now, now.Add(within), excludePrivate)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].DueAt.Before(out[j].DueAt)
+	})
+	return out, nil
+}
+
+// checkQuota rechaza con ErrQuotaExceeded si crear un evento más supera el tope de la mascota.
+// MVP: el conteo y el insert no son atómicos; ante concurrencia el tope puede excederse por poco.
+func (s *Service) checkQuota(ctx context.Context, petID string) error {
+	remaining, limited, err := s.quotaRemaining(ctx, petID)
+	if err != nil {
+		return err
+	}
+	if limited && remaining <= 0 {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// quotaRemaining devuelve cuántos eventos active más admite la mascota; limited=false => sin tope.
+func (s *Service) quotaRemaining(ctx context.Context, petID string) (remaining int, limited bool, err error) {
+	max := s.maxEventsPerPet
+	if s.capResolver != nil {
+		n, err := s.capResolver.MaxEventsPerPet(ctx, petID)
+		if err != nil {
+			return 0, false, err
+		}
+		if n > 0 {
+			max = n
+		}
+	}
+	if max <= 0 {
+		return 0, false, nil
+	}
+
+	n, err := s.repo.CountActiveByPet(ctx, petID)
+	if err != nil {
+		return 0, false, err
+	}
+	return max - n, true, nil
+}
+
+func validUnit(kind details.MeasurementKind, unit string) bool {
+	for _, u := range measurementUnits[kind] {
+		if u == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// attachDetails completa los detalles estructurados de los eventos (in-place, en batch).
+func (s *Service) attachDetails(ctx context.Context, items []PetEvent) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if err := s.attachPreventive(ctx, items); err != nil {
+		return err
+	}
+	if err := s.attachMeasurements(ctx, items); err != nil {
+		return err
+	}
+	if err := s.attachMedications(ctx, items); err != nil {
+		return err
+	}
+	if err := s.attachVaccines(ctx, items); err != nil {
+		return err
+	}
+	return s.attachAttachments(ctx, items)
+}
+
+func (s *Service) attachPreventive(ctx context.Context, items []PetEvent) error {
+	if s.preventive == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, e := range items {
+		if _, ok := preventiveKinds[e.Type]; ok {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	byEvent, err := s.preventive.ListByEventIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if d, ok := byEvent[items[i].ID]; ok {
+			d := d
+			items[i].Preventive = &d
+		}
+	}
+	return nil
+}
+
+func (s *Service) attachMeasurements(ctx context.Context, items []PetEvent) error {
+	if s.measurements == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, e := range items {
+		if _, ok := measurementKinds[e.Type]; ok {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	byEvent, err := s.measurements.ListByEventIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if d, ok := byEvent[items[i].ID]; ok {
+			d := d
+			items[i].Measurement = &d
+		}
+	}
+	return nil
+}
+
+func (s *Service) attachMedications(ctx context.Context, items []PetEvent) error {
+	if s.medications == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, e := range items {
+		if e.Type == EventTypeMedicationPresc {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	byEvent, err := s.medications.ListByEventIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if d, ok := byEvent[items[i].ID]; ok {
+			d := d
+			items[i].Medication = &d
+		}
+	}
+	return nil
+}
+
+func (s *Service) attachVaccines(ctx context.Context, items []PetEvent) error {
+	if s.vaccines == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, e := range items {
+		if e.Type == EventTypeVaccine {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	byEvent, err := s.vaccines.ListByEventIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if d, ok := byEvent[items[i].ID]; ok {
+			d := d
+			items[i].Vaccine = &d
+		}
+	}
+	return nil
+}
+
+func (s *Service) attachAttachments(ctx context.Context, items []PetEvent) error {
+	if s.attachments == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, e := range items {
+		ids = append(ids, e.ID)
+	}
+
+	byEvent, err := s.attachments.ListByEventIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Attachments = byEvent[items[i].ID]
+	}
+	return nil
+}
+
+// Void marca el evento como voided (no se borra) solo si sigue active, registrando
+// quién lo anuló (actorUserID) y el motivo opcional.
+// Si ya estaba anulado devuelve ErrAlreadyVoided, para reportar el conflicto
+// en vez de "éxito" silencioso (p.ej. dos clientes anulando a la vez).
+func (s *Service) Void(ctx context.Context, id string, actor Actor, reason string) (PetEvent, error) {
 	id = strings.TrimSpace(id)
+	audit, err := s.voidAudit(actor, reason)
+	if err != nil {
+		return PetEvent{}, err
+	}
+	if id == "" {
+		return PetEvent{}, ErrInvalidInput
+	}
+	if err := s.repo.VoidIfActive(ctx, id, audit); err != nil {
+		return PetEvent{}, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// voidAudit valida actor y motivo de un void.
+func (s *Service) voidAudit(actor Actor, reason string) (VoidAudit, error) {
+	actorUserID := strings.TrimSpace(actor.ID)
+	if actorUserID == "" {
+		return VoidAudit{}, ErrInvalidInput
+	}
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > MaxVoidReasonLength {
+		return VoidAudit{}, ErrVoidReasonTooLong
+	}
+	return VoidAudit{By: actorUserID, ByEmail: strings.TrimSpace(actor.Email), At: s.now().UTC(), Reason: reason}, nil
+}
+
+// Restore deshace un void: vuelve el evento a active. Si ya estaba active devuelve ErrNotVoided.
+// Cuenta para la cuota de eventos active, igual que un alta.
+func (s *Service) Restore(ctx context.Context, id string) (PetEvent, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return PetEvent{}, ErrInvalidInput
+	}
+	e, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return PetEvent{}, err
+	}
+	if e.Status != EventStatusVoided {
+		return PetEvent{}, ErrNotVoided
+	}
+	if err := s.checkQuota(ctx, e.PetID); err != nil {
+		return PetEvent{}, err
+	}
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return PetEvent{}, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// VoidAllForPet anula todos los eventos activos de la mascota al borrarla (motivo
+// PetDeletedVoidReason, actor actorUserID); no borra nada. Devuelve cuántos anuló.
+func (s *Service) VoidAllForPet(ctx context.Context, petID, actorUserID string) (int, error) {
+	petID = strings.TrimSpace(petID)
+	audit, err := s.voidAudit(Actor{ID: actorUserID}, PetDeletedVoidReason)
+	if err != nil {
+		return 0, err
+	}
+	if petID == "" {
+		return 0, ErrInvalidInput
+	}
+
+	// Primero se juntan los IDs: anular mientras se recorre el stream bloquearía
+	// (o alteraría) la lectura en algunos adapters.
+	var ids []string
+	err = s.repo.StreamByPet(ctx, petID, ListFilter{}, func(e PetEvent) error {
+		if e.Status == EventStatusActive {
+			ids = append(ids, e.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, id := range ids {
+		if err := s.repo.VoidIfActive(ctx, id, audit); err != nil {
+			if errors.Is(err, ErrAlreadyVoided) {
+				continue
+			}
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// VoidIdempotent marca el evento como voided sin importar su estado actual
+// (reintentos seguros: anular dos veces devuelve el mismo resultado, con la auditoría del primero).
+func (s *Service) VoidIdempotent(ctx context.Context, id string, actor Actor, reason string) (PetEvent, error) {
+	id = strings.TrimSpace(id)
+	audit, err := s.voidAudit(actor, reason)
+	if err != nil {
+		return PetEvent{}, err
+	}
 	if id == "" {
 		return PetEvent{}, ErrInvalidInput
 	}
-	if err := s.repo.Void(ctx, id); err != nil {
+	if err := s.repo.Void(ctx, id, audit); err != nil {
 		return PetEvent{}, err
 	}
-	return s.repo.GetByID(ctx, id)
+	return s.GetByID(ctx, id)
 }
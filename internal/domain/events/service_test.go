@@ -0,0 +1,168 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/events/details"
+)
+
+func TestService_Create_RejectsOccurredAtBeyondFutureTolerance(t *testing.T) {
+	now := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	repo := &createOnlyRepo{}
+	svc := NewService(repo)
+	svc.now = func() time.Time { return now }
+	svc.futureTolerance = 2 * time.Hour
+
+	actor := Actor{Type: ActorTypeOwnerUser, ID: "owner-1"}
+	create := func(at time.Time) error {
+		_, err := svc.Create(context.Background(), "pet-1", actor, CreateInput{Type: EventTypeNote, OccurredAt: at})
+		return err
+	}
+
+	// Años adelante => rechazado
+	if err := create(now.AddDate(3, 0, 0)); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for far-future occurred_at, got %v", err)
+	}
+	// Apenas pasada la tolerancia => rechazado
+	if err := create(now.Add(2*time.Hour + time.Second)); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput just beyond tolerance, got %v", err)
+	}
+	if len(repo.created) != 0 {
+		t.Fatalf("expected nothing persisted, got %d events", len(repo.created))
+	}
+
+	// Dentro de la tolerancia (clock skew) => ok
+	if err := create(now.Add(2 * time.Hour)); err != nil {
+		t.Fatalf("expected within-tolerance future date to be accepted, got %v", err)
+	}
+	// Pasado lejano => ok
+	if err := create(now.AddDate(-15, 0, 0)); err != nil {
+		t.Fatalf("expected past date to be accepted, got %v", err)
+	}
+	if len(repo.created) != 2 {
+		t.Fatalf("expected 2 events persisted, got %d", len(repo.created))
+	}
+}
+
+// recordingBatchStore registra cada lote recibido (simula la transacción de postgres).
+type recordingBatchStore struct {
+	batches [][]PetEvent
+}
+
+func (b *recordingBatchStore) CreateBatch(ctx context.Context, items []PetEvent) error {
+	b.batches = append(b.batches, items)
+	return nil
+}
+
+func TestService_CreateBatch_AtomicUsesSingleBatchStoreCall(t *testing.T) {
+	repo := &createOnlyRepo{}
+	store := &recordingBatchStore{}
+	svc := NewService(repo, WithBatchStore(store))
+
+	actor := Actor{Type: ActorTypeOwnerUser, ID: "owner-1"}
+	at := time.Now().Add(-time.Hour)
+	note := CreateInput{Type: EventTypeNote, OccurredAt: at, Title: "n"}
+
+	results, err := svc.CreateBatch(context.Background(), "pet-1", actor, []CreateInput{note, note, note}, BatchModeAtomic)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.batches) != 1 || len(store.batches[0]) != 3 || len(repo.created) != 0 {
+		t.Fatalf("expected one batch of 3 through the store, got %d batches / %d direct creates", len(store.batches), len(repo.created))
+	}
+	for i, r := range results {
+		if r.Index != i || r.Event == nil || r.Err != nil {
+			t.Fatalf("unexpected result %d: %+v", i, r)
+		}
+	}
+
+	// Un item inválido rechaza todo el lote sin tocar el store
+	_, err = svc.CreateBatch(context.Background(), "pet-1", actor, []CreateInput{note, {Type: EventTypeNote}}, BatchModeAtomic)
+	if !errors.Is(err, ErrBatchRejected) || len(store.batches) != 1 {
+		t.Fatalf("expected ErrBatchRejected without store call, got %v (%d batches)", err, len(store.batches))
+	}
+
+	// best_effort guarda item por item, cada uno en su propia transacción
+	results, err = svc.CreateBatch(context.Background(), "pet-1", actor, []CreateInput{note, {Type: EventTypeNote}}, BatchModeBestEffort)
+	if err != nil || len(store.batches) != 2 || len(store.batches[1]) != 1 || len(repo.created) != 0 || results[0].Event == nil || results[1].Err == nil {
+		t.Fatalf("expected 1 created through the store and 1 failed in best_effort, got err=%v batches=%d created=%d results=%+v", err, len(store.batches), len(repo.created), results)
+	}
+}
+
+func TestService_Create_WithBatchStoreWritesEventAndDetailTogether(t *testing.T) {
+	repo := &createOnlyRepo{}
+	store := &recordingBatchStore{}
+	svc := NewService(repo, WithBatchStore(store), WithMeasurementRepo(failingMeasurementRepo{}))
+
+	actor := Actor{Type: ActorTypeOwnerUser, ID: "owner-1"}
+	in := CreateInput{Type: EventTypeWeightRecorded, OccurredAt: time.Now().Add(-time.Hour), Measurement: &MeasurementInput{Value: 4.2, Unit: "kg"}}
+	e, err := svc.Create(context.Background(), "pet-1", actor, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.batches) != 1 || len(store.batches[0]) != 1 || store.batches[0][0].Measurement == nil || len(repo.created) != 0 {
+		t.Fatalf("expected event+measurement in one store call, got %d batches / %d direct creates", len(store.batches), len(repo.created))
+	}
+	if store.batches[0][0].ID != e.ID {
+		t.Fatalf("expected stored event %s, got %s", e.ID, store.batches[0][0].ID)
+	}
+}
+
+// voidRecordingRepo guarda creates y voids en memoria (sin BatchStore).
+type voidRecordingRepo struct {
+	createOnlyRepo
+	voided map[string]VoidAudit
+}
+
+func (r *voidRecordingRepo) Void(ctx context.Context, id string, audit VoidAudit) error {
+	if r.voided == nil {
+		r.voided = map[string]VoidAudit{}
+	}
+	r.voided[id] = audit
+	return nil
+}
+
+var errDetailWrite = errors.New("detail write failed")
+
+// failingMeasurementRepo falla siempre al guardar.
+type failingMeasurementRepo struct {
+	MeasurementRepository
+}
+
+func (failingMeasurementRepo) Create(ctx context.Context, d details.Measurement) error {
+	return errDetailWrite
+}
+
+func TestService_Create_DetailFailureVoidsEvent(t *testing.T) {
+	repo := &voidRecordingRepo{}
+	svc := NewService(repo, WithMeasurementRepo(failingMeasurementRepo{}))
+
+	actor := Actor{Type: ActorTypeOwnerUser, ID: "owner-1", Email: "owner@example.com"}
+	at := time.Now().Add(-time.Hour)
+	weight := CreateInput{Type: EventTypeWeightRecorded, OccurredAt: at, Measurement: &MeasurementInput{Value: 4.2, Unit: "kg"}}
+
+	if _, err := svc.Create(context.Background(), "pet-1", actor, weight); !errors.Is(err, errDetailWrite) {
+		t.Fatalf("expected detail error, got %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected 1 event written, got %d", len(repo.created))
+	}
+	audit, ok := repo.voided[repo.created[0].ID]
+	if !ok || audit.Reason != IncompleteVoidReason || audit.By != actor.ID || audit.ByEmail != actor.Email {
+		t.Fatalf("expected event without detail to be voided, got %+v (ok=%v)", audit, ok)
+	}
+
+	// Lote atomic en memoria: si un item falla se anulan también los ya guardados
+	repo = &voidRecordingRepo{}
+	svc = NewService(repo, WithMeasurementRepo(failingMeasurementRepo{}))
+	note := CreateInput{Type: EventTypeNote, OccurredAt: at, Title: "n"}
+	if _, err := svc.CreateBatch(context.Background(), "pet-1", actor, []CreateInput{note, weight}, BatchModeAtomic); !errors.Is(err, errDetailWrite) {
+		t.Fatalf("expected detail error from atomic batch, got %v", err)
+	}
+	if len(repo.created) != 2 || len(repo.voided) != 2 {
+		t.Fatalf("expected both batch events voided, got created=%d voided=%d", len(repo.created), len(repo.voided))
+	}
+}
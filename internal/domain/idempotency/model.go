@@ -0,0 +1,14 @@
+package idempotency
+
+import "time"
+
+// Record asocia una Idempotency-Key al recurso que creó el primer request con esa key.
+// La key está acotada por Scope (endpoint, p.ej. "pets.create") y por UserID: la misma key
+// en otro endpoint u otro usuario es un registro distinto.
+type Record struct {
+	Scope      string
+	UserID     string
+	Key        string
+	ResourceID string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,11 @@
+package idempotency
+
+import "context"
+
+type Repository interface {
+	// Get devuelve el registro de (scope, userID, key); ok=false si no existe.
+	Get(ctx context.Context, scope, userID, key string) (rec Record, ok bool, err error)
+
+	// Put guarda el registro, reemplazando uno previo con la misma (scope, userID, key) (p.ej. vencido).
+	Put(ctx context.Context, rec Record) error
+}
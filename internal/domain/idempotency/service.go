@@ -0,0 +1,82 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidKey: la Idempotency-Key supera MaxKeyLength.
+var ErrInvalidKey = errors.New("invalid idempotency key")
+
+const (
+	// DefaultTTL es cuánto se recuerda una key: un reintento posterior crea un recurso nuevo.
+	DefaultTTL = 24 * time.Hour
+	// MaxKeyLength es el largo máximo de una Idempotency-Key (suele ser un UUID).
+	MaxKeyLength = 255
+)
+
+type Service struct {
+	repo Repository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// Option configura dependencias opcionales del Service.
+type Option func(*Service)
+
+// WithTTL fija cuánto se recuerda una key (<= 0 => DefaultTTL).
+func WithTTL(d time.Duration) Option {
+	return func(s *Service) {
+		if d > 0 {
+			s.ttl = d
+		}
+	}
+}
+
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{
+		repo: repo,
+		ttl:  DefaultTTL,
+		now:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Lookup devuelve el recurso creado con key por userID en scope, si la key sigue vigente.
+// Una key vencida se trata como nueva.
+func (s *Service) Lookup(ctx context.Context, scope, userID, key string) (string, bool, error) {
+	key = strings.TrimSpace(key)
+	if len(key) > MaxKeyLength {
+		return "", false, ErrInvalidKey
+	}
+	rec, ok, err := s.repo.Get(ctx, scope, userID, key)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	if s.now().Sub(rec.CreatedAt) > s.ttl {
+		return "", false, nil
+	}
+	return rec.ResourceID, true, nil
+}
+
+// Remember registra que key (de userID en scope) creó resourceID.
+// MVP: Lookup y Remember no son atómicos; dos requests simultáneos con la misma key
+// pueden crear dos recursos (gana el último registro).
+func (s *Service) Remember(ctx context.Context, scope, userID, key, resourceID string) error {
+	key = strings.TrimSpace(key)
+	if len(key) > MaxKeyLength {
+		return ErrInvalidKey
+	}
+	return s.repo.Put(ctx, Record{
+		Scope:      scope,
+		UserID:     userID,
+		Key:        key,
+		ResourceID: resourceID,
+		CreatedAt:  s.now(),
+	})
+}
@@ -0,0 +1,48 @@
+package idempotency
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mapRepo map[[3]string]Record
+
+func (m mapRepo) Get(_ context.Context, scope, userID, key string) (Record, bool, error) {
+	rec, ok := m[[3]string{scope, userID, key}]
+	return rec, ok, nil
+}
+
+func (m mapRepo) Put(_ context.Context, rec Record) error {
+	m[[3]string{rec.Scope, rec.UserID, rec.Key}] = rec
+	return nil
+}
+
+func TestService_LookupHonorsScopeAndTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewService(mapRepo{}, WithTTL(time.Hour))
+	svc.now = func() time.Time { return now }
+
+	if err := svc.Remember(ctx, "pets.create", "u1", " k1 ", "pet-1"); err != nil {
+		t.Fatalf("remember: %v", err)
+	}
+	if id, ok, err := svc.Lookup(ctx, "pets.create", "u1", "k1"); err != nil || !ok || id != "pet-1" {
+		t.Fatalf("expected pet-1, got %q ok=%v err=%v", id, ok, err)
+	}
+	for _, c := range [][2]string{{"events.create:p1", "u1"}, {"pets.create", "u2"}} {
+		if _, ok, _ := svc.Lookup(ctx, c[0], c[1], "k1"); ok {
+			t.Fatalf("expected no match for scope=%s user=%s", c[0], c[1])
+		}
+	}
+
+	now = now.Add(time.Hour + time.Second)
+	if _, ok, _ := svc.Lookup(ctx, "pets.create", "u1", "k1"); ok {
+		t.Fatalf("expected expired key to be treated as new")
+	}
+
+	if _, _, err := svc.Lookup(ctx, "pets.create", "u1", strings.Repeat("k", MaxKeyLength+1)); err != ErrInvalidKey {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+}
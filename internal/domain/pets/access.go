@@ -0,0 +1,95 @@
+package pets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"pet-clinical-history/internal/authz"
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/platform/httpjson"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GrantStatusNone indica que el caller no tiene grant activo ni invitación pendiente.
+const GrantStatusNone = "none"
+
+// petAccessResponse resume qué puede hacer el caller con la mascota.
+type petAccessResponse struct {
+	IsOwner bool `json:"is_owner"`
+	// active | invited | none (el owner siempre es none: no necesita grant)
+	GrantStatus string `json:"grant_status" enums:"active,invited,none"`
+	// Scopes efectivos: todos para el owner, los del grant activo para un delegado, vacío si no.
+	Scopes []accessgrants.Scope `json:"scopes"`
+	// GrantID del grant activo o de la invitación pendiente (p.ej. para aceptarla).
+	GrantID string `json:"grant_id,omitempty"`
+}
+
+// petAccessHandler godoc
+// @Summary Acceso del usuario a una mascota
+// @Description Devuelve, para el usuario autenticado, si es dueño de la mascota, el estado de su grant (`active`, `invited` o `none`) y sus scopes efectivos, en una sola llamada (pantalla de detalle). Sin acceso no responde 403 sino `is_owner: false, grant_status: none`, para que la UI muestre "pedir acceso"; una invitación vencida cuenta como `none`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {object} petAccessResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/access [get]
+func petAccessHandler(svc *Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p Pet
+		// Scope vacío => solo el dueño pasa; el Forbidden de un no-dueño no es un error acá.
+		access, err := authz.Authorize(r, grantsSvc, func(ctx context.Context) (authz.PetRef, bool) {
+			var err error
+			p, err = svc.GetByID(ctx, chi.URLParam(r, "petID"))
+			return authz.PetRef{ID: p.ID, OwnerUserID: p.OwnerUserID}, err == nil
+		}, "")
+		if access.Outcome != authz.Forbidden && authz.WriteDenied(w, access, err) {
+			return
+		}
+		claims := access.Claims
+
+		if access.IsOwner {
+			httpjson.WriteJSON(w, http.StatusOK, petAccessResponse{
+				IsOwner:     true,
+				GrantStatus: GrantStatusNone,
+				Scopes:      accessgrants.AllScopes(),
+			})
+			return
+		}
+
+		g, err := grantsSvc.GetActiveGrant(r.Context(), p.ID, claims.UserID)
+		switch {
+		case err == nil:
+			httpjson.WriteJSON(w, http.StatusOK, petAccessResponse{
+				GrantStatus: string(accessgrants.StatusActive),
+				Scopes:      g.Scopes,
+				GrantID:     g.ID,
+			})
+			return
+		case !errors.Is(err, accessgrants.ErrNotFound):
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		// Sin grant activo: ¿hay una invitación pendiente (no vencida) para esta mascota?
+		invites, err := grantsSvc.ListByGrantee(r.Context(), claims.UserID, accessgrants.StatusInvited)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+		out := petAccessResponse{GrantStatus: GrantStatusNone, Scopes: []accessgrants.Scope{}}
+		for _, inv := range invites {
+			if inv.PetID == p.ID {
+				out.GrantStatus = string(accessgrants.StatusInvited)
+				out.GrantID = inv.ID
+				break
+			}
+		}
+		httpjson.WriteJSON(w, http.StatusOK, out)
+	}
+}
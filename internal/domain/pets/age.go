@@ -0,0 +1,59 @@
+package pets
+
+import (
+	"fmt"
+	"time"
+)
+
+// AgeInMonths devuelve los meses cumplidos entre birth y now, comparando fechas de
+// calendario en UTC (birth_date se guarda como fecha a medianoche UTC, así que usar la
+// zona local del server corre el cumpleaños un día según dónde corra).
+// future es true si birth es posterior a now; en ese caso months es 0.
+func AgeInMonths(birth, now time.Time) (months int, future bool) {
+	by, bm, bd := birth.UTC().Date()
+	ny, nm, nd := now.UTC().Date()
+	if time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).After(time.Date(ny, nm, nd, 0, 0, 0, 0, time.UTC)) {
+		return 0, true
+	}
+
+	months = (ny-by)*12 + int(nm-bm)
+	// Si todavía no llegó el día del mes, el mes en curso no está cumplido; salvo que now
+	// sea el último día de un mes más corto (nacido un 31 cumple mes el 30 / 28).
+	if nd < bd && nd != daysIn(ny, nm) {
+		months--
+	}
+	return months, false
+}
+
+// FormatAge representa meses como "2y 3m" (omite la parte en cero; 0 => "0m").
+func FormatAge(months int) string {
+	y, m := months/12, months%12
+	switch {
+	case y == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dy", y)
+	default:
+		return fmt.Sprintf("%dy %dm", y, m)
+	}
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// ageMonths calcula la edad de p según el reloj del servicio. nil si no tiene birth_date.
+// Una birth_date futura (dato mal cargado) se informa como 0 y se loguea.
+func (s *Service) ageMonths(p Pet) *int {
+	if p.BirthDate == nil {
+		return nil
+	}
+	months, future := AgeInMonths(*p.BirthDate, s.now())
+	if future && s.log != nil {
+		s.log.Warn("pet birth_date is in the future", map[string]any{
+			"pet_id":     p.ID,
+			"birth_date": p.BirthDate.UTC().Format("2006-01-02"),
+		})
+	}
+	return &months
+}
@@ -0,0 +1,87 @@
+package pets
+
+import (
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/platform/logger"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAgeInMonths(t *testing.T) {
+	now := date(2025, 12, 15)
+
+	cases := []struct {
+		name       string
+		birth      time.Time
+		now        time.Time
+		wantMonths int
+		wantFuture bool
+	}{
+		{"mismo día", date(2025, 12, 15), now, 0, false},
+		{"mes cumplido justo", date(2025, 11, 15), now, 1, false},
+		{"mes sin cumplir", date(2025, 11, 16), now, 0, false},
+		{"años y meses", date(2023, 9, 1), now, 27, false},
+		{"nacido un 31, fin de mes corto", date(2025, 1, 31), date(2025, 2, 28), 1, false},
+		{"bisiesto", date(2024, 2, 29), date(2025, 2, 28), 12, false},
+		// 23:30 en UTC-3 ya es el día siguiente en UTC: cuenta el calendario UTC
+		{"hora local vs UTC", date(2025, 11, 16), time.Date(2025, 12, 15, 23, 30, 0, 0, time.FixedZone("ART", -3*3600)), 1, false},
+		{"futura", date(2026, 1, 10), now, 0, true},
+		{"mañana", date(2025, 12, 16), now, 0, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			months, future := AgeInMonths(tc.birth, tc.now)
+			if months != tc.wantMonths || future != tc.wantFuture {
+				t.Fatalf("AgeInMonths(%s, %s) = (%d, %v), want (%d, %v)",
+					tc.birth.Format(time.RFC3339), tc.now.Format(time.RFC3339), months, future, tc.wantMonths, tc.wantFuture)
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	cases := map[int]string{0: "0m", 5: "5m", 12: "1y", 27: "2y 3m"}
+	for months, want := range cases {
+		if got := FormatAge(months); got != want {
+			t.Fatalf("FormatAge(%d) = %q, want %q", months, got, want)
+		}
+	}
+}
+
+type warnRecorder struct {
+	logger.Logger
+	warns []string
+}
+
+func (r *warnRecorder) Warn(msg string, _ map[string]any) { r.warns = append(r.warns, msg) }
+
+func TestService_AgeMonths_UsesClockAndWarnsOnFutureBirthDate(t *testing.T) {
+	rec := &warnRecorder{}
+	svc := NewService(nil, WithLogger(rec))
+	svc.now = func() time.Time { return date(2025, 12, 15) }
+
+	if got := svc.ageMonths(Pet{ID: "p1"}); got != nil {
+		t.Fatalf("expected nil age without birth_date, got %d", *got)
+	}
+
+	birth := date(2024, 6, 1)
+	if got := svc.ageMonths(Pet{ID: "p1", BirthDate: &birth}); got == nil || *got != 18 {
+		t.Fatalf("expected 18 months, got %v", got)
+	}
+	if len(rec.warns) != 0 {
+		t.Fatalf("expected no warnings, got %v", rec.warns)
+	}
+
+	future := date(2026, 3, 1)
+	if got := svc.ageMonths(Pet{ID: "p1", BirthDate: &future}); got == nil || *got != 0 {
+		t.Fatalf("expected age 0 for future birth_date, got %v", got)
+	}
+	if len(rec.warns) != 1 {
+		t.Fatalf("expected 1 warning for future birth_date, got %v", rec.warns)
+	}
+}
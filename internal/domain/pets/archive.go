@@ -0,0 +1,58 @@
+package pets
+
+import (
+	"context"
+	"strings"
+
+	"pet-clinical-history/internal/ports/auth"
+)
+
+// Archive oculta la mascota de los listados del owner sin tocar su historial ni sus grants
+// (p.ej. falleció o se dio en adopción). Solo el owner; si ya estaba archivada no cambia nada.
+func (s *Service) Archive(ctx context.Context, petID, ownerUserID string) (Pet, error) {
+	return s.setStatus(ctx, petID, ownerUserID, PetStatusArchived)
+}
+
+// Unarchive vuelve a mostrar una mascota archivada. Solo el owner; idempotente.
+func (s *Service) Unarchive(ctx context.Context, petID, ownerUserID string) (Pet, error) {
+	return s.setStatus(ctx, petID, ownerUserID, PetStatusActive)
+}
+
+// setStatus cambia el Status de la mascota. Las fusionadas (ArchivedAt) quedan fuera:
+// su historial ya se movió al destino, así que devuelven ErrPetArchived.
+func (s *Service) setStatus(ctx context.Context, petID, ownerUserID string, status PetStatus) (Pet, error) {
+	petID = strings.TrimSpace(petID)
+	ownerUserID = strings.TrimSpace(ownerUserID)
+	if petID == "" || ownerUserID == "" {
+		return Pet{}, ErrPetInvalidInput
+	}
+
+	p, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), petID)
+	if err != nil {
+		return Pet{}, ErrPetNotFound
+	}
+	if p.OwnerUserID != ownerUserID {
+		return Pet{}, ErrPetForbidden
+	}
+	if p.ArchivedAt != nil {
+		return Pet{}, ErrPetArchived
+	}
+	if p.EffectiveStatus() == status {
+		return p, nil
+	}
+
+	p.Status = status
+	p.UpdatedAt = s.now()
+	if err := s.repo.Update(ctx, p); err != nil {
+		return Pet{}, err
+	}
+	return p, nil
+}
+
+// EffectiveStatus devuelve el Status, con active para las mascotas guardadas sin él.
+func (p Pet) EffectiveStatus() PetStatus {
+	if p.Status == "" {
+		return PetStatusActive
+	}
+	return p.Status
+}
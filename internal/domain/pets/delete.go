@@ -0,0 +1,72 @@
+package pets
+
+import (
+	"context"
+	"strings"
+
+	"pet-clinical-history/internal/ports/auth"
+)
+
+// GrantRevoker revoca todos los grants de una mascota.
+// Lo implementa accessgrants.Service; se define aquí para que el Service no dependa del módulo.
+type GrantRevoker interface {
+	RevokeAllForPet(ctx context.Context, petID, ownerUserID string) (int, error)
+}
+
+// EventVoider anula (sin borrar) todos los eventos activos de una mascota, a nombre de actorUserID.
+// Lo implementa events.Service; se define aquí para no importar events (rompe ciclos).
+type EventVoider interface {
+	VoidAllForPet(ctx context.Context, petID, actorUserID string) (int, error)
+}
+
+// WithGrantRevoker habilita la revocación de grants al borrar una mascota.
+func WithGrantRevoker(g GrantRevoker) Option {
+	return func(s *Service) { s.grants = g }
+}
+
+// WithEventVoider habilita la anulación de eventos al borrar una mascota.
+func WithEventVoider(e EventVoider) Option {
+	return func(s *Service) { s.events = e }
+}
+
+// DeleteResult resume qué se cerró al borrar una mascota.
+type DeleteResult struct {
+	GrantsRevoked int
+	EventsVoided  int
+}
+
+// Delete borra la mascota (solo el owner). Antes revoca todos sus grants y anula sus eventos,
+// para que ningún delegado conserve acceso y el historial no quede huérfano pero activo.
+// MVP: los pasos no son atómicos; si uno falla se corta y el borrado puede reintentarse.
+func (s *Service) Delete(ctx context.Context, petID, actorUserID string) (DeleteResult, error) {
+	petID = strings.TrimSpace(petID)
+	actorUserID = strings.TrimSpace(actorUserID)
+	if petID == "" || actorUserID == "" {
+		return DeleteResult{}, ErrPetInvalidInput
+	}
+
+	p, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), petID)
+	if err != nil {
+		return DeleteResult{}, ErrPetNotFound
+	}
+	if p.OwnerUserID != actorUserID {
+		return DeleteResult{}, ErrPetForbidden
+	}
+
+	var res DeleteResult
+	if s.grants != nil {
+		if res.GrantsRevoked, err = s.grants.RevokeAllForPet(ctx, petID, actorUserID); err != nil {
+			return DeleteResult{}, err
+		}
+	}
+	if s.events != nil {
+		if res.EventsVoided, err = s.events.VoidAllForPet(ctx, petID, actorUserID); err != nil {
+			return DeleteResult{}, err
+		}
+	}
+
+	if err := s.repo.Delete(ctx, petID); err != nil {
+		return DeleteResult{}, err
+	}
+	return res, nil
+}
@@ -1,31 +1,81 @@
 package pets
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/authz"
 	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/accesslog"
 	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/httpjson"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func RegisterRoutes(r chi.Router, svc *Service, grantsSvc *accessgrants.Service) {
+// ActivityLookup resuelve la última actividad (evento más reciente) por mascota.
+// Lo implementa events.Service; se define aquí para no importar events (rompe ciclos).
+type ActivityLookup interface {
+	LastActivity(ctx context.Context, petIDs []string) (map[string]time.Time, error)
+}
+
+// ProfileEventRecorder registra eventos de perfil en el timeline de la mascota.
+// Lo implementa events.Service; se define aquí para no importar events (rompe ciclos).
+type ProfileEventRecorder interface {
+	RecordProfileEvent(ctx context.Context, petID, ownerUserID, title, notes string) error
+}
+
+func RegisterRoutes(r chi.Router, svc *Service, grantsSvc *accessgrants.Service, accessLog *accesslog.Service, activity ActivityLookup, profileEvents ProfileEventRecorder, quota PetQuota) {
 	r.Route("/pets", func(pr chi.Router) {
-		pr.Post("/", createPetHandler(svc))
-		pr.Get("/", listPetsHandler(svc))
+		pr.Post("/", createPetHandler(svc, quota))
+		pr.Get("/", listPetsHandler(svc, activity))
+
+		// Disponibilidad de microchip (pre-create); no revela el owner
+		pr.Get("/microchip-available", microchipAvailableHandler(svc))
+
+		// Buscar por microchip (owner o delegado con pet:read; si no, 404)
+		pr.Get("/lookup", lookupPetHandler(svc, grantsSvc, accessLog))
 
 		// Perfil de mascota (owner o delegado con pet:read)
-		pr.Get("/{petID}", getPetHandler(svc, grantsSvc))
+		pr.Get("/{petID}", getPetHandler(svc, grantsSvc, accessLog))
 
 		// Editar perfil (owner o delegado con pet:edit_profile)
 		pr.Patch("/{petID}", updatePetHandler(svc, grantsSvc))
+
+		// Borrar mascota (owner): revoca grants y anula eventos
+		pr.Delete("/{petID}", deletePetHandler(svc))
+
+		// Transferir la mascota a otro usuario (owner)
+		pr.Post("/{petID}/transfer", transferPetHandler(svc, profileEvents))
+
+		// Fusionar un duplicado en esta mascota (owner de ambas)
+		pr.Post("/{petID}/merge", mergePetHandler(svc, profileEvents))
+
+		// Archivar / desarchivar (owner): oculta de GET /pets sin perder el historial
+		pr.Post("/{petID}/archive", archivePetHandler(svc))
+		pr.Post("/{petID}/unarchive", unarchivePetHandler(svc))
+
+		// Acceso del caller a la mascota (owner / grant / scopes), para la UI
+		pr.Get("/{petID}/access", petAccessHandler(svc, grantsSvc))
 	})
 
+	// Vocabulario de especies / sexos (etiquetas según Accept-Language)
+	r.Get("/vocab/species", speciesVocabHandler)
+	r.Get("/vocab/sex", sexVocabHandler)
+
 	// Mascotas compartidas conmigo (delegado)
 	r.Get("/me/pets", listMySharedPetsHandler(svc, grantsSvc))
+
+	// Propias + compartidas en una sola lista
+	r.Get("/me/pets/all", listAllMyPetsHandler(svc, grantsSvc))
 }
 
 // createPetRequest es el cuerpo de la solicitud para crear una nueva mascota.
@@ -35,31 +85,74 @@ type createPetRequest struct {
 	Breed     string  `json:"breed"`                   // Ej para dog: labrador, poodle. Ej para cat: persian, common.
 	Sex       Sex     `json:"sex" enums:"male,female,unknown"`
 	BirthDate string  `json:"birth_date"` // YYYY-MM-DD opcional
+	Microchip string  `json:"microchip"`  // opcional, 10-15 caracteres alfanuméricos
 	Notes     string  `json:"notes"`
 }
 
 // updatePetRequest es el cuerpo parcial para actualizar el perfil de una mascota.
 type updatePetRequest struct {
-	Name    *string  `json:"name"`
-	Species *Species `json:"species" enums:"dog,cat"`
-	Breed   *string  `json:"breed"`
-	Sex     *Sex     `json:"sex" enums:"male,female,unknown"`
-	Notes   *string  `json:"notes"`
+	Name      *string  `json:"name"`
+	Species   *Species `json:"species" enums:"dog,cat"`
+	Breed     *string  `json:"breed"`
+	Sex       *Sex     `json:"sex" enums:"male,female,unknown"`
+	Microchip *string  `json:"microchip"` // "" limpia el valor
+	Notes     *string  `json:"notes"`
 	// birth_date se decodifica aparte para soportar null
 }
 
 // petResponse representa el perfil público de una mascota devuelto por la API.
 type petResponse struct {
-	ID          string     `json:"id"`
-	OwnerUserID string     `json:"owner_user_id"`
-	Name        string     `json:"name"`
-	Species     Species    `json:"species"`
-	Breed       string     `json:"breed"`
-	Sex         Sex        `json:"sex"`
-	BirthDate   *time.Time `json:"birth_date,omitempty"`
-	Notes       string     `json:"notes"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          string        `json:"id"`
+	OwnerUserID string        `json:"owner_user_id"`
+	Name        string        `json:"name"`
+	Species     Species       `json:"species"`
+	Breed       string        `json:"breed"`
+	Sex         Sex           `json:"sex"`
+	BirthDate   *apitime.Time `json:"birth_date,omitempty" swaggertype:"string" format:"date-time"`
+	Microchip   string        `json:"microchip,omitempty"`
+	Notes       string        `json:"notes"`
+	Status      PetStatus     `json:"status" enums:"active,archived"`
+	CreatedAt   apitime.Time  `json:"created_at" swaggertype:"string" format:"date-time"`
+	UpdatedAt   apitime.Time  `json:"updated_at" swaggertype:"string" format:"date-time"`
+	ArchivedAt  *apitime.Time `json:"archived_at,omitempty" swaggertype:"string" format:"date-time"`
+
+	// Edad calculada desde birth_date con el reloj del servidor (omitida sin birth_date).
+	AgeMonths *int   `json:"age_months,omitempty"`
+	AgeHuman  string `json:"age_human,omitempty"` // p.ej. "2y 3m"
+
+	// Solo con ?include=last_activity (omitido si la mascota no tiene eventos).
+	LastEventAt *apitime.Time `json:"last_event_at,omitempty" swaggertype:"string" format:"date-time"`
+}
+
+// microchipAvailableResponse indica si un microchip puede registrarse.
+type microchipAvailableResponse struct {
+	Available bool `json:"available"`
+}
+
+// mergePetRequest indica la mascota duplicada que se fusiona en la del path.
+type mergePetRequest struct {
+	SourcePetID string `json:"source_pet_id"`
+}
+
+// mergePetResponse es la mascota destino y lo que se movió desde el origen.
+type mergePetResponse struct {
+	Pet           petResponse `json:"pet"`
+	SourcePetID   string      `json:"source_pet_id"`
+	EventsMoved   int         `json:"events_moved"`
+	GrantsMoved   int         `json:"grants_moved"`
+	GrantsRevoked int         `json:"grants_revoked"` // duplicados del mismo delegado (y sus sub-delegaciones)
+}
+
+// transferPetRequest indica el usuario que pasa a ser owner de la mascota.
+type transferPetRequest struct {
+	NewOwnerUserID string `json:"new_owner_user_id"`
+}
+
+// transferPetResponse es la mascota con su nuevo owner y los grants revocados.
+type transferPetResponse struct {
+	Pet                 petResponse `json:"pet"`
+	PreviousOwnerUserID string      `json:"previous_owner_user_id"`
+	GrantsRevoked       int         `json:"grants_revoked"`
 }
 
 // sharedPetResponse representa una mascota compartida con el usuario autenticado.
@@ -69,6 +162,32 @@ type sharedPetResponse struct {
 	Scopes []accessgrants.Scope `json:"scopes"`
 }
 
+// sharedPetListResponse es una página de GET /me/pets; next_cursor se omite en la última página.
+type sharedPetListResponse struct {
+	Items      []sharedPetResponse `json:"items"`
+	Count      int                 `json:"count"`    // mascotas en esta página
+	Limit      int                 `json:"limit"`    // límite efectivo aplicado (sobre grants)
+	HasMore    bool                `json:"has_more"` // true => hay next_cursor
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// Relationship indica por qué el usuario ve una mascota en GET /me/pets/all.
+type Relationship string
+
+const (
+	RelationshipOwner    Relationship = "owner"
+	RelationshipDelegate Relationship = "delegate"
+)
+
+// myPetResponse es una mascota propia o compartida con el usuario autenticado.
+// Grant y Scopes solo vienen para relationship=delegate.
+type myPetResponse struct {
+	Pet          petResponse          `json:"pet"`
+	Relationship Relationship         `json:"relationship" enums:"owner,delegate"`
+	Grant        *grantMini           `json:"grant,omitempty"`
+	Scopes       []accessgrants.Scope `json:"scopes,omitempty"`
+}
+
 // grantMini resume un grant asociado a una mascota compartida.
 type grantMini struct {
 	ID     string              `json:"id"`
@@ -77,27 +196,32 @@ type grantMini struct {
 
 // createPetHandler godoc
 // @Summary Crear una mascota
-// @Description Crea una mascota. Species: `dog`, `cat`. Breeds Perro: `labrador`, `golden_retriever`, `poodle`, etc. Breeds Gato: `persian`, `common`, etc.
+// @Description Crea una mascota. Species: `dog`, `cat`. Breeds Perro: `labrador`, `golden_retriever`, `poodle`, etc. Breeds Gato: `persian`, `common`, etc. Si el plan del usuario tiene la cuota `pets:max`, no se puede superar (cuentan las mascotas vigentes del owner). Con `Idempotency-Key`, un reintento del mismo usuario devuelve la mascota original (mismo 201) sin crear otra.
 // @Tags pets
 // @Accept json
 // @Produce json
 // @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
 // @Param Authorization header string false "Bearer token en producción"
+// @Param Idempotency-Key header string false "Opcional (máx. 255): un reintento con la misma key devuelve la mascota ya creada (201) en lugar de duplicarla"
 // @Param payload body createPetRequest true "Datos de la mascota; birth_date opcional (YYYY-MM-DD)"
 // @Success 201 {object} petResponse
-// @Failure 401 {string} string "unauthorized"
+// @Failure 400 {object} httpjson.ErrorBody "invalid json / birth_date / datos inválidos"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 402 {object} httpjson.ErrorBody "error.code: quota_exceeded (el plan no admite más mascotas)"
+// @Failure 409 {object} httpjson.ErrorBody "error.code: microchip_taken (el microchip ya es de otra mascota)"
+// @Failure 503 {object} httpjson.ErrorBody "capabilities unavailable"
 // @Router /pets [post]
-func createPetHandler(svc *Service) http.HandlerFunc {
+func createPetHandler(svc *Service, quota PetQuota) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetClaims(r.Context())
 		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
 			return
 		}
 
 		var req createPetRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid json", http.StatusBadRequest)
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
 			return
 		}
 
@@ -105,100 +229,342 @@ func createPetHandler(svc *Service) http.HandlerFunc {
 		if strings.TrimSpace(req.BirthDate) != "" {
 			t, err := time.Parse("2006-01-02", req.BirthDate)
 			if err != nil {
-				http.Error(w, "birth_date must be YYYY-MM-DD", http.StatusBadRequest)
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "birth_date must be YYYY-MM-DD")
 				return
 			}
 			bd = &t
 		}
 
+		// Un reintento de algo ya creado responde lo mismo, aunque el plan ya no admita más mascotas.
+		key := r.Header.Get("Idempotency-Key")
+		prev, replayed, err := svc.Replayed(r.Context(), claims.UserID, key)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			return
+		}
+		if replayed {
+			httpjson.WriteJSON(w, http.StatusCreated, toPetResponse(prev, apitime.FromContext(r.Context()), svc.ageMonths(prev)))
+			return
+		}
+
+		switch err := quota.check(r.Context(), svc, claims); {
+		case err == nil:
+		case errors.Is(err, ErrPetQuotaExceeded):
+			httpjson.WriteError(w, http.StatusPaymentRequired, httpjson.CodeQuotaExceeded, "plan does not allow more pets ("+FeaturePetsMax+")")
+			return
+		case errors.Is(err, errPetQuotaUnavailable):
+			httpjson.WriteError(w, http.StatusServiceUnavailable, httpjson.CodeUnavailable, "capabilities unavailable")
+			return
+		default:
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
 		p, err := svc.Create(r.Context(), claims.UserID, CreateInput{
 			Name:      req.Name,
 			Species:   req.Species,
 			Breed:     req.Breed,
 			Sex:       req.Sex,
 			BirthDate: bd,
+			Microchip: req.Microchip,
 			Notes:     req.Notes,
+
+			IdempotencyKey: key,
 		})
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			if errors.Is(err, ErrMicrochipTaken) {
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeMicrochipTaken, err.Error())
+				return
+			}
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusCreated, toPetResponse(p, apitime.FromContext(r.Context()), svc.ageMonths(p)))
+	}
+}
+
+// microchipAvailableHandler godoc
+// @Summary Verificar disponibilidad de un microchip
+// @Description Indica si un microchip ya está registrado en alguna mascota, para evitar conflictos antes de crearla. Solo devuelve un booleano; nunca expone la mascota ni su owner. Cualquier usuario autenticado puede usarlo. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param microchip query string true "Microchip a verificar (10-15 caracteres alfanuméricos)"
+// @Success 200 {object} microchipAvailableResponse
+// @Failure 400 {object} httpjson.ErrorBody "microchip inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/microchip-available [get]
+func microchipAvailableHandler(svc *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		available, err := svc.MicrochipAvailable(r.Context(), r.URL.Query().Get("microchip"))
+		if err != nil {
+			if err == ErrPetInvalidInput {
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "microchip must be 10-15 alphanumeric characters")
+				return
+			}
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			return
 		}
 
-		writeJSON(w, http.StatusCreated, toPetResponse(p))
+		httpjson.WriteJSON(w, http.StatusOK, microchipAvailableResponse{Available: available})
 	}
 }
 
+// petPage es limit/offset pendientes de aplicar en el handler (sort=last_activity).
+type petPage struct {
+	limit, offset int
+}
+
+// parsePetListFilter lee species, q, include_archived, sort, order, limit y offset de GET /pets.
+// Con sort=last_activity el repo devuelve todo (orden base) y la página se aplica después.
+func parsePetListFilter(r *http.Request) (ListFilter, petPage, error) {
+	q := r.URL.Query()
+	filter := ListFilter{
+		Species: Species(q.Get("species")),
+		Query:   q.Get("q"),
+	}
+
+	if v := strings.TrimSpace(q.Get("include_archived")); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return ListFilter{}, petPage{}, errors.New("include_archived must be true or false")
+		}
+		filter.IncludeArchived = b
+	}
+
+	var page petPage
+	if v := strings.TrimSpace(q.Get("limit")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > MaxListLimit {
+			return ListFilter{}, petPage{}, fmt.Errorf("limit must be between 1 and %d", MaxListLimit)
+		}
+		page.limit = n
+	}
+	if v := strings.TrimSpace(q.Get("offset")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return ListFilter{}, petPage{}, errors.New("offset must be a non-negative integer")
+		}
+		page.offset = n
+	}
+
+	order := strings.TrimSpace(q.Get("order"))
+	switch order {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return ListFilter{}, petPage{}, errors.New("order must be asc or desc")
+	}
+
+	switch sortBy := strings.TrimSpace(q.Get("sort")); sortBy {
+	case "last_activity":
+		if order != "" {
+			return ListFilter{}, petPage{}, errors.New("order is not supported with sort=last_activity")
+		}
+		return filter, page, nil
+	case "":
+	default:
+		filter.Sort = ListSort(sortBy)
+		if !filter.Sort.Valid() {
+			return ListFilter{}, petPage{}, errors.New("sort must be created_at, name, updated_at or last_activity")
+		}
+	}
+
+	filter.Limit, filter.Offset = page.limit, page.offset
+	return filter, petPage{}, nil
+}
+
+// paginatePets aplica offset y limit (0 => sin límite) sobre items ya ordenados.
+func paginatePets(items []Pet, page petPage) []Pet {
+	if page.offset >= len(items) {
+		return items[:0]
+	}
+	items = items[page.offset:]
+	if page.limit > 0 && page.limit < len(items) {
+		items = items[:page.limit]
+	}
+	return items
+}
+
 // listPetsHandler godoc
 // @Summary Listar mis mascotas
-// @Description Lista todas las mascotas cuyo propietario es el usuario autenticado. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod). Solo el owner ve este listado; los delegados no listan aquí.
+// @Description Lista todas las mascotas cuyo propietario es el usuario autenticado. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod). Solo el owner ve este listado; los delegados no listan aquí. Con `include=last_activity` agrega `last_event_at` (occurred_at del evento activo más reciente); `sort` ordena por `created_at` (default), `name` o `updated_at`, con `order=asc|desc` (default asc); `sort=last_activity` ordena por esa fecha, más reciente primero y mascotas sin eventos al final (no admite `order`). `limit` (1..200) y `offset` paginan el resultado ya ordenado. `species` filtra por especie exacta y `q` busca en el nombre (substring, sin distinguir mayúsculas); sin parámetros devuelve todas las vigentes. Las archivadas por el owner (`status=archived`) solo aparecen con `include_archived=true`.
 // @Tags pets
 // @Produce json
 // @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
 // @Param Authorization header string false "Bearer token en producción"
+// @Param include query string false "CSV de campos calculados a incluir" Enums(last_activity)
+// @Param sort query string false "Orden del listado (default: created_at)" Enums(created_at, name, updated_at, last_activity)
+// @Param order query string false "Dirección del orden (default: asc)" Enums(asc, desc)
+// @Param limit query int false "Máximo de mascotas a devolver (1..200; default: todas)"
+// @Param offset query int false "Cantidad de mascotas a saltear"
+// @Param species query string false "Filtrar por especie" Enums(dog, cat)
+// @Param q query string false "Texto a buscar en el nombre"
+// @Param include_archived query bool false "Incluir las mascotas archivadas (default: false)"
 // @Success 200 {array} petResponse
-// @Failure 401 {string} string "unauthorized"
+// @Failure 400 {object} httpjson.ErrorBody "include / include_archived / sort / order / limit / offset inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
 // @Router /pets [get]
-func listPetsHandler(svc *Service) http.HandlerFunc {
+func listPetsHandler(svc *Service, activity ActivityLookup) http.HandlerFunc {
 	// Owner-only
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetClaims(r.Context())
 		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		includeActivity := false
+		for _, inc := range strings.Split(r.URL.Query().Get("include"), ",") {
+			switch strings.TrimSpace(inc) {
+			case "":
+			case "last_activity":
+				includeActivity = true
+			default:
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "include must be last_activity")
+				return
+			}
+		}
+		filter, page, err := parsePetListFilter(r)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
 			return
 		}
+		sortBy := strings.TrimSpace(r.URL.Query().Get("sort"))
 
-		items, err := svc.ListByOwner(r.Context(), claims.UserID)
+		items, err := svc.ListByOwner(r.Context(), claims.UserID, filter)
 		if err != nil {
-			http.Error(w, "internal error", http.StatusInternalServerError)
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			return
 		}
 
+		var last map[string]time.Time
+		if includeActivity || sortBy == "last_activity" {
+			ids := make([]string, 0, len(items))
+			for _, p := range items {
+				ids = append(ids, p.ID)
+			}
+			last, err = activity.LastActivity(r.Context(), ids)
+			if err != nil {
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+				return
+			}
+		}
+
+		if sortBy == "last_activity" {
+			// Más reciente primero; sin eventos al final (manteniendo el orden base).
+			sort.SliceStable(items, func(i, j int) bool {
+				li, iok := last[items[i].ID]
+				lj, jok := last[items[j].ID]
+				if iok != jok {
+					return iok
+				}
+				return li.After(lj)
+			})
+			// Este orden se calcula acá, así que la página también.
+			items = paginatePets(items, page)
+		}
+
+		tf := apitime.FromContext(r.Context())
 		out := make([]petResponse, 0, len(items))
 		for _, p := range items {
-			out = append(out, toPetResponse(p))
+			resp := toPetResponse(p, tf, svc.ageMonths(p))
+			if includeActivity {
+				if t, ok := last[p.ID]; ok {
+					resp.LastEventAt = apitime.NewPtr(&t, tf)
+				}
+			}
+			out = append(out, resp)
 		}
 
-		writeJSON(w, http.StatusOK, out)
+		httpjson.WriteJSON(w, http.StatusOK, out)
 	}
 }
 
 // getPetHandler godoc
 // @Summary Obtener perfil de mascota
-// @Description Obtiene el perfil de una mascota. El dueño siempre tiene acceso (bypass). Un delegado necesita un grant activo con scope `pet:read`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Obtiene el perfil de una mascota. El dueño siempre tiene acceso (bypass). Un delegado necesita un grant activo con scope `pet:read`. La respuesta trae un `ETag` débil; si el cliente lo reenvía en `If-None-Match` y la mascota no cambió responde 304 sin body. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags pets
 // @Produce json
 // @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
 // @Param Authorization header string false "Bearer token en producción"
+// @Param If-None-Match header string false "ETag de una respuesta anterior"
 // @Param petID path string true "ID de la mascota"
 // @Success 200 {object} petResponse
-// @Failure 403 {string} string "forbidden"
-// @Failure 404 {string} string "pet not found"
+// @Header 200 {string} ETag "ETag débil de la respuesta"
+// @Success 304 "not modified"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
 // @Router /pets/{petID} [get]
-func getPetHandler(svc *Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+func getPetHandler(svc *Service, grantsSvc *accessgrants.Service, accessLog *accesslog.Service) http.HandlerFunc {
 	// Owner bypass, delegado requiere pet:read
 	return func(w http.ResponseWriter, r *http.Request) {
-		claims, ok := middleware.GetClaims(r.Context())
-		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		petID := chi.URLParam(r, "petID")
+		p, access, ok := authorizePet(w, r, svc, grantsSvc, petID, accessgrants.ScopePetRead)
+		if !ok {
 			return
 		}
 
-		petID := chi.URLParam(r, "petID")
-		p, err := svc.GetByID(r.Context(), petID)
-		if err != nil {
-			http.Error(w, "pet not found", http.StatusNotFound)
+		// Lectura de delegado: queda en el access log (async, best-effort)
+		if access.IsDelegate() {
+			accessLog.Record(petID, access.Claims.UserID, accesslog.ResourcePetProfile)
+		}
+
+		httpjson.WriteJSONWithETag(w, r, toPetResponse(p, apitime.FromContext(r.Context()), svc.ageMonths(p)))
+	}
+}
+
+// lookupPetHandler godoc
+// @Summary Buscar mascota por microchip
+// @Description Devuelve la mascota con ese microchip si el usuario es su dueño o tiene un grant activo con scope `pet:read`. En cualquier otro caso (no existe, sin acceso) responde 404, para no revelar qué microchips están registrados. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param microchip query string true "Microchip a buscar (10-15 caracteres alfanuméricos)"
+// @Success 200 {object} petResponse
+// @Failure 400 {object} httpjson.ErrorBody "microchip inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/lookup [get]
+func lookupPetHandler(svc *Service, grantsSvc *accessgrants.Service, accessLog *accesslog.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var p Pet
+		var lookupErr error
+		access, err := authz.Authorize(r, grantsSvc, func(ctx context.Context) (authz.PetRef, bool) {
+			p, lookupErr = svc.LookupByMicrochip(ctx, r.URL.Query().Get("microchip"))
+			return authz.PetRef{ID: p.ID, OwnerUserID: p.OwnerUserID}, lookupErr == nil
+		}, accessgrants.ScopePetRead)
+		switch {
+		case errors.Is(lookupErr, ErrPetInvalidInput):
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "microchip must be 10-15 alphanumeric characters")
+			return
+		case lookupErr != nil && !errors.Is(lookupErr, ErrPetNotFound):
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			return
 		}
 
-		if p.OwnerUserID != claims.UserID {
-			g, err := grantsSvc.GetActiveGrant(r.Context(), petID, claims.UserID)
-			if err != nil || !accessgrants.HasScope(g, accessgrants.ScopePetRead) {
-				http.Error(w, "forbidden", http.StatusForbidden)
-				return
-			}
+		// Sin acceso responde igual que "no existe": no se revela que el microchip está registrado.
+		if authz.WriteDenied(w, access.Concealed(), err) {
+			return
+		}
+		if access.IsDelegate() {
+			accessLog.Record(p.ID, access.Claims.UserID, accesslog.ResourcePetProfile)
 		}
 
-		writeJSON(w, http.StatusOK, toPetResponse(p))
+		httpjson.WriteJSON(w, http.StatusOK, toPetResponse(p, apitime.FromContext(r.Context()), svc.ageMonths(p)))
 	}
 }
 
@@ -213,41 +579,26 @@ func getPetHandler(svc *Service, grantsSvc *accessgrants.Service) http.HandlerFu
 // @Param petID path string true "ID de la mascota"
 // @Param payload body updatePetRequest true "Campos a actualizar"
 // @Success 200 {object} petResponse
-// @Failure 403 {string} string "forbidden"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden; error.reason: no_grant | grant_not_active | missing_scope:<scope>"
+// @Failure 409 {object} httpjson.ErrorBody "error.code: microchip_taken (el microchip ya es de otra mascota)"
 // @Router /pets/{petID} [patch]
 func updatePetHandler(svc *Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
 	// Owner bypass, delegado requiere pet:edit_profile
 	return func(w http.ResponseWriter, r *http.Request) {
-		claims, ok := middleware.GetClaims(r.Context())
-		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
-			return
-		}
-
 		petID := chi.URLParam(r, "petID")
 
 		// Verifica existencia + ownership para auth
-		p, err := svc.GetByID(r.Context(), petID)
-		if err != nil {
-			http.Error(w, "pet not found", http.StatusNotFound)
+		if _, _, ok := authorizePet(w, r, svc, grantsSvc, petID, accessgrants.ScopePetEditProfile); !ok {
 			return
 		}
 
-		if p.OwnerUserID != claims.UserID {
-			g, err := grantsSvc.GetActiveGrant(r.Context(), petID, claims.UserID)
-			if err != nil || !accessgrants.HasScope(g, accessgrants.ScopePetEditProfile) {
-				http.Error(w, "forbidden", http.StatusForbidden)
-				return
-			}
-		}
-
 		dec := json.NewDecoder(r.Body)
 		dec.DisallowUnknownFields()
 
 		// Para soportar birth_date: null, detectamos presencia en raw map
 		var raw map[string]json.RawMessage
 		if err := dec.Decode(&raw); err != nil {
-			http.Error(w, "invalid json", http.StatusBadRequest)
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
 			return
 		}
 
@@ -256,7 +607,7 @@ func updatePetHandler(svc *Service, grantsSvc *accessgrants.Service) http.Handle
 		{
 			b, _ := json.Marshal(raw)
 			if err := json.Unmarshal(b, &req); err != nil {
-				http.Error(w, "invalid json", http.StatusBadRequest)
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
 				return
 			}
 		}
@@ -270,7 +621,7 @@ func updatePetHandler(svc *Service, grantsSvc *accessgrants.Service) http.Handle
 			} else {
 				var s string
 				if err := json.Unmarshal(v, &s); err != nil {
-					http.Error(w, "birth_date must be YYYY-MM-DD or null", http.StatusBadRequest)
+					httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "birth_date must be YYYY-MM-DD or null")
 					return
 				}
 				bdp.Value = &s
@@ -282,103 +633,485 @@ func updatePetHandler(svc *Service, grantsSvc *accessgrants.Service) http.Handle
 			Species:   req.Species,
 			Breed:     req.Breed,
 			Sex:       req.Sex,
+			Microchip: req.Microchip,
 			Notes:     req.Notes,
 			BirthDate: bdp,
 		})
 		if err != nil {
 			switch err {
 			case ErrPetInvalidInput:
-				http.Error(w, err.Error(), http.StatusBadRequest)
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			case ErrPetNotFound:
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+			case ErrMicrochipTaken:
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeMicrochipTaken, err.Error())
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, toPetResponse(updated, apitime.FromContext(r.Context()), svc.ageMonths(updated)))
+	}
+}
+
+// mergePetHandler godoc
+// @Summary Fusionar una mascota duplicada
+// @Description Fusiona `source_pet_id` en la mascota del path: mueve todos sus eventos y sus grants vigentes (activos e invitaciones pendientes), archiva el origen (deja de aparecer en listados) y registra un evento `PROFILE_UPDATED` en el destino. Si un delegado tenía grant en ambas queda uno solo (el activo antes que la invitación; a igual estado, el del destino) y el otro se revoca con sus sub-delegaciones (`grants_revoked`). Solo el owner de ambas mascotas puede hacerlo. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Accept json
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota destino"
+// @Param payload body mergePetRequest true "Mascota duplicada a fusionar"
+// @Success 200 {object} mergePetResponse
+// @Failure 400 {object} httpjson.ErrorBody "invalid json / source_pet_id requerido / merge consigo misma"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden (no es owner de ambas)"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 409 {object} httpjson.ErrorBody "pet archived"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/merge [post]
+func mergePetHandler(svc *Service, profileEvents ProfileEventRecorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		petID := chi.URLParam(r, "petID")
+
+		var req mergePetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
+			return
+		}
+		if strings.TrimSpace(req.SourcePetID) == "" {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "source_pet_id required")
+			return
+		}
+
+		target, res, err := svc.Merge(r.Context(), claims.UserID, petID, req.SourcePetID)
+		if err != nil {
+			switch err {
+			case ErrPetInvalidInput:
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
+			case ErrPetNotFound:
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+			case ErrPetForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			case ErrPetArchived:
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, err.Error())
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
+		}
+
+		// El merge ya se confirmó; el evento de perfil es best-effort (MVP).
+		if profileEvents != nil {
+			_ = profileEvents.RecordProfileEvent(r.Context(), target.ID, claims.UserID,
+				"Mascota fusionada",
+				fmt.Sprintf("Se fusionó la mascota duplicada %s: %d eventos y %d grants movidos", strings.TrimSpace(req.SourcePetID), res.EventsMoved, res.GrantsMoved),
+			)
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, mergePetResponse{
+			Pet:           toPetResponse(target, apitime.FromContext(r.Context()), svc.ageMonths(target)),
+			SourcePetID:   strings.TrimSpace(req.SourcePetID),
+			EventsMoved:   res.EventsMoved,
+			GrantsMoved:   res.GrantsMoved,
+			GrantsRevoked: res.GrantsRevoked,
+		})
+	}
+}
+
+// transferPetHandler godoc
+// @Summary Transferir una mascota a otro usuario
+// @Description Entrega la mascota de forma permanente a `new_owner_user_id`: cambia el owner, revoca los grants donde el nuevo owner era delegado (y sus sub-delegaciones), reasigna los grants vigentes restantes y registra un evento `PROFILE_UPDATED`. El owner anterior pierde el acceso. Solo el owner actual puede hacerlo. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Accept json
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Param payload body transferPetRequest true "Nuevo owner"
+// @Success 200 {object} transferPetResponse
+// @Failure 400 {object} httpjson.ErrorBody "invalid json / new_owner_user_id requerido / igual al owner actual"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden (no es owner)"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 409 {object} httpjson.ErrorBody "pet archived"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/transfer [post]
+func transferPetHandler(svc *Service, profileEvents ProfileEventRecorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		petID := chi.URLParam(r, "petID")
+
+		var req transferPetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "invalid json")
+			return
+		}
+		if strings.TrimSpace(req.NewOwnerUserID) == "" {
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "new_owner_user_id required")
+			return
+		}
+
+		p, res, err := svc.TransferOwnership(r.Context(), petID, claims.UserID, req.NewOwnerUserID)
+		if err != nil {
+			switch err {
+			case ErrPetInvalidInput:
+				httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, "new owner must differ from current owner")
 			case ErrPetNotFound:
-				http.Error(w, "pet not found", http.StatusNotFound)
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+			case ErrPetForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			case ErrPetArchived:
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, err.Error())
 			default:
-				http.Error(w, "internal error", http.StatusInternalServerError)
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
 			}
 			return
 		}
 
-		writeJSON(w, http.StatusOK, toPetResponse(updated))
+		// La transferencia ya se confirmó; el evento de perfil es best-effort (MVP).
+		if profileEvents != nil {
+			_ = profileEvents.RecordProfileEvent(r.Context(), p.ID, claims.UserID,
+				"Mascota transferida",
+				fmt.Sprintf("Owner cambiado de %s a %s", res.PreviousOwnerUserID, p.OwnerUserID),
+			)
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, transferPetResponse{
+			Pet:                 toPetResponse(p, apitime.FromContext(r.Context()), svc.ageMonths(p)),
+			PreviousOwnerUserID: res.PreviousOwnerUserID,
+			GrantsRevoked:       res.GrantsRevoked,
+		})
+	}
+}
+
+// archivePetHandler godoc
+// @Summary Archivar una mascota
+// @Description Marca la mascota como `archived` (p.ej. falleció o se dio en adopción): deja de aparecer en `GET /pets` (salvo `include_archived=true`) pero conserva eventos y grants y sigue accesible por ID. Idempotente. Solo el owner puede hacerlo; una mascota fusionada en otra responde 409. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {object} petResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden (no es owner)"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 409 {object} httpjson.ErrorBody "pet archived (fusionada)"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/archive [post]
+func archivePetHandler(svc *Service) http.HandlerFunc {
+	return petStatusHandler(svc, PetStatusArchived)
+}
+
+// unarchivePetHandler godoc
+// @Summary Desarchivar una mascota
+// @Description Vuelve la mascota a `active`: reaparece en `GET /pets`. Idempotente. Solo el owner puede hacerlo; una mascota fusionada en otra responde 409. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {object} petResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden (no es owner)"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 409 {object} httpjson.ErrorBody "pet archived (fusionada)"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID}/unarchive [post]
+func unarchivePetHandler(svc *Service) http.HandlerFunc {
+	return petStatusHandler(svc, PetStatusActive)
+}
+
+// petStatusHandler atiende archive (status archived) y unarchive (status active).
+func petStatusHandler(svc *Service, status PetStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		petID := chi.URLParam(r, "petID")
+
+		setStatus := svc.Unarchive
+		if status == PetStatusArchived {
+			setStatus = svc.Archive
+		}
+		p, err := setStatus(r.Context(), petID, claims.UserID)
+		if err != nil {
+			switch err {
+			case ErrPetInvalidInput, ErrPetNotFound:
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+			case ErrPetForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			case ErrPetArchived:
+				httpjson.WriteError(w, http.StatusConflict, httpjson.CodeBadState, err.Error())
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, toPetResponse(p, apitime.FromContext(r.Context()), svc.ageMonths(p)))
+	}
+}
+
+// deletePetResponse resume el borrado de una mascota.
+type deletePetResponse struct {
+	PetID         string `json:"pet_id"`
+	GrantsRevoked int    `json:"grants_revoked"`
+	EventsVoided  int    `json:"events_voided"`
+}
+
+// deletePetHandler godoc
+// @Summary Borrar una mascota
+// @Description Borra la mascota. Antes revoca todos sus grants (ningún delegado conserva acceso) y anula —sin borrar— todos sus eventos activos, para que el historial no quede huérfano. Solo el owner puede hacerlo. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Param petID path string true "ID de la mascota"
+// @Success 200 {object} deletePetResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 403 {object} httpjson.ErrorBody "forbidden (no es owner)"
+// @Failure 404 {object} httpjson.ErrorBody "pet not found"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /pets/{petID} [delete]
+func deletePetHandler(svc *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		petID := chi.URLParam(r, "petID")
+
+		res, err := svc.Delete(r.Context(), petID, claims.UserID)
+		if err != nil {
+			switch err {
+			case ErrPetInvalidInput, ErrPetNotFound:
+				httpjson.WriteError(w, http.StatusNotFound, httpjson.CodeNotFound, "pet not found")
+			case ErrPetForbidden:
+				httpjson.WriteError(w, http.StatusForbidden, httpjson.CodeForbidden, "forbidden")
+			default:
+				httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			}
+			return
+		}
+
+		httpjson.WriteJSON(w, http.StatusOK, deletePetResponse{
+			PetID:         strings.TrimSpace(petID),
+			GrantsRevoked: res.GrantsRevoked,
+			EventsVoided:  res.EventsVoided,
+		})
 	}
 }
 
 // listMySharedPetsHandler godoc
 // @Summary Listar mascotas compartidas conmigo
-// @Description Lista las mascotas compartidas con el usuario autenticado mediante grants activos que incluyan el scope `pet:read`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Description Lista las mascotas compartidas con el usuario autenticado mediante grants activos que incluyan el scope `pet:read`. Paginado por cursor sobre los grants (orden updated_at desc, id desc): si hay más la respuesta trae `has_more: true` e incluye `next_cursor`, que se pasa como `cursor` para pedir la página siguiente. Una página puede traer menos de `limit` mascotas (grants sin `pet:read` o de mascotas borradas no se muestran). Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
 // @Tags pets
 // @Produce json
 // @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
 // @Param Authorization header string false "Bearer token en producción"
-// @Success 200 {array} sharedPetResponse
+// @Param limit query int false "Máximo de grants por página (default 50, máx 200)"
+// @Param cursor query string false "Cursor opaco de la página siguiente (next_cursor de la respuesta anterior)"
+// @Success 200 {object} sharedPetListResponse
+// @Failure 400 {object} httpjson.ErrorBody "cursor inválido"
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
 // @Router /me/pets [get]
 func listMySharedPetsHandler(svc *Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetClaims(r.Context())
 		if !ok || strings.TrimSpace(claims.UserID) == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
 			return
 		}
 
-		grants, err := grantsSvc.ListByGrantee(r.Context(), claims.UserID)
+		filter, err := accessgrants.ParseGranteeFilter(r)
 		if err != nil {
-			http.Error(w, "internal error", http.StatusInternalServerError)
+			httpjson.WriteError(w, http.StatusBadRequest, httpjson.CodeInvalidInput, err.Error())
 			return
 		}
 
-		seen := map[string]struct{}{}
-		out := make([]sharedPetResponse, 0)
+		out, next, err := listSharedPets(r.Context(), svc, grantsSvc, claims.UserID, filter)
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
 
-		for _, g := range grants {
-			if g.Status != accessgrants.StatusActive {
-				continue
-			}
-			// Para mostrar perfil, exigimos pet:read
-			if !accessgrants.HasScope(g, accessgrants.ScopePetRead) {
-				continue
-			}
-			if _, ok := seen[g.PetID]; ok {
-				continue
-			}
-			seen[g.PetID] = struct{}{}
+		httpjson.WriteJSON(w, http.StatusOK, sharedPetListResponse{
+			Items:      out,
+			Count:      len(out),
+			Limit:      filter.Limit,
+			HasMore:    next != "",
+			NextCursor: next,
+		})
+	}
+}
 
-			p, err := svc.GetByID(r.Context(), g.PetID)
-			if err != nil {
+// listSharedPets arma las mascotas compartidas con userID: grants activos (no vencidos)
+// con pet:read, una entrada por mascota. Pagina sobre los grants (filter.Limit > 0) y recién
+// después resuelve las mascotas; con Limit 0 las devuelve todas (GET /me/pets/all).
+func listSharedPets(ctx context.Context, svc *Service, grantsSvc *accessgrants.Service, userID string, filter accessgrants.GranteeFilter) ([]sharedPetResponse, string, error) {
+	filter.Statuses = []accessgrants.Status{accessgrants.StatusActive}
+
+	var grants []accessgrants.Grant
+	next := ""
+	var err error
+	if filter.Limit > 0 {
+		grants, next, err = grantsSvc.ListByGranteePage(ctx, userID, filter)
+	} else {
+		grants, err = grantsSvc.ListByGrantee(ctx, userID, filter.Statuses...)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	seen := map[string]struct{}{}
+	out := make([]sharedPetResponse, 0)
+
+	now := time.Now()
+	for _, g := range grants {
+		if g.Status != accessgrants.StatusActive || g.ExpiredAt(now) {
+			continue
+		}
+		// Para mostrar perfil, exigimos pet:read
+		if !accessgrants.HasScope(g, accessgrants.ScopePetRead) {
+			continue
+		}
+		if _, ok := seen[g.PetID]; ok {
+			continue
+		}
+		seen[g.PetID] = struct{}{}
+
+		p, err := svc.GetByID(ctx, g.PetID)
+		if err != nil {
+			continue
+		}
+
+		out = append(out, sharedPetResponse{
+			Pet: toPetResponse(p, apitime.FromContext(ctx), svc.ageMonths(p)),
+			Grant: grantMini{
+				ID:     g.ID,
+				Status: g.Status,
+			},
+			Scopes: g.Scopes,
+		})
+	}
+	return out, next, nil
+}
+
+// listAllMyPetsHandler godoc
+// @Summary Listar mis mascotas (propias y compartidas)
+// @Description Une en una sola lista las mascotas propias (como `GET /pets`) y las compartidas conmigo (como `GET /me/pets`: grants activos con `pet:read`). Cada entrada indica `relationship` (`owner` o `delegate`); las de delegado traen además el grant y sus scopes. Primero las propias, luego las compartidas. Si una mascota apareciera por ambos caminos, se informa una sola vez como `owner`. Autenticación: `X-Debug-User-ID` (dev) o `Authorization: Bearer <token>` (prod).
+// @Tags pets
+// @Produce json
+// @Param X-Debug-User-ID header string false "Solo en modo dev, ID de usuario para depuración"
+// @Param Authorization header string false "Bearer token en producción"
+// @Success 200 {array} myPetResponse
+// @Failure 401 {object} httpjson.ErrorBody "unauthorized"
+// @Failure 500 {object} httpjson.ErrorBody "internal error"
+// @Router /me/pets/all [get]
+func listAllMyPetsHandler(svc *Service, grantsSvc *accessgrants.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok || strings.TrimSpace(claims.UserID) == "" {
+			httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+			return
+		}
+
+		owned, err := svc.ListByOwner(r.Context(), claims.UserID, ListFilter{})
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+		shared, _, err := listSharedPets(r.Context(), svc, grantsSvc, claims.UserID, accessgrants.GranteeFilter{})
+		if err != nil {
+			httpjson.WriteError(w, http.StatusInternalServerError, httpjson.CodeInternal, "internal error")
+			return
+		}
+
+		tf := apitime.FromContext(r.Context())
+		seen := make(map[string]struct{}, len(owned))
+		out := make([]myPetResponse, 0, len(owned)+len(shared))
+		for _, p := range owned {
+			seen[p.ID] = struct{}{}
+			out = append(out, myPetResponse{
+				Pet:          toPetResponse(p, tf, svc.ageMonths(p)),
+				Relationship: RelationshipOwner,
+			})
+		}
+		// Defensivo: un owner no debería tener grant sobre su propia mascota; si lo tiene, gana owner.
+		for _, sp := range shared {
+			if _, ok := seen[sp.Pet.ID]; ok {
 				continue
 			}
-
-			out = append(out, sharedPetResponse{
-				Pet: toPetResponse(p),
-				Grant: grantMini{
-					ID:     g.ID,
-					Status: g.Status,
-				},
-				Scopes: g.Scopes,
+			grant := sp.Grant
+			out = append(out, myPetResponse{
+				Pet:          sp.Pet,
+				Relationship: RelationshipDelegate,
+				Grant:        &grant,
+				Scopes:       sp.Scopes,
 			})
 		}
 
-		writeJSON(w, http.StatusOK, out)
+		httpjson.WriteJSON(w, http.StatusOK, out)
 	}
 }
 
-func toPetResponse(p Pet) petResponse {
-	return petResponse{
+// toPetResponse arma la respuesta; ageMonths viene de Service.ageMonths (nil sin birth_date).
+func toPetResponse(p Pet, tf apitime.Format, ageMonths *int) petResponse {
+	resp := petResponse{
 		ID:          p.ID,
 		OwnerUserID: p.OwnerUserID,
 		Name:        p.Name,
 		Species:     p.Species,
 		Breed:       p.Breed,
 		Sex:         p.Sex,
-		BirthDate:   p.BirthDate,
+		BirthDate:   apitime.NewPtr(p.BirthDate, tf),
+		Microchip:   p.Microchip,
 		Notes:       p.Notes,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
+		Status:      p.EffectiveStatus(),
+		CreatedAt:   apitime.New(p.CreatedAt, tf),
+		UpdatedAt:   apitime.New(p.UpdatedAt, tf),
+		ArchivedAt:  apitime.NewPtr(p.ArchivedAt, tf),
+		AgeMonths:   ageMonths,
 	}
+	if ageMonths != nil {
+		resp.AgeHuman = FormatAge(*ageMonths)
+	}
+	return resp
 }
 
-// writeJSON está duplicado intencionalmente en handlers de distintos módulos (pets/events/accessgrants)
-// para evitar crear paquetes/helpers compartidos demasiado pronto.
-// Si más adelante se repite en más módulos, recién conviene extraerlo a un helper común.
-func writeJSON(w http.ResponseWriter, status int, v any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(v)
+// authorizePet decide el acceso a la mascota petID con authz.Authorize (owner, o delegado con
+// scope). Si se niega ya respondió (401 / 404 / 403) y ok es false.
+func authorizePet(w http.ResponseWriter, r *http.Request, svc *Service, grantsSvc *accessgrants.Service, petID string, scope accessgrants.Scope) (Pet, authz.Decision, bool) {
+	var p Pet
+	access, err := authz.Authorize(r, grantsSvc, func(ctx context.Context) (authz.PetRef, bool) {
+		var err error
+		p, err = svc.GetByID(ctx, petID)
+		return authz.PetRef{ID: p.ID, OwnerUserID: p.OwnerUserID}, err == nil
+	}, scope)
+	if authz.WriteDenied(w, access, err) {
+		return Pet{}, access, false
+	}
+	return p, access, true
 }
@@ -48,11 +48,23 @@ const (
 	SexUnknown Sex = "unknown"
 )
 
+// PetStatus indica si la mascota está vigente o la archivó su owner.
+// @Enum active, archived
+type PetStatus string
+
+const (
+	PetStatusActive   PetStatus = "active"
+	PetStatusArchived PetStatus = "archived"
+)
+
 // Pet representa el perfil básico de una mascota registrada en el sistema.
 type Pet struct {
 	ID          string
 	OwnerUserID string
 
+	// TenantID aísla los datos entre tenants: solo lo ven callers del mismo tenant.
+	TenantID string
+
 	Name    string
 	Species Species // dog, cat
 	Breed   string  // Según especie (DogBreed o CatBreed)
@@ -65,4 +77,11 @@ type Pet struct {
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Status lo cambia el owner (archive/unarchive): archivada no aparece en GET /pets salvo
+	// ?include_archived=true, pero sigue accesible por ID. Vacío equivale a active.
+	Status PetStatus
+
+	// ArchivedAt se setea al fusionar la mascota en otra (merge); no aparece en listados.
+	ArchivedAt *time.Time
 }
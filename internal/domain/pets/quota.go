@@ -0,0 +1,61 @@
+package pets
+
+import (
+	"context"
+	"errors"
+
+	"pet-clinical-history/internal/ports/auth"
+	"pet-clinical-history/internal/ports/capabilities"
+)
+
+const (
+	// FeaturePetsMax es la cuota del plan con el máximo de mascotas por owner.
+	FeaturePetsMax = "pets:max"
+)
+
+var (
+	// ErrPetQuotaExceeded: el owner ya tiene tantas mascotas como permite su plan.
+	ErrPetQuotaExceeded = errors.New("pet quota exceeded")
+	// errPetQuotaUnavailable: el resolver falló y la cuota no tolera errores (fail closed).
+	errPetQuotaUnavailable = errors.New("pet quota unavailable")
+)
+
+// PetQuota limita cuántas mascotas puede tener un owner según su plan (FeaturePetsMax).
+// Resolver nil => sin límite (dev). Si el resolver falla, FailOpen=true deja crear igual;
+// false responde 503.
+type PetQuota struct {
+	Resolver capabilities.QuotaResolver
+	FailOpen bool
+}
+
+// check devuelve ErrPetQuotaExceeded si el owner no puede crear otra mascota.
+// Cuentan las mascotas del owner en el tenant, incluidas las archivadas por él (pueden volver
+// con unarchive); no las fusionadas ni las borradas.
+func (q PetQuota) check(ctx context.Context, svc *Service, claims auth.Claims) error {
+	if q.Resolver == nil {
+		return nil
+	}
+	limit, limited, err := q.Resolver.Quota(ctx, capabilities.CapabilityCheck{
+		ProjectKey: capabilities.ProjectKey,
+		TenantID:   claims.TenantID,
+		UserID:     claims.UserID,
+		FeatureKey: FeaturePetsMax,
+	})
+	if err != nil {
+		if q.FailOpen {
+			return nil
+		}
+		return errPetQuotaUnavailable
+	}
+	if !limited {
+		return nil
+	}
+	owned, err := svc.ListByOwner(ctx, claims.UserID, ListFilter{IncludeArchived: true})
+	if err != nil {
+		return err
+	}
+	if len(owned) >= limit {
+		return ErrPetQuotaExceeded
+	}
+	return nil
+}
@@ -1,10 +1,79 @@
 package pets
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// ListFilter acota el listado de mascotas de un owner. Los campos vacíos no filtran.
+type ListFilter struct {
+	// Species compara exacto contra la especie guardada.
+	Species Species
+	// Query busca como substring en el nombre, sin distinguir mayúsculas.
+	Query string
+	// IncludeArchived incluye las archivadas por el owner (Status archived). Las fusionadas
+	// (ArchivedAt) nunca se listan.
+	IncludeArchived bool
+
+	// Sort es el campo de orden; vacío => SortCreatedAt. Desc invierte el orden (default asc).
+	// El id desempata para que la paginación sea estable.
+	Sort ListSort
+	Desc bool
+
+	// Limit acota la cantidad de mascotas (0 => sin límite); Offset saltea las primeras.
+	Limit  int
+	Offset int
+}
+
+// ListSort es un campo de orden permitido para ListByOwner.
+type ListSort string
+
+const (
+	SortCreatedAt ListSort = "created_at"
+	SortName      ListSort = "name"
+	SortUpdatedAt ListSort = "updated_at"
+)
+
+// MaxListLimit es el máximo de mascotas por página de GET /pets.
+const MaxListLimit = 200
+
+func (s ListSort) Valid() bool {
+	switch s {
+	case SortCreatedAt, SortName, SortUpdatedAt:
+		return true
+	default:
+		return false
+	}
+}
+
+// Repository: las lecturas se acotan a tenantID; una mascota de otro tenant es not found.
+// Create/Update devuelven ErrMicrochipTaken si el microchip ya es de otra mascota vigente.
 type Repository interface {
 	Create(ctx context.Context, p Pet) error
 	Update(ctx context.Context, p Pet) error
-	GetByID(ctx context.Context, id string) (Pet, error)
-	ListByOwner(ctx context.Context, ownerUserID string) ([]Pet, error)
+	GetByID(ctx context.Context, tenantID, id string) (Pet, error)
+	ListByOwner(ctx context.Context, tenantID, ownerUserID string, filter ListFilter) ([]Pet, error)
+
+	// ExistsByMicrochip indica si alguna mascota ya tiene ese microchip (normalizado).
+	ExistsByMicrochip(ctx context.Context, microchip string) (bool, error)
+	// GetByMicrochip devuelve la mascota con ese microchip (normalizado) en tenantID.
+	GetByMicrochip(ctx context.Context, tenantID, microchip string) (Pet, error)
+
+	// Delete borra la mascota (en postgres es lógico, para no perder su historial por cascada).
+	// Después, GetByID devuelve not found. Si no existe, el not found del adapter.
+	Delete(ctx context.Context, id string) error
+}
+
+// MergeStore mueve los eventos y los grants vigentes de sourceID a targetID (según
+// accessgrants.PlanMerge, que deja un solo grant vigente por delegado) y archiva sourceID,
+// todo en una misma transacción (o equivalente en memoria).
+type MergeStore interface {
+	MergePets(ctx context.Context, sourceID, targetID string, at time.Time) (MergeResult, error)
+}
+
+// MergeResult resume qué se movió en un merge.
+type MergeResult struct {
+	EventsMoved   int
+	GrantsMoved   int
+	GrantsRevoked int // duplicados del mismo delegado (y sus sub-delegaciones)
 }
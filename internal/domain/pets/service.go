@@ -6,27 +6,79 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
+	"pet-clinical-history/internal/ids"
+	"pet-clinical-history/internal/platform/logger"
+	"pet-clinical-history/internal/ports/auth"
 )
 
 var (
 	ErrPetInvalidInput = errors.New("invalid input")
 	ErrPetNotFound     = errors.New("not found")
+	ErrPetForbidden    = errors.New("forbidden")
+	ErrPetArchived     = errors.New("pet archived")
+
+	// ErrMicrochipTaken: el microchip ya está registrado en otra mascota (identifica a un único animal).
+	ErrMicrochipTaken = errors.New("microchip already registered to another pet")
 )
 
 // Service agrupa casos de uso del dominio Pets.
 // Nota de consistencia: los casos de uso deben preferir s.now() (en lugar de time.Now())
 // para facilitar pruebas (mock del tiempo) y mantener el mismo patrón que otros módulos.
 type Service struct {
-	repo Repository
-	now  func() time.Time
+	repo      Repository
+	merges    MergeStore       // opcional: nil => Merge no disponible
+	grants    GrantRevoker     // opcional: nil => Delete no revoca grants
+	events    EventVoider      // opcional: nil => Delete no anula eventos
+	transfers GrantTransferer  // opcional: nil => TransferOwnership no ajusta grants
+	log       logger.Logger    // opcional: nil => sin logs de dominio
+	keys      IdempotencyStore // opcional: nil => CreateInput.IdempotencyKey se ignora
+	now       func() time.Time
+	ids       ids.Generator
+}
+
+// Option configura dependencias opcionales del Service.
+type Option func(*Service)
+
+// WithIDGenerator reemplaza el generador de IDs (default UUIDv4).
+func WithIDGenerator(g ids.Generator) Option {
+	return func(s *Service) { s.ids = g }
+}
+
+// WithLogger registra advertencias de datos inconsistentes (p.ej. birth_date futura).
+func WithLogger(l logger.Logger) Option {
+	return func(s *Service) { s.log = l }
+}
+
+// IdempotencyStore recuerda qué mascota creó cada Idempotency-Key.
+// Lo implementa idempotency.Service; se define aquí para que el Service no dependa del módulo.
+type IdempotencyStore interface {
+	Lookup(ctx context.Context, scope, userID, key string) (resourceID string, ok bool, err error)
+	Remember(ctx context.Context, scope, userID, key, resourceID string) error
+}
+
+// idempotencyScope separa las keys de creación de mascotas de las de otros endpoints.
+const idempotencyScope = "pets.create"
+
+// WithIdempotencyStore habilita CreateInput.IdempotencyKey (reintentos sin duplicados).
+func WithIdempotencyStore(k IdempotencyStore) Option {
+	return func(s *Service) { s.keys = k }
 }
 
-func NewService(repo Repository) *Service {
-	return &Service{
+// WithMergeStore habilita la fusión de mascotas duplicadas.
+func WithMergeStore(m MergeStore) Option {
+	return func(s *Service) { s.merges = m }
+}
+
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{
 		repo: repo,
 		now:  time.Now,
+		ids:  ids.UUIDv4(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 type CreateInput struct {
@@ -35,7 +87,30 @@ type CreateInput struct {
 	Breed     string
 	Sex       Sex
 	BirthDate *time.Time
+	Microchip string
 	Notes     string
+
+	// IdempotencyKey (opcional): un reintento con la misma key del mismo owner devuelve
+	// la mascota ya creada en lugar de crear otra.
+	IdempotencyKey string
+}
+
+// normalizeMicrochip valida que el microchip (si viene) tenga 10-15 caracteres alfanuméricos.
+// Se guarda en mayúsculas para que la búsqueda/comparación no dependa del casing.
+func normalizeMicrochip(raw string) (string, error) {
+	v := strings.ToUpper(strings.TrimSpace(raw))
+	if v == "" {
+		return "", nil
+	}
+	if len(v) < 10 || len(v) > 15 {
+		return "", ErrPetInvalidInput
+	}
+	for _, c := range v {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return "", ErrPetInvalidInput
+		}
+	}
+	return v, nil
 }
 
 func (s *Service) Create(ctx context.Context, ownerUserID string, in CreateInput) (Pet, error) {
@@ -48,17 +123,38 @@ func (s *Service) Create(ctx context.Context, ownerUserID string, in CreateInput
 		return Pet{}, ErrPetInvalidInput
 	}
 
+	microchip, err := normalizeMicrochip(in.Microchip)
+	if err != nil {
+		return Pet{}, err
+	}
+	species, err := normalizeSpecies(in.Species)
+	if err != nil {
+		return Pet{}, err
+	}
+	sex, err := normalizeSex(in.Sex)
+	if err != nil {
+		return Pet{}, err
+	}
+
+	key := strings.TrimSpace(in.IdempotencyKey)
+	if prev, ok, err := s.Replayed(ctx, ownerUserID, key); err != nil || ok {
+		return prev, err
+	}
+
 	now := s.now()
 
 	p := Pet{
-		ID:          uuid.NewString(),
+		ID:          s.ids.NewID(),
 		OwnerUserID: ownerUserID,
+		TenantID:    auth.TenantFromContext(ctx),
 		Name:        name,
-		Species:     Species(strings.TrimSpace(string(in.Species))),
+		Species:     species,
 		Breed:       strings.TrimSpace(in.Breed),
-		Sex:         Sex(strings.TrimSpace(string(in.Sex))),
+		Sex:         sex,
 		BirthDate:   in.BirthDate,
+		Microchip:   microchip,
 		Notes:       strings.TrimSpace(in.Notes),
+		Status:      PetStatusActive,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -66,27 +162,92 @@ func (s *Service) Create(ctx context.Context, ownerUserID string, in CreateInput
 	if err := s.repo.Create(ctx, p); err != nil {
 		return Pet{}, err
 	}
+	if key != "" && s.keys != nil {
+		// best-effort: la mascota ya se creó; sin registro, un reintento crearía otra.
+		_ = s.keys.Remember(ctx, idempotencyScope, ownerUserID, key, p.ID)
+	}
 	return p, nil
 }
 
+// Replayed devuelve la mascota que ya creó ownerUserID con key, si la key sigue vigente.
+// Si la mascota ya no existe (p.ej. se borró), la key queda libre para crear una nueva.
+// Sin key o sin IdempotencyStore => ok=false.
+func (s *Service) Replayed(ctx context.Context, ownerUserID, key string) (Pet, bool, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || s.keys == nil {
+		return Pet{}, false, nil
+	}
+	id, ok, err := s.keys.Lookup(ctx, idempotencyScope, strings.TrimSpace(ownerUserID), key)
+	if err != nil || !ok {
+		return Pet{}, false, err
+	}
+	p, err := s.GetByID(ctx, id)
+	if err != nil {
+		return Pet{}, false, nil
+	}
+	return p, true, nil
+}
+
 func (s *Service) GetByID(ctx context.Context, id string) (Pet, error) {
 	id = strings.TrimSpace(id)
 	if id == "" {
 		return Pet{}, ErrPetInvalidInput
 	}
-	p, err := s.repo.GetByID(ctx, id)
+	p, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), id)
 	if err != nil {
 		return Pet{}, err
 	}
 	return p, nil
 }
 
-func (s *Service) ListByOwner(ctx context.Context, ownerUserID string) ([]Pet, error) {
+func (s *Service) ListByOwner(ctx context.Context, ownerUserID string, filter ListFilter) ([]Pet, error) {
 	ownerUserID = strings.TrimSpace(ownerUserID)
 	if ownerUserID == "" {
 		return nil, ErrPetInvalidInput
 	}
-	return s.repo.ListByOwner(ctx, ownerUserID)
+	filter.Species = Species(strings.ToLower(strings.TrimSpace(string(filter.Species))))
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Sort == "" {
+		filter.Sort = SortCreatedAt
+	}
+	if !filter.Sort.Valid() || filter.Limit < 0 || filter.Limit > MaxListLimit || filter.Offset < 0 {
+		return nil, ErrPetInvalidInput
+	}
+	return s.repo.ListByOwner(ctx, auth.TenantFromContext(ctx), ownerUserID, filter)
+}
+
+// MicrochipAvailable indica si el microchip aún no está registrado en ninguna mascota.
+// Un microchip vacío o con formato inválido devuelve ErrPetInvalidInput.
+func (s *Service) MicrochipAvailable(ctx context.Context, microchip string) (bool, error) {
+	v, err := normalizeMicrochip(microchip)
+	if err != nil {
+		return false, err
+	}
+	if v == "" {
+		return false, ErrPetInvalidInput
+	}
+	exists, err := s.repo.ExistsByMicrochip(ctx, v)
+	if err != nil {
+		return false, err
+	}
+	return !exists, nil
+}
+
+// LookupByMicrochip busca la mascota con ese microchip. Un microchip vacío o inválido devuelve
+// ErrPetInvalidInput; sin coincidencia, ErrPetNotFound. Los permisos los decide el caller.
+func (s *Service) LookupByMicrochip(ctx context.Context, microchip string) (Pet, error) {
+	v, err := normalizeMicrochip(microchip)
+	if err != nil {
+		return Pet{}, err
+	}
+	if v == "" {
+		return Pet{}, ErrPetInvalidInput
+	}
+	p, err := s.repo.GetByMicrochip(ctx, auth.TenantFromContext(ctx), v)
+	if err != nil {
+		return Pet{}, ErrPetNotFound
+	}
+	return p, nil
 }
 
 // BirthDatePatch permite PATCH real diferenciando:
@@ -103,6 +264,7 @@ type UpdateProfileInput struct {
 	Species   *Species
 	Breed     *string
 	Sex       *Sex
+	Microchip *string // "" limpia el valor
 	BirthDate BirthDatePatch
 	Notes     *string
 }
@@ -113,7 +275,7 @@ func (s *Service) UpdateProfile(ctx context.Context, petID string, in UpdateProf
 		return Pet{}, ErrPetInvalidInput
 	}
 
-	p, err := s.repo.GetByID(ctx, petID)
+	p, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), petID)
 	if err != nil {
 		return Pet{}, ErrPetNotFound
 	}
@@ -126,13 +288,28 @@ func (s *Service) UpdateProfile(ctx context.Context, petID string, in UpdateProf
 		p.Name = v
 	}
 	if in.Species != nil {
-		p.Species = Species(strings.TrimSpace(string(*in.Species)))
+		v, err := normalizeSpecies(*in.Species)
+		if err != nil {
+			return Pet{}, err
+		}
+		p.Species = v
 	}
 	if in.Breed != nil {
 		p.Breed = strings.TrimSpace(*in.Breed)
 	}
 	if in.Sex != nil {
-		p.Sex = Sex(strings.TrimSpace(string(*in.Sex)))
+		v, err := normalizeSex(*in.Sex)
+		if err != nil {
+			return Pet{}, err
+		}
+		p.Sex = v
+	}
+	if in.Microchip != nil {
+		v, err := normalizeMicrochip(*in.Microchip)
+		if err != nil {
+			return Pet{}, err
+		}
+		p.Microchip = v
 	}
 	if in.Notes != nil {
 		p.Notes = strings.TrimSpace(*in.Notes)
@@ -161,3 +338,39 @@ func (s *Service) UpdateProfile(ctx context.Context, petID string, in UpdateProf
 	}
 	return p, nil
 }
+
+// Merge fusiona sourceID en targetID: mueve eventos y grants activos y archiva el origen.
+// Ambas mascotas deben pertenecer a ownerUserID y no estar archivadas.
+func (s *Service) Merge(ctx context.Context, ownerUserID, targetID, sourceID string) (Pet, MergeResult, error) {
+	ownerUserID = strings.TrimSpace(ownerUserID)
+	targetID = strings.TrimSpace(targetID)
+	sourceID = strings.TrimSpace(sourceID)
+
+	if ownerUserID == "" || targetID == "" || sourceID == "" || targetID == sourceID {
+		return Pet{}, MergeResult{}, ErrPetInvalidInput
+	}
+	if s.merges == nil {
+		return Pet{}, MergeResult{}, errors.New("merge not configured")
+	}
+
+	target, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), targetID)
+	if err != nil {
+		return Pet{}, MergeResult{}, ErrPetNotFound
+	}
+	source, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), sourceID)
+	if err != nil {
+		return Pet{}, MergeResult{}, ErrPetNotFound
+	}
+	if target.OwnerUserID != ownerUserID || source.OwnerUserID != ownerUserID {
+		return Pet{}, MergeResult{}, ErrPetForbidden
+	}
+	if target.ArchivedAt != nil || source.ArchivedAt != nil {
+		return Pet{}, MergeResult{}, ErrPetArchived
+	}
+
+	res, err := s.merges.MergePets(ctx, sourceID, targetID, s.now())
+	if err != nil {
+		return Pet{}, MergeResult{}, err
+	}
+	return target, res, nil
+}
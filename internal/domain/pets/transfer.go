@@ -0,0 +1,65 @@
+package pets
+
+import (
+	"context"
+	"strings"
+
+	"pet-clinical-history/internal/ports/auth"
+)
+
+// GrantTransferer ajusta los grants de una mascota que cambia de owner.
+// Lo implementa accessgrants.Service; se define aquí para que el Service no dependa del módulo.
+type GrantTransferer interface {
+	TransferPet(ctx context.Context, petID, newOwnerUserID string) (int, error)
+}
+
+// WithGrantTransferer habilita el ajuste de grants al transferir una mascota.
+func WithGrantTransferer(g GrantTransferer) Option {
+	return func(s *Service) { s.transfers = g }
+}
+
+// TransferResult resume el cambio de owner de una mascota.
+type TransferResult struct {
+	PreviousOwnerUserID string
+	GrantsRevoked       int
+}
+
+// TransferOwnership entrega la mascota a otro usuario de forma permanente (solo el owner actual).
+// Revoca los grants donde el nuevo owner era delegado para que no sea su propio delegado.
+// MVP: los pasos no son atómicos; el cambio de owner se confirma primero.
+func (s *Service) TransferOwnership(ctx context.Context, petID, currentOwnerID, newOwnerID string) (Pet, TransferResult, error) {
+	petID = strings.TrimSpace(petID)
+	currentOwnerID = strings.TrimSpace(currentOwnerID)
+	newOwnerID = strings.TrimSpace(newOwnerID)
+	if petID == "" || currentOwnerID == "" || newOwnerID == "" {
+		return Pet{}, TransferResult{}, ErrPetInvalidInput
+	}
+
+	p, err := s.repo.GetByID(ctx, auth.TenantFromContext(ctx), petID)
+	if err != nil {
+		return Pet{}, TransferResult{}, ErrPetNotFound
+	}
+	if p.OwnerUserID != currentOwnerID {
+		return Pet{}, TransferResult{}, ErrPetForbidden
+	}
+	if newOwnerID == currentOwnerID {
+		return Pet{}, TransferResult{}, ErrPetInvalidInput
+	}
+	if p.ArchivedAt != nil {
+		return Pet{}, TransferResult{}, ErrPetArchived
+	}
+
+	p.OwnerUserID = newOwnerID
+	p.UpdatedAt = s.now()
+	if err := s.repo.Update(ctx, p); err != nil {
+		return Pet{}, TransferResult{}, err
+	}
+
+	res := TransferResult{PreviousOwnerUserID: currentOwnerID}
+	if s.transfers != nil {
+		if res.GrantsRevoked, err = s.transfers.TransferPet(ctx, petID, newOwnerID); err != nil {
+			return Pet{}, TransferResult{}, err
+		}
+	}
+	return p, res, nil
+}
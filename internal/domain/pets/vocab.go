@@ -0,0 +1,160 @@
+package pets
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"pet-clinical-history/internal/platform/httpjson"
+)
+
+// AllowedSpecies es el vocabulario de especies que aceptan Create/UpdateProfile y que expone
+// GET /vocab/species, en el orden en que se listan. Variable de paquete para poder extenderla
+// (agregando también su etiqueta en VocabLabels).
+var AllowedSpecies = []Species{SpeciesDog, SpeciesCat}
+
+// AllowedSexes es el vocabulario de sexos (GET /vocab/sex); extensible como AllowedSpecies.
+var AllowedSexes = []Sex{SexMale, SexFemale, SexUnknown}
+
+// DefaultVocabLanguage es el idioma de las etiquetas si Accept-Language no pide uno soportado.
+const DefaultVocabLanguage = "es"
+
+// VocabLabels son las etiquetas por idioma (subtag primario de Accept-Language) y valor.
+// Un valor sin etiqueta en el idioma elegido se muestra tal cual.
+var VocabLabels = map[string]map[string]string{
+	"es": {
+		string(SpeciesDog): "Perro",
+		string(SpeciesCat): "Gato",
+		string(SexMale):    "Macho",
+		string(SexFemale):  "Hembra",
+		string(SexUnknown): "Desconocido",
+	},
+	"en": {
+		string(SpeciesDog): "Dog",
+		string(SpeciesCat): "Cat",
+		string(SexMale):    "Male",
+		string(SexFemale):  "Female",
+		string(SexUnknown): "Unknown",
+	},
+}
+
+// normalizeSpecies valida contra AllowedSpecies (sin distinguir mayúsculas). Vacío = sin especificar.
+func normalizeSpecies(raw Species) (Species, error) {
+	v := Species(strings.ToLower(strings.TrimSpace(string(raw))))
+	if v == "" {
+		return "", nil
+	}
+	for _, s := range AllowedSpecies {
+		if v == s {
+			return v, nil
+		}
+	}
+	return "", ErrPetInvalidInput
+}
+
+// normalizeSex valida contra AllowedSexes (sin distinguir mayúsculas). Vacío = sin especificar.
+func normalizeSex(raw Sex) (Sex, error) {
+	v := Sex(strings.ToLower(strings.TrimSpace(string(raw))))
+	if v == "" {
+		return "", nil
+	}
+	for _, s := range AllowedSexes {
+		if v == s {
+			return v, nil
+		}
+	}
+	return "", ErrPetInvalidInput
+}
+
+// vocabItem es un valor permitido con su etiqueta localizada.
+type vocabItem struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// vocabResponse es un vocabulario en el idioma elegido.
+type vocabResponse struct {
+	Language string      `json:"language"`
+	Items    []vocabItem `json:"items"`
+}
+
+// vocabLanguage elige el idioma de las etiquetas según Accept-Language (por q, luego orden).
+func vocabLanguage(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var cands []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			cands = append(cands, candidate{lang: lang, q: q})
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].q > cands[j].q })
+	for _, c := range cands {
+		if _, ok := VocabLabels[c.lang]; ok {
+			return c.lang
+		}
+	}
+	return DefaultVocabLanguage
+}
+
+func writeVocab(w http.ResponseWriter, r *http.Request, values []string) {
+	lang := vocabLanguage(r.Header.Get("Accept-Language"))
+	labels := VocabLabels[lang]
+	items := make([]vocabItem, 0, len(values))
+	for _, v := range values {
+		label := labels[v]
+		if label == "" {
+			label = v
+		}
+		items = append(items, vocabItem{Value: v, Label: label})
+	}
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	httpjson.WriteJSON(w, http.StatusOK, vocabResponse{Language: lang, Items: items})
+}
+
+// speciesVocabHandler godoc
+// @Summary Vocabulario de especies
+// @Description Devuelve las especies que acepta la API (`species` al crear/editar una mascota) con su etiqueta en el idioma pedido por `Accept-Language` (es, en; default es). No requiere usuario.
+// @Tags vocab
+// @Produce json
+// @Param Accept-Language header string false "Idioma de las etiquetas (p.ej. en-US,es;q=0.8)"
+// @Success 200 {object} vocabResponse
+// @Router /vocab/species [get]
+func speciesVocabHandler(w http.ResponseWriter, r *http.Request) {
+	values := make([]string, 0, len(AllowedSpecies))
+	for _, s := range AllowedSpecies {
+		values = append(values, string(s))
+	}
+	writeVocab(w, r, values)
+}
+
+// sexVocabHandler godoc
+// @Summary Vocabulario de sexos
+// @Description Devuelve los valores de `sex` que acepta la API con su etiqueta en el idioma pedido por `Accept-Language` (es, en; default es). No requiere usuario.
+// @Tags vocab
+// @Produce json
+// @Param Accept-Language header string false "Idioma de las etiquetas (p.ej. en-US,es;q=0.8)"
+// @Success 200 {object} vocabResponse
+// @Router /vocab/sex [get]
+func sexVocabHandler(w http.ResponseWriter, r *http.Request) {
+	values := make([]string, 0, len(AllowedSexes))
+	for _, s := range AllowedSexes {
+		values = append(values, string(s))
+	}
+	writeVocab(w, r, values)
+}
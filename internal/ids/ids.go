@@ -0,0 +1,96 @@
+// Package ids genera los IDs de las entidades. Los services reciben un Generator
+// (default UUIDv4) para poder usar IDs deterministas en tests u ordenables (ULID) en prod.
+package ids
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Generator crea IDs nuevos. Debe ser seguro para uso concurrente.
+type Generator interface {
+	NewID() string
+}
+
+// GeneratorFunc adapta una función a Generator.
+type GeneratorFunc func() string
+
+func (f GeneratorFunc) NewID() string { return f() }
+
+// UUIDv4 es el generador por defecto (IDs aleatorios, sin orden).
+func UUIDv4() Generator {
+	return GeneratorFunc(uuid.NewString)
+}
+
+// ParseScheme devuelve el generador para un esquema configurado ("uuid" | "ulid").
+// ok=false si el esquema no se reconoce.
+func ParseScheme(raw string) (Generator, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "uuid", "uuidv4":
+		return UUIDv4(), true
+	case "ulid":
+		return ULID(), true
+	default:
+		return nil, false
+	}
+}
+
+// ULID genera IDs ordenables por tiempo (26 chars Crockford base32):
+// el orden lexicográfico sigue al orden de creación (resolución de milisegundos).
+func ULID() Generator {
+	return ULIDWithClock(time.Now)
+}
+
+// ULIDWithClock es ULID con reloj inyectable (tests).
+func ULIDWithClock(now func() time.Time) Generator {
+	return GeneratorFunc(func() string {
+		return newULID(now())
+	})
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func newULID(t time.Time) string {
+	var b [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+	// crypto/rand.Read no falla en plataformas soportadas.
+	_, _ = rand.Read(b[6:])
+
+	// 128 bits => 26 caracteres de 5 bits (los 2 bits altos del primero quedan en 0).
+	var out [26]byte
+	var acc uint64
+	bits := 2 // padding inicial: 130 - 128
+	idx := 0
+	for _, v := range b {
+		acc = acc<<8 | uint64(v)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[idx] = crockford[(acc>>uint(bits))&0x1f]
+			idx++
+		}
+	}
+	return string(out[:])
+}
+
+// Sequence genera IDs deterministas prefix000001, prefix000002, ... (tests).
+// El padding hace que también sean ordenables.
+func Sequence(prefix string) Generator {
+	var mu sync.Mutex
+	n := 0
+	return GeneratorFunc(func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return fmt.Sprintf("%s%06d", prefix, n)
+	})
+}
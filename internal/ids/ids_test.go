@@ -0,0 +1,74 @@
+package ids
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSequence_IsPredictable(t *testing.T) {
+	g := Sequence("evt-")
+
+	want := []string{"evt-000001", "evt-000002", "evt-000003"}
+	for _, w := range want {
+		if got := g.NewID(); got != w {
+			t.Fatalf("expected %s, got %s", w, got)
+		}
+	}
+}
+
+func TestULID_SortsByCreationTime(t *testing.T) {
+	clock := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	g := ULIDWithClock(func() time.Time {
+		clock = clock.Add(time.Millisecond)
+		return clock
+	})
+
+	created := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		id := g.NewID()
+		if len(id) != 26 {
+			t.Fatalf("expected 26-char ULID, got %q", id)
+		}
+		created = append(created, id)
+	}
+
+	// El orden lexicográfico coincide con el de creación.
+	sorted := append([]string(nil), created...)
+	sort.Strings(sorted)
+	for i := range created {
+		if sorted[i] != created[i] {
+			t.Fatalf("ULIDs not sortable by creation: position %d", i)
+		}
+	}
+
+	// Keyset: "id > cursor" devuelve exactamente lo creado después del cursor.
+	cursor := created[19]
+	var page []string
+	for _, id := range sorted {
+		if id > cursor && len(page) < 10 {
+			page = append(page, id)
+		}
+	}
+	if len(page) != 10 || page[0] != created[20] || page[9] != created[29] {
+		t.Fatalf("expected keyset page created[20:30], got %v", page)
+	}
+}
+
+func TestUUIDv4_IsNotSortable(t *testing.T) {
+	// Contraste: UUIDv4 no garantiza orden, por eso keyset por id necesita ULID.
+	g := UUIDv4()
+	a, b := g.NewID(), g.NewID()
+	if a == b || len(a) != 36 {
+		t.Fatalf("expected distinct uuids, got %q %q", a, b)
+	}
+}
+
+func TestParseScheme(t *testing.T) {
+	if _, ok := ParseScheme("ULID"); !ok {
+		t.Fatalf("expected ulid scheme to be accepted")
+	}
+	if _, ok := ParseScheme("snowflake"); ok {
+		t.Fatalf("expected unknown scheme to be rejected")
+	}
+}
@@ -14,7 +14,10 @@ const claimsKey ctxKey = "claims"
 
 // AuthContext:
 // - Si verifier != nil y viene Bearer token => intenta Verify() y setea claims.
-// - Si verifier == nil => modo dev: si viene header X-Debug-User-ID => setea claims.
+// - Si verifier == nil => modo dev: si viene header X-Debug-User-ID => setea claims
+// (opcionales: X-Debug-Email, el email; X-Debug-Tenant-ID, el tenant; X-Debug-Integration-System
+// simula un token de integración). Con verifier esos headers se ignoran.
+// - El tenant de los claims se propaga al context (auth.TenantFromContext) para aislar los datos.
 // - Si no hay claims, el request sigue igual; los handlers decidirán si exigen auth.
 func AuthContext(verifier auth.AuthVerifier) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -22,8 +25,14 @@ func AuthContext(verifier auth.AuthVerifier) func(http.Handler) http.Handler {
 			// Dev mode: permitir inyectar user sin verifier
 			if verifier == nil {
 				if uid := strings.TrimSpace(r.Header.Get("X-Debug-User-ID")); uid != "" {
-					claims := auth.Claims{UserID: uid}
-					ctx := context.WithValue(r.Context(), claimsKey, claims)
+					claims := auth.Claims{
+						UserID:            uid,
+						Email:             strings.TrimSpace(r.Header.Get("X-Debug-Email")),
+						TenantID:          strings.TrimSpace(r.Header.Get("X-Debug-Tenant-ID")),
+						IntegrationSystem: strings.TrimSpace(r.Header.Get("X-Debug-Integration-System")),
+					}
+					setRequestLogUser(r.Context(), uid)
+					ctx := withClaims(r.Context(), claims)
 					next.ServeHTTP(w, r.WithContext(ctx))
 					return
 				}
@@ -46,12 +55,18 @@ func AuthContext(verifier auth.AuthVerifier) func(http.Handler) http.Handler {
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), claimsKey, claims)
+			setRequestLogUser(r.Context(), claims.UserID)
+			ctx := withClaims(r.Context(), claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+func withClaims(ctx context.Context, claims auth.Claims) context.Context {
+	ctx = context.WithValue(ctx, claimsKey, claims)
+	return auth.WithTenant(ctx, claims.TenantID)
+}
+
 func GetClaims(ctx context.Context) (auth.Claims, bool) {
 	v := ctx.Value(claimsKey)
 	if v == nil {
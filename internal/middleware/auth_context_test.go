@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/ports/auth"
+)
+
+// claimsHandler guarda los claims que ve el handler (vía GetClaims).
+func claimsHandler(got *auth.Claims, ok *bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*got, *ok = GetClaims(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+type rejectVerifier struct{}
+
+func (rejectVerifier) Verify(context.Context, string) (auth.Claims, error) {
+	return auth.Claims{}, errors.New("invalid token")
+}
+
+func TestAuthContext_DevHeaders(t *testing.T) {
+	var got auth.Claims
+	var ok bool
+	h := AuthContext(nil)(claimsHandler(&got, &ok))
+
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("X-Debug-User-ID", "user-1")
+	req.Header.Set("X-Debug-Email", " user-1@example.com ")
+	req.Header.Set("X-Debug-Tenant-ID", "tenant-a")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := auth.Claims{UserID: "user-1", Email: "user-1@example.com", TenantID: "tenant-a"}
+	if !ok || got != want {
+		t.Fatalf("expected claims %+v, got %+v (ok=%v)", want, got, ok)
+	}
+
+	// Sin X-Debug-User-ID los demás headers no alcanzan para autenticar
+	got, ok = auth.Claims{}, false
+	req = httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("X-Debug-Email", "user-1@example.com")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if ok {
+		t.Fatalf("expected no claims without X-Debug-User-ID, got %+v", got)
+	}
+}
+
+func TestAuthContext_DevHeadersIgnoredWithVerifier(t *testing.T) {
+	var got auth.Claims
+	var ok bool
+	h := AuthContext(rejectVerifier{})(claimsHandler(&got, &ok))
+
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("X-Debug-User-ID", "user-1")
+	req.Header.Set("X-Debug-Email", "user-1@example.com")
+	req.Header.Set("X-Debug-Tenant-ID", "tenant-a")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if ok {
+		t.Fatalf("expected dev headers ignored with a verifier, got %+v", got)
+	}
+}
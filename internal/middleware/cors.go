@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCORSMethods son los métodos permitidos si CORSOptions.AllowedMethods viene vacío.
+var DefaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete}
+
+// DefaultCORSHeaders son los headers de request permitidos si CORSOptions.AllowedHeaders viene vacío
+// (incluye los de autenticación dev/prod y Idempotency-Key).
+var DefaultCORSHeaders = []string{
+	"Accept",
+	"Authorization",
+	"Content-Type",
+	"Idempotency-Key",
+	"X-Debug-User-ID",
+	"X-Debug-Email",
+	"X-Debug-Integration-System",
+	"X-Debug-Tenant-ID",
+}
+
+// CORSOptions configura CORS para clientes de browser (SPA).
+// Sin AllowedOrigins no se permite ningún origen (deny-all): CORS es opt-in.
+type CORSOptions struct {
+	// AllowedOrigins son los orígenes exactos permitidos (p.ej. "https://app.example.com").
+	// "*" permite cualquiera; con AllowCredentials se devuelve el origen del request en vez de "*".
+	AllowedOrigins []string
+
+	// AllowedMethods vacío => DefaultCORSMethods.
+	AllowedMethods []string
+
+	// AllowedHeaders vacío => DefaultCORSHeaders.
+	AllowedHeaders []string
+
+	// ExposedHeaders son los headers de respuesta que el browser deja leer al cliente.
+	ExposedHeaders []string
+
+	// AllowCredentials permite cookies / Authorization en requests cross-origin.
+	AllowCredentials bool
+
+	// MaxAge es cuánto puede cachear el browser un preflight; 0 => sin header.
+	MaxAge time.Duration
+}
+
+// CORS agrega los headers CORS a las respuestas de orígenes permitidos y responde los
+// preflight (OPTIONS con Access-Control-Request-Method) con 204 sin pasar al resto de la
+// cadena, así no los corta la autenticación. Debe montarse antes de AuthContext / RequireAuth.
+// Los requests sin Origin (mismo origen, curl, server-to-server) pasan sin cambios.
+func CORS(opts CORSOptions) func(http.Handler) http.Handler {
+	origins := make(map[string]struct{}, len(opts.AllowedOrigins))
+	anyOrigin := false
+	for _, o := range opts.AllowedOrigins {
+		o = strings.TrimSpace(o)
+		if o == "*" {
+			anyOrigin = true
+			continue
+		}
+		if o != "" {
+			origins[o] = struct{}{}
+		}
+	}
+
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+	headers := opts.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultCORSHeaders
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	exposeHeaders := strings.Join(opts.ExposedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		// Deny-all: sin orígenes configurados el middleware no hace nada.
+		if !anyOrigin && len(origins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			_, listed := origins[origin]
+			if !anyOrigin && !listed {
+				// Origen no permitido: sin headers CORS el browser bloquea la respuesta.
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if anyOrigin && !opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if preflight {
+				h.Set("Access-Control-Allow-Methods", allowMethods)
+				h.Set("Access-Control-Allow-Headers", allowHeaders)
+				if opts.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if exposeHeaders != "" {
+				h.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
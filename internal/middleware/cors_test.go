@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+func TestCORS_PreflightAndSimpleRequest(t *testing.T) {
+	h := CORS(CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})(okHandler())
+
+	// Preflight de un origen permitido: 204, sin llegar al handler
+	req := httptest.NewRequest(http.MethodOptions, "/pets", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "X-Debug-User-ID, Content-Type")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Fatalf("expected empty 204 preflight, got %d body=%q", rec.Code, rec.Body.String())
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET, POST, PATCH, DELETE",
+		"Access-Control-Allow-Headers":     "Accept, Authorization, Content-Type, Idempotency-Key, X-Debug-User-ID, X-Debug-Email, X-Debug-Integration-System, X-Debug-Tenant-ID",
+		"Access-Control-Max-Age":           "600",
+	}
+	for k, v := range want {
+		if got := rec.Header().Get(k); got != v {
+			t.Fatalf("preflight %s: expected %q, got %q", k, v, got)
+		}
+	}
+
+	// Request simple: pasa al handler con Allow-Origin y Expose-Headers
+	req = httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("expected handler response, got %d body=%q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected Allow-Origin, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-ID" {
+		t.Fatalf("expected Expose-Headers, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "" {
+		t.Fatalf("expected no Allow-Methods outside preflight, got %q", got)
+	}
+	if got := rec.Header().Values("Vary"); len(got) == 0 || got[0] != "Origin" {
+		t.Fatalf("expected Vary: Origin, got %v", got)
+	}
+}
+
+func TestCORS_DisallowedOriginAndDefaults(t *testing.T) {
+	h := CORS(CORSOptions{AllowedOrigins: []string{"https://app.example.com"}})(okHandler())
+
+	// Origen no listado: sin headers CORS (el browser bloquea)
+	for _, method := range []string{http.MethodOptions, http.MethodGet} {
+		req := httptest.NewRequest(method, "/pets", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		req.Header.Set("Access-Control-Request-Method", "GET")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Fatalf("%s: expected no Allow-Origin for other origin, got %q", method, got)
+		}
+	}
+
+	// Sin credenciales y con "*": Allow-Origin literal
+	wild := CORS(CORSOptions{AllowedOrigins: []string{"*"}})(okHandler())
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	rec := httptest.NewRecorder()
+	wild.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Fatalf("expected *, got %q", got)
+	}
+
+	// Default deny-all: nada configurado => sin headers
+	deny := CORS(CORSOptions{})(okHandler())
+	rec = httptest.NewRecorder()
+	deny.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" || rec.Code != http.StatusOK {
+		t.Fatalf("expected deny-all passthrough, got %d Allow-Origin=%q", rec.Code, got)
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"pet-clinical-history/internal/platform/httpjson"
+)
+
+// DefaultMaxBodyBytes es el tamaño máximo del cuerpo de un request (1 MiB).
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// MaxBodyBytes acota el cuerpo de cada request a n bytes (http.MaxBytesReader), para que un
+// JSON gigante no agote la memoria al decodificarlo. Un Content-Length mayor a n se rechaza
+// antes de llegar al handler; si no (chunked), el handler ve un error de lectura al pasar el
+// límite y su respuesta (típicamente 400 "invalid json") se reemplaza por 413 con el cuerpo
+// de error estándar (httpjson.CodePayloadTooLarge). n <= 0 => sin límite.
+func MaxBodyBytes(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if n <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				writeTooLarge(w)
+				return
+			}
+
+			body := &maxBytesBody{ReadCloser: http.MaxBytesReader(w, r.Body, n)}
+			r.Body = body
+			next.ServeHTTP(&maxBytesWriter{ResponseWriter: w, body: body}, r)
+		})
+	}
+}
+
+func writeTooLarge(w http.ResponseWriter) {
+	w.Header().Del("Content-Length")
+	httpjson.WriteError(w, http.StatusRequestEntityTooLarge, httpjson.CodePayloadTooLarge, "request body too large")
+}
+
+// maxBytesBody recuerda si alguna lectura superó el límite.
+type maxBytesBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *maxBytesBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if err != nil && errors.As(err, &tooLarge) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// maxBytesWriter reemplaza la respuesta del handler por el 413 si el cuerpo superó el límite.
+type maxBytesWriter struct {
+	http.ResponseWriter
+	body        *maxBytesBody
+	wroteHeader bool
+	replaced    bool
+}
+
+func (mw *maxBytesWriter) WriteHeader(status int) {
+	if mw.wroteHeader {
+		return
+	}
+	mw.wroteHeader = true
+	if mw.body.exceeded {
+		mw.replaced = true
+		writeTooLarge(mw.ResponseWriter)
+		return
+	}
+	mw.ResponseWriter.WriteHeader(status)
+}
+
+// Write descarta el cuerpo del handler si la respuesta ya se reemplazó por el 413.
+func (mw *maxBytesWriter) Write(b []byte) (int, error) {
+	if !mw.wroteHeader {
+		mw.WriteHeader(http.StatusOK)
+	}
+	if mw.replaced {
+		return len(b), nil
+	}
+	return mw.ResponseWriter.Write(b)
+}
+
+// Flush mantiene el streaming (exports) a través del wrapper.
+func (mw *maxBytesWriter) Flush() {
+	if mw.replaced {
+		return
+	}
+	if f, ok := mw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
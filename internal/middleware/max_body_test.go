@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// decodeHandler responde como los handlers del repo: 400 si el JSON no se puede decodificar.
+func decodeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	h := MaxBodyBytes(64)(decodeHandler())
+	big := `{"notes":"` + strings.Repeat("x", 200) + `"}`
+
+	// Content-Length conocido: se corta antes del handler
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(big)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for large Content-Length, got %d", rec.Code)
+	}
+
+	// Sin Content-Length (chunked): el 400 del handler se reemplaza por 413 JSON
+	req := httptest.NewRequest(http.MethodPost, "/pets", io.NopCloser(strings.NewReader(big)))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusRequestEntityTooLarge || body.Error.Code != "payload_too_large" {
+		t.Fatalf("expected 413 payload_too_large for chunked body, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Dentro del límite: pasa
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name":"Milo"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for small body, got %d", rec.Code)
+	}
+
+	// n <= 0: sin límite
+	rec = httptest.NewRecorder()
+	MaxBodyBytes(0)(decodeHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(big)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 without limit, got %d", rec.Code)
+	}
+}
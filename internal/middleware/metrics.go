@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"pet-clinical-history/internal/platform/metrics"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MetricRequests cuenta requests por method, route (patrón de chi, p.ej. /pets/{petID}) y status.
+const MetricRequests = "http_requests_total"
+
+// unmatchedRoute agrupa los requests que no matchean ninguna ruta (evita una serie por path).
+const unmatchedRoute = "unmatched"
+
+// Metrics incrementa MetricRequests en reg al terminar cada request. Usa el patrón de la ruta
+// y no el path, para no crear una serie por ID. Debe montarse antes de Recoverer para contar
+// también los panics (status 500). reg nil => no-op.
+func Metrics(reg *metrics.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if reg == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+
+			defer func() {
+				p := recover()
+
+				status := rec.status
+				if p != nil {
+					status = http.StatusInternalServerError
+				} else if status == 0 {
+					status = http.StatusOK
+				}
+
+				route := unmatchedRoute
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					route = rctx.RoutePattern()
+				}
+				reg.Inc(MetricRequests, "method", r.Method, "route", route, "status", strconv.Itoa(status))
+
+				if p != nil {
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pet-clinical-history/internal/platform/httpjson"
+)
+
+// DefaultRateLimitIdleTTL es cuánto sobrevive un bucket sin requests antes de descartarse.
+const DefaultRateLimitIdleTTL = 10 * time.Minute
+
+// RateLimitOptions configura RateLimit: un token bucket por usuario (o por IP si es anónimo).
+type RateLimitOptions struct {
+	// Rate es la cantidad de requests por segundo que se reponen; <= 0 => sin límite.
+	Rate float64
+
+	// Burst es el máximo de requests seguidos permitidos; <= 0 => ceil(Rate) (mínimo 1).
+	Burst int
+
+	// IdleTTL acota la memoria: los buckets sin uso por más de IdleTTL se descartan
+	// (volver después equivale a un bucket lleno). 0 => DefaultRateLimitIdleTTL.
+	IdleTTL time.Duration
+
+	// ExemptPaths no se limitan: match exacto, o por prefijo si terminan en "/" (como RequireAuth).
+	ExemptPaths []string
+}
+
+// RateLimit limita los requests con un token bucket por user_id (claims de AuthContext) o,
+// para requests anónimos, por IP (RemoteAddr, ya resuelta por chi RealIP). Al agotarse
+// responde 429 con Retry-After (segundos) y el cuerpo de error estándar (httpjson.CodeRateLimited).
+// Debe montarse después de AuthContext y antes de RequireAuth (así también se limitan los
+// anónimos). Rate <= 0 => sin límite.
+func RateLimit(opts RateLimitOptions) func(http.Handler) http.Handler {
+	return newRateLimiter(opts, time.Now).middleware
+}
+
+type rateLimiter struct {
+	opts RateLimitOptions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+func newRateLimiter(opts RateLimitOptions, now func() time.Time) *rateLimiter {
+	if opts.Burst <= 0 {
+		opts.Burst = int(math.Max(1, math.Ceil(opts.Rate)))
+	}
+	if opts.IdleTTL <= 0 {
+		opts.IdleTTL = DefaultRateLimitIdleTTL
+	}
+	return &rateLimiter{
+		opts:      opts,
+		now:       now,
+		buckets:   map[string]*tokenBucket{},
+		lastSweep: now(),
+	}
+}
+
+func (l *rateLimiter) middleware(next http.Handler) http.Handler {
+	if l.opts.Rate <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isPublicPath(r.URL.Path, l.opts.ExemptPaths) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ok, retryAfter := l.allow(rateLimitKey(r))
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			httpjson.WriteError(w, http.StatusTooManyRequests, httpjson.CodeRateLimited, "too many requests")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow consume un token del bucket de key; si no hay, devuelve cuánto falta para el próximo.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(l.opts.Burst), lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	// Reponer según el tiempo transcurrido, sin pasar de Burst.
+	if elapsed := now.Sub(b.lastSeen).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(l.opts.Burst), b.tokens+elapsed*l.opts.Rate)
+	}
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := (1 - b.tokens) / l.opts.Rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// sweep descarta los buckets sin uso por más de IdleTTL; corre a lo sumo una vez por IdleTTL.
+// Se llama con mu tomado.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.opts.IdleTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= l.opts.IdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// rateLimitKey identifica al cliente: user_id si hay claims, si no la IP.
+func rateLimitKey(r *http.Request) string {
+	if claims, ok := GetClaims(r.Context()); ok && strings.TrimSpace(claims.UserID) != "" {
+		return "user:" + claims.UserID
+	}
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return "ip:" + ip
+}
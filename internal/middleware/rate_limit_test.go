@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/ports/auth"
+)
+
+// fakeClock es un reloj manual para no depender de sleeps.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func rateLimitedRequest(h http.Handler, userID, remoteAddr, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.RemoteAddr = remoteAddr
+	if userID != "" {
+		req = req.WithContext(withClaims(req.Context(), auth.Claims{UserID: userID}))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_ExhaustsAndRecovers(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)}
+	l := newRateLimiter(RateLimitOptions{Rate: 1, Burst: 3, ExemptPaths: []string{"/health"}}, clock.Now)
+	h := l.middleware(okHandler())
+
+	for i := 0; i < 3; i++ {
+		if rec := rateLimitedRequest(h, "user-1", "10.0.0.1:1234", "/pets/p1/grants"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d within burst: expected 200, got %d", i, rec.Code)
+		}
+	}
+
+	rec := rateLimitedRequest(h, "user-1", "10.0.0.1:1234", "/pets/p1/grants")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after burst, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("expected Retry-After 1, got %q", got)
+	}
+
+	// Otro usuario desde la misma IP tiene su propio bucket
+	if rec := rateLimitedRequest(h, "user-2", "10.0.0.1:1234", "/pets/p1/grants"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for other user, got %d", rec.Code)
+	}
+	// /health no se limita
+	if rec := rateLimitedRequest(h, "user-1", "10.0.0.1:1234", "/health"); rec.Code != http.StatusOK {
+		t.Fatalf("expected /health exempt, got %d", rec.Code)
+	}
+
+	// Pasada la ventana se repone un token (y solo uno)
+	clock.Advance(time.Second)
+	if rec := rateLimitedRequest(h, "user-1", "10.0.0.1:1234", "/pets/p1/grants"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after refill, got %d", rec.Code)
+	}
+	if rec := rateLimitedRequest(h, "user-1", "10.0.0.1:1234", "/pets/p1/grants"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 again, got %d", rec.Code)
+	}
+
+	// Con tiempo suficiente vuelve al burst completo
+	clock.Advance(time.Minute)
+	for i := 0; i < 3; i++ {
+		if rec := rateLimitedRequest(h, "user-1", "10.0.0.1:1234", "/pets/p1/grants"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d after full refill: expected 200, got %d", i, rec.Code)
+		}
+	}
+}
+
+func TestRateLimit_AnonymousByIPAndEviction(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)}
+	l := newRateLimiter(RateLimitOptions{Rate: 0.5, Burst: 1, IdleTTL: time.Minute}, clock.Now)
+	h := l.middleware(okHandler())
+
+	if rec := rateLimitedRequest(h, "", "10.0.0.1:1111", "/pets"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	// Misma IP, otro puerto: mismo bucket
+	rec := rateLimitedRequest(h, "", "10.0.0.1:2222", "/pets")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Fatalf("expected 429 with Retry-After 2, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := rateLimitedRequest(h, "", "10.0.0.2:1111", "/pets"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for other IP, got %d", rec.Code)
+	}
+
+	// Buckets ociosos se descartan
+	clock.Advance(2 * time.Minute)
+	rateLimitedRequest(h, "", "10.0.0.3:1111", "/pets")
+	l.mu.Lock()
+	n := len(l.buckets)
+	l.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("expected idle buckets evicted, got %d buckets", n)
+	}
+}
+
+func TestRateLimit_ConcurrentRequests(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)}
+	h := newRateLimiter(RateLimitOptions{Rate: 1, Burst: 10}, clock.Now).middleware(okHandler())
+
+	var mu sync.Mutex
+	allowed := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rec := rateLimitedRequest(h, "user-1", "10.0.0.1:1", "/pets"); rec.Code == http.StatusOK {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 10 {
+		t.Fatalf("expected exactly burst (10) allowed, got %d", allowed)
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"pet-clinical-history/internal/platform/logger"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+const requestLogKey ctxKey = "request_log"
+
+// requestLog acumula datos que se conocen recién aguas abajo (p.ej. el user_id que
+// resuelve AuthContext) para la línea de log del request.
+type requestLog struct {
+	userID string
+}
+
+// RequestLogger escribe una línea estructurada por request: method, path, status,
+// duration_ms, bytes, request_id (chi) y user_id si el request se autenticó.
+// Debe montarse después de chi RequestID y antes de Recoverer, para medir la latencia
+// completa y registrar también los requests cuyo panic se recupera aguas abajo (status 500).
+func RequestLogger(l logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			info := &requestLog{}
+
+			// defer: si un panic sube sin recuperar, igual queda el log (y se re-lanza).
+			defer func() {
+				p := recover()
+
+				status := rec.status
+				if p != nil {
+					status = http.StatusInternalServerError
+				} else if status == 0 {
+					status = http.StatusOK
+				}
+
+				fields := map[string]any{
+					"method":      r.Method,
+					"path":        r.URL.Path,
+					"status":      status,
+					"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+					"bytes":       rec.bytes,
+				}
+				if id := chimw.GetReqID(r.Context()); id != "" {
+					fields["request_id"] = id
+				}
+				if info.userID != "" {
+					fields["user_id"] = info.userID
+				}
+
+				if status >= http.StatusInternalServerError {
+					l.Error("http request", fields)
+				} else {
+					l.Info("http request", fields)
+				}
+
+				if p != nil {
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), requestLogKey, info)))
+		})
+	}
+}
+
+// setRequestLogUser registra el user_id autenticado para RequestLogger (no-op si no está montado).
+func setRequestLogUser(ctx context.Context, userID string) {
+	if info, ok := ctx.Value(requestLogKey).(*requestLog); ok {
+		info.userID = userID
+	}
+}
+
+// statusRecorder captura status y bytes escritos. Implementa Flusher (exports en streaming)
+// y Unwrap (http.ResponseController).
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/platform/logger"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+type lastLogger struct {
+	level  string
+	fields map[string]any
+}
+
+func (l *lastLogger) With(map[string]any) logger.Logger { return l }
+func (l *lastLogger) Debug(_ string, f map[string]any)  { l.level, l.fields = "debug", f }
+func (l *lastLogger) Info(_ string, f map[string]any)   { l.level, l.fields = "info", f }
+func (l *lastLogger) Warn(_ string, f map[string]any)   { l.level, l.fields = "warn", f }
+func (l *lastLogger) Error(_ string, f map[string]any)  { l.level, l.fields = "error", f }
+
+func TestRequestLogger_LogsRecoveredPanic(t *testing.T) {
+	logs := &lastLogger{}
+	r := chi.NewRouter()
+	r.Use(chimw.RequestID)
+	r.Use(RequestLogger(logs))
+	r.Use(chimw.Recoverer)
+	r.Get("/boom", func(http.ResponseWriter, *http.Request) { panic("boom") })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/boom", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	if logs.level != "error" || logs.fields["status"] != http.StatusInternalServerError || logs.fields["path"] != "/boom" {
+		t.Fatalf("expected error log for recovered panic, got %s %#v", logs.level, logs.fields)
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"pet-clinical-history/internal/platform/httpjson"
+)
+
+// RequireAuth corta con 401 cualquier request sin claims válidos (ver AuthContext) antes de
+// llegar a los handlers. Debe montarse después de AuthContext.
+// publicPaths quedan abiertos: match exacto, o por prefijo si terminan en "/" (p.ej. "/swagger/").
+func RequireAuth(publicPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r.URL.Path, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, ok := GetClaims(r.Context())
+			if !ok || strings.TrimSpace(claims.UserID) == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				httpjson.WriteError(w, http.StatusUnauthorized, httpjson.CodeUnauthorized, "unauthorized")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, p := range publicPaths {
+		if path == p {
+			return true
+		}
+		if strings.HasSuffix(p, "/") && strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
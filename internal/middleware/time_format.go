@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"pet-clinical-history/internal/apitime"
+)
+
+// TimeFormat define el formato de timestamps de la respuesta:
+// - Por defecto usa def (RFC3339 si viene vacío).
+// - El cliente puede pedir otro con el parámetro time-format del Accept
+// (p.ej. `Accept: application/json; time-format=epoch_ms`).
+// - Un valor no reconocido se ignora (no rompe el request).
+func TimeFormat(def apitime.Format) func(http.Handler) http.Handler {
+	if def == "" {
+		def = apitime.FormatRFC3339
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f := def
+			if requested, ok := acceptedTimeFormat(r.Header.Get("Accept")); ok {
+				f = requested
+			}
+			next.ServeHTTP(w, r.WithContext(apitime.WithFormat(r.Context(), f)))
+		})
+	}
+}
+
+func acceptedTimeFormat(accept string) (apitime.Format, bool) {
+	for _, part := range strings.Split(accept, ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if v, ok := params["time-format"]; ok {
+			return apitime.ParseFormat(v)
+		}
+	}
+	return "", false
+}
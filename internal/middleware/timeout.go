@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"pet-clinical-history/internal/platform/httpjson"
+)
+
+// timeoutWriteGrace es el margen que se le da a la escritura por encima del plazo del request,
+// para que el 503 de timeout llegue al cliente.
+const timeoutWriteGrace = 2 * time.Second
+
+// Timeout acota cada request a d: el context del request vence a los d, y como los repos
+// (QueryContext) y los clientes HTTP (Odin, plans-features) usan r.Context(), la operación
+// en curso se corta y libera la conexión.
+// Si el plazo venció antes de que el handler empiece a responder, la respuesta se reemplaza
+// por 503 con el cuerpo de error estándar (httpjson.CodeTimeout). Una respuesta ya empezada (p.ej. un export en
+// streaming) no se puede reemplazar: simplemente se corta; por eso los exports usan TimeoutFunc
+// con un plazo propio.
+// d <= 0 => sin timeout. Debe montarse antes de AuthContext para acotar también la verificación del token.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return TimeoutFunc(func(*http.Request) time.Duration { return d })
+}
+
+// TimeoutFunc es Timeout con el plazo elegido por request (p.ej. uno más largo para los exports
+// en streaming). También corre el write deadline de la conexión a plazo+timeoutWriteGrace, para
+// que el WriteTimeout del server no corte antes una respuesta con plazo más largo.
+// Un plazo <= 0 => ese request va sin timeout.
+func TimeoutFunc(deadline func(*http.Request) time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := deadline(r)
+			if d <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// Sin soporte (p.ej. httptest.ResponseRecorder) queda el WriteTimeout del server.
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d + timeoutWriteGrace))
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+
+			// El handler terminó sin escribir nada (p.ej. abortó al ver el context vencido).
+			if !tw.wroteHeader && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				tw.WriteHeader(http.StatusServiceUnavailable)
+			}
+		})
+	}
+}
+
+// timeoutWriter reemplaza la respuesta del handler por el 503 de timeout si, al momento de
+// escribir el status, el plazo del request ya venció (el 500 / 404 que el handler arma con
+// el error de contexto del repo no describe lo que pasó).
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	if errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timedOut = true
+		h := tw.ResponseWriter.Header()
+		h.Del("Content-Length")
+		httpjson.WriteError(tw.ResponseWriter, http.StatusServiceUnavailable, httpjson.CodeTimeout, "request timed out")
+		return
+	}
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+// Write descarta el cuerpo del handler si la respuesta ya se reemplazó por el 503.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
+	}
+	if tw.timedOut {
+		return len(b), nil
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Flush mantiene el streaming (exports) a través del wrapper.
+func (tw *timeoutWriter) Flush() {
+	if tw.timedOut {
+		return
+	}
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
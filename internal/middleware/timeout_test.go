@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// slowRepo simula una query lenta que respeta el context (como QueryContext).
+type slowRepo struct {
+	delay time.Duration
+}
+
+func (r slowRepo) Get(ctx context.Context) (string, error) {
+	select {
+	case <-time.After(r.delay):
+		return "ok", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// repoHandler responde como los handlers del repo: 500 en texto plano si el repo falla.
+func repoHandler(repo slowRepo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, err := repo.Get(r.Context())
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(v))
+	})
+}
+
+func TestTimeout_SlowRepoReturns503(t *testing.T) {
+	h := Timeout(20 * time.Millisecond)(repoHandler(slowRepo{delay: time.Second}))
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/p1", nil))
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected the repo call to be cancelled at the deadline, took %s", elapsed)
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected JSON body, got Content-Type %q", ct)
+	}
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error.Code != "timeout" {
+		t.Fatalf("expected error.code timeout, got %q (%v)", rec.Body.String(), err)
+	}
+}
+
+func TestTimeout_FastRequestUntouched(t *testing.T) {
+	h := Timeout(time.Second)(repoHandler(slowRepo{}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/p1", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("expected 200 ok, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTimeout_HandlerReturnsWithoutWriting(t *testing.T) {
+	h := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+}
+
+func TestTimeoutFunc_LongerDeadlineKeepsStreamAlive(t *testing.T) {
+	stream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 5; i++ {
+			select {
+			case <-time.After(10 * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+			_, _ = w.Write([]byte("row\n"))
+			w.(http.Flusher).Flush()
+		}
+	})
+	h := TimeoutFunc(func(r *http.Request) time.Duration {
+		if r.URL.Path == "/export" {
+			return time.Second
+		}
+		return 20 * time.Millisecond
+	})(stream)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/export", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "row\nrow\nrow\nrow\nrow\n" {
+		t.Fatalf("expected the full stream, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/other", nil))
+	if got := rec.Body.String(); got == "row\nrow\nrow\nrow\nrow\n" {
+		t.Fatalf("expected the short deadline to cut the stream, got %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"pet-clinical-history/internal/platform/httpclient"
+)
+
+const (
+	DefaultAPIKeyHeader = "X-Api-Key"
+	DefaultTimeout      = 5 * time.Second
+)
+
+// Config común de los clientes que hablan con servicios internos vía API key.
+type Config struct {
+	BaseURL string
+	APIKey  string
+
+	// Opcional: nombre del header de la API key. Si está vacío, se usa DefaultAPIKeyHeader.
+	APIKeyHeader string
+
+	// Opcional: si es <= 0 se usa DefaultTimeout.
+	Timeout time.Duration
+
+	// Opcional: reintentos ante fallas transitorias. Cero => httpclient.DefaultRetryPolicy.
+	Retry httpclient.RetryPolicy
+	// Opcional: transport HTTP (p.ej. uno fake en tests). nil => http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// Errors son los sentinels de cada adapter a los que se mapean las fallas upstream.
+type Errors struct {
+	Unauthorized error // 401/403
+	Upstream     error // red, status no-2xx, JSON inválido
+
+	// NotFound (opcional) para 404; si es nil, un 404 se trata como Upstream.
+	NotFound error
+}
+
+// Client envuelve httpclient.Client agregando base URL, API key y mapeo de errores.
+type Client struct {
+	http         *httpclient.Client
+	apiKey       string
+	apiKeyHeader string
+	errs         Errors
+}
+
+func New(cfg Config, errs Errors) *Client {
+	h := strings.TrimSpace(cfg.APIKeyHeader)
+	if h == "" {
+		h = DefaultAPIKeyHeader
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	retry := cfg.Retry
+	if retry == (httpclient.RetryPolicy{}) {
+		retry = httpclient.DefaultRetryPolicy
+	}
+
+	hc := httpclient.NewWithTransportAndRetry(timeout, cfg.Transport, retry)
+	hc.BaseURL = strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+
+	return &Client{
+		http:         hc,
+		apiKey:       strings.TrimSpace(cfg.APIKey),
+		apiKeyHeader: h,
+		errs:         errs,
+	}
+}
+
+func (c *Client) IsConfigured() bool {
+	return c != nil && c.http.BaseURL != "" && c.apiKey != ""
+}
+
+// DoJSON hace el request contra path (relativo a BaseURL) inyectando la API key.
+// Los errores se devuelven envueltos en Errors.Unauthorized / Errors.Upstream.
+func (c *Client) DoJSON(ctx context.Context, method, path string, headers map[string]string, in, out any) error {
+	h := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		h[k] = v
+	}
+	h[c.apiKeyHeader] = c.apiKey
+
+	err := c.http.DoJSON(ctx, method, path, h, in, out)
+	if err == nil {
+		return nil
+	}
+
+	var httpErr *httpclient.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return c.errs.Unauthorized
+		case http.StatusNotFound:
+			if c.errs.NotFound != nil {
+				return c.errs.NotFound
+			}
+			return fmt.Errorf("%w: status=%d", c.errs.Upstream, httpErr.StatusCode)
+		default:
+			return fmt.Errorf("%w: status=%d", c.errs.Upstream, httpErr.StatusCode)
+		}
+	}
+	return fmt.Errorf("%w: %v", c.errs.Upstream, err)
+}
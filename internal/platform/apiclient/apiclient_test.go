@@ -0,0 +1,53 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var (
+	errUnauthorized = errors.New("unauthorized")
+	errUpstream     = errors.New("upstream")
+)
+
+func TestClient_DoJSON_InjectsKeyAndMapsErrors(t *testing.T) {
+	status := http.StatusOK
+	var gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-Custom-Key")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c := New(Config{BaseURL: srv.URL + "/", APIKey: " secret ", APIKeyHeader: "X-Custom-Key"}, Errors{
+		Unauthorized: errUnauthorized,
+		Upstream:     errUpstream,
+	})
+	if !c.IsConfigured() {
+		t.Fatalf("expected client configured")
+	}
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	if err := c.DoJSON(context.Background(), http.MethodGet, "/v1/ping", nil, nil, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotKey != "secret" || !out.OK {
+		t.Fatalf("expected key header and decoded body, got key=%q out=%+v", gotKey, out)
+	}
+
+	status = http.StatusForbidden
+	if err := c.DoJSON(context.Background(), http.MethodGet, "/v1/ping", nil, nil, nil); !errors.Is(err, errUnauthorized) {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+
+	status = http.StatusBadGateway
+	if err := c.DoJSON(context.Background(), http.MethodGet, "/v1/ping", nil, nil, nil); !errors.Is(err, errUpstream) {
+		t.Fatalf("expected upstream, got %v", err)
+	}
+}
@@ -11,16 +11,45 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
 )
 
 const (
 	DefaultTimeout = 10 * time.Second
+
+	// RequestIDHeader lleva el request ID entrante (chi RequestID) a los servicios downstream.
+	RequestIDHeader = "X-Request-ID"
 )
 
+// RequestIDHeaders devuelve una copia de headers con RequestIDHeader tomado del contexto
+// (chi RequestID), para correlacionar logs entre servicios. Si el contexto no tiene request ID,
+// o headers ya trae uno, se respeta lo que venga.
+func RequestIDHeaders(ctx context.Context, headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		out[k] = v
+	}
+	id := chimw.GetReqID(ctx)
+	if id == "" {
+		return out
+	}
+	for k := range out {
+		if http.CanonicalHeaderKey(k) == http.CanonicalHeaderKey(RequestIDHeader) {
+			return out
+		}
+	}
+	out[RequestIDHeader] = id
+	return out
+}
+
 // Client envuelve *http.Client con helpers comunes para adapters.
 type Client struct {
 	HTTP    *http.Client
 	BaseURL string // opcional; si se define, DoJSON puede recibir paths relativos
+
+	// Retry (opcional): reintentos ante errores transitorios. Cero => un solo intento.
+	Retry RetryPolicy
 }
 
 // New crea un Client con timeout razonable.
@@ -65,6 +94,20 @@ func NewWithTransport(timeout time.Duration, tr http.RoundTripper) *Client {
 	}
 }
 
+// NewWithRetry crea un Client con timeout (por intento) y política de reintentos.
+func NewWithRetry(timeout time.Duration, p RetryPolicy) *Client {
+	c := New(timeout)
+	c.Retry = p
+	return c
+}
+
+// NewWithTransportAndRetry es NewWithTransport con política de reintentos (p.ej. para tests).
+func NewWithTransportAndRetry(timeout time.Duration, tr http.RoundTripper, p RetryPolicy) *Client {
+	c := NewWithTransport(timeout, tr)
+	c.Retry = p
+	return c
+}
+
 // HTTPError representa una respuesta no-2xx.
 type HTTPError struct {
 	StatusCode int
@@ -85,6 +128,9 @@ func (e *HTTPError) Error() string {
 // - in: body a enviar (opcional). Si nil => no body.
 // - out: donde decodificar JSON (opcional). Si nil => ignora body.
 // Retorna error si status no es 2xx.
+// Si ctx trae request ID (chi RequestID) se reenvía en RequestIDHeader (ver RequestIDHeaders).
+// Con c.Retry reintenta errores de conexión y 502/503/504 (solo métodos idempotentes,
+// salvo RetryNonIdempotent), con backoff y respetando el contexto; devuelve el último error.
 func (c *Client) DoJSON(
 	ctx context.Context,
 	method string,
@@ -101,24 +147,73 @@ func (c *Client) DoJSON(
 	if err != nil {
 		return err
 	}
+	headers = RequestIDHeaders(ctx, headers)
 
-	var body io.Reader
+	// El body se serializa una vez y se relee en cada intento.
+	var payload []byte
 	if in != nil {
 		b, err := json.Marshal(in)
 		if err != nil {
 			return fmt.Errorf("httpclient: marshal json: %w", err)
 		}
-		body = bytes.NewReader(b)
+		payload = b
+	}
+
+	var (
+		status int
+		raw    []byte
+	)
+	attempts := c.Retry.attempts(method)
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		status, raw, retry, err = c.do(ctx, method, fullURL, headers, payload, in != nil)
+		if !retry || attempt >= attempts {
+			break
+		}
+		if sleepCtx(ctx, c.Retry.delay(attempt)) != nil {
+			break // sin tiempo para otro intento: se devuelve el último error
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if status < 200 || status >= 300 {
+		return &HTTPError{
+			StatusCode: status,
+			Body:       strings.TrimSpace(string(raw)),
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("httpclient: unmarshal json: %w", err)
+	}
+
+	return nil
+}
+
+// do ejecuta un intento: devuelve status y body (limitado, ya cerrado) y si vale la pena reintentar.
+func (c *Client) do(ctx context.Context, method, fullURL string, headers map[string]string, payload []byte, hasBody bool) (int, []byte, bool, error) {
+	var body io.Reader
+	if hasBody {
+		body = bytes.NewReader(payload)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
 	if err != nil {
-		return fmt.Errorf("httpclient: new request: %w", err)
+		return 0, nil, false, fmt.Errorf("httpclient: new request: %w", err)
 	}
 
 	// Defaults
 	req.Header.Set("Accept", "application/json")
-	if in != nil {
+	if hasBody {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
@@ -132,32 +227,13 @@ func (c *Client) DoJSON(
 
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
-		return fmt.Errorf("httpclient: do request: %w", err)
+		return 0, nil, retryable(nil, err), fmt.Errorf("httpclient: do request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Leer body (limitado) para errores / decode
 	raw, _ := readAtMost(resp.Body, 1<<20) // 1MB max
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return &HTTPError{
-			StatusCode: resp.StatusCode,
-			Body:       strings.TrimSpace(string(raw)),
-		}
-	}
-
-	if out == nil {
-		return nil
-	}
-	if len(raw) == 0 {
-		return nil
-	}
-
-	if err := json.Unmarshal(raw, out); err != nil {
-		return fmt.Errorf("httpclient: unmarshal json: %w", err)
-	}
-
-	return nil
+	return resp.StatusCode, raw, retryable(resp, nil), nil
 }
 
 func (c *Client) resolveURL(pathOrURL string) (string, error) {
@@ -0,0 +1,157 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// flakyTransport falla las primeras `failures` veces (error de red o status) y luego responde 200.
+type flakyTransport struct {
+	failures int
+	status   int // 0 => error de conexión
+	calls    int
+	bodies   []string
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		t.bodies = append(t.bodies, string(b))
+	}
+	if t.calls <= t.failures {
+		if t.status == 0 {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: t.status, Body: io.NopCloser(strings.NewReader("unavailable")), Request: req}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"ok":true}`)), Request: req}, nil
+}
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Jitter: 0.5}
+
+func TestDoJSON_RetriesTransientFailures(t *testing.T) {
+	for _, status := range []int{0, http.StatusServiceUnavailable} {
+		tr := &flakyTransport{failures: 2, status: status}
+		c := NewWithTransportAndRetry(time.Second, tr, fastRetry)
+
+		var out struct {
+			OK bool `json:"ok"`
+		}
+		if err := c.DoJSON(context.Background(), http.MethodGet, "http://upstream/x", nil, nil, &out); err != nil {
+			t.Fatalf("status=%d: expected success after retries, got %v", status, err)
+		}
+		if tr.calls != 3 || !out.OK {
+			t.Fatalf("status=%d: expected 3 attempts and decoded body, got %d calls", status, tr.calls)
+		}
+	}
+}
+
+func TestDoJSON_RetryLimitsAndNonIdempotent(t *testing.T) {
+	// Agotados los intentos se devuelve el último error (HTTPError).
+	tr := &flakyTransport{failures: 5, status: http.StatusBadGateway}
+	c := NewWithTransportAndRetry(time.Second, tr, fastRetry)
+	err := c.DoJSON(context.Background(), http.MethodGet, "http://upstream/x", nil, nil, nil)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway || tr.calls != 3 {
+		t.Fatalf("expected 502 after 3 attempts, got %v (%d calls)", err, tr.calls)
+	}
+
+	// 500 no es transitorio: sin reintento.
+	tr = &flakyTransport{failures: 1, status: http.StatusInternalServerError}
+	c = NewWithTransportAndRetry(time.Second, tr, fastRetry)
+	if err := c.DoJSON(context.Background(), http.MethodGet, "http://upstream/x", nil, nil, nil); err == nil || tr.calls != 1 {
+		t.Fatalf("expected no retry on 500, got %v (%d calls)", err, tr.calls)
+	}
+
+	// POST no se reintenta salvo RetryNonIdempotent; cuando se permite, el body se reenvía completo.
+	tr = &flakyTransport{failures: 1}
+	c = NewWithTransportAndRetry(time.Second, tr, fastRetry)
+	if err := c.DoJSON(context.Background(), http.MethodPost, "http://upstream/x", nil, map[string]string{"a": "b"}, nil); err == nil || tr.calls != 1 {
+		t.Fatalf("expected POST not retried, got %v (%d calls)", err, tr.calls)
+	}
+
+	p := fastRetry
+	p.RetryNonIdempotent = true
+	tr = &flakyTransport{failures: 1}
+	c = NewWithTransportAndRetry(time.Second, tr, p)
+	if err := c.DoJSON(context.Background(), http.MethodPost, "http://upstream/x", nil, map[string]string{"a": "b"}, nil); err != nil {
+		t.Fatalf("expected POST retried when allowed, got %v", err)
+	}
+	if tr.calls != 2 || tr.bodies[0] != `{"a":"b"}` || tr.bodies[1] != `{"a":"b"}` {
+		t.Fatalf("expected body resent on retry, got %q", tr.bodies)
+	}
+}
+
+func TestDoJSON_RetryRespectsContextDeadline(t *testing.T) {
+	tr := &flakyTransport{failures: 5}
+	c := NewWithTransportAndRetry(time.Second, tr, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := c.DoJSON(ctx, http.MethodGet, "http://upstream/x", nil, nil, nil); err == nil {
+		t.Fatalf("expected error")
+	}
+	if tr.calls != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected to give up without waiting past the deadline (%d calls, %s)", tr.calls, time.Since(start))
+	}
+}
+
+// headerTransport guarda los headers del último request y responde 200.
+type headerTransport struct {
+	got http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.got = req.Header.Clone()
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`)), Request: req}, nil
+}
+
+func TestDoJSON_ForwardsRequestID(t *testing.T) {
+	tr := &headerTransport{}
+	c := NewWithTransport(time.Second, tr)
+	ctx := context.WithValue(context.Background(), chimw.RequestIDKey, "req-123")
+
+	if err := c.DoJSON(ctx, http.MethodGet, "http://upstream/v1/ping", nil, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tr.got.Get(RequestIDHeader); got != "req-123" {
+		t.Fatalf("expected forwarded request id req-123, got %q", got)
+	}
+
+	// Un header explícito manda sobre el del contexto
+	if err := c.DoJSON(ctx, http.MethodGet, "http://upstream/v1/ping", map[string]string{"x-request-id": "explicit"}, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tr.got.Get(RequestIDHeader); got != "explicit" {
+		t.Fatalf("expected explicit request id, got %q", got)
+	}
+
+	// Sin request ID en el contexto no se agrega el header
+	if err := c.DoJSON(context.Background(), http.MethodGet, "http://upstream/v1/ping", nil, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tr.got.Get(RequestIDHeader); got != "" {
+		t.Fatalf("expected no request id header, got %q", got)
+	}
+}
+
+func TestRequestIDHeaders_CopiesInput(t *testing.T) {
+	in := map[string]string{"X-Api-Key": "k"}
+	out := RequestIDHeaders(context.WithValue(context.Background(), chimw.RequestIDKey, "req-1"), in)
+	if out["X-Api-Key"] != "k" || out[RequestIDHeader] != "req-1" {
+		t.Fatalf("unexpected headers: %v", out)
+	}
+	if _, ok := in[RequestIDHeader]; ok {
+		t.Fatalf("input map must not be modified")
+	}
+}
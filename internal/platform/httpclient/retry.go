@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy define reintentos con backoff exponencial para DoJSON.
+// El valor cero equivale a un único intento (sin reintentos).
+type RetryPolicy struct {
+	// MaxAttempts es el total de intentos (incluido el primero). <= 1 => sin reintentos.
+	MaxAttempts int
+
+	// BaseDelay es la espera antes del primer reintento; se duplica en cada uno.
+	BaseDelay time.Duration
+
+	// MaxDelay acota la espera entre intentos (0 => sin tope).
+	MaxDelay time.Duration
+
+	// Jitter es la fracción aleatoria (0..1) que se suma/resta a cada espera,
+	// para que clientes concurrentes no reintenten sincronizados.
+	Jitter float64
+
+	// RetryNonIdempotent permite reintentar POST/PATCH (por defecto no: podrían duplicar efectos).
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy es la política recomendada para adapters de servicios internos.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Jitter:      0.2,
+}
+
+func (p RetryPolicy) attempts(method string) int {
+	if p.MaxAttempts <= 1 {
+		return 1
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(method) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// delay devuelve la espera antes del reintento n (1 = primer reintento).
+func (p RetryPolicy) delay(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 && d > 0 {
+		j := p.Jitter
+		if j > 1 {
+			j = 1
+		}
+		d = time.Duration(float64(d) * (1 + j*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+// retryable: errores de conexión y 502/503/504. Los errores de contexto no se reintentan.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// sleepCtx espera d salvo que el contexto termine antes (o su deadline no alcance para esperar).
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return context.DeadlineExceeded
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
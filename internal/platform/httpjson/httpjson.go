@@ -0,0 +1,56 @@
+// Package httpjson agrupa las respuestas JSON de los handlers HTTP (antes duplicadas en
+// cada módulo): el writer genérico y el cuerpo de error estándar.
+package httpjson
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Códigos de error estables para los clientes (error.code).
+const (
+	CodeInvalidInput    = "invalid_input"     // 400
+	CodeUnauthorized    = "unauthorized"      // 401
+	CodeForbidden       = "forbidden"         // 403
+	CodeNotFound        = "not_found"         // 404
+	CodeBadState        = "bad_state"         // 409
+	CodePayloadTooLarge = "payload_too_large" // 413
+	CodeRateLimited     = "rate_limited"      // 429
+	CodeInternal        = "internal"          // 500
+	CodeNotImplemented  = "not_implemented"   // 501
+	CodeUnavailable     = "unavailable"       // 503
+)
+
+// Códigos más específicos que usan algunos endpoints, para que el cliente distinga el caso.
+const (
+	CodeQuotaExceeded     = "quota_exceeded"     // 402: el plan no permite más recursos
+	CodeCapabilityMissing = "capability_missing" // 402: el plan no incluye la feature
+	CodeMicrochipTaken    = "microchip_taken"    // 409: microchip ya registrado en otra mascota
+	CodeExportTooLarge    = "export_too_large"   // 413: export sobre el límite sin confirm_full
+	CodeTimeout           = "timeout"            // 503: el request superó su plazo (middleware.Timeout)
+)
+
+// ErrorBody es el cuerpo JSON de todas las respuestas de error:
+// { "error": { "code": "...", "message": "...", "reason": "..." } }.
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describe el error; Reason (opcional) da más contexto (p.ej. por qué se negó un acceso).
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// WriteJSON responde status con v serializado como JSON.
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteError responde status con un ErrorBody (code, message).
+func WriteError(w http.ResponseWriter, status int, code, message string) {
+	WriteJSON(w, status, ErrorBody{Error: ErrorDetail{Code: code, Message: message}})
+}
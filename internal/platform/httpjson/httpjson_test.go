@@ -0,0 +1,52 @@
+package httpjson
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteError(rec, http.StatusNotFound, CodeNotFound, "pet not found")
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %q", ct)
+	}
+	if got, want := rec.Body.String(), `{"error":{"code":"not_found","message":"pet not found"}}`+"\n"; got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestWriteJSONWithETag(t *testing.T) {
+	get := func(ifNoneMatch string, v any) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		WriteJSONWithETag(rec, req, v)
+		return rec
+	}
+
+	first := get("", map[string]string{"name": "Milo"})
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) || first.Body.String() != `{"name":"Milo"}`+"\n" {
+		t.Fatalf("unexpected first response: %d etag=%q body=%s", first.Code, etag, first.Body.String())
+	}
+
+	for _, h := range []string{etag, strings.TrimPrefix(etag, "W/"), `"other", ` + etag, "*"} {
+		if rec := get(h, map[string]string{"name": "Milo"}); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Fatalf("If-None-Match %s: expected empty 304, got %d body=%s", h, rec.Code, rec.Body.String())
+		}
+	}
+
+	changed := get(etag, map[string]string{"name": "Luna"})
+	if changed.Code != http.StatusOK || changed.Header().Get("ETag") == etag {
+		t.Fatalf("expected 200 with a new etag after change, got %d etag=%q", changed.Code, changed.Header().Get("ETag"))
+	}
+}
@@ -3,9 +3,13 @@ package logger
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -75,23 +79,80 @@ type Logger interface {
 	Error(msg string, fields map[string]any)
 }
 
+// Redacted reemplaza el valor de los campos con keys sensibles (ver Options.RedactKeys).
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys son las keys que se redactan si Options.RedactKeys es nil.
+var DefaultRedactKeys = []string{"token", "authorization", "api_key", "password", "secret"}
+
 // StdLogger es un logger minimalista sin deps externas (sirve como base para Odin/Plans).
 type StdLogger struct {
-	mu     sync.Mutex
+	mu     *sync.Mutex // compartido con los loggers de With: serializa ambos streams
 	std    *log.Logger
+	errStd *log.Logger // nil => todo va a std (ver Options.SplitStreams)
+	errMin Level       // nivel desde el que se escribe en errStd
 	level  Level
 	format Format
 	base   map[string]any
+	redact map[string]struct{} // keys en minúsculas
+	caller bool
 }
 
 type Options struct {
 	Level  Level
 	Format Format
 	App    string
+
+	// RedactKeys: keys (sin distinguir mayúsculas) cuyo valor se loguea como Redacted, tanto
+	// en los campos base (With) como en los de cada llamada. nil => DefaultRedactKeys;
+	// vacío (no nil) => sin redacción.
+	RedactKeys []string
+
+	// Output es el destino de las líneas; nil => os.Stdout.
+	Output io.Writer
+
+	// SplitStreams manda las líneas Error (y Warn con SplitWarn) a ErrorOutput; el resto
+	// sigue en Output. Útil en pipelines que separan stdout de stderr.
+	SplitStreams bool
+	SplitWarn    bool
+	// ErrorOutput es el destino de los errores con SplitStreams; nil => os.Stderr.
+	ErrorOutput io.Writer
+
+	// IncludeCaller agrega el campo "caller" (p.ej. events/service.go:58) con el call site
+	// de cada línea. Tiene costo (runtime.Caller por línea), por eso es opcional.
+	IncludeCaller bool
 }
 
 func New(opts Options) Logger {
-	l := log.New(os.Stdout, "", 0)
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	l := log.New(out, "", 0)
+
+	var errStd *log.Logger
+	errMin := Error
+	if opts.SplitStreams {
+		errOut := opts.ErrorOutput
+		if errOut == nil {
+			errOut = os.Stderr
+		}
+		errStd = log.New(errOut, "", 0)
+		if opts.SplitWarn {
+			errMin = Warn
+		}
+	}
+
+	redactKeys := opts.RedactKeys
+	if redactKeys == nil {
+		redactKeys = DefaultRedactKeys
+	}
+	redact := make(map[string]struct{}, len(redactKeys))
+	for _, k := range redactKeys {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			redact[k] = struct{}{}
+		}
+	}
 
 	base := map[string]any{}
 	if strings.TrimSpace(opts.App) != "" {
@@ -99,15 +160,20 @@ func New(opts Options) Logger {
 	}
 
 	return &StdLogger{
-		std:   l,
-		level: opts.Level,
+		mu:     &sync.Mutex{},
+		std:    l,
+		errStd: errStd,
+		errMin: errMin,
+		level:  opts.Level,
 		format: func() Format {
 			if opts.Format == "" {
 				return FormatText
 			}
 			return opts.Format
 		}(),
-		base: base,
+		base:   base,
+		redact: redact,
+		caller: opts.IncludeCaller,
 	}
 }
 
@@ -115,11 +181,25 @@ func New(opts Options) Logger {
 // - LOG_LEVEL=debug|info|warn|error (default info)
 // - LOG_FORMAT=text|json (default text)
 // - APP_NAME=pet-clinical-history (opcional)
+// - LOG_REDACT_KEYS=token,authorization,... (CSV; default DefaultRedactKeys)
+// - LOG_CALLER=true (opcional; ver Options.IncludeCaller)
+// - LOG_SPLIT_STREAMS=true|warn (opcional): errores (y warn) a stderr, ver Options.SplitStreams
 func NewFromEnv() Logger {
+	var redact []string
+	if raw := strings.TrimSpace(os.Getenv("LOG_REDACT_KEYS")); raw != "" {
+		redact = strings.Split(raw, ",")
+	}
+	caller, _ := strconv.ParseBool(os.Getenv("LOG_CALLER"))
+	split := strings.ToLower(strings.TrimSpace(os.Getenv("LOG_SPLIT_STREAMS")))
+	splitErrors, _ := strconv.ParseBool(split)
 	return New(Options{
-		Level:  ParseLevel(os.Getenv("LOG_LEVEL")),
-		Format: ParseFormat(os.Getenv("LOG_FORMAT")),
-		App:    os.Getenv("APP_NAME"),
+		Level:         ParseLevel(os.Getenv("LOG_LEVEL")),
+		Format:        ParseFormat(os.Getenv("LOG_FORMAT")),
+		App:           os.Getenv("APP_NAME"),
+		RedactKeys:    redact,
+		IncludeCaller: caller,
+		SplitStreams:  splitErrors || split == "warn",
+		SplitWarn:     split == "warn",
 	})
 }
 
@@ -139,15 +219,24 @@ func (l *StdLogger) With(fields map[string]any) Logger {
 		merged[k] = v
 	}
 
-	// shallow copy del logger (comparte std, level, format)
+	// shallow copy del logger (comparte writers, level, format, redact, caller)
 	return &StdLogger{
+		mu:     l.mu,
 		std:    l.std,
+		errStd: l.errStd,
+		errMin: l.errMin,
 		level:  l.level,
 		format: l.format,
 		base:   merged,
+		redact: l.redact,
+		caller: l.caller,
 	}
 }
 
+// callerSkip saltea runtime.Caller, log y el wrapper de nivel (Debug/Info/Warn/Error):
+// el frame que queda es el call site. Los loggers de With son *StdLogger, así que no suman frames.
+const callerSkip = 2
+
 func (l *StdLogger) Debug(msg string, fields map[string]any) { l.log(Debug, msg, fields) }
 func (l *StdLogger) Info(msg string, fields map[string]any)  { l.log(Info, msg, fields) }
 func (l *StdLogger) Warn(msg string, fields map[string]any)  { l.log(Warn, msg, fields) }
@@ -173,6 +262,21 @@ func (l *StdLogger) log(lvl Level, msg string, fields map[string]any) {
 		}
 		entry[k] = v
 	}
+	for k := range entry {
+		if _, ok := l.redact[strings.ToLower(k)]; ok {
+			entry[k] = Redacted
+		}
+	}
+	if l.caller {
+		if _, file, line, ok := runtime.Caller(callerSkip); ok {
+			entry["caller"] = filepath.Base(filepath.Dir(file)) + "/" + filepath.Base(file) + ":" + strconv.Itoa(line)
+		}
+	}
+
+	out := l.std
+	if l.errStd != nil && lvl >= l.errMin {
+		out = l.errStd
+	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -180,9 +284,9 @@ func (l *StdLogger) log(lvl Level, msg string, fields map[string]any) {
 	switch l.format {
 	case FormatJSON:
 		b, _ := json.Marshal(entry)
-		l.std.Println(string(b))
+		out.Println(string(b))
 	default:
-		l.std.Println(formatText(entry))
+		out.Println(formatText(entry))
 	}
 }
 
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStdLogger_RedactsSensitiveKeys(t *testing.T) {
+	for _, format := range []Format{FormatText, FormatJSON} {
+		var buf bytes.Buffer
+		l := New(Options{Format: format, Output: &buf, RedactKeys: []string{"token", "API_KEY", " authorization "}})
+
+		l.With(map[string]any{"Authorization": "Bearer base-secret"}).Info("call", map[string]any{
+			"token":   "tok-secret",
+			"Api_Key": "key-secret",
+			"user_id": "u-1",
+		})
+
+		out := buf.String()
+		for _, secret := range []string{"base-secret", "tok-secret", "key-secret"} {
+			if strings.Contains(out, secret) {
+				t.Fatalf("%s: secret %q leaked in %q", format, secret, out)
+			}
+		}
+		if !strings.Contains(out, "u-1") || strings.Count(out, Redacted) != 3 {
+			t.Fatalf("%s: expected user_id and 3 redacted values, got %q", format, out)
+		}
+
+		if format == FormatJSON {
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("invalid json line %q: %v", out, err)
+			}
+			if entry["token"] != Redacted || entry["user_id"] != "u-1" {
+				t.Fatalf("unexpected json entry: %v", entry)
+			}
+		}
+	}
+}
+
+func TestStdLogger_RedactKeysDefaultsAndDisable(t *testing.T) {
+	var buf bytes.Buffer
+	New(Options{Output: &buf}).Info("x", map[string]any{"password": "p4ss", "email": "a@b.c"})
+	if strings.Contains(buf.String(), "p4ss") || !strings.Contains(buf.String(), "a@b.c") {
+		t.Fatalf("expected default redaction of password only, got %q", buf.String())
+	}
+
+	buf.Reset()
+	New(Options{Output: &buf, RedactKeys: []string{}}).Info("x", map[string]any{"token": "visible"})
+	if !strings.Contains(buf.String(), "token=visible") {
+		t.Fatalf("expected no redaction with empty RedactKeys, got %q", buf.String())
+	}
+}
+
+func TestStdLogger_IncludeCallerPointsAtCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(Options{Format: FormatJSON, Output: &buf, IncludeCaller: true})
+
+	_, _, line, _ := runtime.Caller(0)
+	l.Info("direct", nil)
+	l.With(map[string]any{"k": "v"}).Warn("derived", nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	for i, raw := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			t.Fatalf("invalid json line %q: %v", raw, err)
+		}
+		want := "logger/logger_test.go:" + strconv.Itoa(line+1+i)
+		if entry["caller"] != want {
+			t.Fatalf("expected caller %q, got %v", want, entry["caller"])
+		}
+	}
+
+	// Sin la opción no se agrega
+	buf.Reset()
+	New(Options{Output: &buf}).Info("x", nil)
+	if strings.Contains(buf.String(), "caller=") {
+		t.Fatalf("expected no caller field by default, got %q", buf.String())
+	}
+}
+
+func TestStdLogger_SplitStreams(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	l := New(Options{Output: &stdout, ErrorOutput: &stderr, SplitStreams: true})
+
+	l.Info("info line", nil)
+	l.Warn("warn line", nil)
+	l.With(map[string]any{"k": "v"}).Error("error line", nil)
+
+	if !strings.Contains(stdout.String(), "info line") || !strings.Contains(stdout.String(), "warn line") {
+		t.Fatalf("expected info and warn on stdout, got %q", stdout.String())
+	}
+	if strings.Contains(stdout.String(), "error line") {
+		t.Fatalf("error line leaked to stdout: %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "error line") || strings.Contains(stderr.String(), "info line") {
+		t.Fatalf("expected only the error line on stderr, got %q", stderr.String())
+	}
+
+	// SplitWarn manda también warn a stderr
+	stdout.Reset()
+	stderr.Reset()
+	l = New(Options{Output: &stdout, ErrorOutput: &stderr, SplitStreams: true, SplitWarn: true})
+	l.Info("info line", nil)
+	l.Warn("warn line", nil)
+	if !strings.Contains(stderr.String(), "warn line") || strings.Contains(stdout.String(), "warn line") {
+		t.Fatalf("expected warn on stderr, got stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+
+	// Sin la opción, todo a Output
+	stdout.Reset()
+	New(Options{Output: &stdout, ErrorOutput: &stderr}).Error("error line", nil)
+	if !strings.Contains(stdout.String(), "error line") {
+		t.Fatalf("expected error on stdout without SplitStreams, got %q", stdout.String())
+	}
+}
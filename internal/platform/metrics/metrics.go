@@ -0,0 +1,109 @@
+// Package metrics acumula contadores en memoria y los expone en texto plano, una línea
+// `nombre{label="valor",...} valor` por serie (compatible con el formato de texto de
+// Prometheus), sin dependencias externas.
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry guarda los contadores. Es seguro para uso concurrente y un *Registry nil
+// es un no-op (los servicios lo reciben como dependencia opcional).
+type Registry struct {
+	mu       sync.Mutex
+	counters map[string]uint64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{counters: map[string]uint64{}}
+}
+
+// Inc suma 1 a la serie name con labels (pares clave, valor).
+func (r *Registry) Inc(name string, labels ...string) {
+	r.Add(name, 1, labels...)
+}
+
+// Add suma n a la serie name con labels (pares clave, valor).
+func (r *Registry) Add(name string, n uint64, labels ...string) {
+	if r == nil || n == 0 {
+		return
+	}
+	key := seriesKey(name, labels)
+
+	r.mu.Lock()
+	r.counters[key] += n
+	r.mu.Unlock()
+}
+
+// Value devuelve el valor actual de la serie (0 si nunca se incrementó).
+func (r *Registry) Value(name string, labels ...string) uint64 {
+	if r == nil {
+		return 0
+	}
+	key := seriesKey(name, labels)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counters[key]
+}
+
+// WriteText escribe todas las series ordenadas por nombre.
+func (r *Registry) WriteText(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	keys := make([]string, 0, len(r.counters))
+	for k := range r.counters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(' ')
+		sb.WriteString(strconv.FormatUint(r.counters[k], 10))
+		sb.WriteByte('\n')
+	}
+	r.mu.Unlock()
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Handler expone las series en text/plain (GET /metrics).
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+// seriesKey arma `name{k="v",...}` respetando el orden de labels; una clave final sin valor se ignora.
+func seriesKey(name string, labels []string) string {
+	if len(labels) < 2 {
+		return name
+	}
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(labels[i])
+		sb.WriteString(`="`)
+		sb.WriteString(labelEscaper.Replace(labels[i+1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegistry_ConcurrentIncAndText(t *testing.T) {
+	r := NewRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Inc("hits_total", "route", "/pets")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := r.Value("hits_total", "route", "/pets"); got != 5000 {
+		t.Fatalf("expected 5000, got %d", got)
+	}
+
+	r.Add("a_total", 2)
+	r.Inc("hits_total", "route", `x"y`)
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("expected text/plain, got %q", ct)
+	}
+	want := "a_total 2\n" +
+		`hits_total{route="/pets"} 5000` + "\n" +
+		`hits_total{route="x\"y"} 1` + "\n"
+	if rec.Body.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", rec.Body.String(), want)
+	}
+}
+
+func TestRegistry_NilIsNoop(t *testing.T) {
+	var r *Registry
+	r.Inc("x_total")
+	if r.Value("x_total") != 0 {
+		t.Fatalf("expected 0 from nil registry")
+	}
+}
@@ -5,4 +5,23 @@ type Claims struct {
 	UserID   string
 	Email    string
 	TenantID string
+
+	// IntegrationSystem identifica al sistema externo (p.ej. software de una clínica)
+	// cuando el token es de integración. Vacío para usuarios normales.
+	IntegrationSystem string
+
+	// Admin marca a un operador del servicio (claim de admin del token), p.ej. para
+	// importar histórico en nombre de un usuario.
+	Admin bool
+}
+
+// IsIntegration indica si el caller se autenticó como integración.
+func (c Claims) IsIntegration() bool {
+	return c.IntegrationSystem != ""
+}
+
+// CanBackfill indica si el caller puede fijar datos de auditoría al importar (p.ej.
+// recorded_at): integraciones y admins.
+func (c Claims) CanBackfill() bool {
+	return c.IsIntegration() || c.Admin
 }
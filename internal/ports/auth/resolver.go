@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUserNotFound indica que el email no corresponde a ningún usuario.
+var ErrUserNotFound = errors.New("user not found")
+
+// GranteeResolver resuelve el user_id de un usuario a partir de su email
+// (p.ej. para invitar un delegado conociendo solo su email).
+type GranteeResolver interface {
+	ResolveByEmail(ctx context.Context, email string) (userID string, err error)
+}
@@ -0,0 +1,17 @@
+package auth
+
+import "context"
+
+type tenantKey struct{}
+
+// WithTenant guarda el tenant del caller en el context del request.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext devuelve el tenant del caller ("" = tenant por defecto, p.ej. tokens sin tenant).
+// Los services lo usan para sellar lo que crean y acotar lo que leen.
+func TenantFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(tenantKey{}).(string)
+	return v
+}
@@ -1,7 +1,11 @@
 package capabilities
 
+// ProjectKey identifica a este servicio ante plans-features (CapabilityCheck.ProjectKey).
+const ProjectKey = "pet-clinical-history"
+
 type CapabilityCheck struct {
 	ProjectKey string
 	TenantID   string
+	UserID     string // cuenta cuyo plan se evalúa
 	FeatureKey string
 }
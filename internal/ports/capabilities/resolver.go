@@ -5,3 +5,9 @@ import "context"
 type CapabilitiesResolver interface {
 	HasFeature(ctx context.Context, in CapabilityCheck) (bool, error)
 }
+
+// QuotaResolver devuelve límites numéricos del plan (FeatureKey = cuota, p.ej. pets:max).
+// limited=false => el plan no pone tope a esa cuota.
+type QuotaResolver interface {
+	Quota(ctx context.Context, in CapabilityCheck) (limit int, limited bool, err error)
+}
@@ -0,0 +1,28 @@
+package notifications
+
+import "time"
+
+// GrantEventType es la transición del ciclo de vida de un grant que se notifica.
+type GrantEventType string
+
+const (
+	GrantInvited  GrantEventType = "grant.invited"
+	GrantAccepted GrantEventType = "grant.accepted"
+	GrantRevoked  GrantEventType = "grant.revoked"
+)
+
+// GrantEvent describe un cambio de estado de un grant ya persistido.
+type GrantEvent struct {
+	Type GrantEventType
+
+	GrantID       string
+	PetID         string
+	TenantID      string
+	OwnerUserID   string
+	GranteeUserID string
+	Scopes        []string
+
+	// ActorUserID es quién provocó la transición (owner, delegador o el propio grantee).
+	ActorUserID string
+	OccurredAt  time.Time
+}
@@ -0,0 +1,9 @@
+package notifications
+
+import "context"
+
+// GrantNotifier avisa a sistemas externos de cambios en grants (p.ej. webhook.Notifier).
+// Quien llama lo trata como best-effort: un error no revierte la operación.
+type GrantNotifier interface {
+	Notify(ctx context.Context, event GrantEvent) error
+}
@@ -0,0 +1,83 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_PetAccessList(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	scopes := []string{string(accessgrants.ScopePetRead)}
+
+	// Alta intercalada: revocado, invitado, activo (el orden de la respuesta no sigue el de alta)
+	revokedID := inviteGrant(t, ts.URL, ownerID, petID, "vet-revoked", scopes)
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+revokedID+"/revoke", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 revoke, got %d body=%s", st, string(body))
+	}
+	invitedID := inviteGrant(t, ts.URL, ownerID, petID, "vet-invited", scopes)
+	activeID := inviteGrant(t, ts.URL, ownerID, petID, "vet-active", []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeEventsRead),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+activeID+"/accept", "vet-active", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	// El delegado activo usa su grant => last_used_at
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+petID, "vet-active", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 delegate get pet, got %d body=%s", st, string(body))
+	}
+
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/access-list", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 access-list, got %d body=%s", st, string(body))
+	}
+	var items []struct {
+		GrantID       string   `json:"grant_id"`
+		GranteeUserID string   `json:"grantee_user_id"`
+		Status        string   `json:"status"`
+		Scopes        []string `json:"scopes"`
+		CreatedAt     string   `json:"created_at"`
+		LastUsedAt    *string  `json:"last_used_at"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		t.Fatalf("decode access-list: %v body=%s", err, string(body))
+	}
+
+	want := []struct{ id, grantee, status string }{
+		{activeID, "vet-active", "active"},
+		{invitedID, "vet-invited", "invited"},
+		{revokedID, "vet-revoked", "revoked"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("expected %d entries, got %s", len(want), string(body))
+	}
+	for i, w := range want {
+		it := items[i]
+		if it.GrantID != w.id || it.GranteeUserID != w.grantee || it.Status != w.status || it.CreatedAt == "" || len(it.Scopes) == 0 {
+			t.Fatalf("entry %d: expected %s/%s/%s, got %+v", i, w.id, w.grantee, w.status, it)
+		}
+	}
+	if len(items[0].Scopes) != 2 || items[0].LastUsedAt == nil {
+		t.Fatalf("expected active grant with 2 scopes and last_used_at, got %+v", items[0])
+	}
+	if items[1].LastUsedAt != nil {
+		t.Fatalf("expected no last_used_at for pending invite, got %v", *items[1].LastUsedAt)
+	}
+
+	// Solo el owner: delegado => 403, mascota inexistente => 404
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/access-list", "vet-active", nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 for delegate, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/does-not-exist/access-list", ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown pet, got %d", st)
+	}
+}
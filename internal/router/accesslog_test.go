@@ -0,0 +1,91 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+type accessLogEntry struct {
+	PetID         string `json:"pet_id"`
+	GranteeUserID string `json:"grantee_user_id"`
+	Resource      string `json:"resource"`
+}
+
+func TestHTTP_AccessLog_RecordsDelegateReadsOnly(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeEventsRead),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	// Lecturas del owner: no deben registrarse
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 owner get pet, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 owner list events, got %d", st)
+	}
+
+	// Lecturas del delegado
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 delegate get pet, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 delegate list events, got %d", st)
+	}
+
+	// El registro es asíncrono: esperamos hasta ver ambas entradas.
+	var entries []accessLogEntry
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/access-log", ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 access log for owner, got %d body=%s", st, string(body))
+		}
+		entries = nil
+		if err := json.Unmarshal(body, &entries); err != nil {
+			t.Fatalf("unmarshal access log: %v body=%s", err, string(body))
+		}
+		if len(entries) >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 access log entries (delegate only), got %d: %#v", len(entries), entries)
+	}
+	resources := map[string]bool{}
+	for _, e := range entries {
+		if e.GranteeUserID != delegateID {
+			t.Fatalf("expected only delegate entries, got grantee=%s", e.GranteeUserID)
+		}
+		if e.PetID != petID {
+			t.Fatalf("expected pet_id=%s, got %s", petID, e.PetID)
+		}
+		resources[e.Resource] = true
+	}
+	if !resources["pet_profile"] || !resources["events_list"] {
+		t.Fatalf("expected pet_profile and events_list entries, got %#v", entries)
+	}
+
+	// El delegado no puede ver el access log
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/access-log", delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 access log for delegate, got %d", st)
+	}
+}
@@ -0,0 +1,99 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/ports/auth"
+	"pet-clinical-history/internal/router"
+)
+
+// tokenVerifier resuelve tokens fijos a claims (simula el verifier de producción).
+type tokenVerifier map[string]auth.Claims
+
+func (v tokenVerifier) Verify(_ context.Context, token string) (auth.Claims, error) {
+	c, ok := v[token]
+	if !ok {
+		return auth.Claims{}, errors.New("invalid token")
+	}
+	return c, nil
+}
+
+func TestHTTP_Events_RecordActorEmail(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: tokenVerifier{
+		"tok-owner": {UserID: "owner-1", Email: "owner@example.com"},
+	}}))
+	defer ts.Close()
+
+	bearer := map[string]string{"Authorization": "Bearer tok-owner"}
+
+	st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets", bearer, map[string]any{"name": "Milo"})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 create pet, got %d body=%s", st, string(body))
+	}
+	var pet struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &pet)
+
+	st, body = doReqWithHeaders(t, ts.URL, "POST", "/pets/"+pet.ID+"/events", bearer, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "Control",
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 create event, got %d body=%s", st, string(body))
+	}
+	var ev struct {
+		ID            string `json:"id"`
+		ActorID       string `json:"actor_id"`
+		ActorEmail    string `json:"actor_email"`
+		VoidedByEmail string `json:"voided_by_email"`
+	}
+	_ = json.Unmarshal(body, &ev)
+	if ev.ActorID != "owner-1" || ev.ActorEmail != "owner@example.com" {
+		t.Fatalf("expected actor owner-1 <owner@example.com>, got %q <%q>", ev.ActorID, ev.ActorEmail)
+	}
+
+	// El email queda persistido (no solo en la respuesta del alta)
+	st, body = doReqWithHeaders(t, ts.URL, "GET", "/pets/"+pet.ID+"/events/"+ev.ID, bearer, nil)
+	if st != http.StatusOK || !strings.Contains(string(body), `"actor_email":"owner@example.com"`) {
+		t.Fatalf("expected stored actor_email, got %d body=%s", st, string(body))
+	}
+
+	// El void registra también el email de quien anuló
+	st, body = doReqWithHeaders(t, ts.URL, "POST", "/pets/"+pet.ID+"/events/"+ev.ID+"/void", bearer, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+	_ = json.Unmarshal(body, &ev)
+	if ev.VoidedByEmail != "owner@example.com" {
+		t.Fatalf("expected voided_by_email owner@example.com, got %q", ev.VoidedByEmail)
+	}
+}
+
+func TestHTTP_Events_DevModeActorEmailEmpty(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	petID := createPet(t, ts.URL, "owner-1", map[string]any{"name": "Milo"})
+	eventID := createEvent(t, ts.URL, "owner-1", petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "Control",
+	})
+
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/"+eventID, "owner-1", nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 get event, got %d body=%s", st, string(body))
+	}
+	if strings.Contains(string(body), "actor_email") {
+		t.Fatalf("expected no actor_email in dev mode, got %s", string(body))
+	}
+}
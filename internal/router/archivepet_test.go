@@ -0,0 +1,124 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ArchivePet_HidesFromListUntilUnarchived(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	milo := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	luna := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+
+	listIDs := func(query string) []string {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets"+query, ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 list pets%s, got %d body=%s", query, st, string(body))
+		}
+		var items []struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(body, &items)
+		out := make([]string, 0, len(items))
+		for _, it := range items {
+			out = append(out, it.ID)
+		}
+		return out
+	}
+
+	// Solo el owner puede archivar
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+milo+"/archive", "other-user", nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 non-owner archive, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/does-not-exist/archive", ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown pet, got %d", st)
+	}
+
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+milo+"/archive", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 archive, got %d body=%s", st, string(body))
+	}
+	var pet struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &pet); err != nil || pet.Status != "archived" {
+		t.Fatalf("expected status archived, got %s", string(body))
+	}
+
+	// Idempotente
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+milo+"/archive", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 re-archive, got %d", st)
+	}
+
+	// Oculta del listado por defecto; visible con include_archived=true
+	if ids := listIDs(""); len(ids) != 1 || ids[0] != luna {
+		t.Fatalf("expected only %s listed, got %v", luna, ids)
+	}
+	if ids := listIDs("?include_archived=true"); len(ids) != 2 {
+		t.Fatalf("expected 2 pets with include_archived, got %v", ids)
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets?include_archived=maybe", ownerID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid include_archived, got %d", st)
+	}
+
+	// Sigue accesible por ID
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+milo, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 get archived pet, got %d body=%s", st, string(body))
+	}
+	_ = json.Unmarshal(body, &pet)
+	if pet.ID != milo || pet.Status != "archived" {
+		t.Fatalf("expected archived pet %s, got %s", milo, string(body))
+	}
+
+	// Desarchivar lo devuelve al listado
+	st, body = doReq(t, ts.URL, "POST", "/pets/"+milo+"/unarchive", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 unarchive, got %d body=%s", st, string(body))
+	}
+	_ = json.Unmarshal(body, &pet)
+	if pet.Status != "active" {
+		t.Fatalf("expected status active, got %s", string(body))
+	}
+	if ids := listIDs(""); len(ids) != 2 {
+		t.Fatalf("expected 2 pets after unarchive, got %v", ids)
+	}
+}
+
+func TestHTTP_ArchivePet_MergedSourceConflict(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	target := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	source := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo (dup)"})
+
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+target+"/merge", ownerID, map[string]any{"source_pet_id": source}); st != http.StatusOK {
+		t.Fatalf("expected 200 merge, got %d body=%s", st, string(body))
+	}
+
+	// Una mascota fusionada no se puede desarchivar (su historial ya está en el destino)
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+source+"/unarchive", ownerID, nil); st != http.StatusConflict {
+		t.Fatalf("expected 409 unarchive merged pet, got %d", st)
+	}
+	st, body := doReq(t, ts.URL, "GET", "/pets?include_archived=true", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list pets, got %d body=%s", st, string(body))
+	}
+	var items []struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &items)
+	if len(items) != 1 || items[0].ID != target {
+		t.Fatalf("expected merged pet excluded even with include_archived, got %s", string(body))
+	}
+}
@@ -0,0 +1,177 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/adapters/capabilities/plansfeatures"
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_AddAttachment_ScopeAndCapability(t *testing.T) {
+	t.Setenv("ALLOW_ALL_CAPABILITIES", "")
+
+	caps := plansfeatures.NewMemorySource(map[string]map[string]bool{
+		"owner-premium": {
+			"pet:attachments:add":        true,
+			"pet:grants:pet:read":        true,
+			"pet:grants:attachments:add": true,
+		},
+	})
+	ts := httptest.NewServer(router.NewRouter(router.Options{
+		AuthVerifier: nil,
+		Capabilities: plansfeatures.NewResolver(caps),
+	}))
+	defer ts.Close()
+
+	attachment := map[string]any{
+		"file_name":    "radiografia.png",
+		"content_type": "image/png",
+		"url":          "https://files.example.com/rx-1.png",
+		"size_bytes":   2048,
+	}
+	newEvent := func(ownerID, petID string) string {
+		return createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": time.Now().UTC().Format(time.RFC3339),
+			"title":       "Control",
+		})
+	}
+
+	// 1) Owner con la capability en su plan
+	petID := createPet(t, ts.URL, "owner-premium", map[string]any{"name": "Milo"})
+	eventID := newEvent("owner-premium", petID)
+	path := "/pets/" + petID + "/events/" + eventID + "/attachments"
+
+	st, body := doReq(t, ts.URL, "POST", path, "owner-premium", attachment)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 add attachment, got %d body=%s", st, string(body))
+	}
+	var created struct {
+		ID       string `json:"id"`
+		EventID  string `json:"event_id"`
+		FileName string `json:"file_name"`
+		AddedBy  string `json:"added_by"`
+	}
+	_ = json.Unmarshal(body, &created)
+	if created.ID == "" || created.EventID != eventID || created.FileName != "radiografia.png" || created.AddedBy != "owner-premium" {
+		t.Fatalf("unexpected attachment: %s", string(body))
+	}
+
+	// Se devuelve con el evento al listar, junto al evento ATTACHMENT_ADDED
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", "owner-premium", nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list, got %d", st)
+	}
+	var page struct {
+		Items []struct {
+			ID          string `json:"id"`
+			Type        string `json:"type"`
+			ActorID     string `json:"actor_id"`
+			Attachments []struct {
+				ID string `json:"id"`
+			} `json:"attachments"`
+		} `json:"items"`
+	}
+	_ = json.Unmarshal(body, &page)
+	var withAttachment, added int
+	for _, it := range page.Items {
+		if it.ID == eventID && len(it.Attachments) == 1 && it.Attachments[0].ID == created.ID {
+			withAttachment++
+		}
+		if it.Type == "ATTACHMENT_ADDED" && it.ActorID == "owner-premium" {
+			added++
+		}
+	}
+	if len(page.Items) != 2 || withAttachment != 1 || added != 1 {
+		t.Fatalf("expected attachment in events list and an ATTACHMENT_ADDED event, got %s", string(body))
+	}
+
+	// 2) Delegado: sin attachments:add => 403; con el scope => 201 (plan del dueño)
+	grantID := inviteGrant(t, ts.URL, "owner-premium", petID, "vet-1", []string{string(accessgrants.ScopePetRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", "vet-1", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	if st, _ := doReq(t, ts.URL, "POST", path, "vet-1", attachment); st != http.StatusForbidden {
+		t.Fatalf("expected 403 for delegate without attachments:add, got %d", st)
+	}
+
+	grantID = inviteGrant(t, ts.URL, "owner-premium", petID, "vet-2", []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeAttachmentsAdd),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", "vet-2", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", path, "vet-2", attachment); st != http.StatusCreated {
+		t.Fatalf("expected 201 for delegate with attachments:add, got %d body=%s", st, string(body))
+	}
+
+	// Listado: owner ve ambos adjuntos en orden; delegado sin events:read => 403
+	st, body = doReq(t, ts.URL, "GET", path, "owner-premium", nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list attachments, got %d body=%s", st, string(body))
+	}
+	var listed []struct {
+		ID      string `json:"id"`
+		PetID   string `json:"pet_id"`
+		AddedBy string `json:"added_by"`
+	}
+	_ = json.Unmarshal(body, &listed)
+	if len(listed) != 2 || listed[0].ID != created.ID || listed[0].PetID != petID || listed[1].AddedBy != "vet-2" {
+		t.Fatalf("unexpected attachments list: %s", string(body))
+	}
+	if st, _ := doReq(t, ts.URL, "GET", path, "vet-2", nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 listing attachments without events:read, got %d", st)
+	}
+
+	// 3) Owner sin la capability en su plan => 402
+	freePet := createPet(t, ts.URL, "owner-free", map[string]any{"name": "Luna"})
+	freeEvent := newEvent("owner-free", freePet)
+	st, body = doReq(t, ts.URL, "POST", "/pets/"+freePet+"/events/"+freeEvent+"/attachments", "owner-free", attachment)
+	if st != http.StatusPaymentRequired {
+		t.Fatalf("expected 402 without capability, got %d body=%s", st, string(body))
+	}
+	var errResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Code != "capability_missing" {
+		t.Fatalf("expected error.code capability_missing, got %s", string(body))
+	}
+
+	// 4) Validaciones: url no http(s) => 400; evento de otra mascota => 404
+	bad := map[string]any{"file_name": "x.pdf", "url": "file:///etc/passwd"}
+	if st, _ := doReq(t, ts.URL, "POST", path, "owner-premium", bad); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid url, got %d", st)
+	}
+	otherPet := createPet(t, ts.URL, "owner-premium", map[string]any{"name": "Otro"})
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+otherPet+"/events/"+eventID+"/attachments", "owner-premium", attachment); st != http.StatusNotFound {
+		t.Fatalf("expected 404 event from another pet, got %d", st)
+	}
+}
+
+func TestHTTP_AddAttachment_NoResolverAllowsAll(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	petID := createPet(t, ts.URL, "owner-1", map[string]any{"name": "Milo"})
+	eventID := createEvent(t, ts.URL, "owner-1", petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "Control",
+	})
+
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+eventID+"/attachments", "owner-1", map[string]any{
+		"file_name": "receta.pdf",
+		"url":       "https://files.example.com/receta.pdf",
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 without capabilities resolver, got %d body=%s", st, string(body))
+	}
+}
@@ -0,0 +1,177 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+type batchResult struct {
+	Mode    string `json:"mode"`
+	Created int    `json:"created"`
+	Failed  int    `json:"failed"`
+	Results []struct {
+		Index  int    `json:"index"`
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Event  *struct {
+			ID string `json:"id"`
+		} `json:"event"`
+	} `json:"results"`
+}
+
+func TestHTTP_BatchEvents_AtomicAndBestEffort(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	at := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	valid := func(title string) map[string]any {
+		return map[string]any{"type": "NOTE", "occurred_at": at, "title": title}
+	}
+	// Falla la validación del service (VACCINE requiere title)
+	invalid := map[string]any{"type": "VACCINE", "occurred_at": at}
+	// Falla el parseo en el handler
+	badDate := map[string]any{"type": "NOTE", "occurred_at": "ayer", "title": "x"}
+
+	batch := func(mode string, items ...map[string]any) (int, batchResult) {
+		t.Helper()
+		path := "/pets/" + petID + "/events/batch"
+		if mode != "" {
+			path += "?mode=" + mode
+		}
+		st, body := doReq(t, ts.URL, "POST", path, ownerID, map[string]any{"events": items})
+		// Los 400 de request inválido (mode, cantidad) traen error.code en vez del resultado por item
+		var res batchResult
+		if json.Valid(body) {
+			if err := json.Unmarshal(body, &res); err != nil {
+				t.Fatalf("decode batch (status %d): %v body=%s", st, err, string(body))
+			}
+		}
+		return st, res
+	}
+	countEvents := func() int {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 list events, got %d", st)
+		}
+		var page struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			t.Fatalf("decode list: %v", err)
+		}
+		return page.Count
+	}
+
+	// Atomic (default): todos válidos => 201 y todos creados
+	st, res := batch("", valid("a"), valid("b"))
+	if st != http.StatusCreated || res.Mode != "atomic" || res.Created != 2 || res.Failed != 0 {
+		t.Fatalf("expected 201 atomic with 2 created, got %d %+v", st, res)
+	}
+	for i, r := range res.Results {
+		if r.Index != i || r.Status != "created" || r.Event == nil || r.Event.ID == "" {
+			t.Fatalf("unexpected result %d: %+v", i, r)
+		}
+	}
+	if n := countEvents(); n != 2 {
+		t.Fatalf("expected 2 events after atomic batch, got %d", n)
+	}
+
+	// Atomic con un item inválido => 400, no se crea ninguno, error con su index
+	st, res = batch("atomic", valid("c"), invalid, valid("d"))
+	if st != http.StatusBadRequest || res.Created != 0 || res.Failed != 1 {
+		t.Fatalf("expected 400 rejected atomic batch, got %d %+v", st, res)
+	}
+	if res.Results[1].Status != "failed" || res.Results[1].Error == "" {
+		t.Fatalf("expected item 1 failed with error, got %+v", res.Results[1])
+	}
+	if res.Results[0].Status != "skipped" || res.Results[2].Status != "skipped" {
+		t.Fatalf("expected valid items skipped, got %+v", res.Results)
+	}
+	if n := countEvents(); n != 2 {
+		t.Fatalf("expected no events created by rejected batch, got %d total", n)
+	}
+
+	// Atomic con error de parseo => también rechazado
+	if st, res = batch("atomic", valid("e"), badDate); st != http.StatusBadRequest || res.Results[1].Status != "failed" {
+		t.Fatalf("expected 400 with item 1 failed, got %d %+v", st, res)
+	}
+	if n := countEvents(); n != 2 {
+		t.Fatalf("expected no events created by rejected batch, got %d total", n)
+	}
+
+	// Best effort: se crean los válidos y se informan los que fallaron
+	st, res = batch("best_effort", valid("f"), invalid, badDate, valid("g"))
+	if st != http.StatusOK || res.Mode != "best_effort" || res.Created != 2 || res.Failed != 2 {
+		t.Fatalf("expected 200 best_effort 2 created / 2 failed, got %d %+v", st, res)
+	}
+	want := []string{"created", "failed", "failed", "created"}
+	for i, r := range res.Results {
+		if r.Index != i || r.Status != want[i] {
+			t.Fatalf("result %d: expected %s, got %+v", i, want[i], r)
+		}
+	}
+	if n := countEvents(); n != 4 {
+		t.Fatalf("expected 4 events after best_effort batch, got %d", n)
+	}
+
+	// Validaciones del request
+	if st, _ := batch("sometimes", valid("x")); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid mode, got %d", st)
+	}
+	if st, _ := batch(""); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 empty batch, got %d", st)
+	}
+	tooMany := make([]map[string]any, 101)
+	for i := range tooMany {
+		tooMany[i] = valid("n")
+	}
+	if st, _ := batch("", tooMany...); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 batch over 100 items, got %d", st)
+	}
+
+	// Sin grant => 403
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/batch", "stranger", map[string]any{
+		"events": []map[string]any{valid("z")},
+	}); st != http.StatusForbidden {
+		t.Fatalf("expected 403 without grant, got %d", st)
+	}
+}
+
+func TestHTTP_BatchEvents_Quota(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, MaxEventsPerPet: 2}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	at := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	note := map[string]any{"type": "NOTE", "occurred_at": at, "title": "n"}
+
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/batch", ownerID, map[string]any{
+		"events": []map[string]any{note, note, note},
+	})
+	if st != http.StatusPaymentRequired {
+		t.Fatalf("expected 402 batch over quota, got %d body=%s", st, string(body))
+	}
+
+	// best_effort crea hasta llenar la cuota
+	st, body = doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/batch?mode=best_effort", ownerID, map[string]any{
+		"events": []map[string]any{note, note, note},
+	})
+	var res batchResult
+	if err := json.Unmarshal(body, &res); err != nil || st != http.StatusOK {
+		t.Fatalf("expected 200 best_effort, got %d body=%s", st, string(body))
+	}
+	if res.Created != 2 || res.Failed != 1 || res.Results[2].Status != "failed" {
+		t.Fatalf("expected 2 created and last failed by quota, got %+v", res)
+	}
+}
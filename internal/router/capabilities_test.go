@@ -0,0 +1,62 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/ports/capabilities"
+	"pet-clinical-history/internal/router"
+)
+
+// fakeCapabilities implementa el port sin plans-features: el router solo depende de la interfaz.
+type fakeCapabilities struct {
+	mu      sync.Mutex
+	allowed map[string]bool // userID => tiene la feature
+	checks  []capabilities.CapabilityCheck
+}
+
+func (f *fakeCapabilities) HasFeature(_ context.Context, in capabilities.CapabilityCheck) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checks = append(f.checks, in)
+	return f.allowed[in.UserID], nil
+}
+
+func TestHTTP_Capabilities_FakeResolverSatisfiesPort(t *testing.T) {
+	caps := &fakeCapabilities{allowed: map[string]bool{"owner-premium": true}}
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, Capabilities: caps}))
+	defer ts.Close()
+
+	attachment := map[string]any{"file_name": "rx.png", "url": "https://files.example.com/rx.png"}
+	addAttachment := func(ownerID string) int {
+		t.Helper()
+		petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+		eventID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": time.Now().UTC().Format(time.RFC3339),
+			"title":       "Control",
+		})
+		st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+eventID+"/attachments", ownerID, attachment)
+		return st
+	}
+
+	if st := addAttachment("owner-premium"); st != http.StatusCreated {
+		t.Fatalf("expected 201 with the feature, got %d", st)
+	}
+	if st := addAttachment("owner-free"); st != http.StatusPaymentRequired {
+		t.Fatalf("expected 402 without the feature, got %d", st)
+	}
+
+	caps.mu.Lock()
+	defer caps.mu.Unlock()
+	if len(caps.checks) != 2 {
+		t.Fatalf("expected 2 capability checks, got %d", len(caps.checks))
+	}
+	if c := caps.checks[0]; c.UserID != "owner-premium" || c.FeatureKey != "pet:attachments:add" {
+		t.Fatalf("unexpected capability check: %+v", c)
+	}
+}
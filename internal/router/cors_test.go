@@ -0,0 +1,42 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_CORS_PreflightBypassesAuth(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{
+		AuthVerifier: nil,
+		RequireAuth:  true,
+		CORS:         middleware.CORSOptions{AllowedOrigins: []string{"https://app.example.com"}},
+	}))
+	defer ts.Close()
+
+	// Preflight sin credenciales: lo responde CORS, no RequireAuth
+	req, _ := http.NewRequest(http.MethodOptions, ts.URL+"/pets", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "Authorization")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("preflight: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204 preflight, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected Allow-Origin on preflight, got %q", got)
+	}
+
+	// Request real: sigue pasando por auth
+	st, _ := doReqWithHeaders(t, ts.URL, "GET", "/pets", map[string]string{"Origin": "https://app.example.com"}, nil)
+	if st != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", st)
+	}
+}
@@ -0,0 +1,82 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_DeclineGrant(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "vet-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{
+		string(accessgrants.ScopePetRead),
+	})
+	declinePath := "/grants/" + grantID + "/decline"
+
+	// 1) Un no-grantee no puede rechazar
+	if st, body := doReq(t, ts.URL, "POST", declinePath, ownerID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 decline by non-grantee, got %d body=%s", st, string(body))
+	}
+
+	// 2) El grantee rechaza => declined
+	st, body := doReq(t, ts.URL, "POST", declinePath, delegateID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 decline, got %d body=%s", st, string(body))
+	}
+	var g struct {
+		Status string `json:"status"`
+	}
+	_ = json.Unmarshal(body, &g)
+	if g.Status != string(accessgrants.StatusDeclined) {
+		t.Fatalf("expected status declined, got %q", g.Status)
+	}
+
+	// 3) Idempotente
+	if st, body := doReq(t, ts.URL, "POST", declinePath, delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 repeat decline, got %d body=%s", st, string(body))
+	}
+
+	// 4) No otorga acceso y no se puede aceptar después
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+petID, delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 after decline, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusConflict {
+		t.Fatalf("expected 409 accept after decline, got %d body=%s", st, string(body))
+	}
+
+	// 5) Filtro por status
+	st, body = doReq(t, ts.URL, "GET", "/me/grants?status=declined", delegateID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list my grants, got %d body=%s", st, string(body))
+	}
+	var mine struct {
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	_ = json.Unmarshal(body, &mine)
+	if len(mine.Items) != 1 || mine.Items[0].ID != grantID {
+		t.Fatalf("expected declined grant in filtered list, got %s", string(body))
+	}
+
+	// 6) Un grant activo no se puede rechazar => 409
+	otherPetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+	activeID := inviteGrant(t, ts.URL, ownerID, otherPetID, delegateID, []string{
+		string(accessgrants.ScopePetRead),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+activeID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+activeID+"/decline", delegateID, nil); st != http.StatusConflict {
+		t.Fatalf("expected 409 decline active grant, got %d body=%s", st, string(body))
+	}
+}
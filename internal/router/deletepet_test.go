@@ -0,0 +1,86 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_DeletePet_OwnerOnlyRevokesGrantsAndVoidsEvents(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	for i := 0; i < 2; i++ {
+		createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": time.Now().Add(-time.Duration(i+1) * time.Hour).UTC().Format(time.RFC3339),
+			"title":       "Nota",
+		})
+	}
+
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeEventsRead),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	// Un delegado (aun con grant activo) no puede borrar
+	if st, body := doReq(t, ts.URL, "DELETE", "/pets/"+petID, delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 delegate delete, got %d body=%s", st, string(body))
+	}
+
+	// Mascota inexistente
+	if st, _ := doReq(t, ts.URL, "DELETE", "/pets/does-not-exist", ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown pet, got %d", st)
+	}
+
+	st, body := doReq(t, ts.URL, "DELETE", "/pets/"+petID, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 owner delete, got %d body=%s", st, string(body))
+	}
+	var res struct {
+		PetID         string `json:"pet_id"`
+		GrantsRevoked int    `json:"grants_revoked"`
+		EventsVoided  int    `json:"events_voided"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatalf("decode delete response: %v body=%s", err, string(body))
+	}
+	if res.PetID != petID || res.GrantsRevoked != 1 || res.EventsVoided != 2 {
+		t.Fatalf("unexpected delete summary: %+v", res)
+	}
+
+	// Ya no existe para nadie
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 get after delete, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "DELETE", "/pets/"+petID, ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 second delete, got %d", st)
+	}
+
+	// El delegado ya no ve la mascota compartida
+	st, body = doReq(t, ts.URL, "GET", "/me/pets", delegateID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 /me/pets, got %d body=%s", st, string(body))
+	}
+	var shared struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.Unmarshal(body, &shared); err != nil {
+		t.Fatalf("decode /me/pets: %v body=%s", err, string(body))
+	}
+	if len(shared.Items) != 0 {
+		t.Fatalf("expected no shared pets after delete, got %s", string(body))
+	}
+}
@@ -0,0 +1,74 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Reason  string `json:"reason"`
+	} `json:"error"`
+}
+
+func decodeAPIError(t *testing.T, body []byte) apiError {
+	t.Helper()
+	var e apiError
+	if err := json.Unmarshal(body, &e); err != nil {
+		t.Fatalf("expected JSON error body, got %v body=%s", err, string(body))
+	}
+	return e
+}
+
+func TestHTTP_StructuredErrors(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	// 400
+	st, body := doReq(t, ts.URL, "POST", "/pets", ownerID, map[string]any{"name": "Luna", "birth_date": "15/01/2022"})
+	if st != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", st)
+	}
+	if e := decodeAPIError(t, body); e.Error.Code != "invalid_input" || e.Error.Message != "birth_date must be YYYY-MM-DD" {
+		t.Fatalf("unexpected 400 body: %s", string(body))
+	}
+
+	// 403 (delegado sin grant), con el motivo
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", "stranger", nil)
+	if st != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", st)
+	}
+	if e := decodeAPIError(t, body); e.Error.Code != "forbidden" || e.Error.Reason != "no_grant" {
+		t.Fatalf("unexpected 403 body: %s", string(body))
+	}
+
+	// Mismo formato en el resto de los errores
+	cases := []struct {
+		method, path, user string
+		status             int
+		code               string
+	}{
+		{"GET", "/pets", "", http.StatusUnauthorized, "unauthorized"},
+		{"GET", "/pets/missing", ownerID, http.StatusNotFound, "not_found"},
+		{"DELETE", "/pets/" + petID, "stranger", http.StatusForbidden, "forbidden"},
+		{"POST", "/grants/missing/accept", ownerID, http.StatusNotFound, "not_found"},
+	}
+	for _, c := range cases {
+		st, body := doReq(t, ts.URL, c.method, c.path, c.user, nil)
+		if st != c.status {
+			t.Fatalf("%s %s: expected %d, got %d body=%s", c.method, c.path, c.status, st, string(body))
+		}
+		if e := decodeAPIError(t, body); e.Error.Code != c.code || e.Error.Message == "" {
+			t.Fatalf("%s %s: expected code %s, got %s", c.method, c.path, c.code, string(body))
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package router_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+// getWithETag hace un GET como userID (con If-None-Match opcional) y devuelve status, ETag y body.
+func getWithETag(t *testing.T, baseURL, path, userID, ifNoneMatch string) (int, string, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("X-Debug-User-ID", userID)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	return res.StatusCode, res.Header.Get("ETag"), body
+}
+
+func TestHTTP_GetPet_ETag(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	path := "/pets/" + petID
+
+	// 1) Primer GET: 200 con ETag
+	st, etag, body := getWithETag(t, ts.URL, path, ownerID, "")
+	if st != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d etag=%q body=%s", st, etag, string(body))
+	}
+
+	// 2) Mismo ETag => 304 sin body
+	st, again, body := getWithETag(t, ts.URL, path, ownerID, etag)
+	if st != http.StatusNotModified || len(body) != 0 || again != etag {
+		t.Fatalf("expected empty 304 with same ETag, got %d etag=%q body=%s", st, again, string(body))
+	}
+
+	// 3) Un ETag viejo no coincide
+	if st, _, _ := getWithETag(t, ts.URL, path, ownerID, `W/"stale"`); st != http.StatusOK {
+		t.Fatalf("expected 200 for stale ETag, got %d", st)
+	}
+
+	// 4) Tras editar la mascota el ETag cambia y el anterior ya no da 304
+	if st, body := doReq(t, ts.URL, "PATCH", path, ownerID, map[string]any{"name": "Milo II"}); st != http.StatusOK {
+		t.Fatalf("expected 200 patch, got %d body=%s", st, string(body))
+	}
+	st, updated, body := getWithETag(t, ts.URL, path, ownerID, etag)
+	if st != http.StatusOK || updated == "" || updated == etag {
+		t.Fatalf("expected 200 with a new ETag after update, got %d etag=%q (old %q) body=%s", st, updated, etag, string(body))
+	}
+	if st, _, _ := getWithETag(t, ts.URL, path, ownerID, updated); st != http.StatusNotModified {
+		t.Fatalf("expected 304 with the new ETag, got %d", st)
+	}
+}
+
+func TestHTTP_GetEvent_ETag(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	eventID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "x",
+	})
+	path := "/pets/" + petID + "/events/" + eventID
+
+	st, etag, body := getWithETag(t, ts.URL, path, ownerID, "")
+	if st != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d etag=%q body=%s", st, etag, string(body))
+	}
+	if st, _, body := getWithETag(t, ts.URL, path, ownerID, etag); st != http.StatusNotModified || len(body) != 0 {
+		t.Fatalf("expected empty 304, got %d body=%s", st, string(body))
+	}
+
+	// Anular el evento cambia la respuesta => nuevo ETag
+	if st, body := doReq(t, ts.URL, "POST", path+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+	if st, updated, _ := getWithETag(t, ts.URL, path, ownerID, etag); st != http.StatusOK || updated == etag {
+		t.Fatalf("expected 200 with a new ETag after void, got %d etag=%q", st, updated)
+	}
+}
@@ -0,0 +1,97 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListEvents_FilterByStatusAndActor(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{
+		string(accessgrants.ScopeEventsRead),
+		string(accessgrants.ScopeEventsCreate),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	base := time.Now().Add(-24 * time.Hour).UTC()
+	event := func(userID string, i int) string {
+		return createEvent(t, ts.URL, userID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			"title":       "Nota",
+		})
+	}
+	ownerActive := event(ownerID, 0)
+	ownerVoided := event(ownerID, 1)
+	delegateActive := event(delegateID, 2)
+
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+ownerVoided+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+
+	list := func(query string) []string {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events"+query, ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("GET events%s: expected 200, got %d body=%s", query, st, string(body))
+		}
+		var page struct {
+			Items []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			t.Fatalf("decode: %v body=%s", err, string(body))
+		}
+		ids := make([]string, 0, len(page.Items))
+		for _, it := range page.Items {
+			ids = append(ids, it.ID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+	expect := func(query string, want ...string) {
+		t.Helper()
+		sort.Strings(want)
+		got := list(query)
+		if len(got) != len(want) {
+			t.Fatalf("GET events%s: expected %v, got %v", query, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("GET events%s: expected %v, got %v", query, want, got)
+			}
+		}
+	}
+
+	// Default: solo active (cambio de comportamiento: antes incluía los anulados)
+	expect("", ownerActive, delegateActive)
+	expect("?status=active", ownerActive, delegateActive)
+	expect("?status=voided", ownerVoided)
+	expect("?status=all", ownerActive, ownerVoided, delegateActive)
+
+	// Actor
+	expect("?actor_id="+delegateID, delegateActive)
+	expect("?actor_id="+ownerID+"&status=all", ownerActive, ownerVoided)
+	expect("?actor_id="+ownerID+"&status=voided", ownerVoided)
+	expect("?actor_id=nobody")
+
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?status=deleted", ownerID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid status, got %d", st)
+	}
+}
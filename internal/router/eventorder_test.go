@@ -0,0 +1,86 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListEvents_Order(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	ids := make([]string, 0, 5)
+	for i := 0; i < 5; i++ {
+		ids = append(ids, createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			"title":       "Nota",
+		}))
+	}
+
+	type page struct {
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+		NextCursor string `json:"next_cursor"`
+	}
+	list := func(query string) page {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?"+query, ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 for %q, got %d body=%s", query, st, string(body))
+		}
+		var p page
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		return p
+	}
+
+	// Default y desc: más reciente primero; asc: el más antiguo primero
+	if p := list(""); len(p.Items) != 5 || p.Items[0].ID != ids[4] {
+		t.Fatalf("expected newest first by default, got %+v", p.Items)
+	}
+	if p := list("order=desc"); p.Items[0].ID != ids[4] || p.Items[4].ID != ids[0] {
+		t.Fatalf("expected desc order, got %+v", p.Items)
+	}
+	if p := list("order=asc"); p.Items[0].ID != ids[0] || p.Items[4].ID != ids[4] {
+		t.Fatalf("expected asc order, got %+v", p.Items)
+	}
+
+	// El cursor sigue el orden pedido
+	got := []string{}
+	query := "order=asc&limit=2"
+	for pages := 0; pages < 5; pages++ {
+		p := list(query)
+		for _, it := range p.Items {
+			got = append(got, it.ID)
+		}
+		if p.NextCursor == "" {
+			break
+		}
+		query = "order=asc&limit=2&cursor=" + url.QueryEscape(p.NextCursor)
+	}
+	if len(got) != 5 {
+		t.Fatalf("expected 5 events paginating asc, got %v", got)
+	}
+	for i := range ids {
+		if got[i] != ids[i] {
+			t.Fatalf("expected asc pagination %v, got %v", ids, got)
+		}
+	}
+
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?order=sideways", ownerID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid order, got %d", st)
+	}
+}
@@ -0,0 +1,92 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_Events_RankedSearch(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	petID := createPet(t, ts.URL, "owner-1", map[string]any{"name": "Milo"})
+	now := time.Now().UTC()
+
+	weak := createEvent(t, ts.URL, "owner-1", petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": now.Format(time.RFC3339),
+		"title":       "Control general",
+		"notes":       "Se revisó la otitis",
+	})
+	strong := createEvent(t, ts.URL, "owner-1", petID, map[string]any{
+		"type":        "MEDICAL_VISIT",
+		"occurred_at": now.Add(-48 * time.Hour).Format(time.RFC3339),
+		"title":       "Otitis externa",
+		"notes":       "Otitis en oído izquierdo, se indica limpieza",
+	})
+	_ = createEvent(t, ts.URL, "owner-1", petID, map[string]any{
+		"type":        "BATH",
+		"occurred_at": now.Add(-time.Hour).Format(time.RFC3339),
+		"title":       "Baño",
+	})
+
+	type listResp struct {
+		Items []struct {
+			ID      string `json:"id"`
+			Snippet string `json:"snippet"`
+		} `json:"items"`
+		HasMore    bool   `json:"has_more"`
+		NextCursor string `json:"next_cursor"`
+	}
+
+	// rank=true: la mejor coincidencia primero, con snippet resaltado
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?q=otitis&rank=true", "owner-1", nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 ranked list, got %d body=%s", st, string(body))
+	}
+	var ranked listResp
+	_ = json.Unmarshal(body, &ranked)
+	if len(ranked.Items) != 2 || ranked.Items[0].ID != strong || ranked.Items[1].ID != weak {
+		t.Fatalf("expected [%s %s] by relevance, got %s", strong, weak, string(body))
+	}
+	for _, it := range ranked.Items {
+		if !strings.Contains(strings.ToLower(it.Snippet), "**otitis**") {
+			t.Fatalf("expected highlighted snippet, got %q", it.Snippet)
+		}
+	}
+	if ranked.HasMore || ranked.NextCursor != "" {
+		t.Fatalf("expected no pagination with rank=true, got %s", string(body))
+	}
+
+	// Sin rank: substring, orden occurred_at desc y sin snippet
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?q=otitis", "owner-1", nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list, got %d body=%s", st, string(body))
+	}
+	var plain listResp
+	_ = json.Unmarshal(body, &plain)
+	if len(plain.Items) != 2 || plain.Items[0].ID != weak || plain.Items[1].ID != strong {
+		t.Fatalf("expected [%s %s] by date, got %s", weak, strong, string(body))
+	}
+	if strings.Contains(string(body), "snippet") {
+		t.Fatalf("expected no snippet without rank, got %s", string(body))
+	}
+
+	for _, path := range []string{
+		"?rank=true",
+		"?q=otitis&rank=maybe",
+		"?q=otitis&rank=true&order=asc",
+		"?q=otitis&rank=true&cursor=abc",
+	} {
+		st, body = doReq(t, ts.URL, "GET", "/pets/"+petID+"/events"+path, "owner-1", nil)
+		if st != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d body=%s", path, st, string(body))
+		}
+	}
+}
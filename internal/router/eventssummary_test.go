@@ -0,0 +1,90 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+type eventsSummary struct {
+	ByType         map[string]int `json:"by_type"`
+	Total          int            `json:"total"`
+	LastOccurredAt *string        `json:"last_occurred_at"`
+}
+
+func TestHTTP_EventsSummary(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	summary := func(userID string) (int, eventsSummary) {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/summary", userID, nil)
+		var s eventsSummary
+		if st == http.StatusOK {
+			if err := json.Unmarshal(body, &s); err != nil {
+				t.Fatalf("decode summary: %v body=%s", err, string(body))
+			}
+		}
+		return st, s
+	}
+
+	// Sin eventos: total 0 y sin fecha
+	if st, s := summary(ownerID); st != http.StatusOK || s.Total != 0 || len(s.ByType) != 0 || s.LastOccurredAt != nil {
+		t.Fatalf("expected empty summary, got %d %+v", st, s)
+	}
+
+	day := func(daysAgo int) string {
+		return time.Now().UTC().Truncate(time.Hour).Add(-time.Duration(daysAgo) * 24 * time.Hour).Format(time.RFC3339)
+	}
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": "VACCINE", "occurred_at": day(30), "title": "Rabia"})
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": "VACCINE", "occurred_at": day(10), "title": "Séxtuple"})
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": "NOTE", "occurred_at": day(20), "title": "Control"})
+	// El más reciente es privado: el delegado no lo cuenta
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": "NOTE", "occurred_at": day(2), "title": "Privado", "visibility": "private"})
+	// Anulado: no cuenta para nadie
+	voided := createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": "BATH", "occurred_at": day(1), "title": "Baño"})
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+voided+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+
+	st, s := summary(ownerID)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200, got %d", st)
+	}
+	if s.Total != 4 || s.ByType["VACCINE"] != 2 || s.ByType["NOTE"] != 2 || len(s.ByType) != 2 {
+		t.Fatalf("unexpected owner counts: %+v", s)
+	}
+	if s.LastOccurredAt == nil || *s.LastOccurredAt != day(2) {
+		t.Fatalf("expected owner last_occurred_at %s, got %v", day(2), s.LastOccurredAt)
+	}
+
+	// Delegado con events:read: sin el privado
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopeEventsRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	st, s = summary(delegateID)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 for delegate, got %d", st)
+	}
+	if s.Total != 3 || s.ByType["VACCINE"] != 2 || s.ByType["NOTE"] != 1 {
+		t.Fatalf("unexpected delegate counts: %+v", s)
+	}
+	if s.LastOccurredAt == nil || *s.LastOccurredAt != day(10) {
+		t.Fatalf("expected delegate last_occurred_at %s, got %v", day(10), s.LastOccurredAt)
+	}
+
+	// Sin grant => 403
+	if st, _ := summary("stranger"); st != http.StatusForbidden {
+		t.Fatalf("expected 403 without grant, got %d", st)
+	}
+}
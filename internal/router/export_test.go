@@ -0,0 +1,95 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ExportJSON_OwnerGetsFullBundle(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo", "species": "dog"})
+	ev1 := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "VACCINE",
+		"occurred_at": time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339),
+		"title":       "Rabia",
+	})
+	ev2 := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "Control",
+	})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopePetRead)})
+
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/export.json", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 export, got %d body=%s", st, string(body))
+	}
+
+	var bundle struct {
+		Pet struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"pet"`
+		Events []struct {
+			ID string `json:"id"`
+		} `json:"events"`
+		Grants []struct {
+			ID string `json:"id"`
+		} `json:"grants"`
+	}
+	if err := json.Unmarshal(body, &bundle); err != nil {
+		t.Fatalf("export is not valid json: %v body=%s", err, string(body))
+	}
+
+	if bundle.Pet.ID != petID || bundle.Pet.Name != "Milo" {
+		t.Fatalf("unexpected pet in bundle: %#v", bundle.Pet)
+	}
+	if len(bundle.Events) != 2 {
+		t.Fatalf("expected 2 events in bundle, got %d", len(bundle.Events))
+	}
+	// Orden occurred_at desc
+	if bundle.Events[0].ID != ev2 || bundle.Events[1].ID != ev1 {
+		t.Fatalf("unexpected events order: %#v", bundle.Events)
+	}
+	if len(bundle.Grants) != 1 || bundle.Grants[0].ID != grantID {
+		t.Fatalf("expected grant %s in bundle, got %#v", grantID, bundle.Grants)
+	}
+}
+
+func TestHTTP_ExportJSON_RejectsNonOwner(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	// Sin grant
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/export.json", "stranger-1", nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 export for stranger, got %d", st)
+	}
+
+	// Delegado activo pero sin pet:export
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeEventsRead),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/export.json", delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 export for delegate without pet:export, got %d", st)
+	}
+}
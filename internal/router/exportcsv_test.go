@@ -0,0 +1,97 @@
+package router_test
+
+import (
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_Events_ExportCSV(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "vet-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	occurred := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+	eventID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "MEDICAL_VISIT",
+		"occurred_at": occurred.Format(time.RFC3339),
+		"title":       "Control anual",
+		"notes":       "=HYPERLINK(\"x\"), peso estable",
+	})
+	_ = createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": occurred.Add(time.Hour).Format(time.RFC3339),
+		"title":       "Nota privada",
+		"visibility":  "private",
+	})
+
+	get := func(path, userID string) (*http.Response, []byte) {
+		t.Helper()
+		req, _ := http.NewRequest("GET", ts.URL+path, nil)
+		req.Header.Set("X-Debug-User-ID", userID)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return res, body
+	}
+
+	// Owner: header + filas filtradas por tipo
+	res, body := get("/pets/"+petID+"/events/export?types=MEDICAL_VISIT", ownerID)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 export, got %d body=%s", res.StatusCode, string(body))
+	}
+	if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("expected text/csv, got %q", ct)
+	}
+	if cd := res.Header.Get("Content-Disposition"); cd != `attachment; filename="pet-`+petID+`-events.csv"` {
+		t.Fatalf("unexpected Content-Disposition %q", cd)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v body=%s", err, string(body))
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected header + 1 row, got %d rows: %q", len(rows), rows)
+	}
+	if got := strings.Join(rows[0], ","); got != "id,type,occurred_at,title,notes,actor,status" {
+		t.Fatalf("unexpected header %q", got)
+	}
+	want := []string{eventID, "MEDICAL_VISIT", "2025-03-10T14:30:00Z", "Control anual", "'=HYPERLINK(\"x\"), peso estable", ownerID, "active"}
+	if strings.Join(rows[1], "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected row\n got %q\nwant %q", rows[1], want)
+	}
+
+	// Delegado con events:read: no recibe los privados
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopeEventsRead)})
+	if st, b := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(b))
+	}
+	res, body = get("/pets/"+petID+"/events/export?format=csv", delegateID)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 delegate export, got %d body=%s", res.StatusCode, string(body))
+	}
+	if strings.Contains(string(body), "Nota privada") || !strings.Contains(string(body), eventID) {
+		t.Fatalf("expected only non-private events for delegate, got %s", string(body))
+	}
+
+	// Sin grant => 403; formato no soportado => 400
+	if res, body = get("/pets/"+petID+"/events/export", "stranger"); res.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for stranger, got %d body=%s", res.StatusCode, string(body))
+	}
+	if res, body = get("/pets/"+petID+"/events/export?format=xml", ownerID); res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for format=xml, got %d body=%s", res.StatusCode, string(body))
+	}
+}
@@ -0,0 +1,81 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ExportJSON_StreamsChunkedAndRequiresConfirmation(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, ExportMaxEvents: 50}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	const total = 250
+	base := time.Now().Add(-total * time.Hour).UTC()
+	for i := 0; i < total; i++ {
+		createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			"title":       "Nota",
+		})
+	}
+
+	// Sin confirmación: supera el tope => 413
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/export.json", ownerID, nil)
+	if st != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 without confirm_full, got %d body=%s", st, string(body))
+	}
+	var errResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Code != "export_too_large" {
+		t.Fatalf("expected error.code export_too_large, got %s", string(body))
+	}
+
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/export.json?confirm_full=maybe", ownerID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid confirm_full, got %d", st)
+	}
+
+	// Con confirmación: stream chunked, sin Content-Length, con todos los eventos
+	req, err := http.NewRequest("GET", ts.URL+"/pets/"+petID+"/export.json?confirm_full=true", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("X-Debug-User-ID", ownerID)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 with confirm_full, got %d", res.StatusCode)
+	}
+	if res.ContentLength != -1 || res.Header.Get("Content-Length") != "" {
+		t.Fatalf("expected no Content-Length, got %d", res.ContentLength)
+	}
+	if len(res.TransferEncoding) == 0 || res.TransferEncoding[0] != "chunked" {
+		t.Fatalf("expected chunked transfer encoding, got %v", res.TransferEncoding)
+	}
+
+	var bundle struct {
+		Events []struct {
+			ID string `json:"id"`
+		} `json:"events"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&bundle); err != nil {
+		t.Fatalf("export is not valid json: %v", err)
+	}
+	if len(bundle.Events) != total {
+		t.Fatalf("expected %d events in export, got %d", total, len(bundle.Events))
+	}
+}
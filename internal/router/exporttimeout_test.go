@@ -0,0 +1,30 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_Exports_UseExportTimeout(t *testing.T) {
+	// Un plazo general que vence de inmediato: cualquier request fuera de los exports da 503.
+	ts := httptest.NewServer(router.NewRouter(router.Options{
+		AuthVerifier:   nil,
+		RequestTimeout: time.Nanosecond,
+		ExportTimeout:  time.Minute,
+	}))
+	defer ts.Close()
+
+	if st, body := doReq(t, ts.URL, "GET", "/pets/missing", "owner-1", nil); st != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 under the request timeout, got %d body=%s", st, string(body))
+	}
+
+	for _, path := range []string{"/pets/missing/export.json", "/pets/missing/events/export"} {
+		if st, body := doReq(t, ts.URL, "GET", path, "owner-1", nil); st == http.StatusServiceUnavailable {
+			t.Fatalf("GET %s: expected the export timeout, got 503 body=%s", path, string(body))
+		}
+	}
+}
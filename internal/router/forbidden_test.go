@@ -0,0 +1,78 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_Forbidden_ReportsDelegateReason(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	reasonOf := func(method, path string, body any) string {
+		t.Helper()
+		st, raw := doReq(t, ts.URL, method, path, delegateID, body)
+		if st != http.StatusForbidden {
+			t.Fatalf("%s %s: expected 403, got %d body=%s", method, path, st, string(raw))
+		}
+		var resp struct {
+			Error struct {
+				Code   string `json:"code"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			t.Fatalf("403 body is not structured json: %v body=%s", err, string(raw))
+		}
+		if resp.Error.Code != "forbidden" {
+			t.Fatalf("expected error.code=forbidden, got %q", resp.Error.Code)
+		}
+		return resp.Error.Reason
+	}
+
+	newEvent := map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "x",
+	}
+
+	// 1) Sin grant
+	if got := reasonOf("GET", "/pets/"+petID, nil); got != "no_grant" {
+		t.Fatalf("expected no_grant, got %q", got)
+	}
+
+	// 2) Invitado pero no aceptado
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeEventsRead),
+	})
+	if got := reasonOf("GET", "/pets/"+petID, nil); got != "grant_not_active" {
+		t.Fatalf("expected grant_not_active for invited grant, got %q", got)
+	}
+
+	// 3) Activo pero sin events:create
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+	if got := reasonOf("POST", "/pets/"+petID+"/events", newEvent); got != "missing_scope:events:create" {
+		t.Fatalf("expected missing_scope:events:create, got %q", got)
+	}
+
+	// 4) Revocado
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/revoke", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 revoke grant, got %d body=%s", st, string(body))
+	}
+	if got := reasonOf("GET", "/pets/"+petID+"/events", nil); got != "grant_not_active" {
+		t.Fatalf("expected grant_not_active for revoked grant, got %q", got)
+	}
+}
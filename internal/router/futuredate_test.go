@@ -0,0 +1,34 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_CreateEvent_RejectsFarFutureOccurredAt(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", ownerID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().AddDate(2, 0, 0).UTC().Format(time.RFC3339),
+		"title":       "Nota",
+	})
+	if st != http.StatusBadRequest {
+		t.Fatalf("expected 400 far-future occurred_at, got %d body=%s", st, string(body))
+	}
+
+	// Un desfase de reloj chico se tolera
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().Add(5 * time.Minute).UTC().Format(time.RFC3339),
+		"title":       "Nota",
+	})
+}
@@ -0,0 +1,87 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_GetEvent_AccessAndIsolation(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	otherPetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+
+	at := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	sharedID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "MEDICAL_VISIT",
+		"occurred_at": at,
+		"title":       "Control anual",
+	})
+	privateID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": at,
+		"title":       "Nota privada",
+		"visibility":  "private",
+	})
+	otherEventID := createEvent(t, ts.URL, ownerID, otherPetID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": at,
+		"title":       "Nota de Luna",
+	})
+
+	// Owner: ve cualquier evento de la mascota
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/"+sharedID, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 owner get event, got %d body=%s", st, string(body))
+	}
+	var ev struct {
+		ID    string `json:"id"`
+		PetID string `json:"pet_id"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(body, &ev); err != nil {
+		t.Fatalf("decode event: %v body=%s", err, string(body))
+	}
+	if ev.ID != sharedID || ev.PetID != petID || ev.Title != "Control anual" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/"+privateID, ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 owner get private event, got %d", st)
+	}
+
+	// Evento de otra mascota o inexistente => 404
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/"+otherEventID, ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 cross-pet event, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/does-not-exist", ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown event, got %d", st)
+	}
+
+	// Delegado sin grant => 403
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/"+sharedID, delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 delegate without grant, got %d", st)
+	}
+
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopeEventsRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	// Delegado con events:read: ve el compartido, no el privado
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/"+sharedID, delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 delegate get event, got %d body=%s", st, string(body))
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/"+privateID, delegateID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 delegate get private event, got %d", st)
+	}
+}
@@ -0,0 +1,68 @@
+package router_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_GrantExpiresAt_DelegateLosesAccess(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	sitterID := "sitter-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	// 1) expires_at en el pasado => 400
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", ownerID, map[string]any{
+		"grantee_user_id": sitterID,
+		"scopes":          []string{"pet:read"},
+		"expires_at":      time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+	})
+	if st != http.StatusBadRequest {
+		t.Fatalf("expected 400 past expires_at, got %d body=%s", st, string(body))
+	}
+
+	// 2) Grant con vencimiento corto
+	expiresAt := time.Now().Add(300 * time.Millisecond).UTC()
+	st, body = doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", ownerID, map[string]any{
+		"grantee_user_id": sitterID,
+		"scopes":          []string{"pet:read"},
+		"expires_at":      expiresAt.Format(time.RFC3339Nano),
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 invite, got %d body=%s", st, string(body))
+	}
+	var grant struct {
+		ID        string `json:"id"`
+		ExpiresAt string `json:"expires_at"`
+	}
+	_ = json.Unmarshal(body, &grant)
+	if grant.ExpiresAt == "" {
+		t.Fatalf("expected expires_at in response, body=%s", string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grant.ID+"/accept", sitterID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+petID, sitterID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 before expiration, got %d body=%s", st, string(body))
+	}
+
+	// 3) Vencido => 403 sin revocar
+	time.Sleep(time.Until(expiresAt) + 50*time.Millisecond)
+
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+petID, sitterID, nil)
+	if st != http.StatusForbidden {
+		t.Fatalf("expected 403 after expiration, got %d body=%s", st, string(body))
+	}
+	if !bytes.Contains(body, []byte("grant_expired")) {
+		t.Fatalf("expected reason grant_expired, body=%s", string(body))
+	}
+}
@@ -0,0 +1,63 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_InviteGrant_MessageVisibleToGrantee(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	vetID := "vet-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	msg := "Acceso para la semana de la cirugía"
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", ownerID, map[string]any{
+		"grantee_user_id": vetID,
+		"message":         msg,
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 invite, got %d body=%s", st, string(body))
+	}
+	var created struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil || created.Message != msg {
+		t.Fatalf("expected message in invite response, got %s", string(body))
+	}
+
+	// El delegado lo ve antes de aceptar
+	st, body = doReq(t, ts.URL, "GET", "/me/grants?status=invited", vetID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 /me/grants, got %d body=%s", st, string(body))
+	}
+	var mine struct {
+		Items []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &mine); err != nil {
+		t.Fatalf("decode /me/grants: %v body=%s", err, string(body))
+	}
+	if len(mine.Items) != 1 || mine.Items[0].ID != created.ID || mine.Items[0].Message != msg {
+		t.Fatalf("expected invitation with message, got %s", string(body))
+	}
+
+	// Más de 280 caracteres => 400
+	st, _ = doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", ownerID, map[string]any{
+		"grantee_user_id": "other-vet",
+		"message":         strings.Repeat("x", 281),
+	})
+	if st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for long message, got %d", st)
+	}
+}
@@ -0,0 +1,64 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pet-clinical-history/internal/ports/capabilities"
+	"pet-clinical-history/internal/router"
+)
+
+// readOnlyPlan habilita otorgar solo scopes de lectura; el resto de las features se permiten.
+type readOnlyPlan struct{}
+
+func (readOnlyPlan) HasFeature(_ context.Context, in capabilities.CapabilityCheck) (bool, error) {
+	switch in.FeatureKey {
+	case "pet:grants:pet:read", "pet:grants:events:read":
+		return true, nil
+	}
+	return !strings.HasPrefix(in.FeatureKey, "pet:grants:"), nil
+}
+
+func TestHTTP_InviteGrant_ScopesLimitedByOwnerPlan(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, Capabilities: readOnlyPlan{}}))
+	defer ts.Close()
+
+	petID := createPet(t, ts.URL, "owner-1", map[string]any{"name": "Milo"})
+
+	// Scopes de escritura fuera del plan => 402 listando los no permitidos
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", "owner-1", map[string]any{
+		"grantee_user_id": "vet-1",
+		"scopes":          []string{"pet:read", "events:create", "events:void"},
+	})
+	if st != http.StatusPaymentRequired {
+		t.Fatalf("expected 402 for scopes outside the plan, got %d body=%s", st, string(body))
+	}
+	var errResp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(body, &errResp)
+	if errResp.Error.Code != "capability_missing" ||
+		!strings.Contains(errResp.Error.Message, "events:create") ||
+		!strings.Contains(errResp.Error.Message, "events:void") ||
+		strings.Contains(errResp.Error.Message, "pet:read") {
+		t.Fatalf("expected capability_missing listing write scopes, got %s", string(body))
+	}
+
+	// Solo lectura => 201
+	grantID := inviteGrant(t, ts.URL, "owner-1", petID, "vet-1", []string{"pet:read", "events:read"})
+
+	// Tampoco se pueden agregar después vía PATCH
+	st, body = doReq(t, ts.URL, "PATCH", "/grants/"+grantID, "owner-1", map[string]any{
+		"scopes": []string{"pet:read", "events:create"},
+	})
+	if st != http.StatusPaymentRequired {
+		t.Fatalf("expected 402 patching scopes outside the plan, got %d body=%s", st, string(body))
+	}
+}
@@ -0,0 +1,67 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_UpdateGrantScopes(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopePetRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	// Sin events:read el delegado no ve el timeline
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 before scope update, got %d", st)
+	}
+
+	st, body := doReq(t, ts.URL, "PATCH", "/grants/"+grantID, ownerID, map[string]any{
+		"scopes": []string{"pet:read", "events:read"},
+	})
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 owner update scopes, got %d body=%s", st, string(body))
+	}
+	var g struct {
+		ID     string   `json:"id"`
+		Status string   `json:"status"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.Unmarshal(body, &g); err != nil {
+		t.Fatalf("decode grant: %v body=%s", err, string(body))
+	}
+	if g.ID != grantID || g.Status != "active" || len(g.Scopes) != 2 {
+		t.Fatalf("unexpected grant after update: %s", string(body))
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 after scope update, got %d", st)
+	}
+
+	// No-owner => 403; scope inválido => 400
+	if st, _ := doReq(t, ts.URL, "PATCH", "/grants/"+grantID, delegateID, map[string]any{"scopes": []string{"pet:read"}}); st != http.StatusForbidden {
+		t.Fatalf("expected 403 non-owner update, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "PATCH", "/grants/"+grantID, ownerID, map[string]any{"scopes": []string{"pets:fly"}}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid scope, got %d", st)
+	}
+
+	// Revocado => 409
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/revoke", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 revoke, got %d body=%s", st, string(body))
+	}
+	if st, _ := doReq(t, ts.URL, "PATCH", "/grants/"+grantID, ownerID, map[string]any{"scopes": []string{"pet:read"}}); st != http.StatusConflict {
+		t.Fatalf("expected 409 update revoked grant, got %d", st)
+	}
+}
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ReadyzTimeout acota el ping a la DB de /readyz, para que el probe no quede colgado.
+const ReadyzTimeout = 2 * time.Second
+
+// healthPaths quedan fuera de auth y rate limit (probes de liveness / readiness).
+var healthPaths = []string{"/health", "/livez", "/readyz"}
+
+// livezHandler responde 200 mientras el proceso esté vivo (no mira dependencias).
+func livezHandler(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// readyzHandler indica si la instancia puede atender tráfico: con Postgres hace un ping
+// (ReadyzTimeout) y responde 503 si falla; in-memory siempre está lista.
+func readyzHandler(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if db == nil {
+			writeHealth(w, http.StatusOK, map[string]string{"storage": "memory"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), ReadyzTimeout)
+		defer cancel()
+		if err := db.PingContext(ctx); err != nil {
+			writeHealth(w, http.StatusServiceUnavailable, map[string]string{"db": "unavailable", "reason": err.Error()})
+			return
+		}
+		writeHealth(w, http.StatusOK, map[string]string{"db": "ok"})
+	}
+}
+
+func writeHealth(w http.ResponseWriter, status int, body map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
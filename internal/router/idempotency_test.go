@@ -0,0 +1,163 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_IdempotencyKey_PetsAndEvents(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	post := func(userID, path, key string, body any) (int, []byte) {
+		t.Helper()
+		headers := map[string]string{"X-Debug-User-ID": userID}
+		if key != "" {
+			headers["Idempotency-Key"] = key
+		}
+		return doReqWithHeaders(t, ts.URL, "POST", path, headers, body)
+	}
+	countPets := func(userID string) int {
+		t.Helper()
+		_, body := doReq(t, ts.URL, "GET", "/pets", userID, nil)
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			t.Fatalf("decode pets: %v body=%s", err, string(body))
+		}
+		return len(items)
+	}
+	countEvents := func(petID string) int {
+		t.Helper()
+		_, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?status=all", ownerID, nil)
+		var page struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			t.Fatalf("decode events: %v body=%s", err, string(body))
+		}
+		return page.Count
+	}
+
+	// Pets: misma key dos veces => un recurso y la misma respuesta
+	pet := map[string]any{"name": "Milo", "species": "dog", "birth_date": "2022-01-15"}
+	st1, body1 := post(ownerID, "/pets", "key-1", pet)
+	st2, body2 := post(ownerID, "/pets", "key-1", pet)
+	if st1 != http.StatusCreated || st2 != http.StatusCreated {
+		t.Fatalf("expected 201 twice, got %d and %d (%s)", st1, st2, string(body2))
+	}
+	if string(body1) != string(body2) {
+		t.Fatalf("expected identical responses:\n%s\n%s", string(body1), string(body2))
+	}
+	if n := countPets(ownerID); n != 1 {
+		t.Fatalf("expected 1 pet after retried create, got %d", n)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body1, &created)
+	petID := created.ID
+
+	// Otra key u otro usuario con la misma key => recurso nuevo
+	if st, _ := post(ownerID, "/pets", "key-2", pet); st != http.StatusCreated {
+		t.Fatalf("expected 201 with another key, got %d", st)
+	}
+	if n := countPets(ownerID); n != 2 {
+		t.Fatalf("expected 2 pets with different keys, got %d", n)
+	}
+	if st, _ := post("owner-2", "/pets", "key-1", pet); st != http.StatusCreated {
+		t.Fatalf("expected 201 for other user, got %d", st)
+	}
+	if n := countPets("owner-2"); n != 1 {
+		t.Fatalf("expected other user's key not to alias, got %d pets", n)
+	}
+
+	// Events: la misma key que usó el create de la mascota no se confunde (otro endpoint)
+	event := map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		"title":       "Control",
+	}
+	st1, body1 = post(ownerID, "/pets/"+petID+"/events", "key-1", event)
+	st2, body2 = post(ownerID, "/pets/"+petID+"/events", "key-1", event)
+	if st1 != http.StatusCreated || st2 != http.StatusCreated {
+		t.Fatalf("expected 201 twice, got %d and %d (%s)", st1, st2, string(body2))
+	}
+	if string(body1) != string(body2) {
+		t.Fatalf("expected identical event responses:\n%s\n%s", string(body1), string(body2))
+	}
+	if n := countEvents(petID); n != 1 {
+		t.Fatalf("expected 1 event after retried create, got %d", n)
+	}
+
+	// La misma key en otra mascota crea otro evento
+	otherPet := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+	if st, _ := post(ownerID, "/pets/"+otherPet+"/events", "key-1", event); st != http.StatusCreated {
+		t.Fatalf("expected 201 on other pet, got %d", st)
+	}
+	if n := countEvents(otherPet); n != 1 {
+		t.Fatalf("expected key scoped per pet, got %d events", n)
+	}
+
+	// Un delegado con la misma key no recibe el evento del owner
+	delegateID := "delegate-1"
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopeEventsCreate)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	if st, _ := post(delegateID, "/pets/"+petID+"/events", "key-1", event); st != http.StatusCreated {
+		t.Fatalf("expected 201 for delegate, got %d", st)
+	}
+	if n := countEvents(petID); n != 2 {
+		t.Fatalf("expected delegate key not to alias owner's, got %d events", n)
+	}
+
+	// Sin key, cada POST crea
+	post(ownerID, "/pets/"+petID+"/events", "", event)
+	post(ownerID, "/pets/"+petID+"/events", "", event)
+	if n := countEvents(petID); n != 4 {
+		t.Fatalf("expected 4 events, got %d", n)
+	}
+
+	// Key demasiado larga
+	long := make([]byte, 256)
+	for i := range long {
+		long[i] = 'k'
+	}
+	if st, _ := post(ownerID, "/pets", string(long), pet); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for oversized key, got %d", st)
+	}
+}
+
+func TestHTTP_IdempotencyKey_ReplayIgnoresQuota(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, MaxEventsPerPet: 1, Quotas: &stubQuotas{limit: 1}}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	headers := map[string]string{"X-Debug-User-ID": ownerID, "Idempotency-Key": "k"}
+
+	st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets", headers, map[string]any{"name": "Milo"})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", st, string(body))
+	}
+	if st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets", headers, map[string]any{"name": "Milo"}); st != http.StatusCreated {
+		t.Fatalf("expected replay 201 even at the pets quota, got %d body=%s", st, string(body))
+	}
+
+	var p struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &p)
+	event := map[string]any{"type": "NOTE", "occurred_at": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), "title": "n"}
+	for i := 0; i < 2; i++ {
+		if st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets/"+p.ID+"/events", headers, event); st != http.StatusCreated {
+			t.Fatalf("attempt %d: expected 201 (replay at the events quota), got %d body=%s", i, st, string(body))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListEvents_IncludeVoided(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	newEvent := func(title string) string {
+		return createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": time.Now().UTC().Format(time.RFC3339),
+			"title":       title,
+		})
+	}
+	activeID := newEvent("Vigente")
+	voidedID := newEvent("Cargado por error")
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+voidedID+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+
+	ids := func(query string) map[string]bool {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events"+query, ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 for %q, got %d body=%s", query, st, string(body))
+		}
+		var page struct {
+			Items []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		}
+		_ = json.Unmarshal(body, &page)
+		out := map[string]bool{}
+		for _, it := range page.Items {
+			out[it.ID] = true
+		}
+		return out
+	}
+
+	// Por defecto el anulado no aparece
+	if got := ids(""); len(got) != 1 || !got[activeID] {
+		t.Fatalf("expected only the active event by default, got %v", got)
+	}
+	// include_voided=true lo trae junto al active
+	if got := ids("?include_voided=true"); len(got) != 2 || !got[activeID] || !got[voidedID] {
+		t.Fatalf("expected active and voided events with include_voided=true, got %v", got)
+	}
+	if got := ids("?include_voided=false"); len(got) != 1 || !got[activeID] {
+		t.Fatalf("expected only the active event with include_voided=false, got %v", got)
+	}
+	// status explícito manda
+	if got := ids("?include_voided=true&status=voided"); len(got) != 1 || !got[voidedID] {
+		t.Fatalf("expected status to take precedence, got %v", got)
+	}
+
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?include_voided=maybe", ownerID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid include_voided, got %d", st)
+	}
+}
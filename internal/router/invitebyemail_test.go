@@ -0,0 +1,90 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/ports/auth"
+	"pet-clinical-history/internal/router"
+)
+
+type fakeGranteeResolver map[string]string
+
+func (f fakeGranteeResolver) ResolveByEmail(ctx context.Context, email string) (string, error) {
+	if id, ok := f[email]; ok {
+		return id, nil
+	}
+	return "", auth.ErrUserNotFound
+}
+
+func TestHTTP_InviteGrant_ByEmail(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{
+		AuthVerifier:    nil,
+		GranteeResolver: fakeGranteeResolver{"vet@example.com": "vet-1"},
+	}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	path := "/pets/" + petID + "/grants"
+
+	// 1) Email resuelto => grant para el user_id
+	st, body := doReq(t, ts.URL, "POST", path, ownerID, map[string]any{
+		"grantee_email": "vet@example.com",
+		"scopes":        []string{"pet:read"},
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 invite by email, got %d body=%s", st, string(body))
+	}
+	var g struct {
+		GranteeUserID string `json:"grantee_user_id"`
+	}
+	_ = json.Unmarshal(body, &g)
+	if g.GranteeUserID != "vet-1" {
+		t.Fatalf("expected grantee vet-1, got %q", g.GranteeUserID)
+	}
+
+	// 2) Email sin usuario => 404
+	if st, body := doReq(t, ts.URL, "POST", path, ownerID, map[string]any{
+		"grantee_email": "nobody@example.com",
+	}); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown email, got %d body=%s", st, string(body))
+	}
+
+	// 3) Ni user_id ni email => 400
+	if st, body := doReq(t, ts.URL, "POST", path, ownerID, map[string]any{
+		"scopes": []string{"pet:read"},
+	}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 without grantee, got %d body=%s", st, string(body))
+	}
+
+	// 4) grantee_user_id sigue funcionando (y gana si vienen ambos)
+	st, body = doReq(t, ts.URL, "POST", path, ownerID, map[string]any{
+		"grantee_user_id": "sitter-1",
+		"grantee_email":   "vet@example.com",
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 invite by user_id, got %d body=%s", st, string(body))
+	}
+	_ = json.Unmarshal(body, &g)
+	if g.GranteeUserID != "sitter-1" {
+		t.Fatalf("expected grantee sitter-1, got %q", g.GranteeUserID)
+	}
+}
+
+func TestHTTP_InviteGrant_ByEmailWithoutResolver(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", ownerID, map[string]any{
+		"grantee_email": "vet@example.com",
+	}); st != http.StatusNotImplemented {
+		t.Fatalf("expected 501 without resolver, got %d body=%s", st, string(body))
+	}
+}
@@ -0,0 +1,68 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_InviteDeadline_DefaultSevenDays(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	vetID := "vet-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	// Default: plazo de 7 días para aceptar
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", ownerID, map[string]any{"grantee_user_id": vetID})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 invite, got %d body=%s", st, string(body))
+	}
+	var g struct {
+		ID              string `json:"id"`
+		CreatedAt       string `json:"created_at"`
+		InviteExpiresAt string `json:"invite_expires_at"`
+	}
+	_ = json.Unmarshal(body, &g)
+	created, _ := time.Parse(time.RFC3339, g.CreatedAt)
+	deadline, err := time.Parse(time.RFC3339, g.InviteExpiresAt)
+	if err != nil || deadline.Sub(created) != 7*24*time.Hour {
+		t.Fatalf("expected invite_expires_at = created_at + 7d, got %s", string(body))
+	}
+}
+
+func TestHTTP_InviteDeadline_LapsedInviteCannotBeAccepted(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, InviteTTL: time.Millisecond}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	vetID := "vet-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, vetID, nil)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", vetID, nil); st != http.StatusConflict {
+		t.Fatalf("expected 409 accepting lapsed invite, got %d body=%s", st, string(body))
+	}
+
+	st, body := doReq(t, ts.URL, "GET", "/me/grants?status=expired", vetID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 /me/grants, got %d body=%s", st, string(body))
+	}
+	var page struct {
+		Items []struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"items"`
+	}
+	_ = json.Unmarshal(body, &page)
+	if len(page.Items) != 1 || page.Items[0].ID != grantID || page.Items[0].Status != "expired" {
+		t.Fatalf("expected lapsed invite listed as expired, got %s", string(body))
+	}
+}
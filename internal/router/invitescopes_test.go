@@ -0,0 +1,35 @@
+package router_test
+
+import (
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestDefaultInviteScopesFromEnv(t *testing.T) {
+	t.Setenv("INVITE_DEFAULT_SCOPES", " pet:read, events:create ,pet:read")
+	scopes, err := router.DefaultInviteScopesFromEnv()
+	if err != nil || len(scopes) != 2 || scopes[0] != accessgrants.ScopePetRead || scopes[1] != accessgrants.ScopeEventsCreate {
+		t.Fatalf("expected normalized scopes, got %v err=%v", scopes, err)
+	}
+
+	t.Setenv("INVITE_DEFAULT_SCOPES", "pet:read,bad:scope")
+	if _, err := router.DefaultInviteScopesFromEnv(); err == nil {
+		t.Fatalf("expected error for unsupported scope")
+	}
+
+	t.Setenv("INVITE_DEFAULT_SCOPES", "")
+	if scopes, err := router.DefaultInviteScopesFromEnv(); err != nil || scopes != nil {
+		t.Fatalf("expected nil without env, got %v err=%v", scopes, err)
+	}
+}
+
+func TestNewRouter_InvalidDefaultInviteScopesFailsFast(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected NewRouter to panic on invalid default invite scopes")
+		}
+	}()
+	router.NewRouter(router.Options{DefaultInviteScopes: []accessgrants.Scope{"bad:scope"}})
+}
@@ -0,0 +1,78 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListPets_LastActivity(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	base := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	quiet := createPet(t, ts.URL, ownerID, map[string]any{"name": "Sin eventos"})
+	older := createPet(t, ts.URL, ownerID, map[string]any{"name": "Antigua"})
+	recent := createPet(t, ts.URL, ownerID, map[string]any{"name": "Reciente"})
+
+	event := func(petID string, at time.Time) string {
+		return createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": at.Format(time.RFC3339),
+			"title":       "x",
+		})
+	}
+	event(older, base)
+	event(older, base.Add(24*time.Hour)) // último de "older"
+	event(recent, base.Add(48*time.Hour))
+	// Un evento anulado más nuevo no cuenta como actividad
+	voided := event(older, base.Add(72*time.Hour))
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+older+"/events/"+voided+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+
+	st, body := doReq(t, ts.URL, "GET", "/pets?include=last_activity&sort=last_activity", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", st, string(body))
+	}
+
+	var got []struct {
+		ID          string     `json:"id"`
+		LastEventAt *time.Time `json:"last_event_at"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("decode: %v body=%s", err, string(body))
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 pets, got %d", len(got))
+	}
+
+	if got[0].ID != recent || got[1].ID != older || got[2].ID != quiet {
+		t.Fatalf("expected order [recent, older, quiet], got [%s %s %s]", got[0].ID, got[1].ID, got[2].ID)
+	}
+	if got[0].LastEventAt == nil || !got[0].LastEventAt.Equal(base.Add(48*time.Hour)) {
+		t.Fatalf("unexpected last_event_at for recent: %v", got[0].LastEventAt)
+	}
+	if got[1].LastEventAt == nil || !got[1].LastEventAt.Equal(base.Add(24*time.Hour)) {
+		t.Fatalf("unexpected last_event_at for older (voided must not count): %v", got[1].LastEventAt)
+	}
+	if got[2].LastEventAt != nil {
+		t.Fatalf("expected no last_event_at for pet without events, got %v", got[2].LastEventAt)
+	}
+
+	// Sin include no se expone el campo
+	_, body = doReq(t, ts.URL, "GET", "/pets", ownerID, nil)
+	var plain []map[string]any
+	_ = json.Unmarshal(body, &plain)
+	for _, p := range plain {
+		if _, ok := p["last_event_at"]; ok {
+			t.Fatalf("expected last_event_at omitted without include, got %v", p)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_LeaveGrant_DelegateLosesAccess(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopePetRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 delegate read before leaving, got %d", st)
+	}
+
+	// Solo el grantee: ni el owner ni un tercero
+	for _, other := range []string{ownerID, "stranger"} {
+		if st, _ := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/leave", other, nil); st != http.StatusForbidden {
+			t.Fatalf("expected 403 leave by %s, got %d", other, st)
+		}
+	}
+	if st, _ := doReq(t, ts.URL, "POST", "/grants/nope/leave", delegateID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown grant, got %d", st)
+	}
+
+	st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/leave", delegateID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 leave, got %d body=%s", st, string(body))
+	}
+	var g struct {
+		Status    string  `json:"status"`
+		RevokedAt *string `json:"revoked_at"`
+	}
+	if err := json.Unmarshal(body, &g); err != nil {
+		t.Fatalf("decode grant: %v body=%s", err, string(body))
+	}
+	if g.Status != "revoked" || g.RevokedAt == nil {
+		t.Fatalf("expected revoked grant with revoked_at, got %s", string(body))
+	}
+
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 delegate read after leaving, got %d", st)
+	}
+
+	// Idempotente
+	if st, _ := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/leave", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 repeated leave, got %d", st)
+	}
+}
@@ -0,0 +1,30 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_MaxBodyBytes_Returns413(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, MaxBodyBytes: 1024}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	big := map[string]any{"name": "Milo", "notes": strings.Repeat("x", 4096)}
+
+	if st, body := doReq(t, ts.URL, "POST", "/pets", ownerID, big); st != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for oversized create, got %d body=%s", st, string(body))
+	}
+	// El PATCH decodifica a un mapa crudo: también respeta el límite
+	if st, body := doReq(t, ts.URL, "PATCH", "/pets/"+petID, ownerID, big); st != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for oversized patch, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "PATCH", "/pets/"+petID, ownerID, map[string]any{"notes": "ok"}); st != http.StatusOK {
+		t.Fatalf("expected 200 for small patch, got %d body=%s", st, string(body))
+	}
+}
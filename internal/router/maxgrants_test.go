@@ -0,0 +1,39 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_InviteGrant_MaxGrantsPerPet(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, MaxGrantsPerPet: 2}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	scopes := []string{string(accessgrants.ScopePetRead)}
+
+	inviteGrant(t, ts.URL, ownerID, petID, "vet-1", scopes)
+	inviteGrant(t, ts.URL, ownerID, petID, "vet-2", scopes)
+
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", ownerID, map[string]any{
+		"grantee_user_id": "vet-3",
+		"scopes":          scopes,
+	})
+	if st != http.StatusConflict {
+		t.Fatalf("expected 409 over the limit, got %d body=%s", st, string(body))
+	}
+
+	// Re-invitar a un grantee existente (p.ej. para cambiarle scopes) sigue funcionando
+	st, body = doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", ownerID, map[string]any{
+		"grantee_user_id": "vet-1",
+		"scopes":          []string{string(accessgrants.ScopePetRead), string(accessgrants.ScopeEventsRead)},
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 re-invite at the limit, got %d body=%s", st, string(body))
+	}
+}
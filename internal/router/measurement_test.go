@@ -0,0 +1,84 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_WeightRecorded_PersistsMeasurement(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	at := time.Now().UTC().Format(time.RFC3339)
+	path := "/pets/" + petID + "/events"
+
+	// 1) Sin measurement => 400
+	if st, body := doReq(t, ts.URL, "POST", path, ownerID, map[string]any{
+		"type": "WEIGHT_RECORDED", "occurred_at": at, "title": "12kg",
+	}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 without measurement, got %d body=%s", st, string(body))
+	}
+
+	// 2) Unidad inválida / valor no positivo => 400
+	for _, m := range []map[string]any{
+		{"value": 12.5, "unit": "g"},
+		{"value": 0, "unit": "kg"},
+	} {
+		if st, body := doReq(t, ts.URL, "POST", path, ownerID, map[string]any{
+			"type": "WEIGHT_RECORDED", "occurred_at": at, "measurement": m,
+		}); st != http.StatusBadRequest {
+			t.Fatalf("expected 400 for measurement %v, got %d body=%s", m, st, string(body))
+		}
+	}
+
+	// 3) measurement en otro tipo => 400
+	if st, body := doReq(t, ts.URL, "POST", path, ownerID, map[string]any{
+		"type": "NOTE", "occurred_at": at, "measurement": map[string]any{"value": 3, "unit": "kg"},
+	}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 measurement on NOTE, got %d body=%s", st, string(body))
+	}
+
+	type measurement struct {
+		Kind  string  `json:"kind"`
+		Value float64 `json:"value"`
+		Unit  string  `json:"unit"`
+	}
+	type eventResp struct {
+		ID          string       `json:"id"`
+		Measurement *measurement `json:"measurement"`
+	}
+
+	// 4) Válido => 201 con measurement
+	st, body := doReq(t, ts.URL, "POST", path, ownerID, map[string]any{
+		"type": "WEIGHT_RECORDED", "occurred_at": at, "measurement": map[string]any{"value": 12.4, "unit": "KG"},
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 weight event, got %d body=%s", st, string(body))
+	}
+	var created eventResp
+	_ = json.Unmarshal(body, &created)
+	if created.Measurement == nil || created.Measurement.Value != 12.4 || created.Measurement.Unit != "kg" || created.Measurement.Kind != "weight" {
+		t.Fatalf("unexpected measurement in create response: %s", string(body))
+	}
+
+	// 5) Se devuelve al listar
+	st, body = doReq(t, ts.URL, "GET", path, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list, got %d body=%s", st, string(body))
+	}
+	var page struct {
+		Items []eventResp `json:"items"`
+	}
+	_ = json.Unmarshal(body, &page)
+	list := page.Items
+	if len(list) != 1 || list[0].Measurement == nil || list[0].Measurement.Value != 12.4 {
+		t.Fatalf("expected measurement in list, got %s", string(body))
+	}
+}
@@ -0,0 +1,146 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_Medications_PersistedAndActiveListing(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	now := time.Now().UTC()
+	at := now.Add(-time.Hour).Format(time.RFC3339)
+	day := func(d int) string { return now.AddDate(0, 0, d).Format("2006-01-02") }
+
+	prescribe := func(name string, med map[string]any, extra map[string]any) (int, []byte) {
+		t.Helper()
+		payload := map[string]any{
+			"type":        "MEDICATION_PRESCRIBED",
+			"occurred_at": at,
+			"title":       name,
+			"medication":  med,
+		}
+		for k, v := range extra {
+			payload[k] = v
+		}
+		return doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", ownerID, payload)
+	}
+
+	// Validación: name y start_date obligatorios; solo en MEDICATION_PRESCRIBED
+	if st, _ := prescribe("Sin fecha", map[string]any{"name": "Amoxicilina"}, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 missing start_date, got %d", st)
+	}
+	if st, _ := prescribe("Sin nombre", map[string]any{"start_date": day(-1)}, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 missing name, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", ownerID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": at,
+		"medication":  map[string]any{"name": "X", "start_date": day(-1)},
+	}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 medication on NOTE, got %d", st)
+	}
+
+	// Tratamiento continuo: se devuelve en el evento
+	st, body := prescribe("Condroprotector", map[string]any{
+		"name":       "Condroprotector",
+		"dosage":     "1",
+		"dose_unit":  "comprimido",
+		"frequency":  "cada 24h",
+		"start_date": day(-30),
+	}, nil)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 create medication, got %d body=%s", st, string(body))
+	}
+	var created struct {
+		ID         string `json:"id"`
+		Medication *struct {
+			Name      string  `json:"name"`
+			Frequency string  `json:"frequency"`
+			EndDate   *string `json:"end_date"`
+		} `json:"medication"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		t.Fatalf("decode event: %v body=%s", err, string(body))
+	}
+	if created.Medication == nil || created.Medication.Name != "Condroprotector" || created.Medication.Frequency != "cada 24h" || created.Medication.EndDate != nil {
+		t.Fatalf("unexpected medication in event: %s", string(body))
+	}
+
+	// Vigente con fin futuro, terminada, privada y anulada
+	if st, body := prescribe("Amoxicilina", map[string]any{"name": "Amoxicilina", "start_date": day(-2), "end_date": day(5)}, nil); st != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", st, string(body))
+	}
+	if st, body := prescribe("Meloxicam", map[string]any{"name": "Meloxicam", "start_date": day(-20), "end_date": day(-10)}, nil); st != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", st, string(body))
+	}
+	if st, body := prescribe("Ansiolítico", map[string]any{"name": "Ansiolítico", "start_date": day(-1)}, map[string]any{"visibility": "private"}); st != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", st, string(body))
+	}
+	st, body = prescribe("Error de carga", map[string]any{"name": "Error de carga", "start_date": day(-1)}, nil)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", st, string(body))
+	}
+	var voided struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &voided)
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+voided.ID+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d", st)
+	}
+
+	activeNames := func(userID string) []string {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/medications/active", userID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 active medications, got %d body=%s", st, string(body))
+		}
+		var items []struct {
+			EventID string `json:"event_id"`
+			Name    string `json:"name"`
+		}
+		if err := json.Unmarshal(body, &items); err != nil {
+			t.Fatalf("decode active medications: %v body=%s", err, string(body))
+		}
+		names := make([]string, 0, len(items))
+		for _, it := range items {
+			if it.EventID == "" {
+				t.Fatalf("expected event_id in active medication, got %s", string(body))
+			}
+			names = append(names, it.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	got := activeNames(ownerID)
+	want := []string{"Amoxicilina", "Ansiolítico", "Condroprotector"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("expected owner active medications %v, got %v", want, got)
+	}
+
+	// Delegado: requiere events:read y no ve las privadas
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/medications/active", delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 delegate without grant, got %d", st)
+	}
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopeEventsRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+	got = activeNames(delegateID)
+	if len(got) != 2 || got[0] != "Amoxicilina" || got[1] != "Condroprotector" {
+		t.Fatalf("expected delegate active medications without private, got %v", got)
+	}
+}
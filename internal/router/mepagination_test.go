@@ -0,0 +1,121 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_MeListsPaginateWithoutDuplicates(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	vetID := "vet-1"
+
+	// 5 mascotas compartidas (grant activo) + 2 invitaciones pendientes
+	grantIDs := map[string]bool{}
+	petIDs := map[string]bool{}
+	for i := 0; i < 7; i++ {
+		petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Pet"})
+		grantID := inviteGrant(t, ts.URL, ownerID, petID, vetID, []string{string(accessgrants.ScopePetRead)})
+		grantIDs[grantID] = true
+		if i < 5 {
+			if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", vetID, nil); st != http.StatusOK {
+				t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+			}
+			petIDs[petID] = true
+		}
+	}
+
+	// pageAll recorre path con limit=2 siguiendo next_cursor y devuelve los IDs en orden.
+	pageAll := func(path string, id func(json.RawMessage) string) []string {
+		t.Helper()
+		var out []string
+		cursor := ""
+		for pages := 0; ; pages++ {
+			if pages > 10 {
+				t.Fatalf("%s: too many pages", path)
+			}
+			q := "?limit=2"
+			if cursor != "" {
+				q += "&cursor=" + url.QueryEscape(cursor)
+			}
+			st, body := doReq(t, ts.URL, "GET", path+q, vetID, nil)
+			if st != http.StatusOK {
+				t.Fatalf("GET %s%s: expected 200, got %d body=%s", path, q, st, string(body))
+			}
+			var page struct {
+				Items      []json.RawMessage `json:"items"`
+				Count      int               `json:"count"`
+				Limit      int               `json:"limit"`
+				HasMore    bool              `json:"has_more"`
+				NextCursor string            `json:"next_cursor"`
+			}
+			if err := json.Unmarshal(body, &page); err != nil {
+				t.Fatalf("decode %s: %v body=%s", path, err, string(body))
+			}
+			if page.Limit != 2 || page.Count != len(page.Items) || len(page.Items) > 2 {
+				t.Fatalf("%s: unexpected page shape %s", path, string(body))
+			}
+			if page.HasMore != (page.NextCursor != "") {
+				t.Fatalf("%s: has_more and next_cursor disagree: %s", path, string(body))
+			}
+			for _, it := range page.Items {
+				out = append(out, id(it))
+			}
+			if !page.HasMore {
+				return out
+			}
+			cursor = page.NextCursor
+		}
+	}
+	assertSet := func(path string, got []string, want map[string]bool) {
+		t.Helper()
+		seen := map[string]bool{}
+		for _, id := range got {
+			if seen[id] {
+				t.Fatalf("%s: %s repeated across pages: %v", path, id, got)
+			}
+			seen[id] = true
+			if !want[id] {
+				t.Fatalf("%s: unexpected %s", path, id)
+			}
+		}
+		if len(seen) != len(want) {
+			t.Fatalf("%s: expected %d items across pages, got %v", path, len(want), got)
+		}
+	}
+
+	grants := pageAll("/me/grants", func(raw json.RawMessage) string {
+		var g struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(raw, &g)
+		return g.ID
+	})
+	assertSet("/me/grants", grants, grantIDs)
+
+	pets := pageAll("/me/pets", func(raw json.RawMessage) string {
+		var p struct {
+			Pet struct {
+				ID string `json:"id"`
+			} `json:"pet"`
+		}
+		_ = json.Unmarshal(raw, &p)
+		return p.Pet.ID
+	})
+	assertSet("/me/pets", pets, petIDs)
+
+	// Un cursor que no emitió la API => 400
+	for _, path := range []string{"/me/grants", "/me/pets"} {
+		if st, body := doReq(t, ts.URL, "GET", path+"?cursor=not-a-cursor", vetID, nil); st != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400 invalid cursor, got %d body=%s", path, st, string(body))
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_MergePets(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	otherOwnerID := "owner-2"
+	vetID := "vet-1"
+
+	targetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	sourceID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo (dup)"})
+	foreignID := createPet(t, ts.URL, otherOwnerID, map[string]any{"name": "Rex"})
+
+	note := map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "x",
+	}
+	_ = createEvent(t, ts.URL, ownerID, targetID, note)
+	movedEventID := createEvent(t, ts.URL, ownerID, sourceID, note)
+
+	grantID := inviteGrant(t, ts.URL, ownerID, sourceID, vetID, []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeEventsRead),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", vetID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	mergePath := "/pets/" + targetID + "/merge"
+
+	// 1) Validaciones: self-merge, mascota ajena, inexistente
+	if st, body := doReq(t, ts.URL, "POST", mergePath, ownerID, map[string]any{"source_pet_id": targetID}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 self-merge, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", mergePath, ownerID, map[string]any{"source_pet_id": foreignID}); st != http.StatusForbidden {
+		t.Fatalf("expected 403 merging foreign pet, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", mergePath, ownerID, map[string]any{"source_pet_id": "nope"}); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown source, got %d body=%s", st, string(body))
+	}
+
+	// 2) Merge OK
+	st, body := doReq(t, ts.URL, "POST", mergePath, ownerID, map[string]any{"source_pet_id": sourceID})
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 merge, got %d body=%s", st, string(body))
+	}
+	var merged struct {
+		EventsMoved int `json:"events_moved"`
+		GrantsMoved int `json:"grants_moved"`
+	}
+	_ = json.Unmarshal(body, &merged)
+	if merged.EventsMoved != 1 || merged.GrantsMoved != 1 {
+		t.Fatalf("expected 1 event and 1 grant moved, got %+v", merged)
+	}
+
+	// 3) Eventos reasignados (+ evento de perfil del merge)
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+targetID+"/events", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list target events, got %d body=%s", st, string(body))
+	}
+	var page struct {
+		Items []struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"items"`
+	}
+	_ = json.Unmarshal(body, &page)
+	list := page.Items
+	foundMoved, foundProfile := false, false
+	for _, e := range list {
+		if e.ID == movedEventID {
+			foundMoved = true
+		}
+		if e.Type == "PROFILE_UPDATED" {
+			foundProfile = true
+		}
+	}
+	if len(list) != 3 || !foundMoved || !foundProfile {
+		t.Fatalf("expected moved event and merge profile event on target, got %s", string(body))
+	}
+
+	// 4) El grant activo ahora da acceso al destino
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+targetID, vetID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 delegate read target after merge, got %d body=%s", st, string(body))
+	}
+
+	// 5) El origen queda archivado y fuera del listado
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+sourceID, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 get archived source, got %d body=%s", st, string(body))
+	}
+	var src struct {
+		ArchivedAt string `json:"archived_at"`
+	}
+	_ = json.Unmarshal(body, &src)
+	if src.ArchivedAt == "" {
+		t.Fatalf("expected source archived, body=%s", string(body))
+	}
+
+	st, body = doReq(t, ts.URL, "GET", "/pets", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list pets, got %d body=%s", st, string(body))
+	}
+	var mine []struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &mine)
+	if len(mine) != 1 || mine[0].ID != targetID {
+		t.Fatalf("expected only target in owner list, got %s", string(body))
+	}
+
+	// 6) Re-merge de un origen archivado => 409
+	if st, body := doReq(t, ts.URL, "POST", mergePath, ownerID, map[string]any{"source_pet_id": sourceID}); st != http.StatusConflict {
+		t.Fatalf("expected 409 merging archived source, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_MergePets_DedupsGrantsPerGrantee(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	vetID := "vet-1"
+	staffID := "staff-1"
+	sitterID := "sitter-1"
+
+	targetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	sourceID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo (dup)"})
+
+	accept := func(grantID, userID string) {
+		t.Helper()
+		if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", userID, nil); st != http.StatusOK {
+			t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+		}
+	}
+
+	// El vet tiene grant activo en ambas; en el origen además sub-delegó a su staff
+	targetGrantID := inviteGrant(t, ts.URL, ownerID, targetID, vetID, []string{string(accessgrants.ScopePetRead)})
+	accept(targetGrantID, vetID)
+	sourceGrantID := inviteGrant(t, ts.URL, ownerID, sourceID, vetID, []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeGrantsDelegate),
+	})
+	accept(sourceGrantID, vetID)
+	staffGrantID := inviteGrant(t, ts.URL, vetID, sourceID, staffID, []string{string(accessgrants.ScopePetRead)})
+	// Invitación pendiente solo en el origen
+	sitterGrantID := inviteGrant(t, ts.URL, ownerID, sourceID, sitterID, []string{string(accessgrants.ScopePetRead)})
+
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+targetID+"/merge", ownerID, map[string]any{"source_pet_id": sourceID})
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 merge, got %d body=%s", st, string(body))
+	}
+	var merged struct {
+		GrantsMoved   int `json:"grants_moved"`
+		GrantsRevoked int `json:"grants_revoked"`
+	}
+	_ = json.Unmarshal(body, &merged)
+	if merged.GrantsMoved != 1 || merged.GrantsRevoked != 2 {
+		t.Fatalf("expected 1 grant moved (pending invite) and 2 revoked (duplicate + its sub-delegate), got %s", string(body))
+	}
+
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+targetID+"/access-list", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 access-list, got %d body=%s", st, string(body))
+	}
+	var items []struct {
+		GrantID       string `json:"grant_id"`
+		GranteeUserID string `json:"grantee_user_id"`
+		Status        string `json:"status"`
+	}
+	_ = json.Unmarshal(body, &items)
+	if len(items) != 2 || items[0].GrantID != targetGrantID || items[0].Status != "active" ||
+		items[1].GrantID != sitterGrantID || items[1].Status != "invited" {
+		t.Fatalf("expected target vet grant active and moved sitter invite, got %s", string(body))
+	}
+
+	// El sub-delegado del grant duplicado pierde el acceso
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+staffGrantID+"/accept", staffID, nil); st != http.StatusConflict {
+		t.Fatalf("expected 409 accepting revoked sub-grant, got %d body=%s", st, string(body))
+	}
+
+	// Revocar el único grant del vet corta su acceso: no queda otro vigente
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+targetGrantID+"/revoke", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 revoke, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+targetID, vetID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 for vet after revoking its only grant, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_MergePets_MovesAttachments(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	targetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	sourceID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo (dup)"})
+
+	eventID := createEvent(t, ts.URL, ownerID, sourceID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "Control",
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+sourceID+"/events/"+eventID+"/attachments", ownerID, map[string]any{
+		"file_name":    "radiografia.png",
+		"content_type": "image/png",
+		"url":          "https://files.example.com/rx-1.png",
+		"size_bytes":   2048,
+	}); st != http.StatusCreated {
+		t.Fatalf("expected 201 add attachment, got %d body=%s", st, string(body))
+	}
+
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+targetID+"/merge", ownerID, map[string]any{"source_pet_id": sourceID}); st != http.StatusOK {
+		t.Fatalf("expected 200 merge, got %d body=%s", st, string(body))
+	}
+
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+targetID+"/events/"+eventID, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 get moved event, got %d body=%s", st, string(body))
+	}
+	var ev struct {
+		Attachments []struct {
+			PetID string `json:"pet_id"`
+		} `json:"attachments"`
+	}
+	_ = json.Unmarshal(body, &ev)
+	if len(ev.Attachments) != 1 || ev.Attachments[0].PetID != targetID {
+		t.Fatalf("expected attachment to follow its event to the target pet, got %s", string(body))
+	}
+}
@@ -0,0 +1,87 @@
+package router_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/metrics"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_Metrics_CountsRequestsGrantsAndEvents(t *testing.T) {
+	reg := metrics.NewRegistry()
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, Metrics: reg}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "x",
+	})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, "delegate-1", []string{"pet:read"})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", "delegate-1", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	doReq(t, ts.URL, "GET", "/pets/"+petID, "owner-1", nil)
+	doReq(t, ts.URL, "GET", "/pets/"+petID, "stranger", nil)
+
+	// Por patrón de ruta (no por ID) y status
+	if got := reg.Value(middleware.MetricRequests, "method", "GET", "route", "/pets/{petID}", "status", "200"); got != 1 {
+		t.Fatalf("expected 1 GET /pets/{petID} 200, got %d", got)
+	}
+	if got := reg.Value(middleware.MetricRequests, "method", "GET", "route", "/pets/{petID}", "status", "403"); got != 1 {
+		t.Fatalf("expected 1 GET /pets/{petID} 403, got %d", got)
+	}
+	if got := reg.Value(accessgrants.MetricGrantEvents, "type", "grant.invited"); got != 1 {
+		t.Fatalf("expected 1 grant.invited, got %d", got)
+	}
+	if got := reg.Value(accessgrants.MetricGrantEvents, "type", "grant.accepted"); got != 1 {
+		t.Fatalf("expected 1 grant.accepted, got %d", got)
+	}
+	if got := reg.Value(events.MetricEventsCreated, "type", "NOTE"); got != 1 {
+		t.Fatalf("expected 1 NOTE created, got %d", got)
+	}
+
+	// GET /metrics (sin usuario) expone las series en texto plano
+	res, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("get /metrics: %v", err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 /metrics, got %d", res.StatusCode)
+	}
+	for _, line := range []string{
+		`http_requests_total{method="GET",route="/pets/{petID}",status="200"} 1`,
+		`grant_events_total{type="grant.invited"} 1`,
+		`events_created_total{type="NOTE"} 1`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Fatalf("expected line %q in /metrics, got:\n%s", line, string(body))
+		}
+	}
+}
+
+func TestHTTP_Metrics_OpenWithRequireAuth(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, RequireAuth: true}))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("get /metrics: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 /metrics without auth, got %d", res.StatusCode)
+	}
+}
@@ -0,0 +1,199 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_PetMicrochip_CreatePatchAndValidate(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+
+	type petResp struct {
+		Microchip string `json:"microchip"`
+	}
+
+	// 1) Microchip inválido => 400
+	for _, bad := range []string{"123", "1234567890123456", "98514100-123"} {
+		if st, body := doReq(t, ts.URL, "POST", "/pets", ownerID, map[string]any{"name": "Milo", "microchip": bad}); st != http.StatusBadRequest {
+			t.Fatalf("expected 400 for microchip %q, got %d body=%s", bad, st, string(body))
+		}
+	}
+
+	// 2) Create con microchip válido se devuelve normalizado
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo", "microchip": " 985141000123ab "})
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 get pet, got %d body=%s", st, string(body))
+	}
+	var got petResp
+	_ = json.Unmarshal(body, &got)
+	if got.Microchip != "985141000123AB" {
+		t.Fatalf("expected microchip 985141000123AB, got %q", got.Microchip)
+	}
+
+	// 3) PATCH inválido => 400 y no cambia
+	if st, body := doReq(t, ts.URL, "PATCH", "/pets/"+petID, ownerID, map[string]any{"microchip": "x"}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid microchip patch, got %d body=%s", st, string(body))
+	}
+
+	// 4) PATCH sin microchip no lo toca; PATCH con "" lo limpia
+	st, body = doReq(t, ts.URL, "PATCH", "/pets/"+petID, ownerID, map[string]any{"name": "Milo II"})
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 patch name, got %d body=%s", st, string(body))
+	}
+	_ = json.Unmarshal(body, &got)
+	if got.Microchip != "985141000123AB" {
+		t.Fatalf("expected microchip untouched, got %q", got.Microchip)
+	}
+
+	st, body = doReq(t, ts.URL, "PATCH", "/pets/"+petID, ownerID, map[string]any{"microchip": ""})
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 clear microchip, got %d body=%s", st, string(body))
+	}
+	got = petResp{}
+	_ = json.Unmarshal(body, &got)
+	if got.Microchip != "" {
+		t.Fatalf("expected microchip cleared, got %q", got.Microchip)
+	}
+}
+
+func TestHTTP_MicrochipAvailable_NoOwnerLeak(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	otherID := "clinic-1"
+	_ = createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo", "microchip": "98514100ABC123"})
+
+	check := func(microchip string) (bool, []byte) {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/microchip-available?microchip="+microchip, otherID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 microchip-available, got %d body=%s", st, string(body))
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(body, &raw); err != nil {
+			t.Fatalf("invalid json: %v body=%s", err, string(body))
+		}
+		if len(raw) != 1 {
+			t.Fatalf("expected only 'available' in response, got %v", raw)
+		}
+		available, ok := raw["available"].(bool)
+		if !ok {
+			t.Fatalf("expected boolean available, got %v", raw)
+		}
+		return available, body
+	}
+
+	// 1) Tomado (también con otro casing) => false, sin datos del owner
+	for _, chip := range []string{"98514100ABC123", "98514100abc123"} {
+		available, body := check(chip)
+		if available {
+			t.Fatalf("expected taken microchip %s, body=%s", chip, string(body))
+		}
+		if strings.Contains(string(body), ownerID) || strings.Contains(string(body), "Milo") {
+			t.Fatalf("response leaks owner info: %s", string(body))
+		}
+	}
+	// 2) Libre => true
+	if available, body := check("985141000999999"); !available {
+		t.Fatalf("expected microchip available, body=%s", string(body))
+	}
+
+	// 3) Inválido => 400; sin auth => 401
+	if st, body := doReq(t, ts.URL, "GET", "/pets/microchip-available?microchip=12", otherID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid microchip, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "GET", "/pets/microchip-available?microchip=98514100ABC123", "", nil); st != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without auth, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_PetMicrochip_UniqueAcrossPets(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	createPet(t, ts.URL, "owner-1", map[string]any{"name": "Milo", "microchip": "985141000123456"})
+
+	errCode := func(body []byte) string {
+		var resp struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &resp)
+		return resp.Error.Code
+	}
+
+	// Otro owner no puede registrar el mismo chip (ni variando mayúsculas/espacios)
+	st, body := doReq(t, ts.URL, "POST", "/pets", "owner-2", map[string]any{"name": "Luna", "microchip": " 985141000123456 "})
+	if st != http.StatusConflict || errCode(body) != "microchip_taken" {
+		t.Fatalf("expected 409 microchip_taken on create, got %d body=%s", st, string(body))
+	}
+
+	// Ni asignarlo vía PATCH a otra mascota
+	otherID := createPet(t, ts.URL, "owner-2", map[string]any{"name": "Luna"})
+	st, body = doReq(t, ts.URL, "PATCH", "/pets/"+otherID, "owner-2", map[string]any{"microchip": "985141000123456"})
+	if st != http.StatusConflict || errCode(body) != "microchip_taken" {
+		t.Fatalf("expected 409 microchip_taken on patch, got %d body=%s", st, string(body))
+	}
+
+	// Re-enviar el propio chip no es conflicto
+	ownID := createPet(t, ts.URL, "owner-2", map[string]any{"name": "Nala", "microchip": "985141000999999"})
+	if st, body := doReq(t, ts.URL, "PATCH", "/pets/"+ownID, "owner-2", map[string]any{"microchip": "985141000999999"}); st != http.StatusOK {
+		t.Fatalf("expected 200 patching the same microchip, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_PetLookupByMicrochip_Permissions(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	petID := createPet(t, ts.URL, "owner-1", map[string]any{"name": "Milo", "microchip": "985141000123456"})
+	lookup := func(userID, chip string) (int, string) {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/lookup?microchip="+chip, userID, nil)
+		var p struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(body, &p)
+		return st, p.ID
+	}
+
+	// Owner lo encuentra
+	if st, id := lookup("owner-1", "985141000123456"); st != http.StatusOK || id != petID {
+		t.Fatalf("expected owner lookup 200 %s, got %d %s", petID, st, id)
+	}
+
+	// Sin grant: 404 (igual que un chip inexistente)
+	if st, _ := lookup("vet-1", "985141000123456"); st != http.StatusNotFound {
+		t.Fatalf("expected 404 for user without access, got %d", st)
+	}
+	if st, _ := lookup("owner-1", "000000000000000"); st != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown microchip, got %d", st)
+	}
+
+	// Invitado sin aceptar: 404; aceptado con pet:read: 200
+	grantID := inviteGrant(t, ts.URL, "owner-1", petID, "vet-1", []string{"pet:read"})
+	if st, _ := lookup("vet-1", "985141000123456"); st != http.StatusNotFound {
+		t.Fatalf("expected 404 for pending grant, got %d", st)
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", "vet-1", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	if st, id := lookup("vet-1", "985141000123456"); st != http.StatusOK || id != petID {
+		t.Fatalf("expected delegate lookup 200 %s, got %d %s", petID, st, id)
+	}
+
+	if st, _ := lookup("owner-1", "x"); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid microchip, got %d", st)
+	}
+}
@@ -0,0 +1,88 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_MyGrants_StatusFilter(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	invitedPet := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	activePet := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+	revokedPet := createPet(t, ts.URL, ownerID, map[string]any{"name": "Toby"})
+
+	invitedID := inviteGrant(t, ts.URL, ownerID, invitedPet, delegateID, []string{string(accessgrants.ScopePetRead)})
+	activeID := inviteGrant(t, ts.URL, ownerID, activePet, delegateID, []string{string(accessgrants.ScopePetRead)})
+	revokedID := inviteGrant(t, ts.URL, ownerID, revokedPet, delegateID, []string{string(accessgrants.ScopePetRead)})
+
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+activeID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+revokedID+"/revoke", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 revoke, got %d body=%s", st, string(body))
+	}
+
+	ids := func(query string) []string {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/me/grants"+query, delegateID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 /me/grants%s, got %d body=%s", query, st, string(body))
+		}
+		var page struct {
+			Items []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			t.Fatalf("decode /me/grants%s: %v body=%s", query, err, string(body))
+		}
+		out := make([]string, 0, len(page.Items))
+		for _, it := range page.Items {
+			out = append(out, it.ID)
+		}
+		sort.Strings(out)
+		return out
+	}
+	sorted := func(v ...string) []string {
+		sort.Strings(v)
+		return v
+	}
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"", sorted(invitedID, activeID, revokedID)},
+		{"?status=active", sorted(activeID)},
+		{"?status=INVITED,active,invited", sorted(invitedID, activeID)},
+		{"?status=declined", nil},
+		{"?status=unknown", nil},
+	}
+	for _, tc := range cases {
+		if got := ids(tc.query); !equal(got, tc.want) {
+			t.Fatalf("/me/grants%s: expected %v, got %v", tc.query, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_MyPetsAll_OwnedAndShared(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	userID := "user-1"
+	otherOwner := "owner-2"
+
+	ownPet := createPet(t, ts.URL, userID, map[string]any{"name": "Milo"})
+	sharedPet := createPet(t, ts.URL, otherOwner, map[string]any{"name": "Luna"})
+	// Compartida sin pet:read: no aparece (mismo criterio que GET /me/pets)
+	hiddenPet := createPet(t, ts.URL, otherOwner, map[string]any{"name": "Rocky"})
+
+	scopes := []string{string(accessgrants.ScopePetRead), string(accessgrants.ScopeEventsRead)}
+	grantID := inviteGrant(t, ts.URL, otherOwner, sharedPet, userID, scopes)
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", userID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	hiddenGrant := inviteGrant(t, ts.URL, otherOwner, hiddenPet, userID, []string{string(accessgrants.ScopeEventsRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+hiddenGrant+"/accept", userID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+
+	st, body := doReq(t, ts.URL, "GET", "/me/pets/all", userID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", st, string(body))
+	}
+	var items []struct {
+		Pet struct {
+			ID string `json:"id"`
+		} `json:"pet"`
+		Relationship string `json:"relationship"`
+		Grant        *struct {
+			ID string `json:"id"`
+		} `json:"grant"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		t.Fatalf("decode: %v body=%s", err, string(body))
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 pets (owned + shared), got %d body=%s", len(items), string(body))
+	}
+
+	own, shared := items[0], items[1]
+	if own.Pet.ID != ownPet || own.Relationship != "owner" || own.Grant != nil || len(own.Scopes) != 0 {
+		t.Fatalf("unexpected owned entry: %+v", own)
+	}
+	if shared.Pet.ID != sharedPet || shared.Relationship != "delegate" {
+		t.Fatalf("unexpected shared entry: %+v", shared)
+	}
+	if shared.Grant == nil || shared.Grant.ID != grantID || len(shared.Scopes) != len(scopes) {
+		t.Fatalf("expected grant %s with scopes %v, got %+v", grantID, scopes, shared)
+	}
+
+	// Los endpoints existentes no cambian
+	if st, body := doReq(t, ts.URL, "GET", "/me/pets", userID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 /me/pets, got %d body=%s", st, string(body))
+	}
+
+	// El owner del otro lado ve sus dos mascotas como owner
+	_, body = doReq(t, ts.URL, "GET", "/me/pets/all", otherOwner, nil)
+	items = nil
+	if err := json.Unmarshal(body, &items); err != nil || len(items) != 2 {
+		t.Fatalf("expected 2 owned pets for %s, got %s", otherOwner, string(body))
+	}
+	for _, it := range items {
+		if it.Relationship != "owner" {
+			t.Fatalf("expected owner relationship, got %+v", it)
+		}
+	}
+
+	if st, _ := doReq(t, ts.URL, "GET", "/me/pets/all", "", nil); st != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without user, got %d", st)
+	}
+}
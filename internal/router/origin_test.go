@@ -0,0 +1,172 @@
+package router_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/adapters/auth/jwt"
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_CreateEvent_OriginOnlyForIntegrations(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	clinicUserID := "clinic-svc-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, clinicUserID, []string{
+		string(accessgrants.ScopeEventsCreate),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", clinicUserID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	payload := map[string]any{
+		"type":             "MEDICAL_VISIT",
+		"occurred_at":      time.Now().UTC().Format(time.RFC3339),
+		"title":            "Control anual",
+		"origin_clinic_id": "clinic-42",
+	}
+
+	type eventResp struct {
+		ActorType      string `json:"actor_type"`
+		Source         string `json:"source"`
+		OriginClinicID string `json:"origin_clinic_id"`
+		OriginSystem   string `json:"origin_system"`
+	}
+
+	// 1) Integración: se guarda el origen
+	st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets/"+petID+"/events", map[string]string{
+		"X-Debug-User-ID":            clinicUserID,
+		"X-Debug-Integration-System": "vetsoft",
+	}, payload)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 integration create, got %d body=%s", st, string(body))
+	}
+	var created eventResp
+	_ = json.Unmarshal(body, &created)
+	if created.ActorType != "EXTERNAL_SYSTEM" || created.Source != "integration" {
+		t.Fatalf("expected EXTERNAL_SYSTEM/integration, got %s/%s", created.ActorType, created.Source)
+	}
+	if created.OriginClinicID != "clinic-42" || created.OriginSystem != "vetsoft" {
+		t.Fatalf("expected origin clinic-42/vetsoft, got %q/%q", created.OriginClinicID, created.OriginSystem)
+	}
+
+	// 2) Usuario normal: el origen se ignora
+	st, body = doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", ownerID, payload)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 owner create, got %d body=%s", st, string(body))
+	}
+	var manual eventResp
+	_ = json.Unmarshal(body, &manual)
+	if manual.OriginClinicID != "" || manual.OriginSystem != "" {
+		t.Fatalf("expected origin ignored for normal user, got %q/%q", manual.OriginClinicID, manual.OriginSystem)
+	}
+
+	// 3) El origen persiste y se expone al listar
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list events, got %d body=%s", st, string(body))
+	}
+	var page struct {
+		Items []eventResp `json:"items"`
+	}
+	_ = json.Unmarshal(body, &page)
+	list := page.Items
+	withOrigin := 0
+	for _, e := range list {
+		if e.OriginClinicID == "clinic-42" && e.ActorType == "EXTERNAL_SYSTEM" {
+			withOrigin++
+		}
+	}
+	if len(list) != 2 || withOrigin != 1 {
+		t.Fatalf("expected 2 events with 1 from clinic-42, got %d/%d body=%s", len(list), withOrigin, string(body))
+	}
+}
+
+// signHS256 arma un JWT HS256 con claims (para probar con el verifier de producción).
+func signHS256(t *testing.T, secret []byte, claims map[string]any) string {
+	t.Helper()
+
+	enc := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	input := enc(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + enc(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestHTTP_CreateEvent_OriginFromIntegrationToken(t *testing.T) {
+	secret := []byte("test-secret")
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: jwt.NewVerifier(jwt.Config{Secret: secret})}))
+	defer ts.Close()
+
+	exp := time.Now().Add(time.Hour).Unix()
+	owner := map[string]string{"Authorization": "Bearer " + signHS256(t, secret, map[string]any{"sub": "owner-1", "exp": exp})}
+	clinic := map[string]string{"Authorization": "Bearer " + signHS256(t, secret, map[string]any{
+		"sub":                "clinic-svc-1",
+		"integration_system": "vetsoft",
+		"exp":                exp,
+	})}
+
+	st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets", owner, map[string]any{"name": "Milo"})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 create pet, got %d body=%s", st, string(body))
+	}
+	var pet struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &pet)
+
+	st, body = doReqWithHeaders(t, ts.URL, "POST", "/pets/"+pet.ID+"/grants", owner, map[string]any{
+		"grantee_user_id": "clinic-svc-1",
+		"scopes":          []string{string(accessgrants.ScopeEventsCreate)},
+	})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 invite, got %d body=%s", st, string(body))
+	}
+	var grant struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &grant)
+	if st, body := doReqWithHeaders(t, ts.URL, "POST", "/grants/"+grant.ID+"/accept", clinic, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	// Token de integración: el evento guarda su origen
+	payload := map[string]any{
+		"type":             "MEDICAL_VISIT",
+		"occurred_at":      time.Now().UTC().Format(time.RFC3339),
+		"title":            "Control anual",
+		"origin_clinic_id": "clinic-42",
+	}
+	st, body = doReqWithHeaders(t, ts.URL, "POST", "/pets/"+pet.ID+"/events", clinic, payload)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 integration create, got %d body=%s", st, string(body))
+	}
+	var created struct {
+		ActorType      string `json:"actor_type"`
+		Source         string `json:"source"`
+		OriginClinicID string `json:"origin_clinic_id"`
+		OriginSystem   string `json:"origin_system"`
+	}
+	_ = json.Unmarshal(body, &created)
+	if created.ActorType != "EXTERNAL_SYSTEM" || created.Source != "integration" ||
+		created.OriginClinicID != "clinic-42" || created.OriginSystem != "vetsoft" {
+		t.Fatalf("expected integration event from vetsoft/clinic-42, got %s", string(body))
+	}
+}
@@ -0,0 +1,148 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListEvents_CursorPagination(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	// 7 eventos; dos comparten occurred_at para ejercitar el desempate por id.
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	want := map[string]bool{}
+	for i := 0; i < 7; i++ {
+		at := base.Add(time.Duration(i) * time.Hour)
+		if i == 4 {
+			at = base.Add(3 * time.Hour)
+		}
+		id := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": at.Format(time.RFC3339),
+			"title":       "Nota",
+		})
+		want[id] = true
+	}
+
+	type page struct {
+		Items []struct {
+			ID         string `json:"id"`
+			OccurredAt string `json:"occurred_at"`
+		} `json:"items"`
+		NextCursor string `json:"next_cursor"`
+	}
+
+	seen := map[string]bool{}
+	cursor := ""
+	pages := 0
+	lastOccurred := ""
+	for {
+		path := "/pets/" + petID + "/events?limit=3"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+		st, body := doReq(t, ts.URL, "GET", path, ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 list page %d, got %d body=%s", pages+1, st, string(body))
+		}
+		var p page
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Fatalf("invalid page json: %v body=%s", err, string(body))
+		}
+		pages++
+
+		for _, e := range p.Items {
+			if seen[e.ID] {
+				t.Fatalf("duplicate event %s on page %d", e.ID, pages)
+			}
+			if lastOccurred != "" && e.OccurredAt > lastOccurred {
+				t.Fatalf("events out of order: %s after %s", e.OccurredAt, lastOccurred)
+			}
+			seen[e.ID] = true
+			lastOccurred = e.OccurredAt
+		}
+
+		if p.NextCursor == "" {
+			break
+		}
+		if pages > 3 {
+			t.Fatalf("expected at most 3 pages, still got next_cursor")
+		}
+		cursor = p.NextCursor
+	}
+
+	if pages != 3 {
+		t.Fatalf("expected 3 pages (3+3+1), got %d", pages)
+	}
+	if len(seen) != len(want) {
+		t.Fatalf("expected %d distinct events, got %d", len(want), len(seen))
+	}
+	for id := range want {
+		if !seen[id] {
+			t.Fatalf("event %s missing from pages", id)
+		}
+	}
+
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?cursor=not-a-cursor", ownerID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 invalid cursor, got %d", st)
+	}
+}
+
+func TestHTTP_ListEvents_PageMetadata(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "NOTE",
+			"occurred_at": base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			"title":       "Nota",
+		})
+	}
+
+	type meta struct {
+		Count      int    `json:"count"`
+		Limit      int    `json:"limit"`
+		HasMore    bool   `json:"has_more"`
+		NextCursor string `json:"next_cursor"`
+	}
+	get := func(query string) meta {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events"+query, ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", st, string(body))
+		}
+		var m meta
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("invalid json: %v body=%s", err, string(body))
+		}
+		return m
+	}
+
+	// Hay más filas que el límite
+	if m := get("?limit=2"); m.Count != 2 || m.Limit != 2 || !m.HasMore || m.NextCursor == "" {
+		t.Fatalf("expected count=2 limit=2 has_more with cursor, got %+v", m)
+	}
+	// Límite exacto: la fila extra no existe => sin más
+	if m := get("?limit=3"); m.Count != 3 || m.Limit != 3 || m.HasMore || m.NextCursor != "" {
+		t.Fatalf("expected count=3 limit=3 without more, got %+v", m)
+	}
+	// Default
+	if m := get(""); m.Count != 3 || m.Limit != 50 || m.HasMore {
+		t.Fatalf("expected count=3 limit=50 without more, got %+v", m)
+	}
+}
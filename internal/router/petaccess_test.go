@@ -0,0 +1,77 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_PetAccess(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	activeID := inviteGrant(t, ts.URL, ownerID, petID, "vet-1", []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeEventsRead),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+activeID+"/accept", "vet-1", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+	invitedID := inviteGrant(t, ts.URL, ownerID, petID, "vet-2", []string{string(accessgrants.ScopePetRead)})
+
+	type accessResp struct {
+		IsOwner     bool     `json:"is_owner"`
+		GrantStatus string   `json:"grant_status"`
+		Scopes      []string `json:"scopes"`
+		GrantID     string   `json:"grant_id"`
+	}
+	get := func(userID string) accessResp {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/access", userID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d body=%s", userID, st, string(body))
+		}
+		var out accessResp
+		if err := json.Unmarshal(body, &out); err != nil {
+			t.Fatalf("invalid json: %v body=%s", err, string(body))
+		}
+		return out
+	}
+
+	// Owner: todos los scopes, sin grant
+	if got := get(ownerID); !got.IsOwner || got.GrantStatus != "none" || len(got.Scopes) != len(accessgrants.AllScopes()) || got.GrantID != "" {
+		t.Fatalf("unexpected owner access %+v", got)
+	}
+
+	// Delegado activo: los scopes de su grant
+	got := get("vet-1")
+	if got.IsOwner || got.GrantStatus != "active" || got.GrantID != activeID ||
+		len(got.Scopes) != 2 || got.Scopes[0] != string(accessgrants.ScopePetRead) || got.Scopes[1] != string(accessgrants.ScopeEventsRead) {
+		t.Fatalf("unexpected active delegate access %+v", got)
+	}
+
+	// Invitado sin aceptar: sin scopes, con el grant a aceptar
+	if got := get("vet-2"); got.IsOwner || got.GrantStatus != "invited" || len(got.Scopes) != 0 || got.GrantID != invitedID {
+		t.Fatalf("unexpected invited access %+v", got)
+	}
+
+	// Sin acceso: 200 con none (no 403), scopes vacío (no null)
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/access", "stranger", nil)
+	if st != http.StatusOK || string(body) != `{"is_owner":false,"grant_status":"none","scopes":[]}`+"\n" {
+		t.Fatalf("expected none for stranger, got %d body=%s", st, string(body))
+	}
+
+	if st, body := doReq(t, ts.URL, "GET", "/pets/does-not-exist/access", "stranger", nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing pet, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/access", "", nil); st != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without user, got %d body=%s", st, string(body))
+	}
+}
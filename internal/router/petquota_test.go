@@ -0,0 +1,83 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/ports/capabilities"
+	"pet-clinical-history/internal/router"
+)
+
+// stubQuotas devuelve la misma cuota para cualquier usuario (o err si viene).
+type stubQuotas struct {
+	limit int
+	err   error
+	calls []capabilities.CapabilityCheck
+}
+
+func (s *stubQuotas) Quota(_ context.Context, in capabilities.CapabilityCheck) (int, bool, error) {
+	s.calls = append(s.calls, in)
+	if s.err != nil {
+		return 0, false, s.err
+	}
+	return s.limit, true, nil
+}
+
+func TestHTTP_CreatePet_PlanQuota(t *testing.T) {
+	quotas := &stubQuotas{limit: 1}
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, Quotas: quotas}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	st, body := doReq(t, ts.URL, "POST", "/pets", ownerID, map[string]any{"name": "Luna"})
+	if st != http.StatusPaymentRequired {
+		t.Fatalf("expected 402 second pet over quota, got %d body=%s", st, string(body))
+	}
+	var e struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil || e.Error.Code != "quota_exceeded" {
+		t.Fatalf("expected error.code quota_exceeded, got %s", string(body))
+	}
+	if last := quotas.calls[len(quotas.calls)-1]; last.UserID != ownerID || last.FeatureKey != "pets:max" {
+		t.Fatalf("unexpected quota lookup: %+v", last)
+	}
+
+	// La cuota es por owner
+	createPet(t, ts.URL, "owner-2", map[string]any{"name": "Rocky"})
+
+	// Al borrar una mascota se libera el cupo
+	st, body = doReq(t, ts.URL, "GET", "/pets", ownerID, nil)
+	var owned []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &owned); err != nil || st != http.StatusOK || len(owned) != 1 {
+		t.Fatalf("expected 1 pet for owner, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "DELETE", "/pets/"+owned[0].ID, ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 delete pet, got %d body=%s", st, string(body))
+	}
+	createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+}
+
+func TestHTTP_CreatePet_PlanQuotaResolverDown(t *testing.T) {
+	down := &stubQuotas{err: errors.New("plans-features down")}
+
+	closed := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, Quotas: down}))
+	defer closed.Close()
+	if st, _ := doReq(t, closed.URL, "POST", "/pets", "owner-1", map[string]any{"name": "Milo"}); st != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when failing closed, got %d", st)
+	}
+
+	open := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, Quotas: down, QuotaFailOpen: true}))
+	defer open.Close()
+	createPet(t, open.URL, "owner-1", map[string]any{"name": "Milo"})
+}
@@ -0,0 +1,72 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListPets_FilterBySpeciesAndName(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	rocky := createPet(t, ts.URL, ownerID, map[string]any{"name": "Rocky", "species": "dog"})
+	rocco := createPet(t, ts.URL, ownerID, map[string]any{"name": "Rocco", "species": "cat"})
+	luna := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna", "species": "dog"})
+	// Mascota de otro owner: nunca aparece
+	createPet(t, ts.URL, "owner-2", map[string]any{"name": "Rocky", "species": "dog"})
+
+	list := func(query string) []string {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets"+query, ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("GET /pets%s: expected 200, got %d body=%s", query, st, string(body))
+		}
+		var got []struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("decode: %v body=%s", err, string(body))
+		}
+		ids := make([]string, 0, len(got))
+		for _, p := range got {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+	expect := func(query string, want ...string) {
+		t.Helper()
+		got := list(query)
+		if len(got) != len(want) {
+			t.Fatalf("GET /pets%s: expected %v, got %v", query, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("GET /pets%s: expected %v, got %v", query, want, got)
+			}
+		}
+	}
+
+	// Sin parámetros: todas, en orden de creación
+	expect("", rocky, rocco, luna)
+
+	// Solo especie
+	expect("?species=dog", rocky, luna)
+	expect("?species=cat", rocco)
+
+	// Substring del nombre, sin distinguir mayúsculas
+	expect("?q=ROC", rocky, rocco)
+	expect("?q=un", luna)
+
+	// Combinados
+	expect("?species=dog&q=roc", rocky)
+	expect("?species=cat&q=luna")
+
+	// Los comodines se buscan literalmente
+	expect("?q=" + url.QueryEscape("%"))
+}
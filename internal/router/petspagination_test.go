@@ -0,0 +1,105 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListPets_SortAndPagination(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	// Orden de creación distinto del alfabético
+	for _, name := range []string{"Milo", "Bruno", "Luna", "Coco"} {
+		createPet(t, ts.URL, ownerID, map[string]any{"name": name})
+	}
+	createPet(t, ts.URL, "owner-2", map[string]any{"name": "Zeus"})
+
+	names := func(query string) []string {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets"+query, ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("GET /pets%s: expected 200, got %d body=%s", query, st, string(body))
+		}
+		var items []struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(body, &items); err != nil {
+			t.Fatalf("decode: %v body=%s", err, string(body))
+		}
+		out := make([]string, 0, len(items))
+		for _, it := range items {
+			out = append(out, it.Name)
+		}
+		return out
+	}
+	expect := func(query string, want ...string) {
+		t.Helper()
+		got := names(query)
+		if len(got) != len(want) {
+			t.Fatalf("GET /pets%s: expected %v, got %v", query, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("GET /pets%s: expected %v, got %v", query, want, got)
+			}
+		}
+	}
+
+	// Default: created_at asc, sin límite
+	expect("", "Milo", "Bruno", "Luna", "Coco")
+	expect("?sort=created_at&order=desc", "Coco", "Luna", "Bruno", "Milo")
+
+	expect("?sort=name&order=desc", "Milo", "Luna", "Coco", "Bruno")
+	expect("?sort=name", "Bruno", "Coco", "Luna", "Milo")
+
+	// limit menor al total y offset sobre el mismo orden
+	expect("?sort=name&order=desc&limit=2", "Milo", "Luna")
+	expect("?sort=name&order=desc&limit=2&offset=2", "Coco", "Bruno")
+	expect("?limit=3", "Milo", "Bruno", "Luna")
+	expect("?offset=10")
+
+	// Los filtros se aplican antes de paginar
+	expect("?q=o&sort=name&limit=2", "Bruno", "Coco")
+
+	// updated_at: la última editada queda al final
+	_, body := doReq(t, ts.URL, "GET", "/pets?q=luna", ownerID, nil)
+	var found []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &found); err != nil || len(found) != 1 {
+		t.Fatalf("expected Luna, got %s", string(body))
+	}
+	lunaID := found[0].ID
+	time.Sleep(5 * time.Millisecond)
+	if st, body := doReq(t, ts.URL, "PATCH", "/pets/"+lunaID, ownerID, map[string]any{"notes": "editada"}); st != http.StatusOK {
+		t.Fatalf("expected 200 patch, got %d body=%s", st, string(body))
+	}
+	expect("?sort=updated_at&order=desc&limit=1", "Luna")
+
+	// sort=last_activity también pagina
+	if got := names("?sort=last_activity&limit=2"); len(got) != 2 {
+		t.Fatalf("expected 2 pets with last_activity + limit, got %v", got)
+	}
+
+	for _, q := range []string{
+		"?sort=species",
+		"?sort=name%3BDROP",
+		"?order=up",
+		"?limit=0",
+		"?limit=201",
+		"?limit=abc",
+		"?offset=-1",
+		"?sort=last_activity&order=asc",
+	} {
+		if st, body := doReq(t, ts.URL, "GET", "/pets"+q, ownerID, nil); st != http.StatusBadRequest {
+			t.Fatalf("GET /pets%s: expected 400, got %d body=%s", q, st, string(body))
+		}
+	}
+}
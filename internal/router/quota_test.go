@@ -0,0 +1,43 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_CreateEvent_MaxEventsPerPet(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, MaxEventsPerPet: 2}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	note := map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "x",
+	}
+
+	first := createEvent(t, ts.URL, ownerID, petID, note)
+	_ = createEvent(t, ts.URL, ownerID, petID, note)
+
+	// 1) Tercero supera el tope => 402
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", ownerID, note); st != http.StatusPaymentRequired {
+		t.Fatalf("expected 402 over quota, got %d body=%s", st, string(body))
+	}
+
+	// 2) El tope es por mascota
+	otherPetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+	_ = createEvent(t, ts.URL, ownerID, otherPetID, note)
+
+	// 3) Anular uno libera un lugar
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+first+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", ownerID, note); st != http.StatusCreated {
+		t.Fatalf("expected 201 after void frees a slot, got %d body=%s", st, string(body))
+	}
+}
@@ -0,0 +1,53 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_RateLimit(t *testing.T) {
+	// 2 requests seguidos y luego 1 cada 100ms
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, RateLimit: 10, RateLimitBurst: 2}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	for i := 0; i < 2; i++ {
+		if st, body := doReq(t, ts.URL, "GET", "/pets", ownerID, nil); st != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d body=%s", i, st, string(body))
+		}
+	}
+
+	req, _ := http.NewRequest("GET", ts.URL+"/pets", nil)
+	req.Header.Set("X-Debug-User-ID", ownerID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after burst, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header")
+	}
+
+	// Otro usuario no se ve afectado y /health está exento
+	if st, _ := doReq(t, ts.URL, "GET", "/pets", "owner-2", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 for other user, got %d", st)
+	}
+	for i := 0; i < 5; i++ {
+		if st, _ := doReq(t, ts.URL, "GET", "/health", ownerID, nil); st != http.StatusOK {
+			t.Fatalf("expected /health exempt, got %d", st)
+		}
+	}
+
+	// Pasada la ventana se recupera
+	time.Sleep(150 * time.Millisecond)
+	if st, _ := doReq(t, ts.URL, "GET", "/pets", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 after refill, got %d", st)
+	}
+}
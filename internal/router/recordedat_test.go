@@ -0,0 +1,137 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/adapters/auth/jwt"
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_CreateEvent_RecordedAtOnlyForIntegrations(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	clinicUserID := "clinic-svc-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, clinicUserID, []string{
+		string(accessgrants.ScopeEventsCreate),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", clinicUserID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	integration := map[string]string{
+		"X-Debug-User-ID":            clinicUserID,
+		"X-Debug-Integration-System": "vetsoft",
+	}
+	backfill := time.Date(2021, 3, 10, 9, 0, 0, 0, time.UTC)
+	payload := map[string]any{
+		"type":        "MEDICAL_VISIT",
+		"occurred_at": backfill.Add(-time.Hour).Format(time.RFC3339),
+		"title":       "Control importado",
+		"recorded_at": backfill.Format(time.RFC3339),
+	}
+
+	type eventResp struct {
+		RecordedAt time.Time `json:"recorded_at"`
+	}
+
+	// 1) Integración: se respeta la fecha de carga original
+	st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets/"+petID+"/events", integration, payload)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 integration create, got %d body=%s", st, string(body))
+	}
+	var imported eventResp
+	_ = json.Unmarshal(body, &imported)
+	if !imported.RecordedAt.Equal(backfill) {
+		t.Fatalf("expected recorded_at %s, got %s", backfill, imported.RecordedAt)
+	}
+
+	// 2) Usuario normal: recorded_at se ignora y se usa "ahora"
+	before := time.Now().Add(-time.Minute)
+	st, body = doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", ownerID, payload)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 owner create, got %d body=%s", st, string(body))
+	}
+	var manual eventResp
+	_ = json.Unmarshal(body, &manual)
+	if manual.RecordedAt.Before(before) {
+		t.Fatalf("expected recorded_at ignored for normal user, got %s", manual.RecordedAt)
+	}
+
+	// 3) Integración con fecha futura: 400
+	future := map[string]any{
+		"type":        "MEDICAL_VISIT",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "Control",
+		"recorded_at": time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+	}
+	if st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets/"+petID+"/events", integration, future); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 future recorded_at, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_CreateEvent_RecordedAtFromAdminToken(t *testing.T) {
+	secret := []byte("test-secret")
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: jwt.NewVerifier(jwt.Config{Secret: secret})}))
+	defer ts.Close()
+
+	exp := time.Now().Add(time.Hour).Unix()
+	bearer := func(claims map[string]any) map[string]string {
+		return map[string]string{"Authorization": "Bearer " + signHS256(t, secret, claims)}
+	}
+	// Mismo usuario, con y sin el claim de admin
+	admin := bearer(map[string]any{"sub": "owner-1", "admin": true, "exp": exp})
+	user := bearer(map[string]any{"sub": "owner-1", "exp": exp})
+
+	st, body := doReqWithHeaders(t, ts.URL, "POST", "/pets", user, map[string]any{"name": "Milo"})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 create pet, got %d body=%s", st, string(body))
+	}
+	var pet struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &pet)
+
+	backfill := time.Date(2021, 3, 10, 9, 0, 0, 0, time.UTC)
+	payload := map[string]any{
+		"type":        "NOTE",
+		"occurred_at": backfill.Add(-time.Hour).Format(time.RFC3339),
+		"title":       "Nota importada",
+		"recorded_at": backfill.Format(time.RFC3339),
+	}
+	type eventResp struct {
+		ActorType  string    `json:"actor_type"`
+		RecordedAt time.Time `json:"recorded_at"`
+	}
+
+	// Admin: se respeta recorded_at, pero no es una integración
+	st, body = doReqWithHeaders(t, ts.URL, "POST", "/pets/"+pet.ID+"/events", admin, payload)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 admin create, got %d body=%s", st, string(body))
+	}
+	var imported eventResp
+	_ = json.Unmarshal(body, &imported)
+	if !imported.RecordedAt.Equal(backfill) || imported.ActorType != "OWNER_USER" {
+		t.Fatalf("expected OWNER_USER with recorded_at %s, got %s", backfill, string(body))
+	}
+
+	// Sin el claim: recorded_at se ignora
+	before := time.Now().Add(-time.Minute)
+	st, body = doReqWithHeaders(t, ts.URL, "POST", "/pets/"+pet.ID+"/events", user, payload)
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 user create, got %d body=%s", st, string(body))
+	}
+	var manual eventResp
+	_ = json.Unmarshal(body, &manual)
+	if manual.RecordedAt.Before(before) {
+		t.Fatalf("expected recorded_at ignored without admin claim, got %s", manual.RecordedAt)
+	}
+}
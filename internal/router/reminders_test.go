@@ -0,0 +1,219 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_MyReminders_MergesAndSortsAcrossPets(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	now := time.Now().UTC()
+	day := 24 * time.Hour
+
+	milo := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	luna := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+	other := createPet(t, ts.URL, "owner-2", map[string]any{"name": "Ajeno"})
+
+	preventive := func(petID, userID, typ string, due time.Time) string {
+		return createEvent(t, ts.URL, userID, petID, map[string]any{
+			"type":        typ,
+			"occurred_at": now.Add(-day).Format(time.RFC3339),
+			"title":       "Tratamiento",
+			"preventive": map[string]any{
+				"product":  typ + "-product",
+				"next_due": due.Format(time.RFC3339),
+			},
+		})
+	}
+
+	miloFar := preventive(milo, ownerID, "DEWORMING", now.Add(20*day))
+	lunaSoon := preventive(luna, ownerID, "FLEA_TREATMENT", now.Add(5*day))
+	miloMid := preventive(milo, ownerID, "FLEA_TREATMENT", now.Add(10*day))
+	_ = preventive(luna, ownerID, "DEWORMING", now.Add(60*day))   // fuera de ventana
+	_ = preventive(other, "owner-2", "DEWORMING", now.Add(3*day)) // mascota ajena
+
+	st, body := doReq(t, ts.URL, "GET", "/me/reminders?within=30d", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 reminders, got %d body=%s", st, string(body))
+	}
+
+	var items []struct {
+		PetID   string    `json:"pet_id"`
+		PetName string    `json:"pet_name"`
+		EventID string    `json:"event_id"`
+		DueAt   time.Time `json:"due_at"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		t.Fatalf("unmarshal: %v body=%s", err, string(body))
+	}
+
+	want := []struct{ eventID, petName string }{
+		{lunaSoon, "Luna"},
+		{miloMid, "Milo"},
+		{miloFar, "Milo"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("expected %d reminders, got %d: %s", len(want), len(items), string(body))
+	}
+	for i, w := range want {
+		if items[i].EventID != w.eventID || items[i].PetName != w.petName {
+			t.Fatalf("reminder #%d: expected event=%s pet=%s, got %#v", i, w.eventID, w.petName, items[i])
+		}
+		if i > 0 && items[i].DueAt.Before(items[i-1].DueAt) {
+			t.Fatalf("reminders not sorted by due date: %s", string(body))
+		}
+	}
+
+	// Un evento anulado deja de generar recordatorio
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+luna+"/events/"+lunaSoon+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+	_, body = doReq(t, ts.URL, "GET", "/me/reminders?within=7d", ownerID, nil)
+	items = nil
+	_ = json.Unmarshal(body, &items)
+	if len(items) != 0 {
+		t.Fatalf("expected no reminders within 7d after void, got %s", string(body))
+	}
+
+	if st, _ := doReq(t, ts.URL, "GET", "/me/reminders?within=abc", ownerID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid within, got %d", st)
+	}
+}
+
+func TestHTTP_CreateEvent_RejectsPreventiveOnOtherTypes(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	petID := createPet(t, ts.URL, "owner-1", map[string]any{"name": "Milo"})
+	st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", "owner-1", map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"preventive":  map[string]any{"product": "x"},
+	})
+	if st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for preventive on NOTE, got %d", st)
+	}
+}
+
+func TestHTTP_PetReminders_MergesVaccinesAndPreventive(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+	now := time.Now().UTC()
+	day := 24 * time.Hour
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	otherPetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+
+	vaccine := func(petID, name string, due time.Time, visibility string) string {
+		return createEvent(t, ts.URL, ownerID, petID, map[string]any{
+			"type":        "VACCINE",
+			"occurred_at": now.Add(-day).Format(time.RFC3339),
+			"title":       "Vacuna",
+			"visibility":  visibility,
+			"vaccine": map[string]any{
+				"name":     name,
+				"lot":      "L-123",
+				"next_due": due.Format("2006-01-02"),
+			},
+		})
+	}
+
+	rabies := vaccine(petID, "Antirrábica", now.Add(20*day), "shared_with_delegates")
+	private := vaccine(petID, "Séxtuple", now.Add(3*day), "private")
+	_ = vaccine(petID, "Tos de las perreras", now.Add(90*day), "shared_with_delegates") // fuera de ventana
+	_ = vaccine(otherPetID, "Antirrábica", now.Add(2*day), "shared_with_delegates")     // otra mascota
+	deworming := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "DEWORMING",
+		"occurred_at": now.Add(-day).Format(time.RFC3339),
+		"preventive": map[string]any{
+			"product":  "Drontal",
+			"next_due": now.Add(10 * day).Format(time.RFC3339),
+		},
+	})
+
+	// El detalle de vacuna se devuelve en el evento
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/"+rabies, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 get vaccine event, got %d body=%s", st, string(body))
+	}
+	var ev struct {
+		Vaccine *struct {
+			Name    string `json:"name"`
+			Lot     string `json:"lot"`
+			NextDue string `json:"next_due"`
+		} `json:"vaccine"`
+	}
+	if err := json.Unmarshal(body, &ev); err != nil || ev.Vaccine == nil || ev.Vaccine.Name != "Antirrábica" || ev.Vaccine.Lot != "L-123" || ev.Vaccine.NextDue == "" {
+		t.Fatalf("unexpected vaccine detail: %s", string(body))
+	}
+
+	type reminder struct {
+		EventID string `json:"event_id"`
+		Kind    string `json:"kind"`
+		Label   string `json:"label"`
+	}
+	list := func(userID string) []reminder {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/reminders?within=30d", userID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 pet reminders, got %d body=%s", st, string(body))
+		}
+		var items []reminder
+		if err := json.Unmarshal(body, &items); err != nil {
+			t.Fatalf("unmarshal: %v body=%s", err, string(body))
+		}
+		return items
+	}
+
+	// Owner: vacunas y preventivos mezclados, en orden ascendente
+	items := list(ownerID)
+	want := []reminder{
+		{private, "vaccine", "Séxtuple"},
+		{deworming, "deworming", "Drontal"},
+		{rabies, "vaccine", "Antirrábica"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("expected %d reminders, got %#v", len(want), items)
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Fatalf("reminder #%d: expected %#v, got %#v", i, want[i], items[i])
+		}
+	}
+
+	// Delegado: requiere events:read y no ve los de eventos privados
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/reminders", delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 delegate without grant, got %d", st)
+	}
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{"events:read"})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+	if items := list(delegateID); len(items) != 2 || items[0].EventID != deworming || items[1].EventID != rabies {
+		t.Fatalf("expected delegate reminders without private, got %#v", items)
+	}
+
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID+"/reminders?within=abc", ownerID, nil); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid within, got %d", st)
+	}
+
+	// vaccine solo aplica a VACCINE
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events", ownerID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": now.Format(time.RFC3339),
+		"vaccine":     map[string]any{"name": "x"},
+	}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for vaccine on NOTE, got %d", st)
+	}
+}
@@ -0,0 +1,74 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"pet-clinical-history/internal/platform/logger"
+	"pet-clinical-history/internal/router"
+)
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// captureLogger guarda las líneas en memoria para inspeccionarlas.
+type captureLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *captureLogger) With(map[string]any) logger.Logger  { return l }
+func (l *captureLogger) Debug(msg string, f map[string]any) { l.add("debug", msg, f) }
+func (l *captureLogger) Info(msg string, f map[string]any)  { l.add("info", msg, f) }
+func (l *captureLogger) Warn(msg string, f map[string]any)  { l.add("warn", msg, f) }
+func (l *captureLogger) Error(msg string, f map[string]any) { l.add("error", msg, f) }
+
+func (l *captureLogger) add(level, msg string, f map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: f})
+}
+
+func (l *captureLogger) last() logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.entries[len(l.entries)-1]
+}
+
+func TestHTTP_RequestLogger_OneLinePerRequest(t *testing.T) {
+	logs := &captureLogger{}
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, Logger: logs}))
+	defer ts.Close()
+
+	petID := createPet(t, ts.URL, "owner-1", map[string]any{"name": "Milo"})
+	e := logs.last()
+	if e.fields["method"] != "POST" || e.fields["path"] != "/pets" || e.fields["status"] != http.StatusCreated {
+		t.Fatalf("unexpected log fields: %#v", e.fields)
+	}
+	if e.fields["user_id"] != "owner-1" {
+		t.Fatalf("expected user_id in log, got %#v", e.fields)
+	}
+	if id, _ := e.fields["request_id"].(string); id == "" {
+		t.Fatalf("expected request_id in log, got %#v", e.fields)
+	}
+	if _, ok := e.fields["duration_ms"].(float64); !ok {
+		t.Fatalf("expected duration_ms in log, got %#v", e.fields)
+	}
+
+	// Sin auth: status capturado del handler y sin user_id
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, "", nil); st != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", st)
+	}
+	e = logs.last()
+	if e.fields["status"] != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 in log, got %#v", e.fields)
+	}
+	if _, ok := e.fields["user_id"]; ok {
+		t.Fatalf("expected no user_id for anonymous request, got %#v", e.fields)
+	}
+}
@@ -0,0 +1,49 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_RequireAuth_MiddlewareRejectsAnonymous(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, RequireAuth: true}))
+	defer ts.Close()
+
+	// Ruta protegida sin X-Debug-User-ID: el 401 lo emite el middleware (WWW-Authenticate),
+	// incluso para una ruta inexistente que de otro modo sería 404.
+	for _, path := range []string{"/me/pets", "/pets/does-not-exist/events", "/no-such-route"} {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("get %s: %v", path, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected 401 for %s, got %d", path, res.StatusCode)
+		}
+		if res.Header.Get("WWW-Authenticate") != "Bearer" {
+			t.Fatalf("expected WWW-Authenticate from middleware for %s, got %q", path, res.Header.Get("WWW-Authenticate"))
+		}
+	}
+
+	// Con claims, el request llega al handler.
+	if st, body := doReq(t, ts.URL, "GET", "/me/pets", "owner-1", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 /me/pets with claims, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_RequireAuth_HealthStaysOpen(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil, RequireAuth: true}))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/health")
+	if err != nil {
+		t.Fatalf("get /health: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 /health without auth, got %d", res.StatusCode)
+	}
+}
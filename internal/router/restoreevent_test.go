@@ -0,0 +1,89 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_RestoreEvent_VoidThenRestoreReappearsActive(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	eventID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		"title":       "Nota",
+	})
+
+	statusInList := func() string {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?status=all", ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 list events, got %d body=%s", st, string(body))
+		}
+		var list struct {
+			Items []struct {
+				ID     string `json:"id"`
+				Status string `json:"status"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &list); err != nil {
+			t.Fatalf("decode list: %v body=%s", err, string(body))
+		}
+		for _, it := range list.Items {
+			if it.ID == eventID {
+				return it.Status
+			}
+		}
+		return ""
+	}
+
+	// Restaurar un evento activo => 409
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+eventID+"/restore", ownerID, nil); st != http.StatusConflict {
+		t.Fatalf("expected 409 restore active event, got %d", st)
+	}
+
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+eventID+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+	if got := statusInList(); got != "voided" {
+		t.Fatalf("expected voided in list, got %q", got)
+	}
+
+	// Delegado sin events:void => 403
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopeEventsRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+eventID+"/restore", delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 delegate without events:void, got %d", st)
+	}
+
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+eventID+"/restore", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 restore, got %d body=%s", st, string(body))
+	}
+	var restored struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &restored); err != nil || restored.Status != "active" {
+		t.Fatalf("expected restored event active, got %s", string(body))
+	}
+	if got := statusInList(); got != "active" {
+		t.Fatalf("expected active in list after restore, got %q", got)
+	}
+
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/does-not-exist/restore", ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown event, got %d", st)
+	}
+}
@@ -0,0 +1,84 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_RevokeAllGrantsForPet(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegates := []string{"delegate-1", "delegate-2"}
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	for _, d := range delegates {
+		grantID := inviteGrant(t, ts.URL, ownerID, petID, d, []string{string(accessgrants.ScopePetRead)})
+		if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", d, nil); st != http.StatusOK {
+			t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+		}
+		if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, d, nil); st != http.StatusOK {
+			t.Fatalf("expected 200 delegate read before revoke-all, got %d", st)
+		}
+	}
+
+	// Solo el owner
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants/revoke-all", delegates[0], nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 delegate revoke-all, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/nope/grants/revoke-all", ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown pet, got %d", st)
+	}
+
+	revokeAll := func() int {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants/revoke-all", ownerID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 revoke-all, got %d body=%s", st, string(body))
+		}
+		var resp struct {
+			PetID         string `json:"pet_id"`
+			GrantsRevoked int    `json:"grants_revoked"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil || resp.PetID != petID {
+			t.Fatalf("unexpected revoke-all body: %s", string(body))
+		}
+		return resp.GrantsRevoked
+	}
+
+	if n := revokeAll(); n != 2 {
+		t.Fatalf("expected 2 grants revoked, got %d", n)
+	}
+
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/grants", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list grants, got %d", st)
+	}
+	var grants []struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &grants); err != nil {
+		t.Fatalf("decode grants: %v body=%s", err, string(body))
+	}
+	for _, g := range grants {
+		if g.Status != "revoked" {
+			t.Fatalf("expected all grants revoked, got %s", string(body))
+		}
+	}
+	for _, d := range delegates {
+		if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, d, nil); st != http.StatusForbidden {
+			t.Fatalf("expected 403 delegate read after revoke-all, got %d", st)
+		}
+	}
+
+	// Idempotente
+	if n := revokeAll(); n != 0 {
+		t.Fatalf("expected 0 grants revoked on second call, got %d", n)
+	}
+}
@@ -1,51 +1,261 @@
 package router
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"pet-clinical-history/internal/adapters/notifications/webhook"
 	mem "pet-clinical-history/internal/adapters/storage/memory"
 	pg "pet-clinical-history/internal/adapters/storage/postgres"
+	"pet-clinical-history/internal/apitime"
 	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/domain/accesslog"
 	"pet-clinical-history/internal/domain/events"
+	"pet-clinical-history/internal/domain/idempotency"
 	"pet-clinical-history/internal/domain/pets"
+	"pet-clinical-history/internal/ids"
 	"pet-clinical-history/internal/middleware"
+	"pet-clinical-history/internal/platform/logger"
+	"pet-clinical-history/internal/platform/metrics"
 	"pet-clinical-history/internal/ports/auth"
+	"pet-clinical-history/internal/ports/capabilities"
+	"pet-clinical-history/internal/ports/notifications"
 
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// DefaultRequestTimeout queda por debajo del WriteTimeout del server (10s en cmd/api),
+// para que el cliente reciba el 503 y no una conexión cortada.
+const DefaultRequestTimeout = 8 * time.Second
+
+// DefaultExportTimeout acota los exports en streaming (export.json y events/export), que
+// recorren todo el timeline y no entran en DefaultRequestTimeout.
+const DefaultExportTimeout = 5 * time.Minute
+
+// metricsPath queda fuera de auth y rate limit, como los probes (lo consulta un scraper, no un usuario).
+const metricsPath = "/metrics"
+
 type Options struct {
 	AuthVerifier auth.AuthVerifier // puede ser nil (modo dev)
 
+	// Logger para el access log HTTP (una línea por request). nil => logger.NewFromEnv().
+	Logger logger.Logger
+
+	// GranteeResolver resuelve grantee_email => user_id al invitar (p.ej. odin.Client).
+	// nil => las invitaciones por email responden 501.
+	GranteeResolver auth.GranteeResolver
+
+	// Capabilities consulta las features del plan (p.ej. plansfeatures.Resolver).
+	// nil => todo permitido (dev).
+	Capabilities capabilities.CapabilitiesResolver
+
+	// Quotas consulta límites numéricos del plan (p.ej. pets.FeaturePetsMax).
+	// nil => sin límites (dev).
+	Quotas capabilities.QuotaResolver
+
+	// QuotaFailOpen deja crear si Quotas falla; si no, se responde 503.
+	// false => env QUOTA_FAIL_OPEN (bool).
+	QuotaFailOpen bool
+
+	// GrantNotifier recibe las invitaciones, aceptaciones y revocaciones de grants (best-effort).
+	// nil => env GRANT_WEBHOOK_URL (+ GRANT_WEBHOOK_SECRET para firmar) vía webhook.Notifier, y si no, sin notificaciones.
+	GrantNotifier notifications.GrantNotifier
+
+	// DefaultInviteScopes son los scopes de una invitación que no manda scopes.
+	// Vacío => env INVITE_DEFAULT_SCOPES (CSV), y si no, accessgrants.DefaultInviteScopes.
+	// Un scope no soportado (acá o en la env) hace que NewRouter entre en pánico: falla al arrancar.
+	DefaultInviteScopes []accessgrants.Scope
+
+	// InviteTTL es el plazo para aceptar una invitación de grant.
+	// 0 => env INVITE_TTL (duración, p.ej. "72h"), y si no, accessgrants.DefaultInviteTTL; < 0 => sin plazo.
+	InviteTTL time.Duration
+
+	// MaxGrantsPerPet es el máximo de grants vigentes (invitados o activos) por mascota.
+	// 0 => env MAX_GRANTS_PER_PET (entero), y si no, accessgrants.DefaultMaxGrantsPerPet; < 0 => sin límite.
+	MaxGrantsPerPet int
+
 	// Opcional: si viene, usa Postgres. Si no, in-memory.
 	DB *sql.DB
+
+	// DisableAccessLog apaga el registro de lecturas de delegados (GET /pets/{petID}/access-log queda vacío).
+	DisableAccessLog bool
+
+	// TimeFormat es el formato default de timestamps en respuestas (rfc3339 | epoch_ms).
+	// Vacío => env TIME_FORMAT, y si no, RFC3339. El cliente puede pedir otro vía Accept.
+	TimeFormat apitime.Format
+
+	// IDGenerator genera los IDs de todas las entidades.
+	// nil => env ID_SCHEME (uuid | ulid), y si no, UUIDv4.
+	IDGenerator ids.Generator
+
+	// MaxEventsPerPet es el tope de eventos active por mascota.
+	// 0 => env MAX_EVENTS_PER_PET, y si no, ilimitado.
+	MaxEventsPerPet int
+
+	// ExportMaxEvents es el tope de eventos de un export sin ?confirm_full=true.
+	// 0 => env EXPORT_MAX_EVENTS, y si no, events.DefaultExportMaxEvents; < 0 => ilimitado.
+	ExportMaxEvents int
+
+	// IdempotencyTTL es cuánto se recuerda una Idempotency-Key de POST /pets y POST /pets/{petID}/events.
+	// 0 => env IDEMPOTENCY_TTL (duración, p.ej. "24h"), y si no, idempotency.DefaultTTL.
+	IdempotencyTTL time.Duration
+
+	// RequestTimeout acota cada request (context con deadline); vencido => 503 JSON.
+	// 0 => env REQUEST_TIMEOUT (duración, p.ej. "8s"), y si no, DefaultRequestTimeout; < 0 => sin timeout.
+	RequestTimeout time.Duration
+
+	// ExportTimeout reemplaza a RequestTimeout en los exports en streaming (GET /pets/{petID}/export.json
+	// y GET /pets/{petID}/events/export), que con el plazo general se cortarían a mitad de la descarga.
+	// 0 => env EXPORT_TIMEOUT (duración, p.ej. "5m"), y si no, DefaultExportTimeout; < 0 => sin timeout.
+	ExportTimeout time.Duration
+
+	// MaxBodyBytes es el tamaño máximo del cuerpo de un request; excedido => 413 JSON.
+	// 0 => env MAX_BODY_BYTES, y si no, middleware.DefaultMaxBodyBytes (1 MiB); < 0 => sin límite.
+	MaxBodyBytes int64
+
+	// CORS habilita requests cross-origin desde un browser (SPA). Sin AllowedOrigins => env
+	// CORS_ALLOWED_ORIGINS (CSV; "*" = cualquiera), y si no, deny-all (sin headers CORS).
+	CORS middleware.CORSOptions
+
+	// RateLimit es la cantidad de requests por segundo por usuario (o IP si es anónimo); excedido => 429.
+	// 0 => env RATE_LIMIT_RPS, y si no, sin límite.
+	RateLimit float64
+
+	// RateLimitBurst es el máximo de requests seguidos por usuario / IP.
+	// 0 => env RATE_LIMIT_BURST, y si no, ceil(RateLimit).
+	RateLimitBurst int
+
+	// RequireAuth corta con 401 en el middleware todo request sin claims válidos
+	// (salvo /health, /livez, /readyz y /swagger/), antes de llegar a los handlers.
+	// false => env REQUIRE_AUTH (bool).
+	RequireAuth bool
+
+	// Metrics acumula los contadores expuestos en GET /metrics (requests por ruta y status,
+	// transiciones de grants, eventos creados). nil => un metrics.Registry propio del router.
+	Metrics *metrics.Registry
 }
 
 func NewRouter(opts Options) http.Handler {
 	r := chi.NewRouter()
 
+	reqLogger := opts.Logger
+	if reqLogger == nil {
+		reqLogger = logger.NewFromEnv()
+	}
+
 	r.Use(chimw.RequestID)
+	r.Use(middleware.RequestLogger(reqLogger))
+
+	reg := opts.Metrics
+	if reg == nil {
+		reg = metrics.NewRegistry()
+	}
+	r.Use(middleware.Metrics(reg))
 	r.Use(chimw.RealIP)
 	r.Use(chimw.Recoverer)
 
+	// CORS antes de auth: los preflight se responden acá, sin credenciales.
+	cors := opts.CORS
+	if len(cors.AllowedOrigins) == 0 {
+		cors.AllowedOrigins = splitCSV(os.Getenv("CORS_ALLOWED_ORIGINS"))
+	}
+	r.Use(middleware.CORS(cors))
+
+	requestTimeout := opts.RequestTimeout
+	if requestTimeout == 0 {
+		requestTimeout = DefaultRequestTimeout
+		if d, err := time.ParseDuration(os.Getenv("REQUEST_TIMEOUT")); err == nil {
+			requestTimeout = d
+		}
+	}
+	exportTimeout := opts.ExportTimeout
+	if exportTimeout == 0 {
+		exportTimeout = DefaultExportTimeout
+		if d, err := time.ParseDuration(os.Getenv("EXPORT_TIMEOUT")); err == nil {
+			exportTimeout = d
+		}
+	}
+	r.Use(middleware.TimeoutFunc(func(req *http.Request) time.Duration {
+		if isExportPath(req.URL.Path) {
+			return exportTimeout
+		}
+		return requestTimeout
+	}))
+
+	maxBodyBytes := opts.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = middleware.DefaultMaxBodyBytes
+		if n, err := strconv.ParseInt(os.Getenv("MAX_BODY_BYTES"), 10, 64); err == nil && n != 0 {
+			maxBodyBytes = n
+		}
+	}
+	r.Use(middleware.MaxBodyBytes(maxBodyBytes))
+
 	r.Use(middleware.AuthContext(opts.AuthVerifier))
 
-	r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
-	})
+	// Rate limit después de AuthContext (clave por user_id) y antes de RequireAuth (limita también anónimos).
+	rateLimit := opts.RateLimit
+	if rateLimit == 0 {
+		rateLimit, _ = strconv.ParseFloat(os.Getenv("RATE_LIMIT_RPS"), 64)
+	}
+	rateLimitBurst := opts.RateLimitBurst
+	if rateLimitBurst == 0 {
+		rateLimitBurst, _ = strconv.Atoi(os.Getenv("RATE_LIMIT_BURST"))
+	}
+	r.Use(middleware.RateLimit(middleware.RateLimitOptions{
+		Rate:        rateLimit,
+		Burst:       rateLimitBurst,
+		ExemptPaths: append([]string{"/swagger/", metricsPath}, healthPaths...),
+	}))
+
+	requireAuth := opts.RequireAuth
+	if !requireAuth {
+		requireAuth, _ = strconv.ParseBool(os.Getenv("REQUIRE_AUTH"))
+	}
+	if requireAuth {
+		r.Use(middleware.RequireAuth(append([]string{"/swagger/", metricsPath}, healthPaths...)...))
+	}
+
+	timeFormat := opts.TimeFormat
+	if timeFormat == "" {
+		if f, ok := apitime.ParseFormat(os.Getenv("TIME_FORMAT")); ok {
+			timeFormat = f
+		}
+	}
+	r.Use(middleware.TimeFormat(timeFormat))
+
+	// Liveness (/health se mantiene como alias); /readyz se monta al resolver la DB.
+	r.Get("/health", livezHandler)
+	r.Get("/livez", livezHandler)
+
+	// Contadores para operadores (texto plano, formato `nombre{labels} valor`)
+	r.Method(http.MethodGet, metricsPath, reg.Handler())
 
 	// Swagger UI
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
 
 	var (
-		petRepo    pets.Repository
-		eventRepo  events.Repository
-		grantsRepo accessgrants.Repository
+		petRepo       pets.Repository
+		eventRepo     events.Repository
+		grantsRepo    accessgrants.Repository
+		accessLogRepo accesslog.Repository
+		keysRepo      idempotency.Repository
+
+		preventiveRepo   events.PreventiveRepository
+		measurementsRepo events.MeasurementRepository
+		attachmentsRepo  events.AttachmentRepository
+		medicationsRepo  events.MedicationRepository
+		vaccinesRepo     events.VaccineRepository
+		mergeStore       pets.MergeStore
+		batchStore       events.BatchStore // solo postgres; en memoria evento y detalle van repo por repo
 	)
 
 	// Repos in-memory
@@ -60,31 +270,215 @@ func NewRouter(opts Options) http.Handler {
 			opened, err := pg.Open(dsn)
 			if err == nil {
 				db = opened
+			} else {
+				reqLogger.Error("db open failed, using in-memory storage", map[string]any{"error": err.Error()})
 			}
 		}
 	}
 
+	// DB_AUTO_MIGRATE=true aplica las migraciones embebidas al arrancar (dev / entornos efímeros).
 	if db != nil {
-		petRepo = pg.NewPetsRepo(db)
-		eventRepo = pg.NewEventsRepo(db)
-		grantsRepo = pg.NewAccessGrantsRepo(db)
+		if auto, _ := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE")); auto {
+			if err := pg.Migrate(context.Background(), db); err != nil {
+				reqLogger.Error("db auto-migrate failed", map[string]any{"error": err.Error()})
+			}
+		}
+	}
+
+	if db != nil {
+		// DB_READ_RETRIES: reintentos de lecturas ante errores transitorios (default pg.DefaultReadRetries).
+		var repoOpts []pg.RepoOption
+		if n, err := strconv.Atoi(os.Getenv("DB_READ_RETRIES")); err == nil {
+			repoOpts = append(repoOpts, pg.WithReadRetries(n))
+		}
+		petRepo = pg.NewPetsRepo(db, repoOpts...)
+		eventRepo = pg.NewEventsRepo(db, repoOpts...)
+		grantsRepo = pg.NewAccessGrantsRepo(db, repoOpts...)
+		accessLogRepo = pg.NewAccessLogRepo(db)
+		keysRepo = pg.NewIdempotencyRepo(db)
+		preventiveRepo = pg.NewPreventiveRepo(db)
+		measurementsRepo = pg.NewMeasurementsRepo(db)
+		attachmentsRepo = pg.NewAttachmentsRepo(db)
+		medicationsRepo = pg.NewMedicationsRepo(db)
+		vaccinesRepo = pg.NewVaccinesRepo(db)
+		mergeStore = pg.NewPetMergeStore(db)
+		batchStore = pg.NewEventBatchStore(db)
 	} else {
 		petRepo = mem.NewPetRepo()
 		eventRepo = mem.NewEventRepo()
 		grantsRepo = mem.NewAccessGrantsRepo()
+		accessLogRepo = mem.NewAccessLogRepo()
+		keysRepo = mem.NewIdempotencyRepo()
+		preventiveRepo = mem.NewPreventiveRepo(eventRepo)
+		measurementsRepo = mem.NewMeasurementsRepo()
+		attachmentsRepo = mem.NewAttachmentsRepo()
+		medicationsRepo = mem.NewMedicationsRepo(eventRepo)
+		vaccinesRepo = mem.NewVaccinesRepo(eventRepo)
+		mergeStore = mem.NewMergeStore(petRepo, eventRepo, grantsRepo, attachmentsRepo)
+	}
+
+	// Readiness: ping a Postgres (o siempre lista en memoria)
+	r.Get("/readyz", readyzHandler(db))
+
+	idGen := opts.IDGenerator
+	if idGen == nil {
+		idGen = ids.UUIDv4()
+		if g, ok := ids.ParseScheme(os.Getenv("ID_SCHEME")); ok {
+			idGen = g
+		}
+	}
+
+	maxEvents := opts.MaxEventsPerPet
+	if maxEvents == 0 {
+		if n, err := strconv.Atoi(os.Getenv("MAX_EVENTS_PER_PET")); err == nil {
+			maxEvents = n
+		}
+	}
+
+	exportMax := opts.ExportMaxEvents
+	if exportMax == 0 {
+		exportMax = events.DefaultExportMaxEvents
+		if n, err := strconv.Atoi(os.Getenv("EXPORT_MAX_EVENTS")); err == nil {
+			exportMax = n
+		}
+	}
+
+	quotaFailOpen := opts.QuotaFailOpen
+	if !quotaFailOpen {
+		quotaFailOpen, _ = strconv.ParseBool(os.Getenv("QUOTA_FAIL_OPEN"))
+	}
+
+	idempotencyTTL := opts.IdempotencyTTL
+	if idempotencyTTL == 0 {
+		if d, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL")); err == nil {
+			idempotencyTTL = d
+		}
+	}
+
+	inviteTTL := opts.InviteTTL
+	if inviteTTL == 0 {
+		if d, err := time.ParseDuration(os.Getenv("INVITE_TTL")); err == nil {
+			inviteTTL = d
+		}
+	}
+
+	maxGrantsPerPet := opts.MaxGrantsPerPet
+	if maxGrantsPerPet == 0 {
+		if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("MAX_GRANTS_PER_PET"))); err == nil {
+			maxGrantsPerPet = n
+		}
+	}
+
+	grantNotifier := opts.GrantNotifier
+	if grantNotifier == nil {
+		if url := strings.TrimSpace(os.Getenv("GRANT_WEBHOOK_URL")); url != "" {
+			grantNotifier = webhook.NewNotifier(webhook.Config{URL: url, Secret: os.Getenv("GRANT_WEBHOOK_SECRET")})
+		}
 	}
 
 	// Services por módulo
-	petsSvc := pets.NewService(petRepo)
-	eventsSvc := events.NewService(eventRepo)
-	grantsSvc := accessgrants.NewService(grantsRepo)
+	keysSvc := idempotency.NewService(keysRepo, idempotency.WithTTL(idempotencyTTL))
+	grantOpts := []accessgrants.Option{
+		accessgrants.WithIDGenerator(idGen),
+		accessgrants.WithNotifier(grantNotifier),
+		accessgrants.WithLogger(reqLogger),
+		accessgrants.WithCapabilities(opts.Capabilities),
+		accessgrants.WithMetrics(reg),
+	}
+	if inviteTTL != 0 {
+		grantOpts = append(grantOpts, accessgrants.WithInviteTTL(inviteTTL))
+	}
+	if maxGrantsPerPet != 0 {
+		grantOpts = append(grantOpts, accessgrants.WithMaxGrantsPerPet(maxGrantsPerPet))
+	}
+	inviteScopes := opts.DefaultInviteScopes
+	if len(inviteScopes) == 0 {
+		inviteScopes = envInviteScopes()
+	}
+	if len(inviteScopes) > 0 {
+		grantOpts = append(grantOpts, accessgrants.WithDefaultScopes(inviteScopes...))
+	}
+	// Config inválida => falla al arrancar (cmd/api ya la valida con DefaultInviteScopesFromEnv).
+	grantsSvc, err := accessgrants.NewServiceWithOptions(grantsRepo, grantOpts...)
+	if err != nil {
+		panic(fmt.Sprintf("router: invalid default invite scopes: %v", err))
+	}
+	eventsSvc := events.NewService(eventRepo,
+		events.WithPreventiveRepo(preventiveRepo),
+		events.WithMeasurementRepo(measurementsRepo),
+		events.WithAttachmentRepo(attachmentsRepo),
+		events.WithMedicationRepo(medicationsRepo),
+		events.WithVaccineRepo(vaccinesRepo),
+		events.WithBatchStore(batchStore),
+		events.WithIdempotencyStore(keysSvc),
+		events.WithIDGenerator(idGen),
+		events.WithMaxEventsPerPet(maxEvents),
+		events.WithExportMaxEvents(exportMax),
+		events.WithMetrics(reg),
+	)
+	petsSvc := pets.NewService(petRepo,
+		pets.WithIDGenerator(idGen),
+		pets.WithMergeStore(mergeStore),
+		pets.WithGrantRevoker(grantsSvc),
+		pets.WithEventVoider(eventsSvc),
+		pets.WithGrantTransferer(grantsSvc),
+		pets.WithLogger(reqLogger),
+		pets.WithIdempotencyStore(keysSvc),
+	)
+
+	// Access log opcional: un *Service nil es un no-op en Record.
+	var accessLogSvc *accesslog.Service
+	if !opts.DisableAccessLog {
+		accessLogSvc = accesslog.NewService(accessLogRepo, accesslog.WithIDGenerator(idGen))
+	}
 
 	// Rutas por módulo
-	pets.RegisterRoutes(r, petsSvc, grantsSvc)
+	pets.RegisterRoutes(r, petsSvc, grantsSvc, accessLogSvc, eventsSvc, eventsSvc,
+		pets.PetQuota{Resolver: opts.Quotas, FailOpen: quotaFailOpen})
 
 	//events.RegisterRoutes(r, eventsSvc, petsSvc) // en el siguiente paso, lo haremos validar delegados
-	events.RegisterRoutes(r, eventsSvc, petsSvc, grantsSvc)
-	accessgrants.RegisterRoutes(r, grantsSvc, petsSvc)
+	events.RegisterRoutes(r, eventsSvc, petsSvc, grantsSvc, accessLogSvc, opts.Capabilities)
+	accessgrants.RegisterRoutes(r, grantsSvc, petsSvc, opts.GranteeResolver)
+	accesslog.RegisterRoutes(r, accessLogSvc, petsSvc)
 
 	return r
 }
+
+// DefaultInviteScopesFromEnv lee y valida INVITE_DEFAULT_SCOPES (CSV) para que el binario
+// falle al arrancar con un error claro; nil si no está seteado (quedan los defaults).
+func DefaultInviteScopesFromEnv() ([]accessgrants.Scope, error) {
+	scopes := envInviteScopes()
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	normalized, err := accessgrants.NormalizeDefaultScopes(scopes)
+	if err != nil {
+		return nil, fmt.Errorf("INVITE_DEFAULT_SCOPES=%q: %w", os.Getenv("INVITE_DEFAULT_SCOPES"), err)
+	}
+	return normalized, nil
+}
+
+func envInviteScopes() []accessgrants.Scope {
+	var out []accessgrants.Scope
+	for _, sc := range splitCSV(os.Getenv("INVITE_DEFAULT_SCOPES")) {
+		out = append(out, accessgrants.Scope(sc))
+	}
+	return out
+}
+
+// splitCSV separa una lista separada por comas, sin espacios ni elementos vacíos.
+func splitCSV(raw string) []string {
+	out := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// isExportPath reconoce los exports en streaming, que llevan ExportTimeout en vez de RequestTimeout.
+// Se decide por path porque el timeout se monta antes del ruteo de chi.
+func isExportPath(path string) bool {
+	return strings.HasSuffix(path, "/export.json") || strings.HasSuffix(path, "/events/export")
+}
@@ -218,6 +218,16 @@ func createEvent(t *testing.T, baseURL, userID, petID string, payload map[string
 func doReq(t *testing.T, baseURL, method, path, debugUserID string, body any) (int, []byte) {
 	t.Helper()
 
+	headers := map[string]string{}
+	if debugUserID != "" {
+		headers["X-Debug-User-ID"] = debugUserID
+	}
+	return doReqWithHeaders(t, baseURL, method, path, headers, body)
+}
+
+func doReqWithHeaders(t *testing.T, baseURL, method, path string, headers map[string]string, body any) (int, []byte) {
+	t.Helper()
+
 	var rdr io.Reader
 	if body != nil {
 		b, err := json.Marshal(body)
@@ -234,8 +244,8 @@ func doReq(t *testing.T, baseURL, method, path, debugUserID string, body any) (i
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	if debugUserID != "" {
-		req.Header.Set("X-Debug-User-ID", debugUserID)
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
 
 	res, err := http.DefaultClient.Do(req)
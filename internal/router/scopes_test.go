@@ -0,0 +1,81 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ValidateScopes(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	cases := []struct {
+		name       string
+		scopes     []string
+		valid      bool
+		normalized []string
+		invalid    []string
+	}{
+		{
+			name:       "valid",
+			scopes:     []string{"pet:read", " events:read "},
+			valid:      true,
+			normalized: []string{"pet:read", "events:read"},
+			invalid:    []string{},
+		},
+		{
+			name:       "partially invalid",
+			scopes:     []string{"events:read", "events:unknown", "pet:fly"},
+			valid:      false,
+			normalized: []string{"events:read"},
+			invalid:    []string{"events:unknown", "pet:fly"},
+		},
+		{
+			name:       "duplicates",
+			scopes:     []string{"events:create", "events:create", "pet:read", "events:create"},
+			valid:      true,
+			normalized: []string{"events:create", "pet:read"},
+			invalid:    []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			st, body := doReq(t, ts.URL, "POST", "/grants/scopes/validate", "user-1", map[string]any{
+				"scopes": tc.scopes,
+			})
+			if st != http.StatusOK {
+				t.Fatalf("expected 200, got %d body=%s", st, string(body))
+			}
+
+			var resp struct {
+				Valid      bool     `json:"valid"`
+				Normalized []string `json:"normalized"`
+				Invalid    []string `json:"invalid"`
+			}
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatalf("unmarshal: %v body=%s", err, string(body))
+			}
+
+			if resp.Valid != tc.valid {
+				t.Fatalf("expected valid=%v, got %v", tc.valid, resp.Valid)
+			}
+			if !reflect.DeepEqual(resp.Normalized, tc.normalized) {
+				t.Fatalf("expected normalized=%v, got %v", tc.normalized, resp.Normalized)
+			}
+			if !reflect.DeepEqual(resp.Invalid, tc.invalid) {
+				t.Fatalf("expected invalid=%v, got %v", tc.invalid, resp.Invalid)
+			}
+		})
+	}
+
+	// Requiere autenticación
+	if st, _ := doReq(t, ts.URL, "POST", "/grants/scopes/validate", "", map[string]any{"scopes": []string{"pet:read"}}); st != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without user, got %d", st)
+	}
+}
@@ -0,0 +1,71 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListGrants_StaleDays(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	scopes := []string{string(accessgrants.ScopePetRead)}
+	grantIDs := map[string]string{}
+	for _, grantee := range []string{"vet-1", "sitter-1"} {
+		id := inviteGrant(t, ts.URL, ownerID, petID, grantee, scopes)
+		if st, body := doReq(t, ts.URL, "POST", "/grants/"+id+"/accept", grantee, nil); st != http.StatusOK {
+			t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+		}
+		grantIDs[grantee] = id
+	}
+
+	// Solo vet-1 usa su acceso
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, "vet-1", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 delegate read, got %d", st)
+	}
+
+	type grantItem struct {
+		ID         string  `json:"id"`
+		LastUsedAt *string `json:"last_used_at"`
+	}
+	list := func(query string) (int, []grantItem) {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/grants"+query, ownerID, nil)
+		var items []grantItem
+		if st == http.StatusOK {
+			if err := json.Unmarshal(body, &items); err != nil {
+				t.Fatalf("decode: %v body=%s", err, string(body))
+			}
+		}
+		return st, items
+	}
+
+	_, all := list("")
+	if len(all) != 2 {
+		t.Fatalf("expected 2 grants, got %d", len(all))
+	}
+	for _, g := range all {
+		if used := g.LastUsedAt != nil; used != (g.ID == grantIDs["vet-1"]) {
+			t.Fatalf("unexpected last_used_at for %s: %v", g.ID, g.LastUsedAt)
+		}
+	}
+
+	st, stale := list("?stale_days=30")
+	if st != http.StatusOK || len(stale) != 1 || stale[0].ID != grantIDs["sitter-1"] {
+		t.Fatalf("expected only the unused grant as stale, got %d %+v", st, stale)
+	}
+
+	for _, bad := range []string{"0", "-3", "abc"} {
+		if st, _ := list("?stale_days=" + bad); st != http.StatusBadRequest {
+			t.Fatalf("stale_days=%s: expected 400, got %d", bad, st)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_SubDelegation(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	boardingID := "boarding-1"
+	staffID := "staff-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	boardingGrant := inviteGrant(t, ts.URL, ownerID, petID, boardingID, []string{
+		string(accessgrants.ScopePetRead),
+		string(accessgrants.ScopeEventsRead),
+		string(accessgrants.ScopeGrantsDelegate),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+boardingGrant+"/accept", boardingID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+
+	// Excede sus scopes => 403
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/grants", boardingID, map[string]any{
+		"grantee_user_id": staffID,
+		"scopes":          []string{"pet:read", "events:create"},
+	})
+	if st != http.StatusForbidden {
+		t.Fatalf("expected 403 exceeding delegator scopes, got %d body=%s", st, string(body))
+	}
+
+	// Subconjunto => 201, y el staff puede leer tras aceptar
+	staffGrant := inviteGrant(t, ts.URL, boardingID, petID, staffID, []string{"pet:read"})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+staffGrant+"/accept", staffID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+petID, staffID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 staff read, got %d body=%s", st, string(body))
+	}
+
+	// Owner revoca la guardería => el staff pierde acceso
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+boardingGrant+"/revoke", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 revoke, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+petID, staffID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 staff after cascade revoke, got %d body=%s", st, string(body))
+	}
+}
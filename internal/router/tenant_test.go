@@ -0,0 +1,169 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_TenantIsolation(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	// Mismos user IDs en ambos tenants: el aislamiento no puede depender del user.
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	as := func(tenant, userID string) func(method, path string, body any) (int, []byte) {
+		return func(method, path string, body any) (int, []byte) {
+			t.Helper()
+			return doReqWithHeaders(t, ts.URL, method, path, map[string]string{
+				"X-Debug-User-ID":   userID,
+				"X-Debug-Tenant-ID": tenant,
+			}, body)
+		}
+	}
+	ownerA, ownerB := as("tenant-a", ownerID), as("tenant-b", ownerID)
+	delegateA, delegateB := as("tenant-a", delegateID), as("tenant-b", delegateID)
+
+	mustCreate := func(st int, body []byte) string {
+		t.Helper()
+		if st != http.StatusCreated {
+			t.Fatalf("expected 201, got %d body=%s", st, string(body))
+		}
+		var resp struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil || resp.ID == "" {
+			t.Fatalf("missing id: %v body=%s", err, string(body))
+		}
+		return resp.ID
+	}
+
+	petA := mustCreate(ownerA("POST", "/pets", map[string]any{"name": "Milo"}))
+	petB := mustCreate(ownerB("POST", "/pets", map[string]any{"name": "Luna"}))
+
+	grantA := mustCreate(ownerA("POST", "/pets/"+petA+"/grants", map[string]any{
+		"grantee_user_id": delegateID,
+		"scopes":          []string{string(accessgrants.ScopePetRead), string(accessgrants.ScopeEventsRead)},
+	}))
+	if st, body := delegateA("POST", "/grants/"+grantA+"/accept", nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept in tenant-a, got %d body=%s", st, string(body))
+	}
+	mustCreate(ownerA("POST", "/pets/"+petA+"/events", map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		"title":       "Control",
+	}))
+
+	// Dentro del tenant todo sigue funcionando
+	for name, do := range map[string]func(string, string, any) (int, []byte){"owner": ownerA, "delegate": delegateA} {
+		if st, _ := do("GET", "/pets/"+petA, nil); st != http.StatusOK {
+			t.Fatalf("%s tenant-a: expected 200 get pet, got %d", name, st)
+		}
+		if st, _ := do("GET", "/pets/"+petA+"/events", nil); st != http.StatusOK {
+			t.Fatalf("%s tenant-a: expected 200 list events, got %d", name, st)
+		}
+	}
+
+	listIDs := func(do func(string, string, any) (int, []byte), path string) []string {
+		t.Helper()
+		st, body := do("GET", path, nil)
+		if st != http.StatusOK {
+			t.Fatalf("GET %s: expected 200, got %d body=%s", path, st, string(body))
+		}
+		type item struct {
+			ID string `json:"id"`
+		}
+		var items []item
+		if strings.HasPrefix(path, "/me/grants") {
+			// /me/grants es paginado: {items, next_cursor, ...}
+			var page struct {
+				Items []item `json:"items"`
+			}
+			if err := json.Unmarshal(body, &page); err != nil {
+				t.Fatalf("decode %s: %v body=%s", path, err, string(body))
+			}
+			items = page.Items
+		} else if err := json.Unmarshal(body, &items); err != nil {
+			t.Fatalf("decode %s: %v body=%s", path, err, string(body))
+		}
+		ids := make([]string, 0, len(items))
+		for _, it := range items {
+			ids = append(ids, it.ID)
+		}
+		return ids
+	}
+
+	// Listados: cada tenant ve solo lo suyo
+	if got := listIDs(ownerA, "/pets"); len(got) != 1 || got[0] != petA {
+		t.Fatalf("tenant-a owner: expected only %s, got %v", petA, got)
+	}
+	if got := listIDs(ownerB, "/pets"); len(got) != 1 || got[0] != petB {
+		t.Fatalf("tenant-b owner: expected only %s, got %v", petB, got)
+	}
+	if got := listIDs(delegateA, "/me/grants"); len(got) != 1 || got[0] != grantA {
+		t.Fatalf("tenant-a delegate: expected grant %s, got %v", grantA, got)
+	}
+	if got := listIDs(delegateB, "/me/grants"); len(got) != 0 {
+		t.Fatalf("tenant-b delegate: expected no grants, got %v", got)
+	}
+
+	// Cross-tenant con ID conocido: 404 (no 403), para no revelar que existe
+	cross := []struct {
+		method, path string
+		body         any
+	}{
+		{"GET", "/pets/" + petA, nil},
+		{"PATCH", "/pets/" + petA, map[string]any{"name": "Robado"}},
+		{"DELETE", "/pets/" + petA, nil},
+		{"GET", "/pets/" + petA + "/events", nil},
+		{"POST", "/pets/" + petA + "/events", map[string]any{"type": "NOTE", "title": "x"}},
+		{"GET", "/pets/" + petA + "/grants", nil},
+		{"POST", "/pets/" + petA + "/grants", map[string]any{"grantee_user_id": "intruder"}},
+		{"POST", "/pets/" + petA + "/grants/revoke-all", nil},
+		{"GET", "/pets/" + petA + "/access-log", nil},
+		{"GET", "/pets/" + petA + "/export.json", nil},
+		{"POST", "/grants/" + grantA + "/revoke", nil},
+	}
+	for _, c := range cross {
+		if st, body := ownerB(c.method, c.path, c.body); st != http.StatusNotFound {
+			t.Fatalf("tenant-b owner %s %s: expected 404, got %d body=%s", c.method, c.path, st, string(body))
+		}
+	}
+	for _, path := range []string{"/pets/" + petA, "/pets/" + petA + "/events"} {
+		if st, _ := delegateB("GET", path, nil); st != http.StatusNotFound {
+			t.Fatalf("tenant-b delegate GET %s: expected 404, got %d", path, st)
+		}
+	}
+	if st, _ := delegateB("POST", "/grants/"+grantA+"/leave", nil); st != http.StatusNotFound {
+		t.Fatalf("tenant-b delegate leave: expected 404, got %d", st)
+	}
+
+	// Nada de lo anterior tocó los datos de tenant-a
+	st, body := ownerA("GET", "/pets/"+petA, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 pet still in tenant-a, got %d", st)
+	}
+	var p struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(body, &p)
+	if p.Name != "Milo" {
+		t.Fatalf("expected pet untouched, got %s", string(body))
+	}
+	if st, _ := delegateA("GET", "/pets/"+petA, nil); st != http.StatusOK {
+		t.Fatalf("expected delegate grant still active in tenant-a, got %d", st)
+	}
+
+	// Sin tenant (tokens sin claim): tenant por defecto, tampoco ve los otros
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petA, ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("default tenant: expected 404, got %d", st)
+	}
+}
@@ -0,0 +1,72 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/apitime"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_TimeFormat_AcceptAndDefault(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	occurred := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": occurred.Format(time.RFC3339),
+		"title":       "x",
+	})
+
+	// 1) Default: RFC3339
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", st, string(body))
+	}
+	var rfcPage struct {
+		Items []map[string]any `json:"items"`
+	}
+	_ = json.Unmarshal(body, &rfcPage)
+	rfc := rfcPage.Items
+	if len(rfc) != 1 || rfc[0]["occurred_at"] != "2025-12-22T10:00:00Z" {
+		t.Fatalf("expected RFC3339 occurred_at, got body=%s", string(body))
+	}
+
+	// 2) Accept con time-format=epoch_ms
+	st, body = doReqWithHeaders(t, ts.URL, "GET", "/pets/"+petID+"/events", map[string]string{
+		"X-Debug-User-ID": ownerID,
+		"Accept":          "application/json; time-format=epoch_ms",
+	}, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", st, string(body))
+	}
+	var millisPage struct {
+		Items []map[string]any `json:"items"`
+	}
+	_ = json.Unmarshal(body, &millisPage)
+	millis := millisPage.Items
+	if len(millis) != 1 || millis[0]["occurred_at"] != float64(occurred.UnixMilli()) {
+		t.Fatalf("expected epoch millis occurred_at, got body=%s", string(body))
+	}
+
+	// 3) Default del servidor en epoch_ms (Options.TimeFormat)
+	ts2 := httptest.NewServer(router.NewRouter(router.Options{TimeFormat: apitime.FormatEpochMillis}))
+	defer ts2.Close()
+
+	st, body = doReq(t, ts2.URL, "POST", "/pets", ownerID, map[string]any{"name": "Luna"})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", st, string(body))
+	}
+	var pet map[string]any
+	_ = json.Unmarshal(body, &pet)
+	if _, ok := pet["created_at"].(float64); !ok {
+		t.Fatalf("expected numeric created_at, got body=%s", string(body))
+	}
+}
@@ -0,0 +1,100 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_TransferPet_ChangesOwnerAndRevokesNewOwnerGrant(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	familyID := "family-1"
+	vetID := "vet-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	readScopes := []string{string(accessgrants.ScopePetRead), string(accessgrants.ScopeEventsRead)}
+	familyGrant := inviteGrant(t, ts.URL, ownerID, petID, familyID, readScopes)
+	vetGrant := inviteGrant(t, ts.URL, ownerID, petID, vetID, readScopes)
+	for grantID, userID := range map[string]string{familyGrant: familyID, vetGrant: vetID} {
+		if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", userID, nil); st != http.StatusOK {
+			t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+		}
+	}
+
+	// Solo el owner puede transferir
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/transfer", familyID, map[string]any{"new_owner_user_id": familyID}); st != http.StatusForbidden {
+		t.Fatalf("expected 403 delegate transfer, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/transfer", ownerID, map[string]any{"new_owner_user_id": ownerID}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 same owner, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/"+petID+"/transfer", ownerID, map[string]any{}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 missing new owner, got %d", st)
+	}
+	if st, _ := doReq(t, ts.URL, "POST", "/pets/does-not-exist/transfer", ownerID, map[string]any{"new_owner_user_id": familyID}); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown pet, got %d", st)
+	}
+
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/transfer", ownerID, map[string]any{"new_owner_user_id": familyID})
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 transfer, got %d body=%s", st, string(body))
+	}
+	var res struct {
+		Pet struct {
+			OwnerUserID string `json:"owner_user_id"`
+		} `json:"pet"`
+		PreviousOwnerUserID string `json:"previous_owner_user_id"`
+		GrantsRevoked       int    `json:"grants_revoked"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatalf("decode transfer response: %v body=%s", err, string(body))
+	}
+	if res.Pet.OwnerUserID != familyID || res.PreviousOwnerUserID != ownerID || res.GrantsRevoked != 1 {
+		t.Fatalf("unexpected transfer result: %+v", res)
+	}
+
+	// El owner anterior pierde el acceso; el nuevo owner ya no figura como delegado
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, ownerID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 previous owner get pet, got %d", st)
+	}
+	st, body = doReq(t, ts.URL, "GET", "/me/pets", familyID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 /me/pets, got %d", st)
+	}
+	var shared struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.Unmarshal(body, &shared); err != nil || len(shared.Items) != 0 {
+		t.Fatalf("expected new owner not listed as delegate, got %s", string(body))
+	}
+
+	// El grant del vet sigue activo y ahora lo administra el nuevo owner
+	if st, _ := doReq(t, ts.URL, "GET", "/pets/"+petID, vetID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 vet get pet, got %d", st)
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+vetGrant+"/revoke", familyID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 new owner revokes vet grant, got %d body=%s", st, string(body))
+	}
+
+	// Queda registrado en el timeline
+	st, body = doReq(t, ts.URL, "GET", "/pets/"+petID+"/events?type=PROFILE_UPDATED", familyID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 list events, got %d body=%s", st, string(body))
+	}
+	var list struct {
+		Items []struct {
+			Title string `json:"title"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil || len(list.Items) != 1 || list.Items[0].Title != "Mascota transferida" {
+		t.Fatalf("expected one transfer profile event, got %s", string(body))
+	}
+}
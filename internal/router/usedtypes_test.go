@@ -0,0 +1,99 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_UsedTypes_OnlyPresentTypes(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	otherPetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, typ := range []string{"BATH", "NOTE", "BATH"} {
+		createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": typ, "occurred_at": now, "title": "x"})
+	}
+	// Otra mascota: no debe contar
+	createEvent(t, ts.URL, ownerID, otherPetID, map[string]any{"type": "VACCINE", "occurred_at": now, "title": "x"})
+
+	type usedType struct {
+		Type  string `json:"type"`
+		Count int    `json:"count"`
+	}
+
+	st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/used-types", ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", st, string(body))
+	}
+	var got []usedType
+	_ = json.Unmarshal(body, &got)
+	if len(got) != 2 || got[0] != (usedType{"BATH", 2}) || got[1] != (usedType{"NOTE", 1}) {
+		t.Fatalf("expected [BATH:2 NOTE:1], got %+v", got)
+	}
+
+	// Delegado sin events:read => 403
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopePetRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/used-types", delegateID, nil); st != http.StatusForbidden {
+		t.Fatalf("expected 403 without events:read, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_UsedTypes_HidesPrivateAndVoidedFromDelegate(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": "BATH", "occurred_at": now, "title": "x"})
+	createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": "NOTE", "occurred_at": now, "title": "Privado", "visibility": "private"})
+	voidedID := createEvent(t, ts.URL, ownerID, petID, map[string]any{"type": "VACCINE", "occurred_at": now, "title": "x"})
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+voidedID+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopeEventsRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	type usedType struct {
+		Type  string `json:"type"`
+		Count int    `json:"count"`
+	}
+	usedTypes := func(userID string) []usedType {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events/used-types", userID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", st, string(body))
+		}
+		var got []usedType
+		_ = json.Unmarshal(body, &got)
+		return got
+	}
+
+	// Owner: ve su evento privado, no el anulado
+	if got := usedTypes(ownerID); len(got) != 2 || got[0] != (usedType{"BATH", 1}) || got[1] != (usedType{"NOTE", 1}) {
+		t.Fatalf("owner: expected [BATH:1 NOTE:1], got %+v", got)
+	}
+	// Delegado: ni el privado ni el anulado
+	if got := usedTypes(delegateID); len(got) != 1 || got[0] != (usedType{"BATH", 1}) {
+		t.Fatalf("delegate: expected [BATH:1], got %+v", got)
+	}
+}
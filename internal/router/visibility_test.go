@@ -0,0 +1,71 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_ListEvents_HidesPrivateEventsFromDelegates(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "delegate-1"
+
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	now := time.Now().UTC()
+	privateID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": now.Add(-1 * time.Hour).Format(time.RFC3339),
+		"title":       "Nota privada",
+		"visibility":  "private",
+	})
+	sharedID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": now.Add(-2 * time.Hour).Format(time.RFC3339),
+		"title":       "Nota compartida",
+		"visibility":  "shared_with_delegates",
+	})
+
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{string(accessgrants.ScopeEventsRead)})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	listIDs := func(userID string) []string {
+		t.Helper()
+		st, body := doReq(t, ts.URL, "GET", "/pets/"+petID+"/events", userID, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 list events, got %d body=%s", st, string(body))
+		}
+		var list struct {
+			Items []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &list); err != nil {
+			t.Fatalf("decode list: %v body=%s", err, string(body))
+		}
+		ids := make([]string, 0, len(list.Items))
+		for _, it := range list.Items {
+			ids = append(ids, it.ID)
+		}
+		return ids
+	}
+
+	// El owner ve todo
+	if ids := listIDs(ownerID); len(ids) != 2 || ids[0] != privateID || ids[1] != sharedID {
+		t.Fatalf("expected owner to see both events, got %v", ids)
+	}
+
+	// El delegado solo ve el compartido
+	if ids := listIDs(delegateID); len(ids) != 1 || ids[0] != sharedID {
+		t.Fatalf("expected delegate to see only the shared event, got %v", ids)
+	}
+}
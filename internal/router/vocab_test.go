@@ -0,0 +1,89 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_PetSpeciesAndSex_AllowList(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+
+	// Valores del vocabulario (sin distinguir mayúsculas) => 201, guardados normalizados
+	st, body := doReq(t, ts.URL, "POST", "/pets", ownerID, map[string]any{"name": "Milo", "species": "Dog", "sex": "male"})
+	if st != http.StatusCreated {
+		t.Fatalf("expected 201 for valid species, got %d body=%s", st, string(body))
+	}
+	var created struct {
+		ID      string `json:"id"`
+		Species string `json:"species"`
+		Sex     string `json:"sex"`
+	}
+	_ = json.Unmarshal(body, &created)
+	if created.Species != "dog" || created.Sex != "male" {
+		t.Fatalf("expected normalized species/sex, got %s", string(body))
+	}
+
+	// Fuera del vocabulario => 400 (create y patch)
+	if st, body := doReq(t, ts.URL, "POST", "/pets", ownerID, map[string]any{"name": "Nemo", "species": "fish"}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid species, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/pets", ownerID, map[string]any{"name": "Nemo", "sex": "x"}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid sex, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "PATCH", "/pets/"+created.ID, ownerID, map[string]any{"species": "dragon"}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 patching invalid species, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_Vocab_AcceptLanguage(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	type vocab struct {
+		Language string `json:"language"`
+		Items    []struct {
+			Value string `json:"value"`
+			Label string `json:"label"`
+		} `json:"items"`
+	}
+	get := func(path, acceptLanguage string) vocab {
+		t.Helper()
+		headers := map[string]string{}
+		if acceptLanguage != "" {
+			headers["Accept-Language"] = acceptLanguage
+		}
+		st, body := doReqWithHeaders(t, ts.URL, "GET", path, headers, nil)
+		if st != http.StatusOK {
+			t.Fatalf("expected 200 %s, got %d body=%s", path, st, string(body))
+		}
+		var v vocab
+		if err := json.Unmarshal(body, &v); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		return v
+	}
+
+	// Default: español
+	v := get("/vocab/species", "")
+	if v.Language != "es" || len(v.Items) != 2 || v.Items[0].Value != "dog" || v.Items[0].Label != "Perro" {
+		t.Fatalf("unexpected default species vocab: %+v", v)
+	}
+
+	// Accept-Language con q: gana el de mayor q soportado
+	v = get("/vocab/species", "fr-FR, en-US;q=0.9, es;q=0.5")
+	if v.Language != "en" || v.Items[1].Value != "cat" || v.Items[1].Label != "Cat" {
+		t.Fatalf("unexpected english species vocab: %+v", v)
+	}
+
+	v = get("/vocab/sex", "en")
+	if len(v.Items) != 3 || v.Items[0].Value != "male" || v.Items[2].Value != "unknown" || v.Items[2].Label != "Unknown" {
+		t.Fatalf("unexpected sex vocab: %+v", v)
+	}
+}
@@ -0,0 +1,177 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pet-clinical-history/internal/domain/accessgrants"
+	"pet-clinical-history/internal/router"
+)
+
+func TestHTTP_VoidEvent_ConditionalAndIdempotent(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	eventID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "x",
+	})
+	voidPath := "/pets/" + petID + "/events/" + eventID + "/void"
+
+	// 1) Evento active => 200 y queda voided
+	st, body := doReq(t, ts.URL, "POST", voidPath, ownerID, nil)
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 void active event, got %d body=%s", st, string(body))
+	}
+	var resp struct {
+		Status string `json:"status"`
+	}
+	_ = json.Unmarshal(body, &resp)
+	if resp.Status != "voided" {
+		t.Fatalf("expected status voided, got %q", resp.Status)
+	}
+
+	// 2) Ya voided => 409 (condicional por defecto)
+	if st, body := doReq(t, ts.URL, "POST", voidPath, ownerID, nil); st != http.StatusConflict {
+		t.Fatalf("expected 409 void already voided event, got %d body=%s", st, string(body))
+	}
+
+	// 3) Modo idempotente => 200
+	if st, body := doReq(t, ts.URL, "POST", voidPath+"?idempotent=true", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 idempotent void, got %d body=%s", st, string(body))
+	}
+
+	// 4) Evento inexistente sigue siendo 404
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/nope/void", ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 unknown event, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_VoidEvent_ScopeAndPetOwnership(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "vet-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	otherPetID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Luna"})
+	eventID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "x",
+	})
+
+	// Delegado sin events:void
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{
+		string(accessgrants.ScopeEventsRead),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	// 1) Sin scope => 403
+	st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+eventID+"/void", delegateID, nil)
+	if st != http.StatusForbidden {
+		t.Fatalf("expected 403 void without scope, got %d body=%s", st, string(body))
+	}
+
+	// 2) Evento de otra mascota => 404 (no se anula)
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+otherPetID+"/events/"+eventID+"/void", ownerID, nil); st != http.StatusNotFound {
+		t.Fatalf("expected 404 event from another pet, got %d body=%s", st, string(body))
+	}
+	if st, body := doReq(t, ts.URL, "POST", "/pets/"+petID+"/events/"+eventID+"/void", ownerID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 void after cross-pet attempt, got %d body=%s", st, string(body))
+	}
+}
+
+func TestHTTP_VoidEvent_RecordsActorAndReason(t *testing.T) {
+	ts := httptest.NewServer(router.NewRouter(router.Options{AuthVerifier: nil}))
+	defer ts.Close()
+
+	ownerID := "owner-1"
+	delegateID := "vet-1"
+	petID := createPet(t, ts.URL, ownerID, map[string]any{"name": "Milo"})
+	eventID := createEvent(t, ts.URL, ownerID, petID, map[string]any{
+		"type":        "NOTE",
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"title":       "x",
+	})
+	grantID := inviteGrant(t, ts.URL, ownerID, petID, delegateID, []string{
+		string(accessgrants.ScopeEventsRead),
+		string(accessgrants.ScopeEventsVoid),
+	})
+	if st, body := doReq(t, ts.URL, "POST", "/grants/"+grantID+"/accept", delegateID, nil); st != http.StatusOK {
+		t.Fatalf("expected 200 accept grant, got %d body=%s", st, string(body))
+	}
+
+	type voidResp struct {
+		Status     string  `json:"status"`
+		VoidedBy   string  `json:"voided_by"`
+		VoidedAt   *string `json:"voided_at"`
+		VoidReason string  `json:"void_reason"`
+	}
+	decode := func(body []byte) voidResp {
+		t.Helper()
+		var resp voidResp
+		if err := json.Unmarshal(body, &resp); err != nil {
+			t.Fatalf("decode: %v body=%s", err, string(body))
+		}
+		return resp
+	}
+
+	// 1) Evento activo: sin campos de auditoría
+	eventPath := "/pets/" + petID + "/events/" + eventID
+	_, body := doReq(t, ts.URL, "GET", eventPath, ownerID, nil)
+	if resp := decode(body); resp.VoidedBy != "" || resp.VoidedAt != nil || resp.VoidReason != "" {
+		t.Fatalf("expected no void audit on active event, got %s", string(body))
+	}
+
+	// 2) Reason demasiado largo => 400
+	long := make([]byte, 501)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if st, body := doReq(t, ts.URL, "POST", eventPath+"/void", delegateID, map[string]any{"reason": string(long)}); st != http.StatusBadRequest {
+		t.Fatalf("expected 400 long reason, got %d body=%s", st, string(body))
+	}
+
+	// 3) El delegado anula con motivo: actor y reason quedan registrados
+	st, body := doReq(t, ts.URL, "POST", eventPath+"/void", delegateID, map[string]any{"reason": "  cargado en la mascota equivocada "})
+	if st != http.StatusOK {
+		t.Fatalf("expected 200 void, got %d body=%s", st, string(body))
+	}
+	resp := decode(body)
+	if resp.Status != "voided" || resp.VoidedBy != delegateID || resp.VoidReason != "cargado en la mascota equivocada" || resp.VoidedAt == nil {
+		t.Fatalf("unexpected void audit: %s", string(body))
+	}
+
+	// 4) GET devuelve lo mismo
+	_, body = doReq(t, ts.URL, "GET", eventPath, ownerID, nil)
+	if got := decode(body); got.VoidedBy != delegateID || got.VoidReason != resp.VoidReason || got.VoidedAt == nil || *got.VoidedAt != *resp.VoidedAt {
+		t.Fatalf("expected void audit on GET, got %s", string(body))
+	}
+
+	// 5) Reintento idempotente del owner: conserva la auditoría original
+	_, body = doReq(t, ts.URL, "POST", eventPath+"/void?idempotent=true", ownerID, map[string]any{"reason": "otro"})
+	if got := decode(body); got.VoidedBy != delegateID || got.VoidReason != resp.VoidReason {
+		t.Fatalf("expected original void audit after idempotent retry, got %s", string(body))
+	}
+
+	// 6) Restaurar limpia la auditoría
+	_, body = doReq(t, ts.URL, "POST", eventPath+"/restore", ownerID, nil)
+	if got := decode(body); got.Status != "active" || got.VoidedBy != "" || got.VoidedAt != nil || got.VoidReason != "" {
+		t.Fatalf("expected void audit cleared after restore, got %s", string(body))
+	}
+
+	// 7) Sin body: el actor se registra igual, sin motivo
+	_, body = doReq(t, ts.URL, "POST", eventPath+"/void", ownerID, nil)
+	if got := decode(body); got.VoidedBy != ownerID || got.VoidReason != "" {
+		t.Fatalf("expected owner as actor without reason, got %s", string(body))
+	}
+}